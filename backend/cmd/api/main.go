@@ -8,11 +8,15 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // the runtime image ships without a zoneinfo database
 
 	"github.com/joho/godotenv"
 	"github.com/nguyen-duc-loc/task-management/backend/internal/database"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/notifier"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/reminder"
 	"github.com/nguyen-duc-loc/task-management/backend/internal/server"
 	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
 )
 
 func init() {
@@ -45,11 +49,25 @@ func gracefulShutdown(apiServer *http.Server, done chan bool) {
 }
 
 func main() {
-	newServer, err := server.NewServer(store.NewStorage(database.NewConnPool()))
+	storage := store.NewStorage(database.NewConnPool())
+
+	newServer, err := server.NewServer(storage)
 	if err != nil {
 		panic(fmt.Sprintf("http server error: %s", err))
 	}
 
+	reminderConfig, err := util.LoadReminderConfig()
+	if err != nil {
+		panic(fmt.Sprintf("reminder scheduler error: %s", err))
+	}
+
+	// Every replica runs a scheduler; due reminders are claimed by only one
+	// of them.
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	scheduler := reminder.NewScheduler(storage, notifier.NewInAppNotifier(storage), reminderConfig)
+	go scheduler.Run(schedulerCtx)
+
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", newServer.Port),
 		Handler:      newServer.RegisterRoutes(),
@@ -71,5 +89,6 @@ func main() {
 
 	// Wait for the graceful shutdown to complete
 	<-done
+	stopScheduler()
 	log.Println("Graceful shutdown complete.")
 }
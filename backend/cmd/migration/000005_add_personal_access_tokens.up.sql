@@ -0,0 +1,15 @@
+CREATE TABLE "personal_access_tokens" (
+  "id" uuid PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "name" varchar NOT NULL,
+  "token_hash" varchar NOT NULL UNIQUE,
+  "scopes" varchar[] NOT NULL DEFAULT '{}',
+  "expire_at" timestamptz,
+  "last_used_at" timestamptz,
+  "revoked_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "personal_access_tokens" ("user_id");
+
+ALTER TABLE "personal_access_tokens" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
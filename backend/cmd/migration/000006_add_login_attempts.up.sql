@@ -0,0 +1,11 @@
+CREATE TABLE "login_attempts" (
+  "scope" varchar NOT NULL,
+  "key" varchar NOT NULL,
+  "failed_count" integer NOT NULL DEFAULT 0,
+  "locked_until" timestamptz,
+  "last_failed_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("scope", "key"),
+  CONSTRAINT "login_attempts_scope_check" CHECK ("scope" IN ('username', 'ip'))
+);
+
+CREATE INDEX ON "login_attempts" ("last_failed_at");
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS outbox_messages;
+DROP TABLE IF EXISTS password_reset_tokens;
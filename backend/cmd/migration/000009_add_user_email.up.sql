@@ -0,0 +1,15 @@
+ALTER TABLE "users" ADD COLUMN "email" varchar UNIQUE;
+ALTER TABLE "users" ADD COLUMN "email_verified_at" timestamptz;
+
+CREATE TABLE "email_verifications" (
+  "id" uuid PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "email" varchar NOT NULL,
+  "expire_at" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "email_verifications" ("user_id");
+
+ALTER TABLE "email_verifications" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
@@ -0,0 +1 @@
+ALTER TABLE "oidc_auth_requests" DROP COLUMN IF EXISTS "use_cookies";
@@ -0,0 +1 @@
+ALTER TABLE "oidc_auth_requests" ADD COLUMN "use_cookies" boolean NOT NULL DEFAULT false;
@@ -0,0 +1,25 @@
+CREATE TABLE "audit_events" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" bigint,
+  "username" varchar NOT NULL,
+  "event_type" varchar NOT NULL,
+  "detail" varchar,
+  "client_ip" varchar NOT NULL,
+  "user_agent" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "audit_events" ("user_id", "created_at");
+
+ALTER TABLE "audit_events" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+-- Events are append-only. Rows only go away together with their user.
+CREATE FUNCTION reject_audit_event_update() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit events are append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+BEFORE UPDATE ON "audit_events"
+FOR EACH ROW EXECUTE FUNCTION reject_audit_event_update();
@@ -0,0 +1,3 @@
+ALTER TABLE "tasks" ADD COLUMN "priority" varchar NOT NULL DEFAULT 'none';
+
+ALTER TABLE "tasks" ADD CONSTRAINT "tasks_priority_check" CHECK ("priority" IN ('none', 'low', 'medium', 'high', 'urgent'));
@@ -0,0 +1,25 @@
+CREATE TABLE "tags" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "name" varchar NOT NULL,
+  "color" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+-- Tag names are matched case-insensitively, so "Backend" and "backend" are
+-- the same tag.
+CREATE UNIQUE INDEX "tags_user_id_name_key" ON "tags" ("user_id", lower("name"));
+
+ALTER TABLE "tags" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE TABLE "task_tags" (
+  "task_id" varchar NOT NULL,
+  "tag_id" bigint NOT NULL,
+  PRIMARY KEY ("task_id", "tag_id")
+);
+
+CREATE INDEX ON "task_tags" ("tag_id");
+
+ALTER TABLE "task_tags" ADD FOREIGN KEY ("task_id") REFERENCES "tasks" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "task_tags" ADD FOREIGN KEY ("tag_id") REFERENCES "tags" ("id") ON DELETE CASCADE;
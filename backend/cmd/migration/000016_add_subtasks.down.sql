@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS tasks_check_hierarchy ON "tasks";
+
+DROP FUNCTION IF EXISTS check_task_hierarchy;
+
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "parent_id";
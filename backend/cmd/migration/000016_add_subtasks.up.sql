@@ -0,0 +1,60 @@
+ALTER TABLE "tasks" ADD COLUMN "parent_id" varchar;
+
+CREATE INDEX ON "tasks" ("parent_id");
+
+ALTER TABLE "tasks" ADD FOREIGN KEY ("parent_id") REFERENCES "tasks" ("id") ON DELETE CASCADE;
+
+-- Subtasks may not form cycles nor nest more than 5 levels deep, counting the
+-- top-level task as the first level.
+CREATE FUNCTION check_task_hierarchy() RETURNS trigger AS $$
+DECLARE
+  max_depth CONSTANT int := 5;
+  parent_depth int;
+  subtree_height int;
+BEGIN
+  IF NEW.parent_id IS NULL THEN
+    RETURN NEW;
+  END IF;
+
+  -- Serialize hierarchy changes of a user so that two concurrent moves
+  -- cannot build a cycle that neither of them sees on its own.
+  PERFORM pg_advisory_xact_lock(hashtextextended('task_hierarchy:' || NEW.creator_id, 0));
+
+  IF NEW.parent_id = NEW.id OR EXISTS (
+    WITH RECURSIVE ancestors AS (
+      SELECT id, parent_id FROM tasks WHERE id = NEW.parent_id
+      UNION
+      SELECT tasks.id, tasks.parent_id FROM tasks JOIN ancestors ON tasks.id = ancestors.parent_id
+    )
+    SELECT 1 FROM ancestors WHERE id = NEW.id
+  ) THEN
+    RAISE EXCEPTION 'task % cannot be a subtask of itself', NEW.id
+      USING ERRCODE = 'check_violation', CONSTRAINT = 'tasks_parent_cycle_check';
+  END IF;
+
+  WITH RECURSIVE ancestors AS (
+    SELECT id, parent_id, 1 AS depth FROM tasks WHERE id = NEW.parent_id
+    UNION ALL
+    SELECT tasks.id, tasks.parent_id, ancestors.depth + 1 FROM tasks JOIN ancestors ON tasks.id = ancestors.parent_id
+  )
+  SELECT COALESCE(MAX(depth), 0) INTO parent_depth FROM ancestors;
+
+  WITH RECURSIVE descendants AS (
+    SELECT id, 1 AS height FROM tasks WHERE id = NEW.id
+    UNION ALL
+    SELECT tasks.id, descendants.height + 1 FROM tasks JOIN descendants ON tasks.parent_id = descendants.id
+  )
+  SELECT COALESCE(MAX(height), 1) INTO subtree_height FROM descendants;
+
+  IF parent_depth + subtree_height > max_depth THEN
+    RAISE EXCEPTION 'subtasks cannot be nested more than % levels deep', max_depth
+      USING ERRCODE = 'check_violation', CONSTRAINT = 'tasks_depth_check';
+  END IF;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tasks_check_hierarchy
+BEFORE INSERT OR UPDATE OF "parent_id" ON "tasks"
+FOR EACH ROW EXECUTE FUNCTION check_task_hierarchy();
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS task_dependencies;
+
+DROP FUNCTION IF EXISTS check_task_dependency_cycle;
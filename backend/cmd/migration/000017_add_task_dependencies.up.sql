@@ -0,0 +1,42 @@
+CREATE TABLE "task_dependencies" (
+  "task_id" varchar NOT NULL,
+  "blocked_by_id" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("task_id", "blocked_by_id"),
+  CONSTRAINT "task_dependencies_self_check" CHECK ("task_id" <> "blocked_by_id")
+);
+
+CREATE INDEX ON "task_dependencies" ("blocked_by_id");
+
+ALTER TABLE "task_dependencies" ADD FOREIGN KEY ("task_id") REFERENCES "tasks" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "task_dependencies" ADD FOREIGN KEY ("blocked_by_id") REFERENCES "tasks" ("id") ON DELETE CASCADE;
+
+-- A task may not end up waiting on itself through a chain of dependencies.
+CREATE FUNCTION check_task_dependency_cycle() RETURNS trigger AS $$
+BEGIN
+  -- Serialize dependency changes of a user so that two concurrent inserts
+  -- cannot close a cycle that neither of them sees on its own.
+  PERFORM pg_advisory_xact_lock(hashtextextended('task_dependencies:' || creator_id, 0))
+  FROM tasks WHERE id = NEW.task_id;
+
+  IF EXISTS (
+    WITH RECURSIVE blockers AS (
+      SELECT NEW.blocked_by_id AS id
+      UNION
+      SELECT task_dependencies.blocked_by_id
+      FROM task_dependencies JOIN blockers ON task_dependencies.task_id = blockers.id
+    )
+    SELECT 1 FROM blockers WHERE id = NEW.task_id
+  ) THEN
+    RAISE EXCEPTION 'task % would depend on itself', NEW.task_id
+      USING ERRCODE = 'check_violation', CONSTRAINT = 'task_dependencies_cycle_check';
+  END IF;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_dependencies_check_cycle
+BEFORE INSERT OR UPDATE ON "task_dependencies"
+FOR EACH ROW EXECUTE FUNCTION check_task_dependency_cycle();
@@ -0,0 +1,5 @@
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "occurrence";
+
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "recurrence_id";
+
+DROP TABLE IF EXISTS task_recurrences;
@@ -0,0 +1,24 @@
+-- A recurrence is a series of tasks. Only the current occurrence exists as a
+-- task; the next one is created from the template here when it is completed.
+CREATE TABLE "task_recurrences" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "creator_id" bigint NOT NULL,
+  "rule" varchar NOT NULL,
+  "first_deadline" timestamptz NOT NULL,
+  "timezone" varchar NOT NULL,
+  "title" varchar NOT NULL,
+  "description" varchar,
+  "priority" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "task_recurrences" ADD FOREIGN KEY ("creator_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "tasks" ADD COLUMN "recurrence_id" bigint;
+
+ALTER TABLE "tasks" ADD COLUMN "occurrence" int;
+
+ALTER TABLE "tasks" ADD FOREIGN KEY ("recurrence_id") REFERENCES "task_recurrences" ("id") ON DELETE SET NULL;
+
+-- Each occurrence of a series is created at most once.
+CREATE UNIQUE INDEX "tasks_recurrence_id_occurrence_key" ON "tasks" ("recurrence_id", "occurrence");
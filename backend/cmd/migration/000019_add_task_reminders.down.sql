@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS notifications;
+
+DROP TRIGGER IF EXISTS tasks_sync_reminders ON tasks;
+
+DROP FUNCTION IF EXISTS sync_task_reminders();
+
+DROP TABLE IF EXISTS task_reminders;
@@ -0,0 +1,61 @@
+-- A reminder fires either at an absolute time or a fixed offset before the
+-- deadline of its task. fire_at is kept in sync with the deadline for the
+-- latter by the tasks_sync_reminders trigger.
+CREATE TABLE "task_reminders" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "task_id" varchar NOT NULL,
+  "remind_at" timestamptz,
+  "offset_seconds" int,
+  "fire_at" timestamptz NOT NULL,
+  -- claimed_until leases a due reminder to one scheduler so that replicas
+  -- do not deliver it twice; an expired lease makes it due again.
+  "claimed_until" timestamptz,
+  "sent_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "task_reminders_time_check" CHECK (("remind_at" IS NULL) <> ("offset_seconds" IS NULL)),
+  CONSTRAINT "task_reminders_offset_check" CHECK ("offset_seconds" > 0)
+);
+
+CREATE INDEX ON "task_reminders" ("task_id");
+
+CREATE INDEX "task_reminders_due_idx" ON "task_reminders" ("fire_at") WHERE "sent_at" IS NULL;
+
+ALTER TABLE "task_reminders" ADD FOREIGN KEY ("task_id") REFERENCES "tasks" ("id") ON DELETE CASCADE;
+
+CREATE FUNCTION sync_task_reminders() RETURNS trigger AS $$
+BEGIN
+  -- A reminder relative to a postponed deadline fires again.
+  UPDATE task_reminders
+  SET
+    fire_at = NEW.deadline - make_interval(secs => offset_seconds),
+    sent_at = CASE
+      WHEN NEW.deadline - make_interval(secs => offset_seconds) > now() THEN NULL
+      ELSE sent_at
+    END
+  WHERE task_id = NEW.id AND offset_seconds IS NOT NULL;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "tasks_sync_reminders"
+AFTER UPDATE OF "deadline" ON "tasks"
+FOR EACH ROW
+WHEN (OLD.deadline IS DISTINCT FROM NEW.deadline)
+EXECUTE FUNCTION sync_task_reminders();
+
+CREATE TABLE "notifications" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "kind" varchar NOT NULL,
+  "subject" varchar NOT NULL,
+  "body" text NOT NULL,
+  -- dedup_key makes delivering the same message twice a no-op.
+  "dedup_key" varchar UNIQUE,
+  "read_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "notifications" ("user_id");
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
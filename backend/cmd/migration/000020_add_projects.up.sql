@@ -0,0 +1,21 @@
+CREATE TABLE "projects" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "name" varchar NOT NULL,
+  "description" varchar,
+  "color" varchar NOT NULL,
+  "archived" boolean NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+-- Like tags, project names are matched case-insensitively.
+CREATE UNIQUE INDEX "projects_user_id_name_key" ON "projects" ("user_id", lower("name"));
+
+ALTER TABLE "projects" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "tasks" ADD COLUMN "project_id" bigint;
+
+CREATE INDEX ON "tasks" ("project_id");
+
+-- Deleting a project keeps its tasks.
+ALTER TABLE "tasks" ADD FOREIGN KEY ("project_id") REFERENCES "projects" ("id") ON DELETE SET NULL;
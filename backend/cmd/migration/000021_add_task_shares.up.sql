@@ -0,0 +1,15 @@
+CREATE TABLE "task_shares" (
+  "task_id" varchar NOT NULL,
+  "user_id" bigint NOT NULL,
+  "role" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("task_id", "user_id"),
+  CONSTRAINT "task_shares_role_check" CHECK ("role" IN ('viewer', 'editor'))
+);
+
+-- Lists the tasks shared with a user.
+CREATE INDEX ON "task_shares" ("user_id");
+
+ALTER TABLE "task_shares" ADD FOREIGN KEY ("task_id") REFERENCES "tasks" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "task_shares" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
@@ -0,0 +1,49 @@
+CREATE TABLE "workspaces" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "workspace_members" (
+  "workspace_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "role" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("workspace_id", "user_id"),
+  CONSTRAINT "workspace_members_role_check" CHECK ("role" IN ('owner', 'admin', 'member'))
+);
+
+CREATE INDEX ON "workspace_members" ("user_id");
+
+-- A workspace has a single owner.
+CREATE UNIQUE INDEX "workspace_members_owner_key" ON "workspace_members" ("workspace_id") WHERE "role" = 'owner';
+
+ALTER TABLE "workspace_members" ADD FOREIGN KEY ("workspace_id") REFERENCES "workspaces" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "workspace_members" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+-- Invitations may be used by anyone holding the link until they expire or
+-- are revoked. Only the hash of the token is stored.
+CREATE TABLE "workspace_invitations" (
+  "id" uuid PRIMARY KEY,
+  "workspace_id" bigint NOT NULL,
+  "token_hash" varchar NOT NULL UNIQUE,
+  "role" varchar NOT NULL,
+  "created_by" bigint NOT NULL,
+  "expire_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "workspace_invitations_role_check" CHECK ("role" IN ('admin', 'member'))
+);
+
+CREATE INDEX ON "workspace_invitations" ("workspace_id");
+
+ALTER TABLE "workspace_invitations" ADD FOREIGN KEY ("workspace_id") REFERENCES "workspaces" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "workspace_invitations" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+-- Tasks without a workspace are personal tasks of their creator.
+ALTER TABLE "tasks" ADD COLUMN "workspace_id" bigint;
+
+CREATE INDEX ON "tasks" ("workspace_id");
+
+ALTER TABLE "tasks" ADD FOREIGN KEY ("workspace_id") REFERENCES "workspaces" ("id") ON DELETE CASCADE;
@@ -0,0 +1,6 @@
+ALTER TABLE "tasks" ADD COLUMN "assignee_id" bigint;
+
+CREATE INDEX ON "tasks" ("assignee_id");
+
+-- Deleting the assignee leaves the task unassigned.
+ALTER TABLE "tasks" ADD FOREIGN KEY ("assignee_id") REFERENCES "users" ("id") ON DELETE SET NULL;
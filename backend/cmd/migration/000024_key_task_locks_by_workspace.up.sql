@@ -0,0 +1,82 @@
+CREATE OR REPLACE FUNCTION check_task_hierarchy() RETURNS trigger AS $$
+DECLARE
+  max_depth CONSTANT int := 5;
+  parent_depth int;
+  subtree_height int;
+BEGIN
+  IF NEW.parent_id IS NULL THEN
+    RETURN NEW;
+  END IF;
+
+  -- Serialize hierarchy changes of a workspace, or of a user for personal
+  -- tasks, so that two concurrent moves cannot build a cycle that neither of
+  -- them sees on its own. Members of a workspace move each other's tasks.
+  PERFORM pg_advisory_xact_lock(hashtextextended(
+    CASE
+      WHEN NEW.workspace_id IS NOT NULL THEN 'task_hierarchy:workspace:' || NEW.workspace_id
+      ELSE 'task_hierarchy:user:' || NEW.creator_id
+    END, 0));
+
+  IF NEW.parent_id = NEW.id OR EXISTS (
+    WITH RECURSIVE ancestors AS (
+      SELECT id, parent_id FROM tasks WHERE id = NEW.parent_id
+      UNION
+      SELECT tasks.id, tasks.parent_id FROM tasks JOIN ancestors ON tasks.id = ancestors.parent_id
+    )
+    SELECT 1 FROM ancestors WHERE id = NEW.id
+  ) THEN
+    RAISE EXCEPTION 'task % cannot be a subtask of itself', NEW.id
+      USING ERRCODE = 'check_violation', CONSTRAINT = 'tasks_parent_cycle_check';
+  END IF;
+
+  WITH RECURSIVE ancestors AS (
+    SELECT id, parent_id, 1 AS depth FROM tasks WHERE id = NEW.parent_id
+    UNION ALL
+    SELECT tasks.id, tasks.parent_id, ancestors.depth + 1 FROM tasks JOIN ancestors ON tasks.id = ancestors.parent_id
+  )
+  SELECT COALESCE(MAX(depth), 0) INTO parent_depth FROM ancestors;
+
+  WITH RECURSIVE descendants AS (
+    SELECT id, 1 AS height FROM tasks WHERE id = NEW.id
+    UNION ALL
+    SELECT tasks.id, descendants.height + 1 FROM tasks JOIN descendants ON tasks.parent_id = descendants.id
+  )
+  SELECT COALESCE(MAX(height), 1) INTO subtree_height FROM descendants;
+
+  IF parent_depth + subtree_height > max_depth THEN
+    RAISE EXCEPTION 'subtasks cannot be nested more than % levels deep', max_depth
+      USING ERRCODE = 'check_violation', CONSTRAINT = 'tasks_depth_check';
+  END IF;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION check_task_dependency_cycle() RETURNS trigger AS $$
+BEGIN
+  -- Serialize dependency changes of a workspace, or of a user for personal
+  -- tasks, so that two concurrent inserts cannot close a cycle that neither
+  -- of them sees on its own.
+  PERFORM pg_advisory_xact_lock(hashtextextended(
+    CASE
+      WHEN workspace_id IS NOT NULL THEN 'task_dependencies:workspace:' || workspace_id
+      ELSE 'task_dependencies:user:' || creator_id
+    END, 0))
+  FROM tasks WHERE id = NEW.task_id;
+
+  IF EXISTS (
+    WITH RECURSIVE blockers AS (
+      SELECT NEW.blocked_by_id AS id
+      UNION
+      SELECT task_dependencies.blocked_by_id
+      FROM task_dependencies JOIN blockers ON task_dependencies.task_id = blockers.id
+    )
+    SELECT 1 FROM blockers WHERE id = NEW.task_id
+  ) THEN
+    RAISE EXCEPTION 'task % would depend on itself', NEW.task_id
+      USING ERRCODE = 'check_violation', CONSTRAINT = 'task_dependencies_cycle_check';
+  END IF;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS users_reassign_workspace_tasks ON "users";
+
+DROP FUNCTION IF EXISTS reassign_workspace_tasks;
@@ -0,0 +1,30 @@
+-- Tasks of a workspace belong to the workspace, so when the account of their
+-- creator is deleted they are handed over to the workspace owner instead of
+-- being deleted along with it. Owners cannot delete their account while they
+-- own a workspace.
+CREATE FUNCTION reassign_workspace_tasks() RETURNS trigger AS $$
+BEGIN
+  UPDATE task_recurrences
+  SET creator_id = workspace_members.user_id
+  FROM tasks
+  JOIN workspace_members ON workspace_members.workspace_id = tasks.workspace_id
+    AND workspace_members.role = 'owner'
+  WHERE task_recurrences.id = tasks.recurrence_id
+    AND task_recurrences.creator_id = OLD.id
+    AND workspace_members.user_id <> OLD.id;
+
+  UPDATE tasks
+  SET creator_id = workspace_members.user_id
+  FROM workspace_members
+  WHERE tasks.creator_id = OLD.id
+    AND workspace_members.workspace_id = tasks.workspace_id
+    AND workspace_members.role = 'owner'
+    AND workspace_members.user_id <> OLD.id;
+
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_reassign_workspace_tasks
+BEFORE DELETE ON "users"
+FOR EACH ROW EXECUTE FUNCTION reassign_workspace_tasks();
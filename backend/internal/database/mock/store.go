@@ -12,7 +12,10 @@ package mockdb
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	uuid "github.com/google/uuid"
+	pgtype "github.com/jackc/pgx/v5/pgtype"
 	store "github.com/nguyen-duc-loc/task-management/backend/internal/store"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,107 +44,1614 @@ func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
 	return m.recorder
 }
 
+// AddTaskDependency mocks base method.
+func (m *MockStorage) AddTaskDependency(ctx context.Context, arg store.AddTaskDependencyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTaskDependency", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTaskDependency indicates an expected call of AddTaskDependency.
+func (mr *MockStorageMockRecorder) AddTaskDependency(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTaskDependency", reflect.TypeOf((*MockStorage)(nil).AddTaskDependency), ctx, arg)
+}
+
+// AddWorkspaceMember mocks base method.
+func (m *MockStorage) AddWorkspaceMember(ctx context.Context, arg store.AddWorkspaceMemberParams) (store.WorkspaceMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWorkspaceMember", ctx, arg)
+	ret0, _ := ret[0].(store.WorkspaceMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddWorkspaceMember indicates an expected call of AddWorkspaceMember.
+func (mr *MockStorageMockRecorder) AddWorkspaceMember(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWorkspaceMember", reflect.TypeOf((*MockStorage)(nil).AddWorkspaceMember), ctx, arg)
+}
+
+// BlockOtherUserSessions mocks base method.
+func (m *MockStorage) BlockOtherUserSessions(ctx context.Context, arg store.BlockOtherUserSessionsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockOtherUserSessions", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockOtherUserSessions indicates an expected call of BlockOtherUserSessions.
+func (mr *MockStorageMockRecorder) BlockOtherUserSessions(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockOtherUserSessions", reflect.TypeOf((*MockStorage)(nil).BlockOtherUserSessions), ctx, arg)
+}
+
+// BlockSession mocks base method.
+func (m *MockStorage) BlockSession(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockSession", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockSession indicates an expected call of BlockSession.
+func (mr *MockStorageMockRecorder) BlockSession(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockSession", reflect.TypeOf((*MockStorage)(nil).BlockSession), ctx, id)
+}
+
+// BlockUserSessions mocks base method.
+func (m *MockStorage) BlockUserSessions(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUserSessions", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockUserSessions indicates an expected call of BlockUserSessions.
+func (mr *MockStorageMockRecorder) BlockUserSessions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUserSessions", reflect.TypeOf((*MockStorage)(nil).BlockUserSessions), ctx, userID)
+}
+
+// ClaimDueReminders mocks base method.
+func (m *MockStorage) ClaimDueReminders(ctx context.Context, arg store.ClaimDueRemindersParams) ([]store.ClaimDueRemindersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueReminders", ctx, arg)
+	ret0, _ := ret[0].([]store.ClaimDueRemindersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueReminders indicates an expected call of ClaimDueReminders.
+func (mr *MockStorageMockRecorder) ClaimDueReminders(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueReminders", reflect.TypeOf((*MockStorage)(nil).ClaimDueReminders), ctx, arg)
+}
+
+// ClearLoginAttempts mocks base method.
+func (m *MockStorage) ClearLoginAttempts(ctx context.Context, arg store.ClearLoginAttemptsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearLoginAttempts", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearLoginAttempts indicates an expected call of ClearLoginAttempts.
+func (mr *MockStorageMockRecorder) ClearLoginAttempts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearLoginAttempts", reflect.TypeOf((*MockStorage)(nil).ClearLoginAttempts), ctx, arg)
+}
+
+// ConfirmTOTPCredential mocks base method.
+func (m *MockStorage) ConfirmTOTPCredential(ctx context.Context, arg store.ConfirmTOTPCredentialParams) (store.TotpCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTOTPCredential", ctx, arg)
+	ret0, _ := ret[0].(store.TotpCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTOTPCredential indicates an expected call of ConfirmTOTPCredential.
+func (mr *MockStorageMockRecorder) ConfirmTOTPCredential(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTPCredential", reflect.TypeOf((*MockStorage)(nil).ConfirmTOTPCredential), ctx, arg)
+}
+
+// ConsumeEmailVerification mocks base method.
+func (m *MockStorage) ConsumeEmailVerification(ctx context.Context, id uuid.UUID) (store.EmailVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeEmailVerification", ctx, id)
+	ret0, _ := ret[0].(store.EmailVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeEmailVerification indicates an expected call of ConsumeEmailVerification.
+func (mr *MockStorageMockRecorder) ConsumeEmailVerification(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeEmailVerification", reflect.TypeOf((*MockStorage)(nil).ConsumeEmailVerification), ctx, id)
+}
+
+// ConsumeMFAChallenge mocks base method.
+func (m *MockStorage) ConsumeMFAChallenge(ctx context.Context, id uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeMFAChallenge", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeMFAChallenge indicates an expected call of ConsumeMFAChallenge.
+func (mr *MockStorageMockRecorder) ConsumeMFAChallenge(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeMFAChallenge", reflect.TypeOf((*MockStorage)(nil).ConsumeMFAChallenge), ctx, id)
+}
+
+// ConsumeOIDCAuthRequest mocks base method.
+func (m *MockStorage) ConsumeOIDCAuthRequest(ctx context.Context, state string) (store.OidcAuthRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeOIDCAuthRequest", ctx, state)
+	ret0, _ := ret[0].(store.OidcAuthRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeOIDCAuthRequest indicates an expected call of ConsumeOIDCAuthRequest.
+func (mr *MockStorageMockRecorder) ConsumeOIDCAuthRequest(ctx, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeOIDCAuthRequest", reflect.TypeOf((*MockStorage)(nil).ConsumeOIDCAuthRequest), ctx, state)
+}
+
+// ConsumePasswordResetToken mocks base method.
+func (m *MockStorage) ConsumePasswordResetToken(ctx context.Context, tokenHash string) (store.PasswordResetToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumePasswordResetToken", ctx, tokenHash)
+	ret0, _ := ret[0].(store.PasswordResetToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumePasswordResetToken indicates an expected call of ConsumePasswordResetToken.
+func (mr *MockStorageMockRecorder) ConsumePasswordResetToken(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumePasswordResetToken", reflect.TypeOf((*MockStorage)(nil).ConsumePasswordResetToken), ctx, tokenHash)
+}
+
+// CopyTaskReminders mocks base method.
+func (m *MockStorage) CopyTaskReminders(ctx context.Context, arg store.CopyTaskRemindersParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyTaskReminders", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyTaskReminders indicates an expected call of CopyTaskReminders.
+func (mr *MockStorageMockRecorder) CopyTaskReminders(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyTaskReminders", reflect.TypeOf((*MockStorage)(nil).CopyTaskReminders), ctx, arg)
+}
+
+// CopyTaskShares mocks base method.
+func (m *MockStorage) CopyTaskShares(ctx context.Context, arg store.CopyTaskSharesParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyTaskShares", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyTaskShares indicates an expected call of CopyTaskShares.
+func (mr *MockStorageMockRecorder) CopyTaskShares(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyTaskShares", reflect.TypeOf((*MockStorage)(nil).CopyTaskShares), ctx, arg)
+}
+
+// CountOpenBlockers mocks base method.
+func (m *MockStorage) CountOpenBlockers(ctx context.Context, taskID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOpenBlockers", ctx, taskID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOpenBlockers indicates an expected call of CountOpenBlockers.
+func (mr *MockStorageMockRecorder) CountOpenBlockers(ctx, taskID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOpenBlockers", reflect.TypeOf((*MockStorage)(nil).CountOpenBlockers), ctx, taskID)
+}
+
+// CountUnusedRecoveryCodes mocks base method.
+func (m *MockStorage) CountUnusedRecoveryCodes(ctx context.Context, userID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnusedRecoveryCodes", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnusedRecoveryCodes indicates an expected call of CountUnusedRecoveryCodes.
+func (mr *MockStorageMockRecorder) CountUnusedRecoveryCodes(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnusedRecoveryCodes", reflect.TypeOf((*MockStorage)(nil).CountUnusedRecoveryCodes), ctx, userID)
+}
+
+// CreateAuditEvent mocks base method.
+func (m *MockStorage) CreateAuditEvent(ctx context.Context, arg store.CreateAuditEventParams) (store.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditEvent", ctx, arg)
+	ret0, _ := ret[0].(store.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditEvent indicates an expected call of CreateAuditEvent.
+func (mr *MockStorageMockRecorder) CreateAuditEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEvent", reflect.TypeOf((*MockStorage)(nil).CreateAuditEvent), ctx, arg)
+}
+
+// CreateEmailVerification mocks base method.
+func (m *MockStorage) CreateEmailVerification(ctx context.Context, arg store.CreateEmailVerificationParams) (store.EmailVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEmailVerification", ctx, arg)
+	ret0, _ := ret[0].(store.EmailVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEmailVerification indicates an expected call of CreateEmailVerification.
+func (mr *MockStorageMockRecorder) CreateEmailVerification(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEmailVerification", reflect.TypeOf((*MockStorage)(nil).CreateEmailVerification), ctx, arg)
+}
+
+// CreateMFAChallenge mocks base method.
+func (m *MockStorage) CreateMFAChallenge(ctx context.Context, arg store.CreateMFAChallengeParams) (store.MfaChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMFAChallenge", ctx, arg)
+	ret0, _ := ret[0].(store.MfaChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMFAChallenge indicates an expected call of CreateMFAChallenge.
+func (mr *MockStorageMockRecorder) CreateMFAChallenge(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMFAChallenge", reflect.TypeOf((*MockStorage)(nil).CreateMFAChallenge), ctx, arg)
+}
+
+// CreateNotification mocks base method.
+func (m *MockStorage) CreateNotification(ctx context.Context, arg store.CreateNotificationParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotification", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNotification indicates an expected call of CreateNotification.
+func (mr *MockStorageMockRecorder) CreateNotification(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotification", reflect.TypeOf((*MockStorage)(nil).CreateNotification), ctx, arg)
+}
+
+// CreateOIDCAuthRequest mocks base method.
+func (m *MockStorage) CreateOIDCAuthRequest(ctx context.Context, arg store.CreateOIDCAuthRequestParams) (store.OidcAuthRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOIDCAuthRequest", ctx, arg)
+	ret0, _ := ret[0].(store.OidcAuthRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOIDCAuthRequest indicates an expected call of CreateOIDCAuthRequest.
+func (mr *MockStorageMockRecorder) CreateOIDCAuthRequest(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOIDCAuthRequest", reflect.TypeOf((*MockStorage)(nil).CreateOIDCAuthRequest), ctx, arg)
+}
+
+// CreateOutboxMessage mocks base method.
+func (m *MockStorage) CreateOutboxMessage(ctx context.Context, arg store.CreateOutboxMessageParams) (store.OutboxMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxMessage", ctx, arg)
+	ret0, _ := ret[0].(store.OutboxMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOutboxMessage indicates an expected call of CreateOutboxMessage.
+func (mr *MockStorageMockRecorder) CreateOutboxMessage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxMessage", reflect.TypeOf((*MockStorage)(nil).CreateOutboxMessage), ctx, arg)
+}
+
+// CreatePasswordResetToken mocks base method.
+func (m *MockStorage) CreatePasswordResetToken(ctx context.Context, arg store.CreatePasswordResetTokenParams) (store.PasswordResetToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasswordResetToken", ctx, arg)
+	ret0, _ := ret[0].(store.PasswordResetToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePasswordResetToken indicates an expected call of CreatePasswordResetToken.
+func (mr *MockStorageMockRecorder) CreatePasswordResetToken(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordResetToken", reflect.TypeOf((*MockStorage)(nil).CreatePasswordResetToken), ctx, arg)
+}
+
+// CreatePersonalAccessToken mocks base method.
+func (m *MockStorage) CreatePersonalAccessToken(ctx context.Context, arg store.CreatePersonalAccessTokenParams) (store.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePersonalAccessToken", ctx, arg)
+	ret0, _ := ret[0].(store.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePersonalAccessToken indicates an expected call of CreatePersonalAccessToken.
+func (mr *MockStorageMockRecorder) CreatePersonalAccessToken(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePersonalAccessToken", reflect.TypeOf((*MockStorage)(nil).CreatePersonalAccessToken), ctx, arg)
+}
+
+// CreateProject mocks base method.
+func (m *MockStorage) CreateProject(ctx context.Context, arg store.CreateProjectParams) (store.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProject", ctx, arg)
+	ret0, _ := ret[0].(store.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProject indicates an expected call of CreateProject.
+func (mr *MockStorageMockRecorder) CreateProject(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProject", reflect.TypeOf((*MockStorage)(nil).CreateProject), ctx, arg)
+}
+
+// CreateRecoveryCodes mocks base method.
+func (m *MockStorage) CreateRecoveryCodes(ctx context.Context, arg store.CreateRecoveryCodesParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecoveryCodes", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRecoveryCodes indicates an expected call of CreateRecoveryCodes.
+func (mr *MockStorageMockRecorder) CreateRecoveryCodes(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecoveryCodes", reflect.TypeOf((*MockStorage)(nil).CreateRecoveryCodes), ctx, arg)
+}
+
+// CreateRecurringTask mocks base method.
+func (m *MockStorage) CreateRecurringTask(ctx context.Context, arg store.CreateRecurringTaskParams) (store.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecurringTask", ctx, arg)
+	ret0, _ := ret[0].(store.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRecurringTask indicates an expected call of CreateRecurringTask.
+func (mr *MockStorageMockRecorder) CreateRecurringTask(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecurringTask", reflect.TypeOf((*MockStorage)(nil).CreateRecurringTask), ctx, arg)
+}
+
+// CreateSession mocks base method.
+func (m *MockStorage) CreateSession(ctx context.Context, arg store.CreateSessionParams) (store.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", ctx, arg)
+	ret0, _ := ret[0].(store.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockStorageMockRecorder) CreateSession(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStorage)(nil).CreateSession), ctx, arg)
+}
+
+// CreateTag mocks base method.
+func (m *MockStorage) CreateTag(ctx context.Context, arg store.CreateTagParams) (store.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTag", ctx, arg)
+	ret0, _ := ret[0].(store.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTag indicates an expected call of CreateTag.
+func (mr *MockStorageMockRecorder) CreateTag(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTag", reflect.TypeOf((*MockStorage)(nil).CreateTag), ctx, arg)
+}
+
 // CreateTask mocks base method.
 func (m *MockStorage) CreateTask(ctx context.Context, arg store.CreateTaskParams) (store.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTask", ctx, arg)
+	ret := m.ctrl.Call(m, "CreateTask", ctx, arg)
+	ret0, _ := ret[0].(store.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTask indicates an expected call of CreateTask.
+func (mr *MockStorageMockRecorder) CreateTask(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTask", reflect.TypeOf((*MockStorage)(nil).CreateTask), ctx, arg)
+}
+
+// CreateTaskOccurrence mocks base method.
+func (m *MockStorage) CreateTaskOccurrence(ctx context.Context, arg store.CreateTaskOccurrenceParams) (store.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaskOccurrence", ctx, arg)
+	ret0, _ := ret[0].(store.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTaskOccurrence indicates an expected call of CreateTaskOccurrence.
+func (mr *MockStorageMockRecorder) CreateTaskOccurrence(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaskOccurrence", reflect.TypeOf((*MockStorage)(nil).CreateTaskOccurrence), ctx, arg)
+}
+
+// CreateTaskReminder mocks base method.
+func (m *MockStorage) CreateTaskReminder(ctx context.Context, arg store.CreateTaskReminderParams) (store.TaskReminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaskReminder", ctx, arg)
+	ret0, _ := ret[0].(store.TaskReminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTaskReminder indicates an expected call of CreateTaskReminder.
+func (mr *MockStorageMockRecorder) CreateTaskReminder(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaskReminder", reflect.TypeOf((*MockStorage)(nil).CreateTaskReminder), ctx, arg)
+}
+
+// CreateUser mocks base method.
+func (m *MockStorage) CreateUser(ctx context.Context, arg store.CreateUserParams) (store.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, arg)
+	ret0, _ := ret[0].(store.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStorageMockRecorder) CreateUser(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStorage)(nil).CreateUser), ctx, arg)
+}
+
+// CreateUserIdentity mocks base method.
+func (m *MockStorage) CreateUserIdentity(ctx context.Context, arg store.CreateUserIdentityParams) (store.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserIdentity", ctx, arg)
+	ret0, _ := ret[0].(store.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserIdentity indicates an expected call of CreateUserIdentity.
+func (mr *MockStorageMockRecorder) CreateUserIdentity(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserIdentity", reflect.TypeOf((*MockStorage)(nil).CreateUserIdentity), ctx, arg)
+}
+
+// CreateUserWithIdentity mocks base method.
+func (m *MockStorage) CreateUserWithIdentity(ctx context.Context, arg store.CreateUserWithIdentityParams) (store.CreateUserWithIdentityRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserWithIdentity", ctx, arg)
+	ret0, _ := ret[0].(store.CreateUserWithIdentityRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserWithIdentity indicates an expected call of CreateUserWithIdentity.
+func (mr *MockStorageMockRecorder) CreateUserWithIdentity(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserWithIdentity", reflect.TypeOf((*MockStorage)(nil).CreateUserWithIdentity), ctx, arg)
+}
+
+// CreateWorkspace mocks base method.
+func (m *MockStorage) CreateWorkspace(ctx context.Context, arg store.CreateWorkspaceParams) (store.CreateWorkspaceRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkspace", ctx, arg)
+	ret0, _ := ret[0].(store.CreateWorkspaceRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWorkspace indicates an expected call of CreateWorkspace.
+func (mr *MockStorageMockRecorder) CreateWorkspace(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkspace", reflect.TypeOf((*MockStorage)(nil).CreateWorkspace), ctx, arg)
+}
+
+// CreateWorkspaceInvitation mocks base method.
+func (m *MockStorage) CreateWorkspaceInvitation(ctx context.Context, arg store.CreateWorkspaceInvitationParams) (store.WorkspaceInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkspaceInvitation", ctx, arg)
+	ret0, _ := ret[0].(store.WorkspaceInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWorkspaceInvitation indicates an expected call of CreateWorkspaceInvitation.
+func (mr *MockStorageMockRecorder) CreateWorkspaceInvitation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkspaceInvitation", reflect.TypeOf((*MockStorage)(nil).CreateWorkspaceInvitation), ctx, arg)
+}
+
+// DeleteExpiredOIDCAuthRequests mocks base method.
+func (m *MockStorage) DeleteExpiredOIDCAuthRequests(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredOIDCAuthRequests", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredOIDCAuthRequests indicates an expected call of DeleteExpiredOIDCAuthRequests.
+func (mr *MockStorageMockRecorder) DeleteExpiredOIDCAuthRequests(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredOIDCAuthRequests", reflect.TypeOf((*MockStorage)(nil).DeleteExpiredOIDCAuthRequests), ctx)
+}
+
+// DeleteProject mocks base method.
+func (m *MockStorage) DeleteProject(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProject", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProject indicates an expected call of DeleteProject.
+func (mr *MockStorageMockRecorder) DeleteProject(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProject", reflect.TypeOf((*MockStorage)(nil).DeleteProject), ctx, id)
+}
+
+// DeleteRecoveryCodes mocks base method.
+func (m *MockStorage) DeleteRecoveryCodes(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecoveryCodes", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecoveryCodes indicates an expected call of DeleteRecoveryCodes.
+func (mr *MockStorageMockRecorder) DeleteRecoveryCodes(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecoveryCodes", reflect.TypeOf((*MockStorage)(nil).DeleteRecoveryCodes), ctx, userID)
+}
+
+// DeleteTOTPCredential mocks base method.
+func (m *MockStorage) DeleteTOTPCredential(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTOTPCredential", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTOTPCredential indicates an expected call of DeleteTOTPCredential.
+func (mr *MockStorageMockRecorder) DeleteTOTPCredential(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTOTPCredential", reflect.TypeOf((*MockStorage)(nil).DeleteTOTPCredential), ctx, userID)
+}
+
+// DeleteTag mocks base method.
+func (m *MockStorage) DeleteTag(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *MockStorageMockRecorder) DeleteTag(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockStorage)(nil).DeleteTag), ctx, id)
+}
+
+// DeleteTask mocks base method.
+func (m *MockStorage) DeleteTask(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTask", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTask indicates an expected call of DeleteTask.
+func (mr *MockStorageMockRecorder) DeleteTask(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockStorage)(nil).DeleteTask), ctx, id)
+}
+
+// DeleteTaskReminder mocks base method.
+func (m *MockStorage) DeleteTaskReminder(ctx context.Context, arg store.DeleteTaskReminderParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTaskReminder", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTaskReminder indicates an expected call of DeleteTaskReminder.
+func (mr *MockStorageMockRecorder) DeleteTaskReminder(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTaskReminder", reflect.TypeOf((*MockStorage)(nil).DeleteTaskReminder), ctx, arg)
+}
+
+// DeleteUser mocks base method.
+func (m *MockStorage) DeleteUser(ctx context.Context, id int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockStorageMockRecorder) DeleteUser(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockStorage)(nil).DeleteUser), ctx, id)
+}
+
+// DeleteWorkspace mocks base method.
+func (m *MockStorage) DeleteWorkspace(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspace", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspace indicates an expected call of DeleteWorkspace.
+func (mr *MockStorageMockRecorder) DeleteWorkspace(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspace", reflect.TypeOf((*MockStorage)(nil).DeleteWorkspace), ctx, id)
+}
+
+// DeleteWorkspaceInvitation mocks base method.
+func (m *MockStorage) DeleteWorkspaceInvitation(ctx context.Context, arg store.DeleteWorkspaceInvitationParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceInvitation", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWorkspaceInvitation indicates an expected call of DeleteWorkspaceInvitation.
+func (mr *MockStorageMockRecorder) DeleteWorkspaceInvitation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceInvitation", reflect.TypeOf((*MockStorage)(nil).DeleteWorkspaceInvitation), ctx, arg)
+}
+
+// ExecTx mocks base method.
+func (m *MockStorage) ExecTx(ctx context.Context, fn func(store.Querier) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecTx indicates an expected call of ExecTx.
+func (mr *MockStorageMockRecorder) ExecTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecTx", reflect.TypeOf((*MockStorage)(nil).ExecTx), ctx, fn)
+}
+
+// GetActiveMFAChallenge mocks base method.
+func (m *MockStorage) GetActiveMFAChallenge(ctx context.Context, id uuid.UUID) (store.MfaChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveMFAChallenge", ctx, id)
+	ret0, _ := ret[0].(store.MfaChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveMFAChallenge indicates an expected call of GetActiveMFAChallenge.
+func (mr *MockStorageMockRecorder) GetActiveMFAChallenge(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveMFAChallenge", reflect.TypeOf((*MockStorage)(nil).GetActiveMFAChallenge), ctx, id)
+}
+
+// GetLoginLock mocks base method.
+func (m *MockStorage) GetLoginLock(ctx context.Context, arg store.GetLoginLockParams) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginLock", ctx, arg)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginLock indicates an expected call of GetLoginLock.
+func (mr *MockStorageMockRecorder) GetLoginLock(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginLock", reflect.TypeOf((*MockStorage)(nil).GetLoginLock), ctx, arg)
+}
+
+// GetPersonalAccessTokenByHash mocks base method.
+func (m *MockStorage) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (store.GetPersonalAccessTokenByHashRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPersonalAccessTokenByHash", ctx, tokenHash)
+	ret0, _ := ret[0].(store.GetPersonalAccessTokenByHashRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPersonalAccessTokenByHash indicates an expected call of GetPersonalAccessTokenByHash.
+func (mr *MockStorageMockRecorder) GetPersonalAccessTokenByHash(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersonalAccessTokenByHash", reflect.TypeOf((*MockStorage)(nil).GetPersonalAccessTokenByHash), ctx, tokenHash)
+}
+
+// GetProject mocks base method.
+func (m *MockStorage) GetProject(ctx context.Context, id int64) (store.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProject", ctx, id)
+	ret0, _ := ret[0].(store.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProject indicates an expected call of GetProject.
+func (mr *MockStorageMockRecorder) GetProject(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProject", reflect.TypeOf((*MockStorage)(nil).GetProject), ctx, id)
+}
+
+// GetProjectTaskCounts mocks base method.
+func (m *MockStorage) GetProjectTaskCounts(ctx context.Context, projectIds []int64) ([]store.GetProjectTaskCountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectTaskCounts", ctx, projectIds)
+	ret0, _ := ret[0].([]store.GetProjectTaskCountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectTaskCounts indicates an expected call of GetProjectTaskCounts.
+func (mr *MockStorageMockRecorder) GetProjectTaskCounts(ctx, projectIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectTaskCounts", reflect.TypeOf((*MockStorage)(nil).GetProjectTaskCounts), ctx, projectIds)
+}
+
+// GetSession mocks base method.
+func (m *MockStorage) GetSession(ctx context.Context, id uuid.UUID) (store.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", ctx, id)
+	ret0, _ := ret[0].(store.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockStorageMockRecorder) GetSession(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStorage)(nil).GetSession), ctx, id)
+}
+
+// GetSubtaskProgress mocks base method.
+func (m *MockStorage) GetSubtaskProgress(ctx context.Context, taskIds []string) ([]store.GetSubtaskProgressRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubtaskProgress", ctx, taskIds)
+	ret0, _ := ret[0].([]store.GetSubtaskProgressRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubtaskProgress indicates an expected call of GetSubtaskProgress.
+func (mr *MockStorageMockRecorder) GetSubtaskProgress(ctx, taskIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubtaskProgress", reflect.TypeOf((*MockStorage)(nil).GetSubtaskProgress), ctx, taskIds)
+}
+
+// GetTOTPCredential mocks base method.
+func (m *MockStorage) GetTOTPCredential(ctx context.Context, userID int64) (store.TotpCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTOTPCredential", ctx, userID)
+	ret0, _ := ret[0].(store.TotpCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTOTPCredential indicates an expected call of GetTOTPCredential.
+func (mr *MockStorageMockRecorder) GetTOTPCredential(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTOTPCredential", reflect.TypeOf((*MockStorage)(nil).GetTOTPCredential), ctx, userID)
+}
+
+// GetTag mocks base method.
+func (m *MockStorage) GetTag(ctx context.Context, id int64) (store.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTag", ctx, id)
+	ret0, _ := ret[0].(store.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTag indicates an expected call of GetTag.
+func (mr *MockStorageMockRecorder) GetTag(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTag", reflect.TypeOf((*MockStorage)(nil).GetTag), ctx, id)
+}
+
+// GetTaskByID mocks base method.
+func (m *MockStorage) GetTaskByID(ctx context.Context, id string) (store.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskByID", ctx, id)
+	ret0, _ := ret[0].(store.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskByID indicates an expected call of GetTaskByID.
+func (mr *MockStorageMockRecorder) GetTaskByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskByID", reflect.TypeOf((*MockStorage)(nil).GetTaskByID), ctx, id)
+}
+
+// GetTaskRecurrence mocks base method.
+func (m *MockStorage) GetTaskRecurrence(ctx context.Context, id int64) (store.TaskRecurrence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskRecurrence", ctx, id)
+	ret0, _ := ret[0].(store.TaskRecurrence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskRecurrence indicates an expected call of GetTaskRecurrence.
+func (mr *MockStorageMockRecorder) GetTaskRecurrence(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskRecurrence", reflect.TypeOf((*MockStorage)(nil).GetTaskRecurrence), ctx, id)
+}
+
+// GetTaskShare mocks base method.
+func (m *MockStorage) GetTaskShare(ctx context.Context, arg store.GetTaskShareParams) (store.TaskShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskShare", ctx, arg)
+	ret0, _ := ret[0].(store.TaskShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskShare indicates an expected call of GetTaskShare.
+func (mr *MockStorageMockRecorder) GetTaskShare(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskShare", reflect.TypeOf((*MockStorage)(nil).GetTaskShare), ctx, arg)
+}
+
+// GetTasks mocks base method.
+func (m *MockStorage) GetTasks(ctx context.Context, arg store.GetTasksParams) ([]store.GetTasksRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTasks", ctx, arg)
+	ret0, _ := ret[0].([]store.GetTasksRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTasks indicates an expected call of GetTasks.
+func (mr *MockStorageMockRecorder) GetTasks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasks", reflect.TypeOf((*MockStorage)(nil).GetTasks), ctx, arg)
+}
+
+// GetUser mocks base method.
+func (m *MockStorage) GetUser(ctx context.Context, username string) (store.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", ctx, username)
+	ret0, _ := ret[0].(store.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStorageMockRecorder) GetUser(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStorage)(nil).GetUser), ctx, username)
+}
+
+// GetUserByID mocks base method.
+func (m *MockStorage) GetUserByID(ctx context.Context, id int64) (store.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", ctx, id)
+	ret0, _ := ret[0].(store.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockStorageMockRecorder) GetUserByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockStorage)(nil).GetUserByID), ctx, id)
+}
+
+// GetUserByVerifiedEmail mocks base method.
+func (m *MockStorage) GetUserByVerifiedEmail(ctx context.Context, email pgtype.Text) (store.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByVerifiedEmail", ctx, email)
+	ret0, _ := ret[0].(store.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByVerifiedEmail indicates an expected call of GetUserByVerifiedEmail.
+func (mr *MockStorageMockRecorder) GetUserByVerifiedEmail(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByVerifiedEmail", reflect.TypeOf((*MockStorage)(nil).GetUserByVerifiedEmail), ctx, email)
+}
+
+// GetUserIdentity mocks base method.
+func (m *MockStorage) GetUserIdentity(ctx context.Context, arg store.GetUserIdentityParams) (store.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIdentity", ctx, arg)
+	ret0, _ := ret[0].(store.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserIdentity indicates an expected call of GetUserIdentity.
+func (mr *MockStorageMockRecorder) GetUserIdentity(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIdentity", reflect.TypeOf((*MockStorage)(nil).GetUserIdentity), ctx, arg)
+}
+
+// GetUserTagsByIDs mocks base method.
+func (m *MockStorage) GetUserTagsByIDs(ctx context.Context, arg store.GetUserTagsByIDsParams) ([]store.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserTagsByIDs", ctx, arg)
+	ret0, _ := ret[0].([]store.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTagsByIDs indicates an expected call of GetUserTagsByIDs.
+func (mr *MockStorageMockRecorder) GetUserTagsByIDs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTagsByIDs", reflect.TypeOf((*MockStorage)(nil).GetUserTagsByIDs), ctx, arg)
+}
+
+// GetWorkspace mocks base method.
+func (m *MockStorage) GetWorkspace(ctx context.Context, id int64) (store.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspace", ctx, id)
+	ret0, _ := ret[0].(store.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspace indicates an expected call of GetWorkspace.
+func (mr *MockStorageMockRecorder) GetWorkspace(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspace", reflect.TypeOf((*MockStorage)(nil).GetWorkspace), ctx, id)
+}
+
+// GetWorkspaceInvitationByHash mocks base method.
+func (m *MockStorage) GetWorkspaceInvitationByHash(ctx context.Context, tokenHash string) (store.WorkspaceInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceInvitationByHash", ctx, tokenHash)
+	ret0, _ := ret[0].(store.WorkspaceInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceInvitationByHash indicates an expected call of GetWorkspaceInvitationByHash.
+func (mr *MockStorageMockRecorder) GetWorkspaceInvitationByHash(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceInvitationByHash", reflect.TypeOf((*MockStorage)(nil).GetWorkspaceInvitationByHash), ctx, tokenHash)
+}
+
+// GetWorkspaceMember mocks base method.
+func (m *MockStorage) GetWorkspaceMember(ctx context.Context, arg store.GetWorkspaceMemberParams) (store.WorkspaceMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceMember", ctx, arg)
+	ret0, _ := ret[0].(store.WorkspaceMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceMember indicates an expected call of GetWorkspaceMember.
+func (mr *MockStorageMockRecorder) GetWorkspaceMember(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceMember", reflect.TypeOf((*MockStorage)(nil).GetWorkspaceMember), ctx, arg)
+}
+
+// Health mocks base method.
+func (m *MockStorage) Health() map[string]string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Health")
+	ret0, _ := ret[0].(map[string]string)
+	return ret0
+}
+
+// Health indicates an expected call of Health.
+func (mr *MockStorageMockRecorder) Health() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockStorage)(nil).Health))
+}
+
+// InvalidateUserPasswordResetTokens mocks base method.
+func (m *MockStorage) InvalidateUserPasswordResetTokens(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateUserPasswordResetTokens", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateUserPasswordResetTokens indicates an expected call of InvalidateUserPasswordResetTokens.
+func (mr *MockStorageMockRecorder) InvalidateUserPasswordResetTokens(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateUserPasswordResetTokens", reflect.TypeOf((*MockStorage)(nil).InvalidateUserPasswordResetTokens), ctx, userID)
+}
+
+// IsTokenRevoked mocks base method.
+func (m *MockStorage) IsTokenRevoked(ctx context.Context, arg store.IsTokenRevokedParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenRevoked", ctx, arg)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenRevoked indicates an expected call of IsTokenRevoked.
+func (mr *MockStorageMockRecorder) IsTokenRevoked(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenRevoked", reflect.TypeOf((*MockStorage)(nil).IsTokenRevoked), ctx, arg)
+}
+
+// ListOutboxMessages mocks base method.
+func (m *MockStorage) ListOutboxMessages(ctx context.Context, userID int64) ([]store.OutboxMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutboxMessages", ctx, userID)
+	ret0, _ := ret[0].([]store.OutboxMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOutboxMessages indicates an expected call of ListOutboxMessages.
+func (mr *MockStorageMockRecorder) ListOutboxMessages(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutboxMessages", reflect.TypeOf((*MockStorage)(nil).ListOutboxMessages), ctx, userID)
+}
+
+// ListPersonalAccessTokens mocks base method.
+func (m *MockStorage) ListPersonalAccessTokens(ctx context.Context, userID int64) ([]store.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPersonalAccessTokens", ctx, userID)
+	ret0, _ := ret[0].([]store.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPersonalAccessTokens indicates an expected call of ListPersonalAccessTokens.
+func (mr *MockStorageMockRecorder) ListPersonalAccessTokens(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPersonalAccessTokens", reflect.TypeOf((*MockStorage)(nil).ListPersonalAccessTokens), ctx, userID)
+}
+
+// ListProjects mocks base method.
+func (m *MockStorage) ListProjects(ctx context.Context, arg store.ListProjectsParams) ([]store.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProjects", ctx, arg)
+	ret0, _ := ret[0].([]store.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProjects indicates an expected call of ListProjects.
+func (mr *MockStorageMockRecorder) ListProjects(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProjects", reflect.TypeOf((*MockStorage)(nil).ListProjects), ctx, arg)
+}
+
+// ListSubtasks mocks base method.
+func (m *MockStorage) ListSubtasks(ctx context.Context, parentID pgtype.Text) ([]store.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubtasks", ctx, parentID)
+	ret0, _ := ret[0].([]store.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubtasks indicates an expected call of ListSubtasks.
+func (mr *MockStorageMockRecorder) ListSubtasks(ctx, parentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubtasks", reflect.TypeOf((*MockStorage)(nil).ListSubtasks), ctx, parentID)
+}
+
+// ListTags mocks base method.
+func (m *MockStorage) ListTags(ctx context.Context, userID int64) ([]store.ListTagsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTags", ctx, userID)
+	ret0, _ := ret[0].([]store.ListTagsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTags indicates an expected call of ListTags.
+func (mr *MockStorageMockRecorder) ListTags(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockStorage)(nil).ListTags), ctx, userID)
+}
+
+// ListTagsForTasks mocks base method.
+func (m *MockStorage) ListTagsForTasks(ctx context.Context, taskIds []string) ([]store.ListTagsForTasksRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTagsForTasks", ctx, taskIds)
+	ret0, _ := ret[0].([]store.ListTagsForTasksRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTagsForTasks indicates an expected call of ListTagsForTasks.
+func (mr *MockStorageMockRecorder) ListTagsForTasks(ctx, taskIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagsForTasks", reflect.TypeOf((*MockStorage)(nil).ListTagsForTasks), ctx, taskIds)
+}
+
+// ListTaskBlockers mocks base method.
+func (m *MockStorage) ListTaskBlockers(ctx context.Context, taskID string) ([]store.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaskBlockers", ctx, taskID)
+	ret0, _ := ret[0].([]store.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaskBlockers indicates an expected call of ListTaskBlockers.
+func (mr *MockStorageMockRecorder) ListTaskBlockers(ctx, taskID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskBlockers", reflect.TypeOf((*MockStorage)(nil).ListTaskBlockers), ctx, taskID)
+}
+
+// ListTaskReminders mocks base method.
+func (m *MockStorage) ListTaskReminders(ctx context.Context, taskID string) ([]store.TaskReminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaskReminders", ctx, taskID)
+	ret0, _ := ret[0].([]store.TaskReminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaskReminders indicates an expected call of ListTaskReminders.
+func (mr *MockStorageMockRecorder) ListTaskReminders(ctx, taskID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskReminders", reflect.TypeOf((*MockStorage)(nil).ListTaskReminders), ctx, taskID)
+}
+
+// ListTaskShares mocks base method.
+func (m *MockStorage) ListTaskShares(ctx context.Context, taskID string) ([]store.ListTaskSharesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaskShares", ctx, taskID)
+	ret0, _ := ret[0].([]store.ListTaskSharesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaskShares indicates an expected call of ListTaskShares.
+func (mr *MockStorageMockRecorder) ListTaskShares(ctx, taskID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskShares", reflect.TypeOf((*MockStorage)(nil).ListTaskShares), ctx, taskID)
+}
+
+// ListUserAuditEvents mocks base method.
+func (m *MockStorage) ListUserAuditEvents(ctx context.Context, arg store.ListUserAuditEventsParams) ([]store.ListUserAuditEventsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserAuditEvents", ctx, arg)
+	ret0, _ := ret[0].([]store.ListUserAuditEventsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserAuditEvents indicates an expected call of ListUserAuditEvents.
+func (mr *MockStorageMockRecorder) ListUserAuditEvents(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserAuditEvents", reflect.TypeOf((*MockStorage)(nil).ListUserAuditEvents), ctx, arg)
+}
+
+// ListUserNotifications mocks base method.
+func (m *MockStorage) ListUserNotifications(ctx context.Context, arg store.ListUserNotificationsParams) ([]store.ListUserNotificationsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserNotifications", ctx, arg)
+	ret0, _ := ret[0].([]store.ListUserNotificationsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserNotifications indicates an expected call of ListUserNotifications.
+func (mr *MockStorageMockRecorder) ListUserNotifications(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserNotifications", reflect.TypeOf((*MockStorage)(nil).ListUserNotifications), ctx, arg)
+}
+
+// ListUserWorkspaces mocks base method.
+func (m *MockStorage) ListUserWorkspaces(ctx context.Context, userID int64) ([]store.ListUserWorkspacesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserWorkspaces", ctx, userID)
+	ret0, _ := ret[0].([]store.ListUserWorkspacesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserWorkspaces indicates an expected call of ListUserWorkspaces.
+func (mr *MockStorageMockRecorder) ListUserWorkspaces(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserWorkspaces", reflect.TypeOf((*MockStorage)(nil).ListUserWorkspaces), ctx, userID)
+}
+
+// ListWorkspaceInvitations mocks base method.
+func (m *MockStorage) ListWorkspaceInvitations(ctx context.Context, workspaceID int64) ([]store.WorkspaceInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkspaceInvitations", ctx, workspaceID)
+	ret0, _ := ret[0].([]store.WorkspaceInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkspaceInvitations indicates an expected call of ListWorkspaceInvitations.
+func (mr *MockStorageMockRecorder) ListWorkspaceInvitations(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkspaceInvitations", reflect.TypeOf((*MockStorage)(nil).ListWorkspaceInvitations), ctx, workspaceID)
+}
+
+// ListWorkspaceMembers mocks base method.
+func (m *MockStorage) ListWorkspaceMembers(ctx context.Context, workspaceID int64) ([]store.ListWorkspaceMembersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkspaceMembers", ctx, workspaceID)
+	ret0, _ := ret[0].([]store.ListWorkspaceMembersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkspaceMembers indicates an expected call of ListWorkspaceMembers.
+func (mr *MockStorageMockRecorder) ListWorkspaceMembers(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkspaceMembers", reflect.TypeOf((*MockStorage)(nil).ListWorkspaceMembers), ctx, workspaceID)
+}
+
+// LockLoginAttempts mocks base method.
+func (m *MockStorage) LockLoginAttempts(ctx context.Context, arg store.LockLoginAttemptsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockLoginAttempts", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockLoginAttempts indicates an expected call of LockLoginAttempts.
+func (mr *MockStorageMockRecorder) LockLoginAttempts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockLoginAttempts", reflect.TypeOf((*MockStorage)(nil).LockLoginAttempts), ctx, arg)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockStorage) MarkNotificationRead(ctx context.Context, arg store.MarkNotificationReadParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockStorageMockRecorder) MarkNotificationRead(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockStorage)(nil).MarkNotificationRead), ctx, arg)
+}
+
+// MarkReminderSent mocks base method.
+func (m *MockStorage) MarkReminderSent(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkReminderSent", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkReminderSent indicates an expected call of MarkReminderSent.
+func (mr *MockStorageMockRecorder) MarkReminderSent(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkReminderSent", reflect.TypeOf((*MockStorage)(nil).MarkReminderSent), ctx, id)
+}
+
+// MergeTag mocks base method.
+func (m *MockStorage) MergeTag(ctx context.Context, arg store.MergeTagParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeTag", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeTag indicates an expected call of MergeTag.
+func (mr *MockStorageMockRecorder) MergeTag(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeTag", reflect.TypeOf((*MockStorage)(nil).MergeTag), ctx, arg)
+}
+
+// MoveTask mocks base method.
+func (m *MockStorage) MoveTask(ctx context.Context, arg store.MoveTaskParams) (store.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveTask", ctx, arg)
 	ret0, _ := ret[0].(store.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateTask indicates an expected call of CreateTask.
-func (mr *MockStorageMockRecorder) CreateTask(ctx, arg any) *gomock.Call {
+// MoveTask indicates an expected call of MoveTask.
+func (mr *MockStorageMockRecorder) MoveTask(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTask", reflect.TypeOf((*MockStorage)(nil).CreateTask), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveTask", reflect.TypeOf((*MockStorage)(nil).MoveTask), ctx, arg)
 }
 
-// CreateUser mocks base method.
-func (m *MockStorage) CreateUser(ctx context.Context, arg store.CreateUserParams) (store.User, error) {
+// RecordLoginFailure mocks base method.
+func (m *MockStorage) RecordLoginFailure(ctx context.Context, arg store.RecordLoginFailureParams) (int32, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", ctx, arg)
-	ret0, _ := ret[0].(store.User)
+	ret := m.ctrl.Call(m, "RecordLoginFailure", ctx, arg)
+	ret0, _ := ret[0].(int32)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockStorageMockRecorder) CreateUser(ctx, arg any) *gomock.Call {
+// RecordLoginFailure indicates an expected call of RecordLoginFailure.
+func (mr *MockStorageMockRecorder) RecordLoginFailure(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStorage)(nil).CreateUser), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLoginFailure", reflect.TypeOf((*MockStorage)(nil).RecordLoginFailure), ctx, arg)
 }
 
-// DeleteTask mocks base method.
-func (m *MockStorage) DeleteTask(ctx context.Context, id string) error {
+// RecordMFAChallengeFailure mocks base method.
+func (m *MockStorage) RecordMFAChallengeFailure(ctx context.Context, arg store.RecordMFAChallengeFailureParams) (int32, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTask", ctx, id)
+	ret := m.ctrl.Call(m, "RecordMFAChallengeFailure", ctx, arg)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordMFAChallengeFailure indicates an expected call of RecordMFAChallengeFailure.
+func (mr *MockStorageMockRecorder) RecordMFAChallengeFailure(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMFAChallengeFailure", reflect.TypeOf((*MockStorage)(nil).RecordMFAChallengeFailure), ctx, arg)
+}
+
+// RemoveTaskDependency mocks base method.
+func (m *MockStorage) RemoveTaskDependency(ctx context.Context, arg store.RemoveTaskDependencyParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTaskDependency", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveTaskDependency indicates an expected call of RemoveTaskDependency.
+func (mr *MockStorageMockRecorder) RemoveTaskDependency(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTaskDependency", reflect.TypeOf((*MockStorage)(nil).RemoveTaskDependency), ctx, arg)
+}
+
+// RemoveWorkspaceMember mocks base method.
+func (m *MockStorage) RemoveWorkspaceMember(ctx context.Context, arg store.RemoveWorkspaceMemberParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveWorkspaceMember", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveWorkspaceMember indicates an expected call of RemoveWorkspaceMember.
+func (mr *MockStorageMockRecorder) RemoveWorkspaceMember(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveWorkspaceMember", reflect.TypeOf((*MockStorage)(nil).RemoveWorkspaceMember), ctx, arg)
+}
+
+// RevokePersonalAccessToken mocks base method.
+func (m *MockStorage) RevokePersonalAccessToken(ctx context.Context, arg store.RevokePersonalAccessTokenParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokePersonalAccessToken", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokePersonalAccessToken indicates an expected call of RevokePersonalAccessToken.
+func (mr *MockStorageMockRecorder) RevokePersonalAccessToken(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokePersonalAccessToken", reflect.TypeOf((*MockStorage)(nil).RevokePersonalAccessToken), ctx, arg)
+}
+
+// RevokeToken mocks base method.
+func (m *MockStorage) RevokeToken(ctx context.Context, arg store.RevokeTokenParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", ctx, arg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteTask indicates an expected call of DeleteTask.
-func (mr *MockStorageMockRecorder) DeleteTask(ctx, id any) *gomock.Call {
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockStorageMockRecorder) RevokeToken(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockStorage)(nil).DeleteTask), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockStorage)(nil).RevokeToken), ctx, arg)
 }
 
-// GetTaskByID mocks base method.
-func (m *MockStorage) GetTaskByID(ctx context.Context, id string) (store.Task, error) {
+// RevokeUserTokens mocks base method.
+func (m *MockStorage) RevokeUserTokens(ctx context.Context, arg store.RevokeUserTokensParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTaskByID", ctx, id)
+	ret := m.ctrl.Call(m, "RevokeUserTokens", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserTokens indicates an expected call of RevokeUserTokens.
+func (mr *MockStorageMockRecorder) RevokeUserTokens(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserTokens", reflect.TypeOf((*MockStorage)(nil).RevokeUserTokens), ctx, arg)
+}
+
+// SetTaskAssignee mocks base method.
+func (m *MockStorage) SetTaskAssignee(ctx context.Context, arg store.SetTaskAssigneeParams) (store.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTaskAssignee", ctx, arg)
 	ret0, _ := ret[0].(store.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTaskByID indicates an expected call of GetTaskByID.
-func (mr *MockStorageMockRecorder) GetTaskByID(ctx, id any) *gomock.Call {
+// SetTaskAssignee indicates an expected call of SetTaskAssignee.
+func (mr *MockStorageMockRecorder) SetTaskAssignee(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskByID", reflect.TypeOf((*MockStorage)(nil).GetTaskByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTaskAssignee", reflect.TypeOf((*MockStorage)(nil).SetTaskAssignee), ctx, arg)
 }
 
-// GetTasks mocks base method.
-func (m *MockStorage) GetTasks(ctx context.Context, arg store.GetTasksParams) ([]store.GetTasksRow, error) {
+// SetTaskProject mocks base method.
+func (m *MockStorage) SetTaskProject(ctx context.Context, arg store.SetTaskProjectParams) (store.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTasks", ctx, arg)
-	ret0, _ := ret[0].([]store.GetTasksRow)
+	ret := m.ctrl.Call(m, "SetTaskProject", ctx, arg)
+	ret0, _ := ret[0].(store.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTasks indicates an expected call of GetTasks.
-func (mr *MockStorageMockRecorder) GetTasks(ctx, arg any) *gomock.Call {
+// SetTaskProject indicates an expected call of SetTaskProject.
+func (mr *MockStorageMockRecorder) SetTaskProject(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasks", reflect.TypeOf((*MockStorage)(nil).GetTasks), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTaskProject", reflect.TypeOf((*MockStorage)(nil).SetTaskProject), ctx, arg)
 }
 
-// GetUser mocks base method.
-func (m *MockStorage) GetUser(ctx context.Context, username string) (store.User, error) {
+// SetTaskTags mocks base method.
+func (m *MockStorage) SetTaskTags(ctx context.Context, arg store.SetTaskTagsParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", ctx, username)
-	ret0, _ := ret[0].(store.User)
+	ret := m.ctrl.Call(m, "SetTaskTags", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTaskTags indicates an expected call of SetTaskTags.
+func (mr *MockStorageMockRecorder) SetTaskTags(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTaskTags", reflect.TypeOf((*MockStorage)(nil).SetTaskTags), ctx, arg)
+}
+
+// ShareTask mocks base method.
+func (m *MockStorage) ShareTask(ctx context.Context, arg store.ShareTaskParams) (store.TaskShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShareTask", ctx, arg)
+	ret0, _ := ret[0].(store.TaskShare)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockStorageMockRecorder) GetUser(ctx, username any) *gomock.Call {
+// ShareTask indicates an expected call of ShareTask.
+func (mr *MockStorageMockRecorder) ShareTask(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStorage)(nil).GetUser), ctx, username)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShareTask", reflect.TypeOf((*MockStorage)(nil).ShareTask), ctx, arg)
 }
 
-// Health mocks base method.
-func (m *MockStorage) Health() map[string]string {
+// SkipTaskOccurrence mocks base method.
+func (m *MockStorage) SkipTaskOccurrence(ctx context.Context, arg store.SkipTaskOccurrenceParams) (store.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Health")
-	ret0, _ := ret[0].(map[string]string)
+	ret := m.ctrl.Call(m, "SkipTaskOccurrence", ctx, arg)
+	ret0, _ := ret[0].(store.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SkipTaskOccurrence indicates an expected call of SkipTaskOccurrence.
+func (mr *MockStorageMockRecorder) SkipTaskOccurrence(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SkipTaskOccurrence", reflect.TypeOf((*MockStorage)(nil).SkipTaskOccurrence), ctx, arg)
+}
+
+// SplitTaskRecurrence mocks base method.
+func (m *MockStorage) SplitTaskRecurrence(ctx context.Context, arg store.SplitTaskRecurrenceParams) (store.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SplitTaskRecurrence", ctx, arg)
+	ret0, _ := ret[0].(store.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SplitTaskRecurrence indicates an expected call of SplitTaskRecurrence.
+func (mr *MockStorageMockRecorder) SplitTaskRecurrence(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitTaskRecurrence", reflect.TypeOf((*MockStorage)(nil).SplitTaskRecurrence), ctx, arg)
+}
+
+// StopTaskRecurrence mocks base method.
+func (m *MockStorage) StopTaskRecurrence(ctx context.Context, id string) (store.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopTaskRecurrence", ctx, id)
+	ret0, _ := ret[0].(store.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StopTaskRecurrence indicates an expected call of StopTaskRecurrence.
+func (mr *MockStorageMockRecorder) StopTaskRecurrence(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopTaskRecurrence", reflect.TypeOf((*MockStorage)(nil).StopTaskRecurrence), ctx, id)
+}
+
+// TouchPersonalAccessToken mocks base method.
+func (m *MockStorage) TouchPersonalAccessToken(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchPersonalAccessToken", ctx, id)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Health indicates an expected call of Health.
-func (mr *MockStorageMockRecorder) Health() *gomock.Call {
+// TouchPersonalAccessToken indicates an expected call of TouchPersonalAccessToken.
+func (mr *MockStorageMockRecorder) TouchPersonalAccessToken(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockStorage)(nil).Health))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchPersonalAccessToken", reflect.TypeOf((*MockStorage)(nil).TouchPersonalAccessToken), ctx, id)
+}
+
+// UnshareTask mocks base method.
+func (m *MockStorage) UnshareTask(ctx context.Context, arg store.UnshareTaskParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnshareTask", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnshareTask indicates an expected call of UnshareTask.
+func (mr *MockStorageMockRecorder) UnshareTask(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnshareTask", reflect.TypeOf((*MockStorage)(nil).UnshareTask), ctx, arg)
+}
+
+// UpdateProject mocks base method.
+func (m *MockStorage) UpdateProject(ctx context.Context, arg store.UpdateProjectParams) (store.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProject", ctx, arg)
+	ret0, _ := ret[0].(store.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProject indicates an expected call of UpdateProject.
+func (mr *MockStorageMockRecorder) UpdateProject(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProject", reflect.TypeOf((*MockStorage)(nil).UpdateProject), ctx, arg)
+}
+
+// UpdateTag mocks base method.
+func (m *MockStorage) UpdateTag(ctx context.Context, arg store.UpdateTagParams) (store.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTag", ctx, arg)
+	ret0, _ := ret[0].(store.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTag indicates an expected call of UpdateTag.
+func (mr *MockStorageMockRecorder) UpdateTag(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTag", reflect.TypeOf((*MockStorage)(nil).UpdateTag), ctx, arg)
 }
 
 // UpdateTask mocks base method.
@@ -158,3 +1668,153 @@ func (mr *MockStorageMockRecorder) UpdateTask(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockStorage)(nil).UpdateTask), ctx, arg)
 }
+
+// UpdateUserEmail mocks base method.
+func (m *MockStorage) UpdateUserEmail(ctx context.Context, arg store.UpdateUserEmailParams) (store.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserEmail", ctx, arg)
+	ret0, _ := ret[0].(store.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserEmail indicates an expected call of UpdateUserEmail.
+func (mr *MockStorageMockRecorder) UpdateUserEmail(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserEmail", reflect.TypeOf((*MockStorage)(nil).UpdateUserEmail), ctx, arg)
+}
+
+// UpdateUserPassword mocks base method.
+func (m *MockStorage) UpdateUserPassword(ctx context.Context, arg store.UpdateUserPasswordParams) (store.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserPassword", ctx, arg)
+	ret0, _ := ret[0].(store.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserPassword indicates an expected call of UpdateUserPassword.
+func (mr *MockStorageMockRecorder) UpdateUserPassword(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPassword", reflect.TypeOf((*MockStorage)(nil).UpdateUserPassword), ctx, arg)
+}
+
+// UpdateUserProfile mocks base method.
+func (m *MockStorage) UpdateUserProfile(ctx context.Context, arg store.UpdateUserProfileParams) (store.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserProfile", ctx, arg)
+	ret0, _ := ret[0].(store.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserProfile indicates an expected call of UpdateUserProfile.
+func (mr *MockStorageMockRecorder) UpdateUserProfile(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserProfile", reflect.TypeOf((*MockStorage)(nil).UpdateUserProfile), ctx, arg)
+}
+
+// UpdateUserRole mocks base method.
+func (m *MockStorage) UpdateUserRole(ctx context.Context, arg store.UpdateUserRoleParams) (store.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", ctx, arg)
+	ret0, _ := ret[0].(store.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockStorageMockRecorder) UpdateUserRole(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockStorage)(nil).UpdateUserRole), ctx, arg)
+}
+
+// UpdateWorkspace mocks base method.
+func (m *MockStorage) UpdateWorkspace(ctx context.Context, arg store.UpdateWorkspaceParams) (store.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspace", ctx, arg)
+	ret0, _ := ret[0].(store.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspace indicates an expected call of UpdateWorkspace.
+func (mr *MockStorageMockRecorder) UpdateWorkspace(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspace", reflect.TypeOf((*MockStorage)(nil).UpdateWorkspace), ctx, arg)
+}
+
+// UpdateWorkspaceMemberRole mocks base method.
+func (m *MockStorage) UpdateWorkspaceMemberRole(ctx context.Context, arg store.UpdateWorkspaceMemberRoleParams) (store.WorkspaceMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceMemberRole", ctx, arg)
+	ret0, _ := ret[0].(store.WorkspaceMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspaceMemberRole indicates an expected call of UpdateWorkspaceMemberRole.
+func (mr *MockStorageMockRecorder) UpdateWorkspaceMemberRole(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceMemberRole", reflect.TypeOf((*MockStorage)(nil).UpdateWorkspaceMemberRole), ctx, arg)
+}
+
+// UpsertPendingTOTPCredential mocks base method.
+func (m *MockStorage) UpsertPendingTOTPCredential(ctx context.Context, arg store.UpsertPendingTOTPCredentialParams) (store.TotpCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertPendingTOTPCredential", ctx, arg)
+	ret0, _ := ret[0].(store.TotpCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertPendingTOTPCredential indicates an expected call of UpsertPendingTOTPCredential.
+func (mr *MockStorageMockRecorder) UpsertPendingTOTPCredential(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertPendingTOTPCredential", reflect.TypeOf((*MockStorage)(nil).UpsertPendingTOTPCredential), ctx, arg)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockStorage) UseRecoveryCode(ctx context.Context, arg store.UseRecoveryCodeParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockStorageMockRecorder) UseRecoveryCode(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockStorage)(nil).UseRecoveryCode), ctx, arg)
+}
+
+// UseTOTPStep mocks base method.
+func (m *MockStorage) UseTOTPStep(ctx context.Context, arg store.UseTOTPStepParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseTOTPStep", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseTOTPStep indicates an expected call of UseTOTPStep.
+func (mr *MockStorageMockRecorder) UseTOTPStep(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTOTPStep", reflect.TypeOf((*MockStorage)(nil).UseTOTPStep), ctx, arg)
+}
+
+// VerifyUserEmail mocks base method.
+func (m *MockStorage) VerifyUserEmail(ctx context.Context, arg store.VerifyUserEmailParams) (store.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyUserEmail", ctx, arg)
+	ret0, _ := ret[0].(store.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyUserEmail indicates an expected call of VerifyUserEmail.
+func (mr *MockStorageMockRecorder) VerifyUserEmail(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyUserEmail", reflect.TypeOf((*MockStorage)(nil).VerifyUserEmail), ctx, arg)
+}
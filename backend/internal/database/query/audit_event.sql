@@ -0,0 +1,20 @@
+-- name: CreateAuditEvent :one
+INSERT INTO audit_events (
+  user_id,
+  username,
+  event_type,
+  detail,
+  client_ip,
+  user_agent
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: ListUserAuditEvents :many
+SELECT
+  *,
+  COUNT(*) OVER() AS total
+FROM audit_events
+WHERE user_id = sqlc.arg('user_id')::bigint
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
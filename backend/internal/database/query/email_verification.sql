@@ -0,0 +1,17 @@
+-- name: CreateEmailVerification :one
+INSERT INTO email_verifications (
+  id,
+  user_id,
+  email,
+  expire_at
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ConsumeEmailVerification :one
+UPDATE email_verifications
+SET used_at = now()
+WHERE id = $1
+  AND used_at IS NULL
+  AND expire_at > now()
+RETURNING *;
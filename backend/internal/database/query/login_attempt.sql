@@ -0,0 +1,38 @@
+-- name: GetLoginLock :one
+SELECT locked_until::timestamptz FROM login_attempts
+WHERE (
+  (scope = 'username' AND key = sqlc.arg('username'))
+  OR (scope = 'ip' AND key = sqlc.arg('client_ip'))
+) AND locked_until > now()
+ORDER BY locked_until DESC
+LIMIT 1;
+
+-- name: RecordLoginFailure :one
+WITH pruned AS (
+  DELETE FROM login_attempts
+  WHERE last_failed_at < sqlc.arg('window_start')
+    AND (locked_until IS NULL OR locked_until < now())
+    AND NOT (scope = sqlc.arg('scope') AND key = sqlc.arg('key'))
+)
+INSERT INTO login_attempts (
+  scope,
+  key,
+  failed_count
+) VALUES (
+  sqlc.arg('scope'), sqlc.arg('key'), 1
+) ON CONFLICT (scope, key) DO UPDATE
+SET failed_count = CASE
+    WHEN login_attempts.last_failed_at < sqlc.arg('window_start') THEN 1
+    ELSE login_attempts.failed_count + 1
+  END,
+  last_failed_at = now()
+RETURNING failed_count;
+
+-- name: LockLoginAttempts :exec
+UPDATE login_attempts
+SET locked_until = sqlc.arg('locked_until')::timestamptz
+WHERE scope = sqlc.arg('scope') AND key = sqlc.arg('key');
+
+-- name: ClearLoginAttempts :exec
+DELETE FROM login_attempts
+WHERE scope = $1 AND key = $2;
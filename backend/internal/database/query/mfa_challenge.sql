@@ -0,0 +1,33 @@
+-- name: CreateMFAChallenge :one
+INSERT INTO mfa_challenges (
+  id,
+  user_id,
+  expire_at
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: GetActiveMFAChallenge :one
+SELECT * FROM mfa_challenges
+WHERE id = $1
+  AND used_at IS NULL
+  AND expire_at > now()
+LIMIT 1;
+
+-- name: RecordMFAChallengeFailure :one
+-- The challenge is used up once the failures reach max_attempts.
+UPDATE mfa_challenges
+SET failed_attempts = failed_attempts + 1,
+    used_at = CASE
+      WHEN failed_attempts + 1 >= sqlc.arg('max_attempts')::int THEN now()
+      ELSE used_at
+    END
+WHERE id = sqlc.arg('id')
+RETURNING failed_attempts;
+
+-- name: ConsumeMFAChallenge :execrows
+UPDATE mfa_challenges
+SET used_at = now()
+WHERE id = $1
+  AND used_at IS NULL
+  AND expire_at > now();
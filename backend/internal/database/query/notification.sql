@@ -0,0 +1,27 @@
+-- name: CreateNotification :exec
+-- CreateNotification does nothing when a notification with the same
+-- dedup_key already exists.
+INSERT INTO notifications (
+  user_id,
+  kind,
+  subject,
+  body,
+  dedup_key
+) VALUES (
+  $1, $2, $3, $4, $5
+) ON CONFLICT (dedup_key) DO NOTHING;
+
+-- name: ListUserNotifications :many
+SELECT
+  *,
+  COUNT(*) OVER() AS total
+FROM notifications
+WHERE user_id = sqlc.arg('user_id')::bigint
+  AND (NOT sqlc.arg('unread')::bool OR read_at IS NULL)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications
+SET read_at = COALESCE(read_at, now())
+WHERE id = $1 AND user_id = $2;
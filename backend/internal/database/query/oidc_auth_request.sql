@@ -0,0 +1,19 @@
+-- name: CreateOIDCAuthRequest :one
+INSERT INTO oidc_auth_requests (
+  state,
+  nonce,
+  code_verifier,
+  use_cookies,
+  expire_at
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ConsumeOIDCAuthRequest :one
+DELETE FROM oidc_auth_requests
+WHERE state = $1 AND expire_at > now()
+RETURNING *;
+
+-- name: DeleteExpiredOIDCAuthRequests :exec
+DELETE FROM oidc_auth_requests
+WHERE expire_at <= now();
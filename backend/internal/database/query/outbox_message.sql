@@ -0,0 +1,14 @@
+-- name: CreateOutboxMessage :one
+INSERT INTO outbox_messages (
+  user_id,
+  kind,
+  subject,
+  body
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListOutboxMessages :many
+SELECT * FROM outbox_messages
+WHERE user_id = $1
+ORDER BY id DESC;
@@ -0,0 +1,50 @@
+-- name: CreatePersonalAccessToken :one
+INSERT INTO personal_access_tokens (
+  id,
+  user_id,
+  name,
+  token_hash,
+  scopes,
+  expire_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: ListPersonalAccessTokens :many
+SELECT * FROM personal_access_tokens
+WHERE 
+  user_id = $1
+  AND revoked_at IS NULL
+ORDER BY created_at DESC;
+
+-- name: GetPersonalAccessTokenByHash :one
+SELECT
+  personal_access_tokens.id,
+  personal_access_tokens.user_id,
+  personal_access_tokens.scopes,
+  personal_access_tokens.expire_at,
+  personal_access_tokens.revoked_at,
+  personal_access_tokens.created_at,
+  users.username,
+  users.role
+FROM personal_access_tokens
+JOIN users ON users.id = personal_access_tokens.user_id
+WHERE token_hash = $1 LIMIT 1;
+
+-- name: TouchPersonalAccessToken :exec
+UPDATE personal_access_tokens
+SET last_used_at = now()
+WHERE 
+  id = $1
+  AND (
+    last_used_at IS NULL
+    OR last_used_at < now() - interval '1 minute'
+  );
+
+-- name: RevokePersonalAccessToken :execrows
+UPDATE personal_access_tokens
+SET revoked_at = now()
+WHERE 
+  id = $1
+  AND user_id = $2
+  AND revoked_at IS NULL;
@@ -0,0 +1,57 @@
+-- name: CreateProject :one
+INSERT INTO projects (
+  user_id,
+  name,
+  description,
+  color
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetProject :one
+SELECT * FROM projects
+WHERE id = $1 LIMIT 1;
+
+-- name: ListProjects :many
+-- ListProjects leaves out archived projects unless include_archived is set.
+SELECT * FROM projects
+WHERE
+  user_id = sqlc.arg('user_id')
+  AND (sqlc.arg('include_archived')::bool OR NOT archived)
+ORDER BY archived ASC, lower(name) ASC;
+
+-- name: UpdateProject :one
+UPDATE projects
+SET
+  name = COALESCE(sqlc.narg(name), name),
+  description = COALESCE(sqlc.narg(description), description),
+  color = COALESCE(sqlc.narg(color), color),
+  archived = COALESCE(sqlc.narg(archived), archived)
+WHERE
+  id = $1
+RETURNING *;
+
+-- name: DeleteProject :exec
+DELETE FROM projects
+WHERE id = $1;
+
+-- name: GetProjectTaskCounts :many
+-- GetProjectTaskCounts counts the tasks of each of the given projects.
+-- Overdue tasks are open tasks past their deadline, so they are counted as
+-- open too. Projects without tasks have no row.
+SELECT
+  project_id::bigint AS project_id,
+  COUNT(*) FILTER (WHERE NOT completed) AS open,
+  COUNT(*) FILTER (WHERE NOT completed AND deadline < now()) AS overdue,
+  COUNT(*) FILTER (WHERE completed) AS completed
+FROM tasks
+WHERE project_id = ANY(sqlc.arg('project_ids')::bigint[])
+GROUP BY project_id;
+
+-- name: SetTaskProject :one
+-- SetTaskProject moves a task into a project, or out of any project when
+-- project_id is NULL.
+UPDATE tasks
+SET project_id = sqlc.narg('project_id')
+WHERE id = sqlc.arg('id')
+RETURNING *;
@@ -0,0 +1,40 @@
+-- name: RevokeToken :exec
+WITH pruned AS (
+  DELETE FROM revoked_tokens
+  WHERE expire_at < now()
+)
+INSERT INTO revoked_tokens (
+  id,
+  user_id,
+  expire_at
+) VALUES (
+  $1, $2, $3
+) ON CONFLICT (id) DO NOTHING;
+
+-- name: RevokeUserTokens :exec
+INSERT INTO user_token_revocations (
+  user_id,
+  revoked_before
+) VALUES (
+  $1, $2
+) ON CONFLICT (user_id) DO UPDATE
+SET revoked_before = EXCLUDED.revoked_before;
+
+-- name: IsTokenRevoked :one
+SELECT (
+  EXISTS (
+    SELECT 1 FROM revoked_tokens rt
+    WHERE rt.id = sqlc.arg('id')
+  )
+  OR EXISTS (
+    SELECT 1 FROM user_token_revocations utr
+    WHERE utr.user_id = sqlc.arg('user_id')
+      AND utr.revoked_before >= sqlc.arg('issued_at')
+  )
+  -- Revocations are deleted along with the user, so the tokens of a
+  -- deleted user are rejected explicitly.
+  OR NOT EXISTS (
+    SELECT 1 FROM users u
+    WHERE u.id = sqlc.arg('user_id')
+  )
+)::bool AS revoked;
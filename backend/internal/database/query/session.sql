@@ -0,0 +1,31 @@
+-- name: CreateSession :one
+INSERT INTO sessions (
+  id,
+  user_id,
+  refresh_token,
+  user_agent,
+  client_ip,
+  is_blocked,
+  expire_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetSession :one
+SELECT * FROM sessions
+WHERE id = $1 LIMIT 1;
+
+-- name: BlockSession :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE id = $1;
+
+-- name: BlockUserSessions :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE user_id = $1;
+
+-- name: BlockOtherUserSessions :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE user_id = $1 AND id <> sqlc.arg('current_session_id');
@@ -0,0 +1,76 @@
+-- name: CreateTag :one
+INSERT INTO tags (
+  user_id,
+  name,
+  color
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: GetTag :one
+SELECT * FROM tags
+WHERE id = $1 LIMIT 1;
+
+-- name: ListTags :many
+SELECT
+  tags.*,
+  COUNT(task_tags.task_id) AS task_count
+FROM tags
+LEFT JOIN task_tags ON task_tags.tag_id = tags.id
+WHERE tags.user_id = $1
+GROUP BY tags.id
+ORDER BY lower(tags.name) ASC;
+
+-- name: GetUserTagsByIDs :many
+SELECT * FROM tags
+WHERE
+  user_id = sqlc.arg('user_id')
+  AND id = ANY(sqlc.arg('ids')::bigint[]);
+
+-- name: UpdateTag :one
+UPDATE tags
+SET
+  name = COALESCE(sqlc.narg(name), name),
+  color = COALESCE(sqlc.narg(color), color)
+WHERE
+  id = $1
+RETURNING *;
+
+-- name: DeleteTag :exec
+DELETE FROM tags
+WHERE id = $1;
+
+-- name: MergeTag :execrows
+-- MergeTag moves the tasks of the source tag to the target tag and deletes the
+-- source tag in a single statement. Tasks that already have both tags keep a
+-- single assignment.
+WITH moved AS (
+  INSERT INTO task_tags (task_id, tag_id)
+  SELECT task_id, sqlc.arg('target_id')::bigint
+  FROM task_tags
+  WHERE tag_id = sqlc.arg('source_id')::bigint
+  ON CONFLICT DO NOTHING
+)
+DELETE FROM tags
+WHERE id = sqlc.arg('source_id')::bigint;
+
+-- name: SetTaskTags :exec
+-- SetTaskTags replaces the tags of a task with the given ones.
+WITH removed AS (
+  DELETE FROM task_tags
+  WHERE
+    task_tags.task_id = sqlc.arg('task_id')
+    AND task_tags.tag_id <> ALL(sqlc.arg('tag_ids')::bigint[])
+)
+INSERT INTO task_tags (task_id, tag_id)
+SELECT sqlc.arg('task_id'), unnest(sqlc.arg('tag_ids')::bigint[])
+ON CONFLICT DO NOTHING;
+
+-- name: ListTagsForTasks :many
+SELECT
+  task_tags.task_id,
+  tags.*
+FROM task_tags
+JOIN tags ON tags.id = task_tags.tag_id
+WHERE task_tags.task_id = ANY(sqlc.arg('task_ids')::varchar[])
+ORDER BY lower(tags.name) ASC;
@@ -4,18 +4,60 @@ INSERT INTO tasks (
   creator_id,
   title,
   description,
-  deadline
+  deadline,
+  priority,
+  parent_id,
+  project_id,
+  workspace_id,
+  assignee_id
 ) VALUES (
-  $1, $2, $3, $4, $5
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
 ) RETURNING *;
 
 -- name: GetTasks :many
 SELECT 
   *,
+  EXISTS (
+    SELECT 1 FROM task_dependencies
+    JOIN tasks AS blockers ON blockers.id = task_dependencies.blocked_by_id
+    WHERE
+      task_dependencies.task_id = tasks.id
+      AND NOT blockers.completed
+  ) AS blocked,
   COUNT(*) OVER() AS total
 FROM tasks
 WHERE 
-  creator_id = $1
+  -- Without a workspace, the personal tasks the user can see: those they
+  -- created and those shared with or assigned to them. In a workspace, which
+  -- the user must be a member of, all of its tasks, the ones created by
+  -- others counting as shared. shared is NULL for both kinds of tasks, false
+  -- for the tasks the user created and true for the others.
+  (
+    CASE
+      WHEN sqlc.narg('workspace_id')::bigint IS NULL THEN tasks.workspace_id IS NULL
+      ELSE tasks.workspace_id = sqlc.narg('workspace_id')::bigint
+    END
+  )
+  AND (
+    (
+      sqlc.narg('shared')::bool IS NOT TRUE
+      AND tasks.creator_id = sqlc.arg('user_id')::bigint
+    )
+    OR (
+      sqlc.narg('shared')::bool IS NOT FALSE
+      AND tasks.creator_id <> sqlc.arg('user_id')::bigint
+      AND (
+        tasks.workspace_id IS NOT NULL
+        OR tasks.assignee_id = sqlc.arg('user_id')::bigint
+        OR EXISTS (
+          SELECT 1 FROM task_shares
+          WHERE
+            task_shares.task_id = tasks.id
+            AND task_shares.user_id = sqlc.arg('user_id')::bigint
+        )
+      )
+    )
+  )
   AND (
     title ILIKE '%' || COALESCE(sqlc.arg('title'), '') || '%'
     OR 
@@ -33,24 +75,98 @@ WHERE
     sqlc.narg('completed')::bool IS NULL 
     OR completed = sqlc.narg('completed')::bool
   )
-  ORDER BY completed ASC, deadline ASC
-  LIMIT $2 OFFSET $3;
+  AND (
+    sqlc.narg('project_id')::bigint IS NULL
+    OR project_id = sqlc.narg('project_id')
+  )
+  AND (
+    sqlc.narg('assignee_id')::bigint IS NULL
+    OR assignee_id = sqlc.narg('assignee_id')
+  )
+  AND (
+    NOT sqlc.arg('unassigned')::bool
+    OR assignee_id IS NULL
+  )
+  AND (
+    sqlc.narg('priorities')::varchar[] IS NULL
+    OR priority = ANY(sqlc.narg('priorities')::varchar[])
+  )
+  AND (
+    sqlc.narg('tags')::varchar[] IS NULL
+    OR (
+      SELECT COUNT(DISTINCT lower(tags.name))
+      FROM task_tags
+      JOIN tags ON tags.id = task_tags.tag_id
+      WHERE
+        task_tags.task_id = tasks.id
+        AND lower(tags.name) = ANY(sqlc.narg('tags')::varchar[])
+    ) >= CASE
+      WHEN sqlc.arg('match_all_tags')::bool THEN cardinality(sqlc.narg('tags')::varchar[])
+      ELSE 1
+    END
+  )
+  ORDER BY
+    completed ASC,
+    CASE WHEN sqlc.arg('sort_by_priority')::bool THEN
+      CASE priority
+        WHEN 'urgent' THEN 4
+        WHEN 'high' THEN 3
+        WHEN 'medium' THEN 2
+        WHEN 'low' THEN 1
+        ELSE 0
+      END
+    END DESC,
+    deadline ASC
+  LIMIT sqlc.arg('limit')::int OFFSET sqlc.arg('offset')::int;
 
 -- name: GetTaskByID :one
 SELECT * FROM tasks
 WHERE id = $1 LIMIT 1;
 
+-- name: ListSubtasks :many
+SELECT * FROM tasks
+WHERE parent_id = $1
+ORDER BY completed ASC, deadline ASC;
+
+-- name: GetSubtaskProgress :many
+-- GetSubtaskProgress counts the direct subtasks of each of the given tasks.
+-- Tasks without subtasks have no row.
+SELECT
+  parent_id::varchar AS task_id,
+  COUNT(*) AS total,
+  COUNT(*) FILTER (WHERE completed) AS completed
+FROM tasks
+WHERE parent_id = ANY(sqlc.arg('task_ids')::varchar[])
+GROUP BY parent_id;
+
+-- name: MoveTask :one
+-- MoveTask makes a task a subtask of another one, or a top-level task when
+-- parent_id is NULL.
+UPDATE tasks
+SET parent_id = sqlc.narg('parent_id')
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
 -- name: UpdateTask :one
 UPDATE tasks
 SET
   title = COALESCE(sqlc.narg(title), title),
   description = COALESCE(sqlc.narg(description), description),
   deadline = COALESCE(sqlc.narg(deadline), deadline),
-  completed = COALESCE(sqlc.narg(completed), completed)
+  completed = COALESCE(sqlc.narg(completed), completed),
+  priority = COALESCE(sqlc.narg(priority), priority)
 WHERE
   id = $1
 RETURNING *;
 
 -- name: DeleteTask :exec
 DELETE FROM tasks
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: SetTaskAssignee :one
+-- SetTaskAssignee assigns a task to a user, or unassigns it when assignee_id
+-- is NULL.
+UPDATE tasks
+SET assignee_id = sqlc.narg('assignee_id')
+WHERE id = sqlc.arg('id')
+RETURNING *;
@@ -0,0 +1,27 @@
+-- name: AddTaskDependency :exec
+INSERT INTO task_dependencies (
+  task_id,
+  blocked_by_id
+) VALUES (
+  $1, $2
+) ON CONFLICT DO NOTHING;
+
+-- name: RemoveTaskDependency :execrows
+DELETE FROM task_dependencies
+WHERE task_id = $1 AND blocked_by_id = $2;
+
+-- name: ListTaskBlockers :many
+SELECT tasks.* FROM task_dependencies
+JOIN tasks ON tasks.id = task_dependencies.blocked_by_id
+WHERE task_dependencies.task_id = $1
+ORDER BY tasks.completed ASC, tasks.deadline ASC;
+
+-- name: CountOpenBlockers :one
+-- The blockers stay locked until the end of the transaction, so that none of
+-- them can be reopened while the task is being completed.
+SELECT COUNT(*) FILTER (WHERE NOT blockers.completed) FROM (
+  SELECT tasks.completed FROM task_dependencies
+  JOIN tasks ON tasks.id = task_dependencies.blocked_by_id
+  WHERE task_dependencies.task_id = $1
+  FOR SHARE OF tasks
+) AS blockers;
@@ -0,0 +1,141 @@
+-- name: CreateRecurringTask :one
+-- CreateRecurringTask starts a series whose first occurrence is the new task.
+WITH recurrence AS (
+  INSERT INTO task_recurrences (
+    creator_id,
+    rule,
+    first_deadline,
+    timezone,
+    title,
+    description,
+    priority
+  ) VALUES (
+    sqlc.arg('creator_id'),
+    sqlc.arg('rule'),
+    sqlc.arg('deadline'),
+    sqlc.arg('timezone'),
+    sqlc.arg('title'),
+    sqlc.narg('description'),
+    sqlc.arg('priority')
+  ) RETURNING id
+)
+INSERT INTO tasks (
+  id,
+  creator_id,
+  title,
+  description,
+  deadline,
+  priority,
+  parent_id,
+  project_id,
+  workspace_id,
+  assignee_id,
+  recurrence_id,
+  occurrence
+)
+SELECT
+  sqlc.arg('id'),
+  sqlc.arg('creator_id'),
+  sqlc.arg('title'),
+  sqlc.narg('description'),
+  sqlc.arg('deadline'),
+  sqlc.arg('priority'),
+  sqlc.narg('parent_id'),
+  sqlc.narg('project_id'),
+  sqlc.narg('workspace_id'),
+  sqlc.narg('assignee_id'),
+  recurrence.id,
+  1
+FROM recurrence
+RETURNING *;
+
+-- name: GetTaskRecurrence :one
+SELECT * FROM task_recurrences
+WHERE id = $1 LIMIT 1;
+
+-- name: CreateTaskOccurrence :one
+-- CreateTaskOccurrence returns no rows when the occurrence already exists,
+-- for example because a completed occurrence was reopened and completed again.
+INSERT INTO tasks (
+  id,
+  creator_id,
+  title,
+  description,
+  deadline,
+  priority,
+  parent_id,
+  project_id,
+  workspace_id,
+  assignee_id,
+  recurrence_id,
+  occurrence
+)
+SELECT
+  sqlc.arg('id'),
+  task_recurrences.creator_id,
+  task_recurrences.title,
+  task_recurrences.description,
+  sqlc.arg('deadline'),
+  task_recurrences.priority,
+  sqlc.narg('parent_id'),
+  sqlc.narg('project_id'),
+  sqlc.narg('workspace_id'),
+  sqlc.narg('assignee_id'),
+  task_recurrences.id,
+  sqlc.arg('occurrence')
+FROM task_recurrences
+WHERE task_recurrences.id = sqlc.arg('recurrence_id')
+ON CONFLICT (recurrence_id, occurrence) DO NOTHING
+RETURNING *;
+
+-- name: SkipTaskOccurrence :one
+-- SkipTaskOccurrence turns an open occurrence into a later one of its series.
+UPDATE tasks
+SET
+  deadline = sqlc.arg('deadline'),
+  occurrence = sqlc.arg('occurrence')
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: SplitTaskRecurrence :one
+-- SplitTaskRecurrence starts a new series at the task, which becomes its first
+-- occurrence. Earlier occurrences stay with the old series.
+WITH recurrence AS (
+  INSERT INTO task_recurrences (
+    creator_id,
+    rule,
+    first_deadline,
+    timezone,
+    title,
+    description,
+    priority
+  )
+  SELECT
+    creator_id,
+    sqlc.arg('rule'),
+    deadline,
+    sqlc.arg('timezone'),
+    title,
+    description,
+    priority
+  FROM tasks
+  WHERE id = sqlc.arg('id')
+  RETURNING id
+)
+UPDATE tasks
+SET
+  recurrence_id = recurrence.id,
+  occurrence = 1
+FROM recurrence
+WHERE tasks.id = sqlc.arg('id')
+RETURNING tasks.*;
+
+-- name: StopTaskRecurrence :one
+-- StopTaskRecurrence detaches a task from its series so that completing it
+-- no longer creates another occurrence.
+UPDATE tasks
+SET
+  recurrence_id = NULL,
+  occurrence = NULL
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,83 @@
+-- name: CreateTaskReminder :one
+-- CreateTaskReminder computes fire_at from the deadline of the task for
+-- reminders given as an offset.
+INSERT INTO task_reminders (
+  task_id,
+  remind_at,
+  offset_seconds,
+  fire_at
+)
+SELECT
+  tasks.id,
+  sqlc.narg('remind_at'),
+  sqlc.narg('offset_seconds'),
+  COALESCE(
+    sqlc.narg('remind_at'),
+    tasks.deadline - make_interval(secs => sqlc.narg('offset_seconds')::int)
+  )
+FROM tasks
+WHERE tasks.id = sqlc.arg('task_id')
+RETURNING *;
+
+-- name: ListTaskReminders :many
+SELECT * FROM task_reminders
+WHERE task_id = $1
+ORDER BY fire_at, id;
+
+-- name: DeleteTaskReminder :execrows
+DELETE FROM task_reminders
+WHERE id = $1 AND task_id = $2;
+
+-- name: CopyTaskReminders :exec
+-- CopyTaskReminders gives a task the reminders of another one that are
+-- relative to its deadline, e.g. for the next occurrence of a recurring task.
+INSERT INTO task_reminders (
+  task_id,
+  offset_seconds,
+  fire_at
+)
+SELECT
+  tasks.id,
+  task_reminders.offset_seconds,
+  tasks.deadline - make_interval(secs => task_reminders.offset_seconds)
+FROM task_reminders, tasks
+WHERE task_reminders.task_id = sqlc.arg('from_task_id')
+  AND task_reminders.offset_seconds IS NOT NULL
+  AND tasks.id = sqlc.arg('to_task_id');
+
+-- name: ClaimDueReminders :many
+-- ClaimDueReminders leases up to limit due reminders of open tasks to the
+-- caller. Concurrent callers never claim the same reminder: locked rows are
+-- skipped and a claimed reminder is only due again once its lease expires.
+UPDATE task_reminders
+SET claimed_until = now() + sqlc.arg('lease_seconds')::int * interval '1 second'
+FROM tasks, users
+WHERE task_reminders.id IN (
+  SELECT due.id
+  FROM task_reminders AS due
+  JOIN tasks AS due_tasks ON due_tasks.id = due.task_id
+  WHERE due.sent_at IS NULL
+    AND due.fire_at <= now()
+    AND (due.claimed_until IS NULL OR due.claimed_until < now())
+    AND NOT due_tasks.completed
+  ORDER BY due.fire_at
+  LIMIT sqlc.arg('limit')
+  FOR UPDATE OF due SKIP LOCKED
+)
+  AND tasks.id = task_reminders.task_id
+  AND users.id = tasks.creator_id
+RETURNING
+  task_reminders.id,
+  task_reminders.fire_at,
+  tasks.id AS task_id,
+  tasks.creator_id,
+  tasks.title,
+  tasks.deadline,
+  users.timezone;
+
+-- name: MarkReminderSent :exec
+UPDATE task_reminders
+SET
+  sent_at = now(),
+  claimed_until = NULL
+WHERE id = $1;
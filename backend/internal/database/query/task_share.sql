@@ -0,0 +1,44 @@
+-- name: ShareTask :one
+-- ShareTask gives a user a role on a task, replacing the role they had.
+INSERT INTO task_shares (
+  task_id,
+  user_id,
+  role
+) VALUES (
+  $1, $2, $3
+) ON CONFLICT (task_id, user_id) DO UPDATE
+SET role = EXCLUDED.role
+RETURNING *;
+
+-- name: GetTaskShare :one
+SELECT * FROM task_shares
+WHERE task_id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: ListTaskShares :many
+SELECT
+  task_shares.*,
+  users.username
+FROM task_shares
+JOIN users ON users.id = task_shares.user_id
+WHERE task_shares.task_id = $1
+ORDER BY task_shares.created_at ASC;
+
+-- name: UnshareTask :execrows
+DELETE FROM task_shares
+WHERE task_id = $1 AND user_id = $2;
+
+-- name: CopyTaskShares :exec
+-- CopyTaskShares shares a task with the users another one is shared with,
+-- e.g. for the next occurrence of a recurring task.
+INSERT INTO task_shares (
+  task_id,
+  user_id,
+  role
+)
+SELECT
+  sqlc.arg('to_task_id')::varchar,
+  task_shares.user_id,
+  task_shares.role
+FROM task_shares
+WHERE task_shares.task_id = sqlc.arg('from_task_id')
+ON CONFLICT DO NOTHING;
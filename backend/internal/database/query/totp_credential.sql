@@ -0,0 +1,38 @@
+-- name: GetTOTPCredential :one
+SELECT * FROM totp_credentials
+WHERE user_id = $1 LIMIT 1;
+
+-- name: UpsertPendingTOTPCredential :one
+-- Starts (or restarts) an enrollment. A confirmed credential is left
+-- untouched and no row is returned.
+INSERT INTO totp_credentials (
+  user_id,
+  secret
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret,
+    last_used_step = 0,
+    created_at = now()
+WHERE totp_credentials.confirmed_at IS NULL
+RETURNING *;
+
+-- name: ConfirmTOTPCredential :one
+UPDATE totp_credentials
+SET confirmed_at = now(),
+    last_used_step = sqlc.arg('step')
+WHERE user_id = sqlc.arg('user_id') AND confirmed_at IS NULL
+RETURNING *;
+
+-- name: UseTOTPStep :execrows
+-- Only moves forward, so every code is accepted at most once.
+UPDATE totp_credentials
+SET last_used_step = sqlc.arg('step')
+WHERE user_id = sqlc.arg('user_id')
+  AND confirmed_at IS NOT NULL
+  AND last_used_step < sqlc.arg('step');
+
+-- name: DeleteTOTPCredential :exec
+DELETE FROM totp_credentials
+WHERE user_id = $1;
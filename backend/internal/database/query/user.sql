@@ -1,11 +1,61 @@
 -- name: CreateUser :one
 INSERT INTO users (
   username,
-  hashed_password
+  hashed_password,
+  email
 ) VALUES (
-  $1, $2
+  $1, $2, $3
 ) RETURNING *;
 
 -- name: GetUser :one
 SELECT * FROM users
 WHERE username = $1 LIMIT 1;
+
+-- name: GetUserByID :one
+SELECT * FROM users
+WHERE id = $1 LIMIT 1;
+
+-- name: UpdateUserRole :one
+UPDATE users
+SET role = $2
+WHERE username = $1
+RETURNING *;
+
+-- name: UpdateUserPassword :one
+UPDATE users
+SET hashed_password = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateUserProfile :one
+UPDATE users
+SET
+  display_name = COALESCE(sqlc.narg(display_name), display_name),
+  timezone = COALESCE(sqlc.narg(timezone), timezone),
+  locale = COALESCE(sqlc.narg(locale), locale)
+WHERE
+  id = $1
+RETURNING *;
+
+-- name: DeleteUser :execrows
+DELETE FROM users
+WHERE id = $1;
+
+-- name: UpdateUserEmail :one
+UPDATE users
+SET
+  email = $2,
+  email_verified_at = NULL
+WHERE id = $1
+RETURNING *;
+
+-- name: VerifyUserEmail :one
+UPDATE users
+SET email_verified_at = now()
+WHERE id = $1 AND email = $2
+RETURNING *;
+
+-- name: GetUserByVerifiedEmail :one
+SELECT * FROM users
+WHERE email = $1 AND email_verified_at IS NOT NULL
+LIMIT 1;
@@ -0,0 +1,41 @@
+-- name: GetUserIdentity :one
+SELECT * FROM user_identities
+WHERE issuer = $1 AND subject = $2 LIMIT 1;
+
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities (
+  user_id,
+  issuer,
+  subject,
+  email
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: CreateUserWithIdentity :one
+-- Provisions a user for an external identity in one statement so that no
+-- user is left without the identity it was created for. An email is only
+-- passed when the provider has verified it, so it is stored as verified.
+WITH new_user AS (
+  INSERT INTO users (
+    username,
+    hashed_password,
+    email,
+    email_verified_at
+  ) VALUES (
+    sqlc.arg('username'),
+    sqlc.arg('hashed_password'),
+    sqlc.narg('email'),
+    CASE WHEN sqlc.narg('email')::varchar IS NULL THEN NULL ELSE now() END
+  ) RETURNING *
+), new_identity AS (
+  INSERT INTO user_identities (
+    user_id,
+    issuer,
+    subject,
+    email
+  )
+  SELECT id, sqlc.arg('issuer'), sqlc.arg('subject'), email
+  FROM new_user
+)
+SELECT * FROM new_user;
@@ -0,0 +1,74 @@
+-- name: CreateWorkspace :one
+-- CreateWorkspace creates a workspace owned by the given user.
+WITH workspace AS (
+  INSERT INTO workspaces (
+    name
+  ) VALUES (
+    sqlc.arg('name')
+  ) RETURNING *
+), owner AS (
+  INSERT INTO workspace_members (
+    workspace_id,
+    user_id,
+    role
+  )
+  SELECT id, sqlc.arg('owner_id'), 'owner' FROM workspace
+)
+SELECT * FROM workspace;
+
+-- name: GetWorkspace :one
+SELECT * FROM workspaces
+WHERE id = $1 LIMIT 1;
+
+-- name: ListUserWorkspaces :many
+SELECT
+  workspaces.*,
+  workspace_members.role
+FROM workspaces
+JOIN workspace_members ON workspace_members.workspace_id = workspaces.id
+WHERE workspace_members.user_id = $1
+ORDER BY lower(workspaces.name) ASC, workspaces.id ASC;
+
+-- name: UpdateWorkspace :one
+UPDATE workspaces
+SET name = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteWorkspace :exec
+DELETE FROM workspaces
+WHERE id = $1;
+
+-- name: GetWorkspaceMember :one
+SELECT * FROM workspace_members
+WHERE workspace_id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: ListWorkspaceMembers :many
+SELECT
+  workspace_members.*,
+  users.username
+FROM workspace_members
+JOIN users ON users.id = workspace_members.user_id
+WHERE workspace_members.workspace_id = $1
+ORDER BY workspace_members.created_at ASC;
+
+-- name: AddWorkspaceMember :one
+-- AddWorkspaceMember returns no rows when the user already is a member.
+INSERT INTO workspace_members (
+  workspace_id,
+  user_id,
+  role
+) VALUES (
+  $1, $2, $3
+) ON CONFLICT DO NOTHING
+RETURNING *;
+
+-- name: UpdateWorkspaceMemberRole :one
+UPDATE workspace_members
+SET role = $3
+WHERE workspace_id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: RemoveWorkspaceMember :execrows
+DELETE FROM workspace_members
+WHERE workspace_id = $1 AND user_id = $2;
@@ -0,0 +1,31 @@
+-- name: CreateWorkspaceInvitation :one
+INSERT INTO workspace_invitations (
+  id,
+  workspace_id,
+  token_hash,
+  role,
+  created_by,
+  expire_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: ListWorkspaceInvitations :many
+-- ListWorkspaceInvitations leaves out expired invitations.
+SELECT * FROM workspace_invitations
+WHERE
+  workspace_id = $1
+  AND expire_at > now()
+ORDER BY created_at DESC;
+
+-- name: GetWorkspaceInvitationByHash :one
+-- GetWorkspaceInvitationByHash returns no rows for expired invitations.
+SELECT * FROM workspace_invitations
+WHERE
+  token_hash = $1
+  AND expire_at > now()
+LIMIT 1;
+
+-- name: DeleteWorkspaceInvitation :execrows
+DELETE FROM workspace_invitations
+WHERE id = $1 AND workspace_id = $2;
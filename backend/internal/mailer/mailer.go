@@ -0,0 +1,16 @@
+package mailer
+
+import (
+	"context"
+)
+
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers emails. Implementations must be safe for concurrent use.
+type Sender interface {
+	Send(ctx context.Context, email Email) error
+}
@@ -0,0 +1,152 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedEmail struct {
+	from string
+	to   []string
+	data string
+}
+
+// startFakeSMTPServer accepts a single SMTP session the way MailHog does: no
+// TLS, no authentication, every message is accepted.
+func startFakeSMTPServer(t *testing.T) (host string, port string, received <-chan receivedEmail) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	ch := make(chan receivedEmail, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) {
+			conn.Write([]byte(line + "\r\n"))
+		}
+
+		var email receivedEmail
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			command := strings.ToUpper(line)
+
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "MAIL FROM:"):
+				email.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+				reply("250 OK")
+			case strings.HasPrefix(command, "RCPT TO:"):
+				email.to = append(email.to, strings.Trim(line[len("RCPT TO:"):], "<> "))
+				reply("250 OK")
+			case command == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					dataLine, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if dataLine == ".\r\n" {
+						break
+					}
+					data.WriteString(dataLine)
+				}
+				email.data = data.String()
+				reply("250 OK")
+			case command == "QUIT":
+				reply("221 Bye")
+				ch <- email
+				return
+			default:
+				reply("502 Command not implemented")
+			}
+		}
+	}()
+
+	host, port, err = net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	return host, port, ch
+}
+
+func TestSMTPSender(t *testing.T) {
+	host, port, received := startFakeSMTPServer(t)
+
+	sender := NewSMTPSender(util.MailConfig{
+		SMTPHost: host,
+		SMTPPort: port,
+		From:     "no-reply@example.com",
+	})
+
+	email := Email{
+		To:      "user@example.com",
+		Subject: "Verify your email",
+		Body:    "line 1\nline 2",
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := sender.Send(ctx, email)
+	require.NoError(t, err)
+
+	select {
+	case got := <-received:
+		require.Equal(t, "no-reply@example.com", got.from)
+		require.Equal(t, []string{email.To}, got.to)
+		require.Contains(t, got.data, "To: user@example.com\r\n")
+		require.Contains(t, got.data, "Subject: Verify your email\r\n")
+		require.Contains(t, got.data, "\r\n\r\nline 1\r\nline 2")
+	case <-time.After(5 * time.Second):
+		t.Fatal("email was not received")
+	}
+}
+
+func TestSMTPSenderHeaderInjection(t *testing.T) {
+	sender := NewSMTPSender(util.MailConfig{
+		SMTPHost: "127.0.0.1",
+		SMTPPort: "1",
+		From:     "no-reply@example.com",
+	})
+
+	err := sender.Send(context.Background(), Email{
+		To:      "user@example.com\r\nBcc: victim@example.com",
+		Subject: "subject",
+	})
+	require.ErrorIs(t, err, ErrInvalidHeader)
+}
+
+func TestSMTPSenderConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	listener.Close()
+
+	sender := NewSMTPSender(util.MailConfig{
+		SMTPHost: host,
+		SMTPPort: port,
+		From:     "no-reply@example.com",
+	})
+
+	err = sender.Send(context.Background(), Email{To: "user@example.com", Subject: "subject"})
+	require.Error(t, err)
+}
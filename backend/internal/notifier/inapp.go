@@ -0,0 +1,32 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+)
+
+// InAppNotifier stores messages in the notifications table, where users read
+// them through the API. Messages with a key are stored only once, so a retried
+// delivery does not notify the user twice.
+type InAppNotifier struct {
+	storage store.Storage
+}
+
+func NewInAppNotifier(storage store.Storage) Notifier {
+	return &InAppNotifier{storage: storage}
+}
+
+func (n *InAppNotifier) Notify(ctx context.Context, msg Message) error {
+	return n.storage.CreateNotification(ctx, store.CreateNotificationParams{
+		UserID:  msg.UserID,
+		Kind:    msg.Kind,
+		Subject: msg.Subject,
+		Body:    msg.Body,
+		DedupKey: pgtype.Text{
+			String: msg.Key,
+			Valid:  len(msg.Key) > 0,
+		},
+	})
+}
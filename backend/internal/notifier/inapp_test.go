@@ -0,0 +1,53 @@
+package notifier
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestInAppNotifier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	msg := Message{
+		UserID:  1,
+		Kind:    KindTaskReminder,
+		Subject: "subject",
+		Body:    "body",
+		Key:     "task_reminder:1",
+	}
+
+	storage := mockdb.NewMockStorage(ctrl)
+	storage.EXPECT().
+		CreateNotification(gomock.Any(), gomock.Eq(store.CreateNotificationParams{
+			UserID:   msg.UserID,
+			Kind:     msg.Kind,
+			Subject:  msg.Subject,
+			Body:     msg.Body,
+			DedupKey: pgtype.Text{String: msg.Key, Valid: true},
+		})).
+		Times(1)
+	storage.EXPECT().
+		CreateNotification(gomock.Any(), gomock.Eq(store.CreateNotificationParams{
+			UserID:  msg.UserID,
+			Kind:    msg.Kind,
+			Subject: msg.Subject,
+			Body:    msg.Body,
+		})).
+		Times(1).
+		Return(sql.ErrConnDone)
+
+	n := NewInAppNotifier(storage)
+	require.NoError(t, n.Notify(context.Background(), msg))
+
+	// Messages without a key are never deduplicated.
+	msg.Key = ""
+	require.ErrorIs(t, n.Notify(context.Background(), msg), sql.ErrConnDone)
+}
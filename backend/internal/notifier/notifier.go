@@ -0,0 +1,26 @@
+package notifier
+
+import (
+	"context"
+)
+
+const (
+	KindPasswordReset = "password_reset"
+	KindTaskReminder  = "task_reminder"
+)
+
+type Message struct {
+	UserID  int64
+	Kind    string
+	Subject string
+	Body    string
+	// Key identifies the message across retries. Notifiers that can
+	// deduplicate deliver a message with a non-empty key at most once.
+	Key string
+}
+
+// Notifier delivers messages to users out of band, e.g. password reset
+// tokens. Implementations must be safe for concurrent use.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,28 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+)
+
+// OutboxNotifier records messages in the outbox_messages table instead of
+// sending them anywhere. It is the default so that local setups and tests can
+// read what would have been delivered.
+type OutboxNotifier struct {
+	storage store.Storage
+}
+
+func NewOutboxNotifier(storage store.Storage) Notifier {
+	return &OutboxNotifier{storage: storage}
+}
+
+func (n *OutboxNotifier) Notify(ctx context.Context, msg Message) error {
+	_, err := n.storage.CreateOutboxMessage(ctx, store.CreateOutboxMessageParams{
+		UserID:  msg.UserID,
+		Kind:    msg.Kind,
+		Subject: msg.Subject,
+		Body:    msg.Body,
+	})
+	return err
+}
@@ -0,0 +1,42 @@
+package notifier
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOutboxNotifier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	msg := Message{
+		UserID:  1,
+		Kind:    KindPasswordReset,
+		Subject: "subject",
+		Body:    "body",
+	}
+
+	storage := mockdb.NewMockStorage(ctrl)
+	storage.EXPECT().
+		CreateOutboxMessage(gomock.Any(), gomock.Eq(store.CreateOutboxMessageParams{
+			UserID:  msg.UserID,
+			Kind:    msg.Kind,
+			Subject: msg.Subject,
+			Body:    msg.Body,
+		})).
+		Times(1)
+	storage.EXPECT().
+		CreateOutboxMessage(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(store.OutboxMessage{}, sql.ErrConnDone)
+
+	n := NewOutboxNotifier(storage)
+	require.NoError(t, n.Notify(context.Background(), msg))
+	require.ErrorIs(t, n.Notify(context.Background(), msg), sql.ErrConnDone)
+}
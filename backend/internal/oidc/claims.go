@@ -0,0 +1,68 @@
+package oidc
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+)
+
+// clockSkew is tolerated between our clock and the provider's when checking
+// the expiry and issue time of an ID token.
+const clockSkew = time.Minute
+
+var errExpiredIDToken = errors.New("ID token has expired")
+
+// audience accepts both forms allowed for the aud claim: a single string or
+// an array of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*a = multiple
+	return nil
+}
+
+func (a audience) contains(clientID string) bool {
+	return slices.Contains(a, clientID)
+}
+
+// IDTokenClaims are the claims of an ID token that are used to link or
+// provision a user.
+type IDTokenClaims struct {
+	Issuer            string   `json:"iss"`
+	Subject           string   `json:"sub"`
+	Audience          audience `json:"aud"`
+	AuthorizedParty   string   `json:"azp"`
+	ExpiresAt         int64    `json:"exp"`
+	IssuedAt          int64    `json:"iat"`
+	Nonce             string   `json:"nonce"`
+	Email             string   `json:"email"`
+	EmailVerified     bool     `json:"email_verified"`
+	PreferredUsername string   `json:"preferred_username"`
+	Name              string   `json:"name"`
+}
+
+// Valid is called by the JWT parser once the signature has been checked.
+func (c *IDTokenClaims) Valid() error {
+	now := time.Now()
+
+	if time.Unix(c.ExpiresAt, 0).Add(clockSkew).Before(now) {
+		return errExpiredIDToken
+	}
+
+	if time.Unix(c.IssuedAt, 0).Add(-clockSkew).After(now) {
+		return errors.New("ID token is issued in the future")
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package oidc
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/nguyen-duc-loc/task-management/backend/internal/oidc/oidctest"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProvider(t *testing.T, clientSecret string) (*Provider, *oidctest.Issuer) {
+	issuer, err := oidctest.NewIssuer("task-app", clientSecret)
+	require.NoError(t, err)
+	t.Cleanup(issuer.Close)
+
+	provider := NewProvider(util.OIDCConfig{
+		IssuerURL:    issuer.URL(),
+		ClientID:     "task-app",
+		ClientSecret: clientSecret,
+		RedirectURL:  "http://localhost:8080/auth/oidc/callback",
+		Scopes:       []string{"openid", "email"},
+	}, nil)
+	return provider, issuer
+}
+
+func authorize(t *testing.T, provider *Provider, issuer *oidctest.Issuer, state string, nonce string, codeVerifier string) *url.URL {
+	authCodeURL, err := provider.AuthCodeURL(context.Background(), state, nonce, codeVerifier)
+	require.NoError(t, err)
+
+	callback, err := issuer.Authorize(authCodeURL)
+	require.NoError(t, err)
+	require.Equal(t, "/auth/oidc/callback", callback.Path)
+	require.Equal(t, state, callback.Query().Get("state"))
+	return callback
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	for _, clientSecret := range []string{"s3cret", ""} {
+		provider, issuer := newTestProvider(t, clientSecret)
+		issuer.SetIdentity(oidctest.Identity{
+			Subject:       "subject-1",
+			Email:         "jane@example.com",
+			EmailVerified: true,
+		})
+
+		state, nonce, codeVerifier := "state", "nonce", "verifier-verifier-verifier-verifier-verifier"
+		callback := authorize(t, provider, issuer, state, nonce, codeVerifier)
+
+		rawIDToken, err := provider.Exchange(context.Background(), callback.Query().Get("code"), codeVerifier)
+		require.NoError(t, err)
+
+		claims, err := provider.VerifyIDToken(context.Background(), rawIDToken, nonce)
+		require.NoError(t, err)
+		require.Equal(t, issuer.URL(), claims.Issuer)
+		require.Equal(t, "subject-1", claims.Subject)
+		require.Equal(t, "jane@example.com", claims.Email)
+		require.True(t, claims.EmailVerified)
+
+		// Codes are single use.
+		_, err = provider.Exchange(context.Background(), callback.Query().Get("code"), codeVerifier)
+		require.ErrorIs(t, err, ErrExchangeFailed)
+	}
+}
+
+func TestExchangeWrongCodeVerifier(t *testing.T) {
+	provider, issuer := newTestProvider(t, "s3cret")
+	issuer.SetIdentity(oidctest.Identity{Subject: "subject-1"})
+
+	callback := authorize(t, provider, issuer, "state", "nonce", "verifier-1")
+
+	_, err := provider.Exchange(context.Background(), callback.Query().Get("code"), "verifier-2")
+	require.ErrorIs(t, err, ErrExchangeFailed)
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	provider, issuer := newTestProvider(t, "s3cret")
+	identity := oidctest.Identity{Subject: "subject-1"}
+
+	testCases := []struct {
+		name  string
+		extra map[string]any
+		ok    bool
+	}{
+		{
+			name:  "OK",
+			extra: map[string]any{"nonce": "nonce"},
+			ok:    true,
+		},
+		{
+			name:  "AudienceArray",
+			extra: map[string]any{"nonce": "nonce", "aud": []string{"task-app", "other"}, "azp": "task-app"},
+			ok:    true,
+		},
+		{
+			name:  "WrongNonce",
+			extra: map[string]any{"nonce": "other"},
+		},
+		{
+			name:  "WrongAudience",
+			extra: map[string]any{"nonce": "nonce", "aud": "other-app"},
+		},
+		{
+			name:  "WrongAuthorizedParty",
+			extra: map[string]any{"nonce": "nonce", "aud": []string{"task-app", "other"}, "azp": "other"},
+		},
+		{
+			name:  "WrongIssuer",
+			extra: map[string]any{"nonce": "nonce", "iss": "https://evil.example.com"},
+		},
+		{
+			name:  "Expired",
+			extra: map[string]any{"nonce": "nonce", "exp": time.Now().Add(-time.Hour).Unix()},
+		},
+		{
+			name:  "MissingSubject",
+			extra: map[string]any{"nonce": "nonce", "sub": ""},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rawIDToken, err := issuer.IDToken(identity, tc.extra)
+			require.NoError(t, err)
+
+			claims, err := provider.VerifyIDToken(context.Background(), rawIDToken, "nonce")
+			if tc.ok {
+				require.NoError(t, err)
+				require.Equal(t, identity.Subject, claims.Subject)
+				return
+			}
+			require.ErrorIs(t, err, ErrInvalidIDToken)
+		})
+	}
+}
+
+func TestVerifyIDTokenFromOtherIssuer(t *testing.T) {
+	provider, _ := newTestProvider(t, "s3cret")
+	_, otherIssuer := newTestProvider(t, "s3cret")
+
+	// Signed by a key the configured issuer has never published.
+	rawIDToken, err := otherIssuer.IDToken(oidctest.Identity{Subject: "subject-1"}, map[string]any{"nonce": "nonce"})
+	require.NoError(t, err)
+
+	_, err = provider.VerifyIDToken(context.Background(), rawIDToken, "nonce")
+	require.ErrorIs(t, err, ErrInvalidIDToken)
+}
+
+func TestUnknownKeyIDRefetchIsRateLimited(t *testing.T) {
+	provider, issuer := newTestProvider(t, "s3cret")
+	_, otherIssuer := newTestProvider(t, "s3cret")
+
+	rawIDToken, err := otherIssuer.IDToken(oidctest.Identity{Subject: "subject-1"}, map[string]any{"nonce": "nonce"})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = provider.VerifyIDToken(context.Background(), rawIDToken, "nonce")
+		require.ErrorIs(t, err, ErrInvalidIDToken)
+	}
+	require.Equal(t, 1, issuer.JWKSRequests())
+
+	// Known keys are still served from the cache.
+	rawIDToken, err = issuer.IDToken(oidctest.Identity{Subject: "subject-1"}, map[string]any{"nonce": "nonce"})
+	require.NoError(t, err)
+
+	_, err = provider.VerifyIDToken(context.Background(), rawIDToken, "nonce")
+	require.NoError(t, err)
+	require.Equal(t, 1, issuer.JWKSRequests())
+}
+
+func TestPKCEChallenge(t *testing.T) {
+	verifier1, err := RandomString()
+	require.NoError(t, err)
+	verifier2, err := RandomString()
+	require.NoError(t, err)
+	require.NotEqual(t, verifier1, verifier2)
+
+	// RFC 7636 requires 43 to 128 characters from the unreserved set.
+	require.Len(t, verifier1, 43)
+	require.Regexp(t, `^[A-Za-z0-9\-._~]+$`, verifier1)
+
+	challenge := PKCEChallenge(verifier1)
+	require.Len(t, challenge, 43)
+	require.Equal(t, challenge, PKCEChallenge(verifier1))
+	require.NotEqual(t, challenge, PKCEChallenge(verifier2))
+}
@@ -0,0 +1,443 @@
+// Package recurrence implements the subset of RFC 5545 recurrence rules that
+// makes sense for task deadlines: daily, weekly, monthly and yearly rules with
+// INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH and WKST.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// searchYears bounds the search for the next occurrence so that rules which
+// can never match again, like the 30th of February, terminate.
+const searchYears = 100
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// WeekdayNum is a BYDAY entry. A non-zero N selects the Nth (or, when
+// negative, the Nth last) such weekday of the month or year.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+// Rule is a parsed RRULE value.
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	// Count limits the series to that many occurrences, counting the first
+	// one. Zero means no limit.
+	Count int
+	// Until is the last instant an occurrence may fall on. The zero time
+	// means no limit.
+	Until      time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	WeekStart  time.Weekday
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidRule, fmt.Sprintf(format, args...))
+}
+
+// Parse parses an RRULE value such as "FREQ=WEEKLY;BYDAY=MO,WE". An optional
+// "RRULE:" prefix is accepted.
+func Parse(value string) (Rule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+
+	rule := Rule{
+		Interval:  1,
+		WeekStart: time.Monday,
+	}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(part, "=")
+		if !ok || len(val) == 0 {
+			return Rule{}, invalid("malformed part %q", part)
+		}
+		name = strings.ToUpper(name)
+		val = strings.ToUpper(val)
+		if seen[name] {
+			return Rule{}, invalid("%s is given more than once", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			rule.Freq = Frequency(val)
+			switch rule.Freq {
+			case Daily, Weekly, Monthly, Yearly:
+			default:
+				err = invalid("unsupported frequency %s", val)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(name, val)
+		case "COUNT":
+			rule.Count, err = parsePositive(name, val)
+		case "UNTIL":
+			rule.Until, err = parseUntil(val)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(val)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseIntList(name, val, 1, 31, true)
+		case "BYMONTH":
+			var months []int
+			months, err = parseIntList(name, val, 1, 12, false)
+			for _, month := range months {
+				rule.ByMonth = append(rule.ByMonth, time.Month(month))
+			}
+		case "WKST":
+			weekday, ok := weekdays[val]
+			if !ok {
+				err = invalid("unknown weekday %s", val)
+			}
+			rule.WeekStart = weekday
+		default:
+			err = invalid("unsupported part %s", name)
+		}
+		if err != nil {
+			return Rule{}, err
+		}
+	}
+
+	if len(rule.Freq) == 0 {
+		return Rule{}, invalid("FREQ is required")
+	}
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		return Rule{}, invalid("COUNT and UNTIL cannot be combined")
+	}
+	if rule.Freq == Weekly && len(rule.ByMonthDay) > 0 {
+		return Rule{}, invalid("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	}
+	if rule.Freq == Daily || rule.Freq == Weekly {
+		for _, day := range rule.ByDay {
+			if day.N != 0 {
+				return Rule{}, invalid("numbered BYDAY entries need FREQ=MONTHLY or FREQ=YEARLY")
+			}
+		}
+	}
+
+	return rule, nil
+}
+
+func parsePositive(name string, val string) (int, error) {
+	n, err := strconv.Atoi(val)
+	if err != nil || n < 1 {
+		return 0, invalid("%s must be a positive integer", name)
+	}
+	return n, nil
+}
+
+func parseUntil(val string) (time.Time, error) {
+	if until, err := time.Parse("20060102T150405Z", val); err == nil {
+		return until, nil
+	}
+	// A date without time covers the whole day. It is read as UTC since
+	// the rule carries no time zone of its own.
+	if until, err := time.Parse("20060102", val); err == nil {
+		return until.Add(24*time.Hour - time.Second), nil
+	}
+	return time.Time{}, invalid("UNTIL must be a UTC date-time like 20250131T235959Z or a date like 20250131")
+}
+
+func parseByDay(val string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, item := range strings.Split(val, ",") {
+		if len(item) < 2 {
+			return nil, invalid("malformed BYDAY entry %q", item)
+		}
+		weekday, ok := weekdays[item[len(item)-2:]]
+		if !ok {
+			return nil, invalid("unknown weekday in %q", item)
+		}
+
+		day := WeekdayNum{Weekday: weekday}
+		if prefix := item[:len(item)-2]; len(prefix) > 0 {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, invalid("malformed BYDAY entry %q", item)
+			}
+			day.N = n
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+func parseIntList(name string, val string, lowest int, highest int, allowNegative bool) ([]int, error) {
+	var list []int
+	for _, item := range strings.Split(val, ",") {
+		n, err := strconv.Atoi(item)
+		abs := n
+		if abs < 0 && allowNegative {
+			abs = -abs
+		}
+		if err != nil || abs < lowest || abs > highest {
+			return nil, invalid("%s entry %q is out of range", name, item)
+		}
+		list = append(list, n)
+	}
+	return list, nil
+}
+
+// String formats the rule as an RRULE value.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if len(r.ByDay) > 0 {
+		items := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			item := weekdayCode(day.Weekday)
+			if day.N != 0 {
+				item = strconv.Itoa(day.N) + item
+			}
+			items = append(items, item)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(items, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]int, 0, len(r.ByMonth))
+		for _, month := range r.ByMonth {
+			months = append(months, int(month))
+		}
+		parts = append(parts, "BYMONTH="+joinInts(months))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayCode(r.WeekStart))
+	}
+	return strings.Join(parts, ";")
+}
+
+func weekdayCode(weekday time.Weekday) string {
+	for code, day := range weekdays {
+		if day == weekday {
+			return code
+		}
+	}
+	return ""
+}
+
+func joinInts(list []int) string {
+	items := make([]string, 0, len(list))
+	for _, n := range list {
+		items = append(items, strconv.Itoa(n))
+	}
+	return strings.Join(items, ",")
+}
+
+// Occurrence returns the nth occurrence of the series that starts at start,
+// counting start itself as the first one. Occurrences keep the wall-clock
+// time of start in loc, so a daily 09:00 task stays at 09:00 across DST
+// changes. It reports false when the series has fewer than n occurrences.
+func (r Rule) Occurrence(start time.Time, loc *time.Location, n int) (time.Time, bool) {
+	occurrences := r.Occurrences(start, loc, n, 1)
+	if len(occurrences) == 0 {
+		return time.Time{}, false
+	}
+	return occurrences[0], true
+}
+
+// Occurrences returns up to limit occurrences of the series, beginning with
+// the nth one.
+func (r Rule) Occurrences(start time.Time, loc *time.Location, n int, limit int) []time.Time {
+	if n < 1 {
+		return nil
+	}
+
+	start = start.In(loc)
+	occurrences := make([]time.Time, 0, limit)
+	occurrence := start
+	for i := 1; len(occurrences) < limit; i++ {
+		if r.Count > 0 && i > r.Count {
+			break
+		}
+		if i > 1 {
+			next, ok := r.next(start, occurrence)
+			if !ok {
+				break
+			}
+			occurrence = next
+		}
+		if i >= n {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+	return occurrences
+}
+
+// next returns the first occurrence strictly after the given one.
+func (r Rule) next(start time.Time, after time.Time) (time.Time, bool) {
+	loc := start.Location()
+	hour, minute, sec := start.Clock()
+
+	startDate := civilDate(start)
+	day := civilDate(after)
+	limit := day.AddDate(searchYears, 0, 0)
+	for ; day.Before(limit); day = day.AddDate(0, 0, 1) {
+		if !r.matches(startDate, day) {
+			continue
+		}
+
+		candidate := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, sec, 0, loc)
+		if !candidate.After(after) {
+			continue
+		}
+		if !r.Until.IsZero() && candidate.After(r.Until) {
+			return time.Time{}, false
+		}
+		return candidate, true
+	}
+	return time.Time{}, false
+}
+
+// civilDate returns the calendar date of t as midnight UTC, which makes day
+// arithmetic independent of DST.
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// matches reports whether the calendar date day belongs to the series that
+// starts on the calendar date start.
+func (r Rule) matches(start time.Time, day time.Time) bool {
+	if !r.inInterval(start, day) {
+		return false
+	}
+
+	byDay, byMonthDay, byMonth := r.ByDay, r.ByMonthDay, r.ByMonth
+	// Without any day selector the start date decides which days match, as
+	// described in RFC 5545 section 3.3.10.
+	if len(byDay) == 0 && len(byMonthDay) == 0 {
+		switch r.Freq {
+		case Weekly:
+			byDay = []WeekdayNum{{Weekday: start.Weekday()}}
+		case Monthly:
+			byMonthDay = []int{start.Day()}
+		case Yearly:
+			byMonthDay = []int{start.Day()}
+			if len(byMonth) == 0 {
+				byMonth = []time.Month{start.Month()}
+			}
+		}
+	}
+
+	if len(byMonth) > 0 && !slices.Contains(byMonth, day.Month()) {
+		return false
+	}
+
+	if len(byMonthDay) > 0 && !matchesMonthDay(byMonthDay, day) {
+		return false
+	}
+
+	if len(byDay) > 0 && !r.matchesWeekday(byDay, day) {
+		return false
+	}
+
+	return true
+}
+
+func (r Rule) inInterval(start time.Time, day time.Time) bool {
+	var periods int
+	switch r.Freq {
+	case Daily:
+		periods = daysBetween(start, day)
+	case Weekly:
+		periods = daysBetween(r.weekStartOf(start), r.weekStartOf(day)) / 7
+	case Monthly:
+		periods = (day.Year()-start.Year())*12 + int(day.Month()-start.Month())
+	case Yearly:
+		periods = day.Year() - start.Year()
+	}
+	return periods%r.Interval == 0
+}
+
+func (r Rule) weekStartOf(day time.Time) time.Time {
+	offset := (int(day.Weekday()) - int(r.WeekStart) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+func daysBetween(from time.Time, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func daysInMonth(day time.Time) int {
+	return time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func matchesMonthDay(byMonthDay []int, day time.Time) bool {
+	for _, monthDay := range byMonthDay {
+		if monthDay < 0 {
+			monthDay = daysInMonth(day) + monthDay + 1
+		}
+		if day.Day() == monthDay {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWeekday checks BYDAY. Numbered entries count within the month for
+// monthly rules and for yearly rules with BYMONTH, and within the year
+// otherwise.
+func (r Rule) matchesWeekday(byDay []WeekdayNum, day time.Time) bool {
+	withinYear := r.Freq == Yearly && len(r.ByMonth) == 0
+
+	for _, entry := range byDay {
+		if entry.Weekday != day.Weekday() {
+			continue
+		}
+		if entry.N == 0 {
+			return true
+		}
+
+		position, length := day.Day(), daysInMonth(day)
+		if withinYear {
+			position = day.YearDay()
+			length = time.Date(day.Year(), time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+		}
+
+		if entry.N > 0 && (position-1)/7+1 == entry.N {
+			return true
+		}
+		if entry.N < 0 && (length-position)/7+1 == -entry.N {
+			return true
+		}
+	}
+	return false
+}
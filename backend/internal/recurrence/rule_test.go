@@ -0,0 +1,224 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	require.NoError(t, err)
+	return loc
+}
+
+func TestParse(t *testing.T) {
+	rule, err := Parse("RRULE:FREQ=MONTHLY;INTERVAL=2;COUNT=5;BYDAY=-1FR,2MO;WKST=SU")
+	require.NoError(t, err)
+	require.Equal(t, Monthly, rule.Freq)
+	require.Equal(t, 2, rule.Interval)
+	require.Equal(t, 5, rule.Count)
+	require.Equal(t, []WeekdayNum{{Weekday: time.Friday, N: -1}, {Weekday: time.Monday, N: 2}}, rule.ByDay)
+	require.Equal(t, time.Sunday, rule.WeekStart)
+	require.Equal(t, "FREQ=MONTHLY;INTERVAL=2;COUNT=5;BYDAY=-1FR,2MO;WKST=SU", rule.String())
+
+	rule, err = Parse("freq=yearly;bymonth=2;bymonthday=-1;until=20301231")
+	require.NoError(t, err)
+	require.Equal(t, []time.Month{time.February}, rule.ByMonth)
+	require.Equal(t, []int{-1}, rule.ByMonthDay)
+	require.Equal(t, time.Date(2030, time.December, 31, 23, 59, 59, 0, time.UTC), rule.Until)
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"BYDAY=MO",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=3;UNTIL=20300101",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYDAY=XX",
+		"FREQ=YEARLY;BYMONTH=13",
+		"FREQ=DAILY;BYSETPOS=1",
+		"FREQ=DAILY;UNTIL=tomorrow",
+	} {
+		_, err := Parse(value)
+		require.ErrorIs(t, err, ErrInvalidRule, value)
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	testCases := []struct {
+		name     string
+		rule     string
+		start    time.Time
+		loc      *time.Location
+		expected []time.Time
+		// ends is set when expected lists every occurrence of the series.
+		ends bool
+	}{
+		{
+			name:  "DailyKeepsLocalTimeAcrossDST",
+			rule:  "FREQ=DAILY",
+			start: time.Date(2025, time.March, 8, 9, 0, 0, 0, newYork),
+			loc:   newYork,
+			expected: []time.Time{
+				time.Date(2025, time.March, 8, 9, 0, 0, 0, newYork),
+				time.Date(2025, time.March, 9, 9, 0, 0, 0, newYork),
+				time.Date(2025, time.March, 10, 9, 0, 0, 0, newYork),
+			},
+		},
+		{
+			name:  "WeeklyOnSeveralDays",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,FR",
+			start: time.Date(2025, time.June, 2, 8, 30, 0, 0, time.UTC),
+			loc:   time.UTC,
+			expected: []time.Time{
+				time.Date(2025, time.June, 2, 8, 30, 0, 0, time.UTC),
+				time.Date(2025, time.June, 6, 8, 30, 0, 0, time.UTC),
+				time.Date(2025, time.June, 9, 8, 30, 0, 0, time.UTC),
+				time.Date(2025, time.June, 13, 8, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "BiweeklyWithSundayWeekStart",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,SU;WKST=SU",
+			start: time.Date(1997, time.August, 5, 9, 0, 0, 0, time.UTC),
+			loc:   time.UTC,
+			// RFC 5545 section 3.3.10 example.
+			expected: []time.Time{
+				time.Date(1997, time.August, 5, 9, 0, 0, 0, time.UTC),
+				time.Date(1997, time.August, 17, 9, 0, 0, 0, time.UTC),
+				time.Date(1997, time.August, 19, 9, 0, 0, 0, time.UTC),
+				time.Date(1997, time.August, 31, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "MonthlySkipsMonthsWithoutTheDay",
+			rule:  "FREQ=MONTHLY",
+			start: time.Date(2025, time.January, 31, 17, 0, 0, 0, time.UTC),
+			loc:   time.UTC,
+			expected: []time.Time{
+				time.Date(2025, time.January, 31, 17, 0, 0, 0, time.UTC),
+				time.Date(2025, time.March, 31, 17, 0, 0, 0, time.UTC),
+				time.Date(2025, time.May, 31, 17, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "MonthlyOnLastDay",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start: time.Date(2025, time.January, 31, 17, 0, 0, 0, time.UTC),
+			loc:   time.UTC,
+			expected: []time.Time{
+				time.Date(2025, time.January, 31, 17, 0, 0, 0, time.UTC),
+				time.Date(2025, time.February, 28, 17, 0, 0, 0, time.UTC),
+				time.Date(2025, time.March, 31, 17, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "MonthlyOnLastFriday",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR",
+			start: time.Date(2025, time.January, 31, 10, 0, 0, 0, newYork),
+			loc:   newYork,
+			expected: []time.Time{
+				time.Date(2025, time.January, 31, 10, 0, 0, 0, newYork),
+				time.Date(2025, time.February, 28, 10, 0, 0, 0, newYork),
+				time.Date(2025, time.March, 28, 10, 0, 0, 0, newYork),
+			},
+		},
+		{
+			name:  "YearlyOnLeapDay",
+			rule:  "FREQ=YEARLY",
+			start: time.Date(2024, time.February, 29, 12, 0, 0, 0, time.UTC),
+			loc:   time.UTC,
+			expected: []time.Time{
+				time.Date(2024, time.February, 29, 12, 0, 0, 0, time.UTC),
+				time.Date(2028, time.February, 29, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "Count",
+			rule:  "FREQ=DAILY;INTERVAL=3;COUNT=2",
+			start: time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC),
+			loc:   time.UTC,
+			expected: []time.Time{
+				time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, time.May, 4, 0, 0, 0, 0, time.UTC),
+			},
+			ends: true,
+		},
+		{
+			name:  "Until",
+			rule:  "FREQ=WEEKLY;UNTIL=20250515T000000Z",
+			start: time.Date(2025, time.May, 1, 9, 0, 0, 0, time.UTC),
+			loc:   time.UTC,
+			expected: []time.Time{
+				time.Date(2025, time.May, 1, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, time.May, 8, 9, 0, 0, 0, time.UTC),
+			},
+			ends: true,
+		},
+		{
+			name:  "NeverAgain",
+			rule:  "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+			start: time.Date(2025, time.May, 1, 9, 0, 0, 0, time.UTC),
+			loc:   time.UTC,
+			expected: []time.Time{
+				time.Date(2025, time.May, 1, 9, 0, 0, 0, time.UTC),
+			},
+			ends: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := Parse(tc.rule)
+			require.NoError(t, err)
+
+			limit := len(tc.expected)
+			if tc.ends {
+				limit++
+			}
+			occurrences := rule.Occurrences(tc.start, tc.loc, 1, limit)
+			require.Len(t, occurrences, len(tc.expected))
+			for i := range tc.expected {
+				require.True(t, tc.expected[i].Equal(occurrences[i]), "occurrence %d: expected %s, got %s", i+1, tc.expected[i], occurrences[i])
+			}
+		})
+	}
+}
+
+func TestOccurrence(t *testing.T) {
+	rule, err := Parse("FREQ=WEEKLY;COUNT=3")
+	require.NoError(t, err)
+
+	start := time.Date(2025, time.October, 20, 9, 0, 0, 0, time.UTC)
+
+	third, ok := rule.Occurrence(start, time.UTC, 3)
+	require.True(t, ok)
+	require.Equal(t, start.AddDate(0, 0, 14), third)
+
+	_, ok = rule.Occurrence(start, time.UTC, 4)
+	require.False(t, ok)
+
+	_, ok = rule.Occurrence(start, time.UTC, 0)
+	require.False(t, ok)
+}
+
+func TestOccurrenceAcrossDSTChangesUTCOffset(t *testing.T) {
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	rule, err := Parse("FREQ=WEEKLY")
+	require.NoError(t, err)
+
+	// 09:00 in Berlin is 07:00 UTC in summer and 08:00 UTC in winter.
+	start := time.Date(2025, time.October, 20, 7, 0, 0, 0, time.UTC)
+	next, ok := rule.Occurrence(start, berlin, 2)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2025, time.October, 27, 8, 0, 0, 0, time.UTC), next.UTC())
+}
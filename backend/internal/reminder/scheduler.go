@@ -0,0 +1,103 @@
+// Package reminder delivers task reminders once they are due.
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nguyen-duc-loc/task-management/backend/internal/notifier"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+)
+
+// Scheduler polls for due reminders and hands them to a notifier. Any number
+// of schedulers may share a database: each due reminder is claimed by exactly
+// one of them, and a reminder whose delivery fails is retried once its lease
+// expires. With a notifier that deduplicates by key, such as the in-app one,
+// every reminder is therefore delivered exactly once.
+type Scheduler struct {
+	storage  store.Storage
+	notifier notifier.Notifier
+	config   util.ReminderConfig
+}
+
+func NewScheduler(storage store.Storage, n notifier.Notifier, config util.ReminderConfig) *Scheduler {
+	return &Scheduler{
+		storage:  storage,
+		notifier: n,
+		config:   config,
+	}
+}
+
+// Run delivers due reminders every poll interval until ctx is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("cannot deliver due reminders: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue delivers the reminders that are due now, one batch at a time.
+// Failed deliveries are only logged; they stay claimed until their lease
+// expires and are then picked up again.
+func (s *Scheduler) DeliverDue(ctx context.Context) error {
+	for {
+		reminders, err := s.storage.ClaimDueReminders(ctx, store.ClaimDueRemindersParams{
+			LeaseSeconds: int32(s.config.Lease / time.Second),
+			Limit:        s.config.BatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, reminder := range reminders {
+			if err := s.deliver(ctx, reminder); err != nil {
+				log.Printf("cannot deliver reminder %d of task %s: %v", reminder.ID, reminder.TaskID, err)
+			}
+		}
+
+		if len(reminders) < int(s.config.BatchSize) {
+			return nil
+		}
+	}
+}
+
+func (s *Scheduler) deliver(ctx context.Context, reminder store.ClaimDueRemindersRow) error {
+	err := s.notifier.Notify(ctx, Message(reminder))
+	if err != nil {
+		return err
+	}
+
+	return s.storage.MarkReminderSent(ctx, reminder.ID)
+}
+
+// Message describes a due reminder to the creator of its task, with the
+// deadline in the creator's timezone.
+func Message(reminder store.ClaimDueRemindersRow) notifier.Message {
+	deadline := reminder.Deadline
+	if loc, err := time.LoadLocation(reminder.Timezone); err == nil {
+		deadline = deadline.In(loc)
+	}
+
+	return notifier.Message{
+		UserID:  reminder.CreatorID,
+		Kind:    notifier.KindTaskReminder,
+		Subject: fmt.Sprintf("Reminder: %s", reminder.Title),
+		Body:    fmt.Sprintf("Task %q is due on %s.", reminder.Title, deadline.Format("Mon, 02 Jan 2006 15:04 MST")),
+		// A reminder fires again when the deadline of its task is postponed,
+		// so the key covers the time it fires at.
+		Key: fmt.Sprintf("task_reminder:%d:%d", reminder.ID, reminder.FireAt.Unix()),
+	}
+}
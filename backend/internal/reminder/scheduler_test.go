@@ -0,0 +1,137 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/notifier"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var errDeliveryFailed = errors.New("delivery failed")
+
+// recordingNotifier records the messages it is given and fails for the users
+// in failFor.
+type recordingNotifier struct {
+	mu       sync.Mutex
+	messages []notifier.Message
+	failFor  map[int64]bool
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, msg notifier.Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.failFor[msg.UserID] {
+		return errDeliveryFailed
+	}
+	n.messages = append(n.messages, msg)
+	return nil
+}
+
+func testConfig(batchSize int32) util.ReminderConfig {
+	return util.ReminderConfig{
+		PollInterval: time.Millisecond,
+		Lease:        90 * time.Second,
+		BatchSize:    batchSize,
+	}
+}
+
+func dueReminder(id int64, userID int64) store.ClaimDueRemindersRow {
+	return store.ClaimDueRemindersRow{
+		ID:        id,
+		FireAt:    time.Date(2025, time.March, 9, 12, 0, 0, 0, time.UTC),
+		TaskID:    "task",
+		CreatorID: userID,
+		Title:     "Send invoice",
+		Deadline:  time.Date(2025, time.March, 9, 13, 0, 0, 0, time.UTC),
+		Timezone:  "America/New_York",
+	}
+}
+
+func TestDeliverDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mockdb.NewMockStorage(ctrl)
+	gomock.InOrder(
+		storage.EXPECT().
+			ClaimDueReminders(gomock.Any(), gomock.Eq(store.ClaimDueRemindersParams{
+				LeaseSeconds: 90,
+				Limit:        2,
+			})).
+			Return([]store.ClaimDueRemindersRow{dueReminder(1, 10), dueReminder(2, 20)}, nil),
+		// A full batch means more reminders may be due.
+		storage.EXPECT().
+			ClaimDueReminders(gomock.Any(), gomock.Any()).
+			Return([]store.ClaimDueRemindersRow{dueReminder(3, 10)}, nil),
+	)
+	storage.EXPECT().MarkReminderSent(gomock.Any(), gomock.Eq(int64(1))).Times(1)
+	storage.EXPECT().MarkReminderSent(gomock.Any(), gomock.Eq(int64(3))).Times(1)
+	// The failed reminder is retried once its lease expires.
+	storage.EXPECT().MarkReminderSent(gomock.Any(), gomock.Eq(int64(2))).Times(0)
+
+	n := &recordingNotifier{failFor: map[int64]bool{20: true}}
+	scheduler := NewScheduler(storage, n, testConfig(2))
+	require.NoError(t, scheduler.DeliverDue(context.Background()))
+	require.Len(t, n.messages, 2)
+	require.Equal(t, int64(10), n.messages[0].UserID)
+}
+
+func TestDeliverDueClaimFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mockdb.NewMockStorage(ctrl)
+	storage.EXPECT().
+		ClaimDueReminders(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(nil, errDeliveryFailed)
+
+	scheduler := NewScheduler(storage, &recordingNotifier{}, testConfig(10))
+	require.ErrorIs(t, scheduler.DeliverDue(context.Background()), errDeliveryFailed)
+}
+
+func TestRunStopsWithContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	storage := mockdb.NewMockStorage(ctrl)
+	storage.EXPECT().
+		ClaimDueReminders(gomock.Any(), gomock.Any()).
+		MinTimes(1).
+		DoAndReturn(func(context.Context, store.ClaimDueRemindersParams) ([]store.ClaimDueRemindersRow, error) {
+			cancel()
+			return nil, nil
+		})
+
+	done := make(chan struct{})
+	go func() {
+		NewScheduler(storage, &recordingNotifier{}, testConfig(10)).Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop")
+	}
+}
+
+func TestMessage(t *testing.T) {
+	msg := Message(dueReminder(7, 10))
+	require.Equal(t, int64(10), msg.UserID)
+	require.Equal(t, notifier.KindTaskReminder, msg.Kind)
+	require.Equal(t, "Reminder: Send invoice", msg.Subject)
+	require.Equal(t, `Task "Send invoice" is due on Sun, 09 Mar 2025 09:00 EDT.`, msg.Body)
+	require.Equal(t, "task_reminder:7:1741521600", msg.Key)
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+)
+
+// The assignee filter of a task listing is "me", "none" or a user ID.
+const (
+	assigneeFilterMe   = "me"
+	assigneeFilterNone = "none"
+)
+
+var (
+	errInvalidAssignee = errors.New("a task can only be assigned to its creator, to a user it is shared with, or to a member of its workspace")
+	errAssigneeStatus  = errors.New("the task is assigned to the authenticated user, who may only complete or reopen it")
+)
+
+// checkAssignee checks that a task may be assigned to a user. Tasks of a
+// workspace may be assigned to any of its members, and personal tasks to
+// their creator or to a user they are shared with. A task that is not
+// created yet has no ID.
+func (s *Server) checkAssignee(ctx *gin.Context, task store.Task, assigneeID int64) error {
+	if assigneeID == task.CreatorID {
+		return nil
+	}
+
+	var err error
+	switch {
+	case task.WorkspaceID.Valid:
+		_, err = s.storage.GetWorkspaceMember(ctx, store.GetWorkspaceMemberParams{
+			WorkspaceID: task.WorkspaceID.Int64,
+			UserID:      assigneeID,
+		})
+	case len(task.ID) > 0:
+		_, err = s.storage.GetTaskShare(ctx, store.GetTaskShareParams{
+			TaskID: task.ID,
+			UserID: assigneeID,
+		})
+	default:
+		// A new personal task is not shared with anyone yet.
+		return errInvalidAssignee
+	}
+
+	if errors.Is(err, store.ErrRecordNotFound) {
+		return errInvalidAssignee
+	}
+	return err
+}
+
+// newAssigneeFilter translates the assignee filter of a task listing into the
+// assignee to match, or into a filter on unassigned tasks.
+func newAssigneeFilter(userID int64, assignee string) (pgtype.Int8, bool) {
+	switch assignee {
+	case "":
+		return pgtype.Int8{}, false
+	case assigneeFilterMe:
+		return pgtype.Int8{Int64: userID, Valid: true}, false
+	case assigneeFilterNone:
+		return pgtype.Int8{}, true
+	}
+
+	// Request validation only lets numbers through.
+	id, _ := strconv.ParseInt(assignee, 10, 64)
+	return pgtype.Int8{Int64: id, Valid: true}, false
+}
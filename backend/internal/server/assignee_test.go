@@ -0,0 +1,360 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetTasksAssigneeFilter(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		query      string
+		assigneeID pgtype.Int8
+		unassigned bool
+	}{
+		{query: "", assigneeID: pgtype.Int8{}},
+		{query: "?assignee=me", assigneeID: pgtype.Int8{Int64: user.ID, Valid: true}},
+		{query: "?assignee=42", assigneeID: pgtype.Int8{Int64: 42, Valid: true}},
+		{query: "?assignee=none", unassigned: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			storage.EXPECT().
+				GetTasks(gomock.Any(), EqGetTasksParams(store.GetTasksParams{
+					UserID:     user.ID,
+					AssigneeID: tc.assigneeID,
+					Unassigned: tc.unassigned,
+					Limit:      5,
+				})).
+				Times(1).
+				Return([]store.GetTasksRow{}, nil)
+
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodGet, "/tasks"+tc.query, nil)
+			require.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+
+	for _, query := range []string{"?assignee=someone", "?assignee=-1"} {
+		t.Run(query, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodGet, "/tasks"+query, nil)
+			require.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
+
+func TestCreateTaskAssignee(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+	workspace := randomWorkspace()
+	deadline := time.Now().Add(time.Hour).Format(time.RFC3339)
+
+	testCases := []struct {
+		name       string
+		url        string
+		assigneeID int64
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name:       "Creator",
+			url:        "/tasks",
+			assigneeID: user.ID,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateTask(gomock.Any(), gomock.Cond(func(arg store.CreateTaskParams) bool {
+						return arg.AssigneeID == pgtype.Int8{Int64: user.ID, Valid: true}
+					})).
+					Times(1).
+					Return(randomTask(t, user.ID), nil)
+			},
+			expectCode: http.StatusCreated,
+		},
+		{
+			name:       "OtherUser",
+			url:        "/tasks",
+			assigneeID: other.ID,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:       "WorkspaceMember",
+			url:        fmt.Sprintf("/workspaces/%d/tasks", workspace.ID),
+			assigneeID: other.ID,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubWorkspaceMember(storage, workspace.ID, user.ID, util.WorkspaceRoleMember)
+				stubWorkspaceMember(storage, workspace.ID, other.ID, util.WorkspaceRoleMember)
+				storage.EXPECT().
+					CreateTask(gomock.Any(), gomock.Cond(func(arg store.CreateTaskParams) bool {
+						return arg.AssigneeID == pgtype.Int8{Int64: other.ID, Valid: true}
+					})).
+					Times(1).
+					Return(randomWorkspaceTask(t, user.ID, workspace.ID), nil)
+			},
+			expectCode: http.StatusCreated,
+		},
+		{
+			name:       "NotWorkspaceMember",
+			url:        fmt.Sprintf("/workspaces/%d/tasks", workspace.ID),
+			assigneeID: other.ID,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubWorkspaceMember(storage, workspace.ID, user.ID, util.WorkspaceRoleMember)
+				stubWorkspaceMember(storage, workspace.ID, other.ID, "")
+				storage.EXPECT().
+					CreateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			body := gin.H{
+				"title":       util.RandomPrintableString(20),
+				"deadline":    deadline,
+				"assignee_id": tc.assigneeID,
+			}
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPost, tc.url, body)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestAssignedTaskAccess(t *testing.T) {
+	creator, _ := randomUser(t)
+	assignee, _ := randomUser(t)
+	task := randomTask(t, creator.ID)
+	task.AssigneeID = pgtype.Int8{Int64: assignee.ID, Valid: true}
+	url := "/tasks/" + task.ID
+
+	testCases := []struct {
+		name       string
+		method     string
+		body       gin.H
+		role       string
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name:       "Reads",
+			method:     http.MethodGet,
+			buildStubs: func(storage *mockdb.MockStorage) {},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:   "Completes",
+			method: http.MethodPut,
+			body:   gin.H{"completed": true},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CountOpenBlockers(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(int64(0), nil)
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Eq(store.UpdateTaskParams{
+						ID:        task.ID,
+						Completed: pgtype.Bool{Bool: true, Valid: true},
+					})).
+					Times(1).
+					Return(task, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:   "Renames",
+			method: http.MethodPut,
+			body:   gin.H{"title": "Renamed", "completed": true},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:   "EditorRenames",
+			method: http.MethodPut,
+			body:   gin.H{"title": "Renamed"},
+			role:   util.ShareRoleEditor,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(task, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:   "Deletes",
+			method: http.MethodDelete,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					DeleteTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			stubTaskShare(storage, task, assignee.ID, tc.role)
+			stubNoTaskTags(storage)
+			tc.buildStubs(storage)
+
+			recorder := serveAuthenticatedRequest(t, storage, assignee, tc.method, url, tc.body)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestUpdateTaskAssignee(t *testing.T) {
+	creator, _ := randomUser(t)
+	other, _ := randomUser(t)
+	task := randomTask(t, creator.ID)
+	assigned := task
+	assigned.AssigneeID = pgtype.Int8{Int64: other.ID, Valid: true}
+	url := "/tasks/" + task.ID
+
+	testCases := []struct {
+		name       string
+		task       store.Task
+		assigneeID int64
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name:       "SharedUser",
+			task:       task,
+			assigneeID: other.ID,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubTaskShare(storage, task, other.ID, util.ShareRoleViewer)
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					SetTaskAssignee(gomock.Any(), gomock.Eq(store.SetTaskAssigneeParams{
+						ID:         task.ID,
+						AssigneeID: pgtype.Int8{Int64: other.ID, Valid: true},
+					})).
+					Times(1).
+					Return(assigned, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "NotSharedUser",
+			task:       task,
+			assigneeID: other.ID,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubTaskShare(storage, task, other.ID, "")
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Unassign",
+			task:       assigned,
+			assigneeID: 0,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(assigned, nil)
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(assigned, nil)
+				storage.EXPECT().
+					SetTaskAssignee(gomock.Any(), gomock.Eq(store.SetTaskAssigneeParams{
+						ID: task.ID,
+					})).
+					Times(1).
+					Return(task, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			stubNoTaskTags(storage)
+			tc.buildStubs(storage)
+
+			recorder := serveAuthenticatedRequest(t, storage, creator, http.MethodPut, url, gin.H{"assignee_id": tc.assigneeID})
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestUnshareUnassigns(t *testing.T) {
+	creator, _ := randomUser(t)
+	assignee, _ := randomUser(t)
+	task := randomTask(t, creator.ID)
+	task.AssigneeID = pgtype.Int8{Int64: assignee.ID, Valid: true}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mockdb.NewMockStorage(ctrl)
+	storage.EXPECT().
+		GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+		Times(1).
+		Return(task, nil)
+	storage.EXPECT().
+		UnshareTask(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(int64(1), nil)
+	storage.EXPECT().
+		SetTaskAssignee(gomock.Any(), gomock.Eq(store.SetTaskAssigneeParams{
+			ID: task.ID,
+		})).
+		Times(1).
+		Return(store.Task{}, nil)
+
+	url := fmt.Sprintf("/tasks/%s/shares/%s", task.ID, strconv.FormatInt(assignee.ID, 10))
+	recorder := serveAuthenticatedRequest(t, storage, creator, http.MethodDelete, url, nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
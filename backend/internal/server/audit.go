@@ -0,0 +1,106 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+)
+
+const (
+	auditEventSignup          = "signup"
+	auditEventLoginSucceeded  = "login_succeeded"
+	auditEventLoginFailed     = "login_failed"
+	auditEventTokenRevoked    = "token_revoked"
+	auditEventPasswordChanged = "password_changed"
+)
+
+// recordAuditEvent appends to the security audit log. A userID of zero records
+// an event for an unknown username, which only shows up in the table itself.
+// Auditing never fails the request it describes, so errors are only logged.
+func (s *Server) recordAuditEvent(ctx *gin.Context, userID int64, username string, eventType string, detail string) {
+	_, err := s.storage.CreateAuditEvent(ctx, store.CreateAuditEventParams{
+		UserID: pgtype.Int8{
+			Int64: userID,
+			Valid: userID != 0,
+		},
+		Username:  username,
+		EventType: eventType,
+		Detail: pgtype.Text{
+			String: detail,
+			Valid:  len(detail) > 0,
+		},
+		ClientIp:  ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
+	if err != nil {
+		log.Printf("cannot record %s audit event for %s: %v", eventType, username, err)
+	}
+}
+
+type listSecurityEventsRequest struct {
+	Page  int32 `form:"page" binding:"omitempty,min=1"`
+	Limit int32 `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+type securityEventResponse struct {
+	ID        int64       `json:"id"`
+	EventType string      `json:"event_type"`
+	Detail    pgtype.Text `json:"detail"`
+	ClientIp  string      `json:"client_ip"`
+	UserAgent string      `json:"user_agent"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type listSecurityEventsResponse struct {
+	Total  int64                   `json:"total"`
+	Events []securityEventResponse `json:"events"`
+}
+
+func (s *Server) listSecurityEventsHandler(ctx *gin.Context) {
+	var req listSecurityEventsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	events, err := s.storage.ListUserAuditEvents(ctx, store.ListUserAuditEventsParams{
+		UserID: authPayload.UserID,
+		Limit:  req.Limit,
+		Offset: (req.Page - 1) * req.Limit,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := listSecurityEventsResponse{
+		Events: []securityEventResponse{},
+	}
+	for _, event := range events {
+		rsp.Total = event.Total
+		rsp.Events = append(rsp.Events, securityEventResponse{
+			ID:        event.ID,
+			EventType: event.EventType,
+			Detail:    event.Detail,
+			ClientIp:  event.ClientIp,
+			UserAgent: event.UserAgent,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp))
+}
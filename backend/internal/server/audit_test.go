@@ -0,0 +1,306 @@
+package server
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func stubAuditEvents(storage *mockdb.MockStorage) {
+	storage.EXPECT().
+		CreateAuditEvent(gomock.Any(), gomock.Any()).
+		AnyTimes()
+}
+
+func randomAuditEvent(userID int64, eventType string) store.ListUserAuditEventsRow {
+	return store.ListUserAuditEventsRow{
+		ID:        rand.Int64N(1000) + 1,
+		UserID:    pgtype.Int8{Int64: userID, Valid: true},
+		Username:  util.RandomUsername(),
+		EventType: eventType,
+		ClientIp:  testClientIP,
+		UserAgent: testUserAgent,
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestLoginAuditEvents(t *testing.T) {
+	user, password := randomUser(t)
+
+	testCases := []struct {
+		name       string
+		body       gin.H
+		buildStubs func(storage *mockdb.MockStorage)
+		expected   store.CreateAuditEventParams
+	}{
+		{
+			name: "Succeeded",
+			body: gin.H{
+				"username": user.Username,
+				"password": password,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					ClearLoginAttempts(gomock.Any(), gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			expected: store.CreateAuditEventParams{
+				UserID:    pgtype.Int8{Int64: user.ID, Valid: true},
+				Username:  user.Username,
+				EventType: auditEventLoginSucceeded,
+			},
+		},
+		{
+			name: "InvalidPassword",
+			body: gin.H{
+				"username": user.Username,
+				"password": "wrong-password",
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					RecordLoginFailure(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(int32(1), nil)
+			},
+			expected: store.CreateAuditEventParams{
+				UserID:    pgtype.Int8{Int64: user.ID, Valid: true},
+				Username:  user.Username,
+				EventType: auditEventLoginFailed,
+				Detail:    pgtype.Text{String: "invalid_password", Valid: true},
+			},
+		},
+		{
+			name: "UnknownUser",
+			body: gin.H{
+				"username": "unknown",
+				"password": password,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq("unknown")).
+					Times(1).
+					Return(store.User{}, store.ErrRecordNotFound)
+				storage.EXPECT().
+					RecordLoginFailure(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(int32(1), nil)
+			},
+			expected: store.CreateAuditEventParams{
+				Username:  "unknown",
+				EventType: auditEventLoginFailed,
+				Detail:    pgtype.Text{String: "unknown_user", Valid: true},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			expected := tc.expected
+			expected.ClientIp = testClientIP
+			expected.UserAgent = testUserAgent
+			storage.EXPECT().
+				CreateAuditEvent(gomock.Any(), gomock.Eq(expected)).
+				Times(1)
+			stubLoginNotLocked(storage)
+			stubTOTPNotEnabled(storage)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("User-Agent", testUserAgent)
+			request.RemoteAddr = testClientIP + ":1234"
+
+			server.router.ServeHTTP(recorder, request)
+		})
+	}
+}
+
+func TestAuditEventFailureDoesNotFailRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mockdb.NewMockStorage(ctrl)
+	stubTokenNotRevoked(storage)
+	storage.EXPECT().
+		RevokeToken(gomock.Any(), gomock.Any()).
+		Times(1)
+	storage.EXPECT().
+		CreateAuditEvent(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(store.AuditEvent{}, sql.ErrConnDone)
+
+	server, err := NewServer(storage)
+	require.NoError(t, err)
+	server.RegisterRoutes()
+
+	user, _ := randomUser(t)
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/users/logout", http.NoBody)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestListSecurityEventsHandler(t *testing.T) {
+	user, _ := randomUser(t)
+
+	events := []store.ListUserAuditEventsRow{
+		randomAuditEvent(user.ID, auditEventPasswordChanged),
+		randomAuditEvent(user.ID, auditEventLoginSucceeded),
+	}
+	for i := range events {
+		events[i].Total = int64(len(events))
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "page=2&limit=10",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					ListUserAuditEvents(gomock.Any(), gomock.Eq(store.ListUserAuditEventsParams{
+						UserID: user.ID,
+						Limit:  10,
+						Offset: 10,
+					})).
+					Times(1).
+					Return(events, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp struct {
+					Data listSecurityEventsResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, int64(len(events)), rsp.Data.Total)
+				require.Len(t, rsp.Data.Events, len(events))
+				for i, event := range rsp.Data.Events {
+					require.Equal(t, events[i].ID, event.ID)
+					require.Equal(t, events[i].EventType, event.EventType)
+					require.Equal(t, events[i].ClientIp, event.ClientIp)
+					require.Equal(t, events[i].UserAgent, event.UserAgent)
+				}
+			},
+		},
+		{
+			name:  "DefaultPagination",
+			query: "",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					ListUserAuditEvents(gomock.Any(), gomock.Eq(store.ListUserAuditEventsParams{
+						UserID: user.ID,
+						Limit:  20,
+						Offset: 0,
+					})).
+					Times(1).
+					Return([]store.ListUserAuditEventsRow{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp struct {
+					Data listSecurityEventsResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Zero(t, rsp.Data.Total)
+				require.NotNil(t, rsp.Data.Events)
+				require.Empty(t, rsp.Data.Events)
+			},
+		},
+		{
+			name:  "InvalidLimit",
+			query: "limit=100",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					ListUserAuditEvents(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			query: "",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					ListUserAuditEvents(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+			stubTokenNotRevoked(storage)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/users/me/security-events?%s", tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	accessTokenCookieName  = "access_token"
+	refreshTokenCookieName = "refresh_token"
+	// The CSRF cookie is readable by scripts so the client can echo it back in
+	// csrfHeaderKey, which a cross-site request cannot do.
+	csrfCookieName = "csrf_token"
+	csrfHeaderKey  = "X-CSRF-Token"
+)
+
+var errInvalidCSRFToken = errors.New("missing or invalid CSRF token")
+
+// cookieLoginResponse replaces loginResponse when the client has opted into
+// cookie authentication; the tokens only travel in HttpOnly cookies.
+type cookieLoginResponse struct {
+	SessionID            uuid.UUID    `json:"session_id"`
+	AccessTokenExpireAt  time.Time    `json:"access_token_expire_at"`
+	RefreshTokenExpireAt time.Time    `json:"refresh_token_expire_at"`
+	CSRFToken            string       `json:"csrf_token"`
+	User                 userResponse `json:"user"`
+}
+
+// respondLogin answers a completed login with the tokens in the body or, when
+// useCookies is set, in session cookies.
+func (s *Server) respondLogin(ctx *gin.Context, rsp loginResponse, useCookies bool) {
+	if !useCookies {
+		ctx.JSON(http.StatusOK, successResponse(rsp))
+		return
+	}
+
+	csrfToken, err := newCSRFToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	s.setCookie(ctx, accessTokenCookieName, rsp.AccessToken, rsp.AccessTokenExpireAt, true)
+	s.setCookie(ctx, refreshTokenCookieName, rsp.RefreshToken, rsp.RefreshTokenExpireAt, true)
+	s.setCookie(ctx, csrfCookieName, csrfToken, rsp.RefreshTokenExpireAt, false)
+
+	ctx.JSON(http.StatusOK, successResponse(cookieLoginResponse{
+		SessionID:            rsp.SessionID,
+		AccessTokenExpireAt:  rsp.AccessTokenExpireAt,
+		RefreshTokenExpireAt: rsp.RefreshTokenExpireAt,
+		CSRFToken:            csrfToken,
+		User:                 rsp.User,
+	}))
+}
+
+func (s *Server) setCookie(ctx *gin.Context, name string, value string, expireAt time.Time, httpOnly bool) {
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Domain:   s.cookieConfig.Domain,
+		Expires:  expireAt,
+		Secure:   s.cookieConfig.Secure,
+		HttpOnly: httpOnly,
+		SameSite: s.cookieConfig.SameSite,
+	})
+}
+
+func (s *Server) clearAuthCookies(ctx *gin.Context) {
+	for _, name := range []string{accessTokenCookieName, refreshTokenCookieName, csrfCookieName} {
+		http.SetCookie(ctx.Writer, &http.Cookie{
+			Name:     name,
+			Path:     "/",
+			Domain:   s.cookieConfig.Domain,
+			MaxAge:   -1,
+			Secure:   s.cookieConfig.Secure,
+			HttpOnly: name != csrfCookieName,
+			SameSite: s.cookieConfig.SameSite,
+		})
+	}
+}
+
+// refreshTokenFromRequest returns the refresh token given in the body or, for
+// clients using cookie authentication, the refresh token cookie. Reading the
+// cookie on a state-changing request requires the CSRF token.
+func refreshTokenFromRequest(ctx *gin.Context, bodyToken string) (string, error) {
+	if len(bodyToken) > 0 {
+		return bodyToken, nil
+	}
+
+	cookie, err := ctx.Cookie(refreshTokenCookieName)
+	if err != nil || len(cookie) == 0 {
+		return "", nil
+	}
+
+	if err := checkCSRF(ctx); err != nil {
+		return "", err
+	}
+	return cookie, nil
+}
+
+// checkCSRF enforces the double-submit pattern on state-changing requests: the
+// header must repeat the CSRF cookie, which only the client's own origin can
+// read.
+func checkCSRF(ctx *gin.Context) error {
+	switch ctx.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return nil
+	}
+
+	cookie, err := ctx.Cookie(csrfCookieName)
+	if err != nil || len(cookie) == 0 {
+		return errInvalidCSRFToken
+	}
+
+	header := ctx.GetHeader(csrfHeaderKey)
+	if subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) != 1 {
+		return errInvalidCSRFToken
+	}
+	return nil
+}
+
+func newCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,291 @@
+package server
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func addCookieAuthorization(t *testing.T, request *http.Request, tokenMaker token.Maker, csrfToken string) {
+	accessToken, _, err := tokenMaker.CreateToken(rand.Int64(), util.RandomUsername(), util.UserRole, token.TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	request.AddCookie(&http.Cookie{Name: accessTokenCookieName, Value: accessToken})
+	request.AddCookie(&http.Cookie{Name: csrfCookieName, Value: csrfToken})
+}
+
+func findCookie(recorder *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, cookie := range recorder.Result().Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestAuthMiddlewareCookie(t *testing.T) {
+	csrfToken, err := newCSRFToken()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		method     string
+		setupAuth  func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		expectCode int
+	}{
+		{
+			name:   "SafeMethodWithoutCSRFToken",
+			method: http.MethodGet,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addCookieAuthorization(t, request, tokenMaker, csrfToken)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:   "UnsafeMethodWithCSRFToken",
+			method: http.MethodPost,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addCookieAuthorization(t, request, tokenMaker, csrfToken)
+				request.Header.Set(csrfHeaderKey, csrfToken)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:   "UnsafeMethodWithoutCSRFToken",
+			method: http.MethodPost,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addCookieAuthorization(t, request, tokenMaker, csrfToken)
+			},
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:   "UnsafeMethodWithWrongCSRFToken",
+			method: http.MethodDelete,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addCookieAuthorization(t, request, tokenMaker, csrfToken)
+				request.Header.Set(csrfHeaderKey, csrfToken+"x")
+			},
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:   "UnsafeMethodWithoutCSRFCookie",
+			method: http.MethodPut,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				accessToken, _, err := tokenMaker.CreateToken(rand.Int64(), util.RandomUsername(), util.UserRole, token.TokenTypeAccess, time.Minute)
+				require.NoError(t, err)
+				request.AddCookie(&http.Cookie{Name: accessTokenCookieName, Value: accessToken})
+				request.Header.Set(csrfHeaderKey, "")
+			},
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:   "HeaderTakesPrecedenceWithoutCSRFToken",
+			method: http.MethodPost,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				request.AddCookie(&http.Cookie{Name: accessTokenCookieName, Value: "invalid"})
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, rand.Int64(), util.RandomUsername(), util.UserRole, time.Minute)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:   "InvalidCookieToken",
+			method: http.MethodGet,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				request.AddCookie(&http.Cookie{Name: accessTokenCookieName, Value: "invalid"})
+			},
+			expectCode: http.StatusUnauthorized,
+		},
+		{
+			name:   "PersonalAccessTokenCookie",
+			method: http.MethodGet,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				patToken, _ := randomPersonalAccessToken(t, []string{scopeTasksRead})
+				request.AddCookie(&http.Cookie{Name: accessTokenCookieName, Value: patToken})
+			},
+			expectCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			storage.EXPECT().
+				GetPersonalAccessTokenByHash(gomock.Any(), gomock.Any()).
+				Times(0)
+			stubTokenNotRevoked(storage)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+
+			authPath := "/auth"
+			server.router.Handle(
+				tc.method,
+				authPath,
+				authMiddleware(server.tokenMaker, server.storage, scopeTasksRead),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(tc.method, authPath, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestRenewAccessTokenFromCookie(t *testing.T) {
+	user, _ := randomUser(t)
+	csrfToken, err := newCSRFToken()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		setupCookies  func(request *http.Request, refreshToken string)
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupCookies: func(request *http.Request, refreshToken string) {
+				request.AddCookie(&http.Cookie{Name: refreshTokenCookieName, Value: refreshToken})
+				request.AddCookie(&http.Cookie{Name: csrfCookieName, Value: csrfToken})
+				request.Header.Set(csrfHeaderKey, csrfToken)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				cookie := findCookie(recorder, accessTokenCookieName)
+				require.NotNil(t, cookie)
+				require.NotEmpty(t, cookie.Value)
+				require.True(t, cookie.HttpOnly)
+
+				var rsp struct {
+					Data map[string]any `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.NotContains(t, rsp.Data, "access_token")
+				require.Contains(t, rsp.Data, "access_token_expire_at")
+			},
+		},
+		{
+			name: "MissingCSRFToken",
+			setupCookies: func(request *http.Request, refreshToken string) {
+				request.AddCookie(&http.Cookie{Name: refreshTokenCookieName, Value: refreshToken})
+				request.AddCookie(&http.Cookie{Name: csrfCookieName, Value: csrfToken})
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Nil(t, findCookie(recorder, accessTokenCookieName))
+			},
+		},
+		{
+			name:         "NoRefreshToken",
+			setupCookies: func(request *http.Request, refreshToken string) {},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			server.RegisterRoutes()
+
+			session, refreshToken := randomSession(t, server.tokenMaker, user, time.Minute)
+			tc.buildStubs(storage)
+			storage.EXPECT().
+				GetSession(gomock.Any(), gomock.Eq(session.ID)).
+				AnyTimes().
+				Return(session, nil)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/tokens/renew_access", http.NoBody)
+			require.NoError(t, err)
+			request.Header.Set("User-Agent", testUserAgent)
+			request.RemoteAddr = testClientIP + ":1234"
+			tc.setupCookies(request, refreshToken)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestLogoutClearsCookies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mockdb.NewMockStorage(ctrl)
+	stubTokenNotRevoked(storage)
+	stubAuditEvents(storage)
+	storage.EXPECT().
+		BlockSession(gomock.Any(), gomock.Any()).
+		Times(0)
+	storage.EXPECT().
+		RevokeToken(gomock.Any(), gomock.Any()).
+		Times(1)
+
+	server, err := NewServer(storage)
+	require.NoError(t, err)
+	server.RegisterRoutes()
+
+	csrfToken, err := newCSRFToken()
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/users/logout", http.NoBody)
+	require.NoError(t, err)
+	addCookieAuthorization(t, request, server.tokenMaker, csrfToken)
+	request.Header.Set(csrfHeaderKey, csrfToken)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	for _, name := range []string{accessTokenCookieName, refreshTokenCookieName, csrfCookieName} {
+		cookie := findCookie(recorder, name)
+		require.NotNil(t, cookie)
+		require.Empty(t, cookie.Value)
+		require.Negative(t, cookie.MaxAge)
+	}
+}
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/mailer"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+)
+
+const emailVerificationDuration = 24 * time.Hour
+
+var (
+	errEmailConflict            = errors.New("email already exists")
+	errEmailNotSet              = errors.New("user has no email address")
+	errEmailAlreadyVerified     = errors.New("email address is already verified")
+	errInvalidEmailVerification = errors.New("invalid or expired email verification link")
+)
+
+// normalizeEmail lowercases the address so that the unique constraint on
+// users.email is case-insensitive.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// sendEmailVerification records a one-time verification for the current email
+// of the user and mails a signed link to it.
+func (s *Server) sendEmailVerification(ctx *gin.Context, user store.User) error {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return err
+	}
+
+	verification, err := s.storage.CreateEmailVerification(ctx, store.CreateEmailVerificationParams{
+		ID:       id,
+		UserID:   user.ID,
+		Email:    user.Email.String,
+		ExpireAt: time.Now().Add(emailVerificationDuration),
+	})
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf(
+		"%s/users/email/verify?token=%s",
+		s.publicBaseURL,
+		url.QueryEscape(s.emailSigner.Sign(verification.ID, verification.ExpireAt)),
+	)
+
+	return s.mailer.Send(ctx, mailer.Email{
+		To:      verification.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nOpen this link to verify your email address:\n%s\n\nThe link expires at %s and can only be used once.",
+			user.Username,
+			link,
+			verification.ExpireAt.Format(time.RFC3339),
+		),
+	})
+}
+
+type changeEmailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// changeEmailHandler sets a new address and marks it unverified until the link
+// sent to it is opened.
+func (s *Server) changeEmailHandler(ctx *gin.Context) {
+	var req changeEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	user, err := s.storage.UpdateUserEmail(ctx, store.UpdateUserEmailParams{
+		ID: authPayload.UserID,
+		Email: pgtype.Text{
+			String: normalizeEmail(req.Email),
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errUserNotFound))
+			return
+		}
+		if store.ErrorCode(err) == store.UniqueViolation {
+			ctx.JSON(http.StatusConflict, errorResponse(errEmailConflict))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = s.sendEmailVerification(ctx, user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(newUserResponse(user)))
+}
+
+func (s *Server) resendEmailVerificationHandler(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	user, ok := s.getAuthenticatedUser(ctx, authPayload)
+	if !ok {
+		return
+	}
+
+	if !user.Email.Valid {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errEmailNotSet))
+		return
+	}
+
+	if user.EmailVerifiedAt.Valid {
+		ctx.JSON(http.StatusConflict, errorResponse(errEmailAlreadyVerified))
+		return
+	}
+
+	err := s.sendEmailVerification(ctx, user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, successResponse(nil))
+}
+
+type verifyEmailRequest struct {
+	Token string `form:"token" binding:"required"`
+}
+
+func (s *Server) verifyEmailHandler(ctx *gin.Context) {
+	var req verifyEmailRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	id, err := s.emailSigner.Verify(req.Token)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidEmailVerification))
+		return
+	}
+
+	verification, err := s.storage.ConsumeEmailVerification(ctx, id)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidEmailVerification))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// The update only matches while the user still has the address the link
+	// was sent to, so links for a replaced address are rejected.
+	user, err := s.storage.VerifyUserEmail(ctx, store.VerifyUserEmailParams{
+		ID: verification.UserID,
+		Email: pgtype.Text{
+			String: verification.Email,
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidEmailVerification))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(newUserResponse(user)))
+}
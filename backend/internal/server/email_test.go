@@ -0,0 +1,524 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/mailer"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type recordingMailer struct {
+	mu     sync.Mutex
+	emails []mailer.Email
+	err    error
+}
+
+func (m *recordingMailer) Send(_ context.Context, email mailer.Email) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+	m.emails = append(m.emails, email)
+	return nil
+}
+
+// verificationTokenFromEmail extracts the token from the link in a
+// verification email.
+func verificationTokenFromEmail(t *testing.T, email mailer.Email) string {
+	for _, field := range strings.Fields(email.Body) {
+		if strings.Contains(field, "/users/email/verify?") {
+			link, err := url.Parse(field)
+			require.NoError(t, err)
+			return link.Query().Get("token")
+		}
+	}
+	t.Fatal("verification link not found")
+	return ""
+}
+
+func stubEmailVerification(storage *mockdb.MockStorage) {
+	storage.EXPECT().
+		CreateEmailVerification(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ any, arg store.CreateEmailVerificationParams) (store.EmailVerification, error) {
+			return store.EmailVerification{
+				ID:       arg.ID,
+				UserID:   arg.UserID,
+				Email:    arg.Email,
+				ExpireAt: arg.ExpireAt,
+			}, nil
+		})
+}
+
+func randomUserWithEmail(t *testing.T) (store.User, string) {
+	user, password := randomUser(t)
+	user.Email = pgtype.Text{
+		String: fmt.Sprintf("%s@example.com", strings.ToLower(user.Username)),
+		Valid:  true,
+	}
+	return user, password
+}
+
+func TestCreateUserWithEmail(t *testing.T) {
+	user, password := randomUserWithEmail(t)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, m *recordingMailer)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.CreateUserParams) (store.User, error) {
+						require.Equal(t, user.Email, arg.Email)
+						return user, nil
+					})
+				stubEmailVerification(storage)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, m *recordingMailer) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Len(t, m.emails, 1)
+				require.Equal(t, user.Email.String, m.emails[0].To)
+			},
+		},
+		{
+			name: "EmailConflict",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.User{}, &pgconn.PgError{
+						Code:           store.UniqueViolation,
+						ConstraintName: "users_email_key",
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, m *recordingMailer) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errEmailConflict.Error())
+				require.Empty(t, m.emails)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+			stubAuditEvents(storage)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			m := &recordingMailer{}
+			server.SetMailer(m)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"username": user.Username,
+				"password": password,
+				"email":    strings.ToUpper(user.Email.String),
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder, m)
+		})
+	}
+}
+
+func TestChangeEmailHandler(t *testing.T) {
+	user, _ := randomUserWithEmail(t)
+	newEmail := "New.Address@Example.com"
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		mailerErr     error
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder, m *recordingMailer)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"email": newEmail},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				arg := store.UpdateUserEmailParams{
+					ID: user.ID,
+					Email: pgtype.Text{
+						String: "new.address@example.com",
+						Valid:  true,
+					},
+				}
+				updatedUser := user
+				updatedUser.Email = arg.Email
+				storage.EXPECT().
+					UpdateUserEmail(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(updatedUser, nil)
+				stubEmailVerification(storage)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder, m *recordingMailer) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Len(t, m.emails, 1)
+				require.Equal(t, "new.address@example.com", m.emails[0].To)
+
+				_, err := server.emailSigner.Verify(verificationTokenFromEmail(t, m.emails[0]))
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "InvalidEmail",
+			body: gin.H{"email": "not-an-email"},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpdateUserEmail(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder, m *recordingMailer) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "EmailConflict",
+			body: gin.H{"email": newEmail},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpdateUserEmail(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.User{}, store.ErrUniqueViolation)
+				storage.EXPECT().
+					CreateEmailVerification(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder, m *recordingMailer) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Empty(t, m.emails)
+			},
+		},
+		{
+			name:      "MailerError",
+			body:      gin.H{"email": newEmail},
+			mailerErr: errors.New("connection refused"),
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpdateUserEmail(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(user, nil)
+				stubEmailVerification(storage)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder, m *recordingMailer) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+			stubTokenNotRevoked(storage)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			m := &recordingMailer{err: tc.mailerErr}
+			server.SetMailer(m)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, "/users/me/email", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, server, recorder, m)
+		})
+	}
+}
+
+func TestResendEmailVerificationHandler(t *testing.T) {
+	user, _ := randomUserWithEmail(t)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, m *recordingMailer)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+				stubEmailVerification(storage)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, m *recordingMailer) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				require.Len(t, m.emails, 1)
+				require.Equal(t, user.Email.String, m.emails[0].To)
+			},
+		},
+		{
+			name: "NoEmail",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				userWithoutEmail := user
+				userWithoutEmail.Email = pgtype.Text{}
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(userWithoutEmail, nil)
+				storage.EXPECT().
+					CreateEmailVerification(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, m *recordingMailer) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyVerified",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				verifiedUser := user
+				verifiedUser.EmailVerifiedAt = pgtype.Timestamptz{
+					Time:  time.Now(),
+					Valid: true,
+				}
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(verifiedUser, nil)
+				storage.EXPECT().
+					CreateEmailVerification(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, m *recordingMailer) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					CreateEmailVerification(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.EmailVerification{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, m *recordingMailer) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Empty(t, m.emails)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+			stubTokenNotRevoked(storage)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			m := &recordingMailer{}
+			server.SetMailer(m)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/users/me/email/verification", nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder, m)
+		})
+	}
+}
+
+func TestVerifyEmailHandler(t *testing.T) {
+	user, _ := randomUserWithEmail(t)
+	verification := store.EmailVerification{
+		ID:       uuid.New(),
+		UserID:   user.ID,
+		Email:    user.Email.String,
+		ExpireAt: time.Now().Add(emailVerificationDuration),
+	}
+
+	testCases := []struct {
+		name          string
+		buildToken    func(server *Server) string
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildToken: func(server *Server) string {
+				return server.emailSigner.Sign(verification.ID, verification.ExpireAt)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					ConsumeEmailVerification(gomock.Any(), gomock.Eq(verification.ID)).
+					Times(1).
+					Return(verification, nil)
+
+				verifiedUser := user
+				verifiedUser.EmailVerifiedAt = pgtype.Timestamptz{
+					Time:  time.Now(),
+					Valid: true,
+				}
+				storage.EXPECT().
+					VerifyUserEmail(gomock.Any(), gomock.Eq(store.VerifyUserEmailParams{
+						ID:    user.ID,
+						Email: user.Email,
+					})).
+					Times(1).
+					Return(verifiedUser, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "TamperedToken",
+			buildToken: func(server *Server) string {
+				return server.emailSigner.Sign(verification.ID, verification.ExpireAt) + "x"
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					ConsumeEmailVerification(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ExpiredToken",
+			buildToken: func(server *Server) string {
+				return server.emailSigner.Sign(verification.ID, time.Now().Add(-time.Minute))
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					ConsumeEmailVerification(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyUsed",
+			buildToken: func(server *Server) string {
+				return server.emailSigner.Sign(verification.ID, verification.ExpireAt)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					ConsumeEmailVerification(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.EmailVerification{}, store.ErrRecordNotFound)
+				storage.EXPECT().
+					VerifyUserEmail(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "EmailChanged",
+			buildToken: func(server *Server) string {
+				return server.emailSigner.Sign(verification.ID, verification.ExpireAt)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					ConsumeEmailVerification(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(verification, nil)
+				storage.EXPECT().
+					VerifyUserEmail(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.User{}, store.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "MissingToken",
+			buildToken: func(server *Server) string {
+				return ""
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					ConsumeEmailVerification(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			verifyURL := "/users/email/verify?token=" + url.QueryEscape(tc.buildToken(server))
+			request, err := http.NewRequest(http.MethodGet, verifyURL, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
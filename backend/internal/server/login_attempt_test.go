@@ -0,0 +1,30 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginBackoff(t *testing.T) {
+	testCases := []struct {
+		failedCount int32
+		threshold   int32
+		expected    time.Duration
+	}{
+		{1, loginUsernameLockoutThreshold, 0},
+		{loginDelayThreshold - 1, loginUsernameLockoutThreshold, 0},
+		{loginDelayThreshold, loginUsernameLockoutThreshold, time.Second},
+		{loginDelayThreshold + 1, loginUsernameLockoutThreshold, 2 * time.Second},
+		{loginDelayThreshold + 3, loginUsernameLockoutThreshold, 8 * time.Second},
+		{loginUsernameLockoutThreshold - 1, loginUsernameLockoutThreshold, loginMaxDelay},
+		{loginUsernameLockoutThreshold, loginUsernameLockoutThreshold, loginLockoutDuration},
+		{loginIPLockoutThreshold - 1, loginIPLockoutThreshold, loginMaxDelay},
+		{loginIPLockoutThreshold, loginIPLockoutThreshold, loginLockoutDuration},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, loginBackoff(tc.failedCount, tc.threshold))
+	}
+}
@@ -0,0 +1,457 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+)
+
+const (
+	totpIssuer = "Task Management"
+	// An MFA challenge bridges the password and code steps of a login.
+	mfaChallengeDuration = 5 * time.Minute
+	// Wrong codes after which a challenge is used up and the password has to
+	// be entered again.
+	mfaChallengeMaxAttempts = 5
+)
+
+var (
+	errTOTPAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	errTOTPNotEnrolled    = errors.New("no two-factor authentication enrollment in progress")
+	errTOTPNotEnabled     = errors.New("two-factor authentication is not enabled")
+	errInvalidMFACode     = errors.New("invalid two-factor authentication code")
+	errInvalidMFAToken    = errors.New("invalid or expired MFA token")
+)
+
+// totpEnabled reports whether the user has a confirmed TOTP credential. An
+// enrollment that was never confirmed does not count.
+func (s *Server) totpEnabled(ctx *gin.Context, userID int64) (bool, error) {
+	credential, err := s.storage.GetTOTPCredential(ctx, userID)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return credential.ConfirmedAt.Valid, nil
+}
+
+// replaceRecoveryCodes invalidates every recovery code of the user and returns
+// a fresh set to show once. The old codes are only dropped together with the
+// creation of the new ones, so that a failure never leaves the user without
+// any.
+func (s *Server) replaceRecoveryCodes(ctx *gin.Context, userID int64) ([]string, error) {
+	codes, hashes, err := token.NewRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.storage.ExecTx(ctx, func(q store.Querier) error {
+		if err := q.DeleteRecoveryCodes(ctx, userID); err != nil {
+			return err
+		}
+
+		return q.CreateRecoveryCodes(ctx, store.CreateRecoveryCodesParams{
+			UserID:     userID,
+			CodeHashes: hashes,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+type mfaStatusResponse struct {
+	TOTPEnabled            bool  `json:"totp_enabled"`
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+}
+
+func (s *Server) getMFAStatusHandler(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	enabled, err := s.totpEnabled(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := mfaStatusResponse{TOTPEnabled: enabled}
+	if enabled {
+		rsp.RecoveryCodesRemaining, err = s.storage.CountUnusedRecoveryCodes(ctx, authPayload.UserID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp))
+}
+
+type enrollTOTPResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+// enrollTOTPHandler starts an enrollment with a new secret. TOTP is only
+// required at login once the enrollment is confirmed with a code; enrolling
+// again before that replaces the secret.
+func (s *Server) enrollTOTPHandler(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	secret, err := token.NewTOTPSecret()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	credential, err := s.storage.UpsertPendingTOTPCredential(ctx, store.UpsertPendingTOTPCredentialParams{
+		UserID: authPayload.UserID,
+		Secret: secret,
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusConflict, errorResponse(errTOTPAlreadyEnabled))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, successResponse(enrollTOTPResponse{
+		Secret:     credential.Secret,
+		OtpauthURI: token.TOTPURI(totpIssuer, authPayload.Username, credential.Secret),
+	}))
+}
+
+type confirmTOTPRequest struct {
+	Code string `json:"code" binding:"required,numeric"`
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func (s *Server) confirmTOTPHandler(ctx *gin.Context) {
+	var req confirmTOTPRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	credential, err := s.storage.GetTOTPCredential(ctx, authPayload.UserID)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errTOTPNotEnrolled))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if credential.ConfirmedAt.Valid {
+		ctx.JSON(http.StatusConflict, errorResponse(errTOTPAlreadyEnabled))
+		return
+	}
+
+	step, err := token.VerifyTOTP(credential.Secret, req.Code, time.Now())
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidMFACode))
+		return
+	}
+
+	_, err = s.storage.ConfirmTOTPCredential(ctx, store.ConfirmTOTPCredentialParams{
+		Step:   step,
+		UserID: authPayload.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusConflict, errorResponse(errTOTPAlreadyEnabled))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	codes, err := s.replaceRecoveryCodes(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(recoveryCodesResponse{RecoveryCodes: codes}))
+}
+
+type mfaPasswordRequest struct {
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// checkCurrentPassword binds a password confirmation and checks it against
+// the authenticated user. It reports whether the request may proceed.
+func (s *Server) checkCurrentPassword(ctx *gin.Context) bool {
+	var req mfaPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	user, ok := s.getAuthenticatedUser(ctx, authPayload)
+	if !ok {
+		return false
+	}
+
+	err := util.CheckPassword(req.Password, user.HashedPassword)
+	if err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(errIncorrectPassword))
+		return false
+	}
+
+	return true
+}
+
+func (s *Server) disableTOTPHandler(ctx *gin.Context) {
+	if !s.checkCurrentPassword(ctx) {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	err := s.storage.DeleteTOTPCredential(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = s.storage.DeleteRecoveryCodes(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(nil))
+}
+
+func (s *Server) regenerateRecoveryCodesHandler(ctx *gin.Context) {
+	if !s.checkCurrentPassword(ctx) {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	enabled, err := s.totpEnabled(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !enabled {
+		ctx.JSON(http.StatusConflict, errorResponse(errTOTPNotEnabled))
+		return
+	}
+
+	codes, err := s.replaceRecoveryCodes(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(recoveryCodesResponse{RecoveryCodes: codes}))
+}
+
+type mfaChallengeResponse struct {
+	MFARequired      bool      `json:"mfa_required"`
+	MFAToken         string    `json:"mfa_token"`
+	MFATokenExpireAt time.Time `json:"mfa_token_expire_at"`
+}
+
+// startMFAChallenge answers a login with a correct password by a short-lived
+// token that has to be exchanged together with a code at /users/login/mfa.
+func (s *Server) startMFAChallenge(ctx *gin.Context, user store.User) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	challenge, err := s.storage.CreateMFAChallenge(ctx, store.CreateMFAChallengeParams{
+		ID:       id,
+		UserID:   user.ID,
+		ExpireAt: time.Now().Add(mfaChallengeDuration),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(mfaChallengeResponse{
+		MFARequired:      true,
+		MFAToken:         s.mfaSigner.Sign(challenge.ID, challenge.ExpireAt),
+		MFATokenExpireAt: challenge.ExpireAt,
+	}))
+}
+
+type loginMFARequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code" binding:"required_without=RecoveryCode,omitempty,numeric"`
+	RecoveryCode string `json:"recovery_code" binding:"required_without=Code"`
+	UseCookies   bool   `json:"use_cookies"`
+}
+
+// loginMFAHandler completes a login started by loginUserHandler with either a
+// TOTP code or one of the recovery codes.
+func (s *Server) loginMFAHandler(ctx *gin.Context) {
+	var req loginMFARequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	id, err := s.mfaSigner.Verify(req.MFAToken)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidMFAToken))
+		return
+	}
+
+	challenge, err := s.storage.GetActiveMFAChallenge(ctx, id)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidMFAToken))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	user, err := s.storage.GetUserByID(ctx, challenge.UserID)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidMFAToken))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !s.checkLoginLock(ctx, user.Username) {
+		return
+	}
+
+	var accepted bool
+	if len(req.Code) > 0 {
+		accepted, err = s.useTOTPCode(ctx, user.ID, req.Code)
+	} else {
+		accepted, err = s.useRecoveryCode(ctx, user.ID, req.RecoveryCode)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !accepted {
+		s.recordAuditEvent(ctx, user.ID, user.Username, auditEventLoginFailed, "invalid_mfa_code")
+		s.rejectMFACode(ctx, challenge.ID, user.Username)
+		return
+	}
+
+	rows, err := s.storage.ConsumeMFAChallenge(ctx, challenge.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Another request completed or exhausted the challenge in the meantime.
+	if rows == 0 {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidMFAToken))
+		return
+	}
+
+	err = s.storage.ClearLoginAttempts(ctx, store.ClearLoginAttemptsParams{
+		Scope: loginScopeUsername,
+		Key:   user.Username,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := s.createLoginSession(ctx, user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	s.respondLogin(ctx, rsp, req.UseCookies)
+}
+
+// useTOTPCode accepts a code at most once by only moving the last used time
+// step forward.
+func (s *Server) useTOTPCode(ctx *gin.Context, userID int64, code string) (bool, error) {
+	credential, err := s.storage.GetTOTPCredential(ctx, userID)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if !credential.ConfirmedAt.Valid {
+		return false, nil
+	}
+
+	step, err := token.VerifyTOTP(credential.Secret, code, time.Now())
+	if err != nil {
+		return false, nil
+	}
+
+	rows, err := s.storage.UseTOTPStep(ctx, store.UseTOTPStepParams{
+		Step:   step,
+		UserID: userID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (s *Server) useRecoveryCode(ctx *gin.Context, userID int64, code string) (bool, error) {
+	rows, err := s.storage.UseRecoveryCode(ctx, store.UseRecoveryCodeParams{
+		UserID:   userID,
+		CodeHash: token.HashRecoveryCode(code),
+	})
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// rejectMFACode counts a wrong code against the challenge and, like a wrong
+// password, against the username and client IP so that codes cannot be
+// guessed by starting new challenges.
+func (s *Server) rejectMFACode(ctx *gin.Context, challengeID uuid.UUID, username string) {
+	_, err := s.storage.RecordMFAChallengeFailure(ctx, store.RecordMFAChallengeFailureParams{
+		MaxAttempts: mfaChallengeMaxAttempts,
+		ID:          challengeID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if err := s.recordLoginFailure(ctx, username); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidMFACode))
+}
@@ -0,0 +1,859 @@
+package server
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func stubTOTPNotEnabled(storage *mockdb.MockStorage) {
+	storage.EXPECT().
+		GetTOTPCredential(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(store.TotpCredential{}, store.ErrRecordNotFound)
+}
+
+func randomTOTPCredential(t *testing.T, userID int64, confirmed bool) store.TotpCredential {
+	secret, err := token.NewTOTPSecret()
+	require.NoError(t, err)
+
+	credential := store.TotpCredential{
+		UserID:    userID,
+		Secret:    secret,
+		CreatedAt: time.Now(),
+	}
+	if confirmed {
+		credential.ConfirmedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		credential.LastUsedStep = token.TOTPStep(time.Now()) - 10
+	}
+	return credential
+}
+
+func currentTOTPCode(t *testing.T, secret string) string {
+	code, err := token.TOTPCode(secret, token.TOTPStep(time.Now()))
+	require.NoError(t, err)
+	return code
+}
+
+func requireBodyMatchRecoveryCodes(t *testing.T, body *bytes.Buffer, hashes []string) {
+	var rsp struct {
+		Data recoveryCodesResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body.Bytes(), &rsp))
+	require.Len(t, rsp.Data.RecoveryCodes, token.RecoveryCodeCount)
+
+	for i, code := range rsp.Data.RecoveryCodes {
+		require.Equal(t, hashes[i], token.HashRecoveryCode(code))
+	}
+}
+
+func TestEnrollTOTPHandler(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpsertPendingTOTPCredential(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.UpsertPendingTOTPCredentialParams) (store.TotpCredential, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.NotEmpty(t, arg.Secret)
+						return store.TotpCredential{UserID: arg.UserID, Secret: arg.Secret}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var rsp struct {
+					Data enrollTOTPResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.NotEmpty(t, rsp.Data.Secret)
+
+				uri, err := url.Parse(rsp.Data.OtpauthURI)
+				require.NoError(t, err)
+				require.Equal(t, "otpauth", uri.Scheme)
+				require.Equal(t, rsp.Data.Secret, uri.Query().Get("secret"))
+				require.Contains(t, uri.Path, user.Username)
+			},
+		},
+		{
+			name: "AlreadyEnabled",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpsertPendingTOTPCredential(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.TotpCredential{}, store.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpsertPendingTOTPCredential(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpsertPendingTOTPCredential(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.TotpCredential{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+			stubTokenNotRevoked(storage)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/users/me/mfa/totp", nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestConfirmTOTPHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	credential := randomTOTPCredential(t, user.ID, false)
+
+	// Codes are only accepted one step around the current time.
+	staleCode, err := token.TOTPCode(credential.Secret, token.TOTPStep(time.Now())-5)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recoder *httptest.ResponseRecorder, hashes []string)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"code": currentTOTPCode(t, credential.Secret),
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(credential, nil)
+				storage.EXPECT().
+					ConfirmTOTPCredential(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.ConfirmTOTPCredentialParams) (store.TotpCredential, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.InDelta(t, token.TOTPStep(time.Now()), arg.Step, 1)
+						return credential, nil
+					})
+				storage.EXPECT().
+					DeleteRecoveryCodes(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, hashes []string) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchRecoveryCodes(t, recorder.Body, hashes)
+			},
+		},
+		{
+			name: "StaleCode",
+			body: gin.H{
+				"code": staleCode,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(credential, nil)
+				storage.EXPECT().
+					ConfirmTOTPCredential(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, hashes []string) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotEnrolled",
+			body: gin.H{
+				"code": "123456",
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTOTPCredential(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.TotpCredential{}, store.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, hashes []string) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyEnabled",
+			body: gin.H{
+				"code": "123456",
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTOTPCredential(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(randomTOTPCredential(t, user.ID, true), nil)
+				storage.EXPECT().
+					ConfirmTOTPCredential(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, hashes []string) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "MissingCode",
+			body: gin.H{},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTOTPCredential(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, hashes []string) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+			stubTokenNotRevoked(storage)
+			stubExecTx(storage)
+
+			var hashes []string
+			storage.EXPECT().
+				CreateRecoveryCodes(gomock.Any(), gomock.Any()).
+				AnyTimes().
+				DoAndReturn(func(_ any, arg store.CreateRecoveryCodesParams) error {
+					require.Equal(t, user.ID, arg.UserID)
+					hashes = arg.CodeHashes
+					return nil
+				})
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/me/mfa/totp/confirm", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder, hashes)
+		})
+	}
+}
+
+func TestDisableTOTPHandler(t *testing.T) {
+	user, password := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"password": password,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					DeleteTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1)
+				storage.EXPECT().
+					DeleteRecoveryCodes(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "IncorrectPassword",
+			body: gin.H{
+				"password": "incorrect",
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					DeleteTOTPCredential(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{
+				"password": password,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					DeleteTOTPCredential(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+				storage.EXPECT().
+					DeleteRecoveryCodes(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+			stubTokenNotRevoked(storage)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodDelete, "/users/me/mfa/totp", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestRegenerateRecoveryCodesHandler(t *testing.T) {
+	user, password := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recoder *httptest.ResponseRecorder, hashes []string)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(randomTOTPCredential(t, user.ID, true), nil)
+				storage.EXPECT().
+					DeleteRecoveryCodes(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, hashes []string) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchRecoveryCodes(t, recorder.Body, hashes)
+			},
+		},
+		{
+			// The deletion of the old codes is rolled back with the failed
+			// insert, so that they keep working.
+			name: "CreateError",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(randomTOTPCredential(t, user.ID, true), nil)
+				storage.EXPECT().
+					DeleteRecoveryCodes(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1)
+				storage.EXPECT().
+					CreateRecoveryCodes(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, hashes []string) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "NotEnabled",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(randomTOTPCredential(t, user.ID, false), nil)
+				storage.EXPECT().
+					DeleteRecoveryCodes(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, hashes []string) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+			stubTokenNotRevoked(storage)
+			stubExecTx(storage)
+
+			storage.EXPECT().
+				GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+				Times(1).
+				Return(user, nil)
+
+			var hashes []string
+			storage.EXPECT().
+				CreateRecoveryCodes(gomock.Any(), gomock.Any()).
+				AnyTimes().
+				DoAndReturn(func(_ any, arg store.CreateRecoveryCodesParams) error {
+					hashes = arg.CodeHashes
+					return nil
+				})
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"password": password})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/me/mfa/recovery_codes", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder, hashes)
+		})
+	}
+}
+
+func TestGetMFAStatusHandler(t *testing.T) {
+	user, _ := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mockdb.NewMockStorage(ctrl)
+	storage.EXPECT().
+		GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).
+		Times(1).
+		Return(randomTOTPCredential(t, user.ID, true), nil)
+	storage.EXPECT().
+		CountUnusedRecoveryCodes(gomock.Any(), gomock.Eq(user.ID)).
+		Times(1).
+		Return(int64(7), nil)
+	stubTokenNotRevoked(storage)
+
+	server, err := NewServer(storage)
+	require.NoError(t, err)
+	server.RegisterRoutes()
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/users/me/mfa", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp struct {
+		Data mfaStatusResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.True(t, rsp.Data.TOTPEnabled)
+	require.Equal(t, int64(7), rsp.Data.RecoveryCodesRemaining)
+}
+
+func TestLoginMFAHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	credential := randomTOTPCredential(t, user.ID, true)
+	challenge := store.MfaChallenge{
+		ID:       uuid.New(),
+		UserID:   user.ID,
+		ExpireAt: time.Now().Add(mfaChallengeDuration),
+	}
+
+	stubActiveChallenge := func(storage *mockdb.MockStorage) {
+		storage.EXPECT().
+			GetActiveMFAChallenge(gomock.Any(), gomock.Eq(challenge.ID)).
+			Times(1).
+			Return(challenge, nil)
+		storage.EXPECT().
+			GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+			Times(1).
+			Return(user, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		body          func(mfaToken string) gin.H
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OKWithCode",
+			body: func(mfaToken string) gin.H {
+				return gin.H{
+					"mfa_token": mfaToken,
+					"code":      currentTOTPCode(t, credential.Secret),
+				}
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubActiveChallenge(storage)
+				storage.EXPECT().
+					GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(credential, nil)
+				storage.EXPECT().
+					UseTOTPStep(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.UseTOTPStepParams) (int64, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.InDelta(t, token.TOTPStep(time.Now()), arg.Step, 1)
+						return 1, nil
+					})
+				storage.EXPECT().
+					ConsumeMFAChallenge(gomock.Any(), gomock.Eq(challenge.ID)).
+					Times(1).
+					Return(int64(1), nil)
+				storage.EXPECT().
+					ClearLoginAttempts(gomock.Any(), gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp struct {
+					Data loginResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.NotEmpty(t, rsp.Data.AccessToken)
+				require.NotEmpty(t, rsp.Data.RefreshToken)
+				require.Equal(t, user.ID, rsp.Data.User.ID)
+			},
+		},
+		{
+			name: "OKWithRecoveryCode",
+			body: func(mfaToken string) gin.H {
+				return gin.H{
+					"mfa_token":     mfaToken,
+					"recovery_code": "ABCDE-FGHJK",
+				}
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubActiveChallenge(storage)
+				storage.EXPECT().
+					UseRecoveryCode(gomock.Any(), gomock.Eq(store.UseRecoveryCodeParams{
+						UserID:   user.ID,
+						CodeHash: token.HashRecoveryCode("abcde-fghjk"),
+					})).
+					Times(1).
+					Return(int64(1), nil)
+				storage.EXPECT().
+					ConsumeMFAChallenge(gomock.Any(), gomock.Eq(challenge.ID)).
+					Times(1).
+					Return(int64(1), nil)
+				storage.EXPECT().
+					ClearLoginAttempts(gomock.Any(), gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "ReplayedCode",
+			body: func(mfaToken string) gin.H {
+				return gin.H{
+					"mfa_token": mfaToken,
+					"code":      currentTOTPCode(t, credential.Secret),
+				}
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubActiveChallenge(storage)
+				storage.EXPECT().
+					GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(credential, nil)
+				storage.EXPECT().
+					UseTOTPStep(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+				storage.EXPECT().
+					RecordMFAChallengeFailure(gomock.Any(), gomock.Eq(store.RecordMFAChallengeFailureParams{
+						MaxAttempts: mfaChallengeMaxAttempts,
+						ID:          challenge.ID,
+					})).
+					Times(1).
+					Return(int32(1), nil)
+				storage.EXPECT().
+					RecordLoginFailure(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(int32(1), nil)
+				storage.EXPECT().
+					ConsumeMFAChallenge(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownRecoveryCode",
+			body: func(mfaToken string) gin.H {
+				return gin.H{
+					"mfa_token":     mfaToken,
+					"recovery_code": "abcde-fghjk",
+				}
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubActiveChallenge(storage)
+				storage.EXPECT().
+					UseRecoveryCode(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+				storage.EXPECT().
+					RecordMFAChallengeFailure(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int32(1), nil)
+				storage.EXPECT().
+					RecordLoginFailure(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(int32(1), nil)
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ChallengeCompletedConcurrently",
+			body: func(mfaToken string) gin.H {
+				return gin.H{
+					"mfa_token":     mfaToken,
+					"recovery_code": "abcde-fghjk",
+				}
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubActiveChallenge(storage)
+				storage.EXPECT().
+					UseRecoveryCode(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(1), nil)
+				storage.EXPECT().
+					ConsumeMFAChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ChallengeNotActive",
+			body: func(mfaToken string) gin.H {
+				return gin.H{
+					"mfa_token": mfaToken,
+					"code":      "123456",
+				}
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetActiveMFAChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.MfaChallenge{}, store.ErrRecordNotFound)
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "TamperedToken",
+			body: func(mfaToken string) gin.H {
+				return gin.H{
+					"mfa_token": mfaToken + "x",
+					"code":      "123456",
+				}
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetActiveMFAChallenge(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Locked",
+			body: func(mfaToken string) gin.H {
+				return gin.H{
+					"mfa_token": mfaToken,
+					"code":      "123456",
+				}
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubActiveChallenge(storage)
+				storage.EXPECT().
+					GetLoginLock(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(time.Now().Add(time.Minute), nil)
+				storage.EXPECT().
+					GetTOTPCredential(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+			},
+		},
+		{
+			name: "MissingCode",
+			body: func(mfaToken string) gin.H {
+				return gin.H{
+					"mfa_token": mfaToken,
+				}
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetActiveMFAChallenge(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+			stubAuditEvents(storage)
+			stubLoginNotLocked(storage)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			mfaToken := server.mfaSigner.Sign(challenge.ID, challenge.ExpireAt)
+			data, err := json.Marshal(tc.body(mfaToken))
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/login/mfa", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -4,9 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
 	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
 )
 
@@ -16,33 +21,60 @@ const (
 	authorizationPayloadKey = "authorization_payload"
 )
 
-func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+const (
+	workspaceHeaderKey = "X-Workspace-ID"
+	workspaceParamKey  = "workspace_id"
+	workspaceMemberKey = "workspace_member"
+)
+
+// authMiddleware accepts access tokens issued at login, from the
+// Authorization header or the access token cookie, and, when scopes are given,
+// personal access tokens that hold every one of those scopes.
+func authMiddleware(tokenMaker token.Maker, storage store.Storage, scopes ...string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
+		accessToken, fromCookie, status, err := accessTokenFromRequest(ctx)
+		if err != nil {
+			ctx.AbortWithStatusJSON(status, errorResponse(err))
+			return
+		}
 
-		if len(authorizationHeader) == 0 {
-			err := errors.New("authorization header is not provided")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+		// Personal access tokens are never set as cookies.
+		if token.IsPersonalAccessToken(accessToken) && !fromCookie {
+			payload, status, err := verifyPersonalAccessToken(ctx, storage, accessToken, scopes)
+			if err != nil {
+				ctx.AbortWithStatusJSON(status, errorResponse(err))
+				return
+			}
+
+			ctx.Set(authorizationPayloadKey, payload)
+			ctx.Next()
 			return
 		}
 
-		fields := strings.Fields(authorizationHeader)
-		if len(fields) < 2 {
-			err := errors.New("invalid authorization header format")
+		payload, err := tokenMaker.VerifyToken(accessToken)
+		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
 			return
 		}
 
-		authorizationType := strings.ToLower(fields[0])
-		if authorizationType != authorizationTypeBearer {
-			err := fmt.Errorf("unsupported authorization type %s", authorizationType)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+		// Refresh tokens are signed by the same maker but only renew access tokens.
+		if payload.Type != token.TokenTypeAccess {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(errNotAccessToken))
 			return
 		}
 
-		accessToken := fields[1]
-		payload, err := tokenMaker.VerifyToken(accessToken)
+		revoked, err := storage.IsTokenRevoked(ctx, store.IsTokenRevokedParams{
+			ID:       payload.ID,
+			UserID:   payload.UserID,
+			IssuedAt: payload.IssuedAt,
+		})
 		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		if revoked {
+			err := errors.New("token has been revoked")
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
 			return
 		}
@@ -51,3 +83,168 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// accessTokenFromRequest prefers the Authorization header. The cookie is only
+// used without one, and then requires the CSRF token on state-changing
+// requests because the browser sends it along with cross-site requests too.
+func accessTokenFromRequest(ctx *gin.Context) (string, bool, int, error) {
+	authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
+
+	if len(authorizationHeader) == 0 {
+		cookie, err := ctx.Cookie(accessTokenCookieName)
+		if err != nil || len(cookie) == 0 {
+			err := errors.New("authorization header is not provided")
+			return "", false, http.StatusUnauthorized, err
+		}
+
+		if err := checkCSRF(ctx); err != nil {
+			return "", true, http.StatusForbidden, err
+		}
+		return cookie, true, http.StatusOK, nil
+	}
+
+	fields := strings.Fields(authorizationHeader)
+	if len(fields) < 2 {
+		err := errors.New("invalid authorization header format")
+		return "", false, http.StatusUnauthorized, err
+	}
+
+	authorizationType := strings.ToLower(fields[0])
+	if authorizationType != authorizationTypeBearer {
+		err := fmt.Errorf("unsupported authorization type %s", authorizationType)
+		return "", false, http.StatusUnauthorized, err
+	}
+
+	return fields[1], false, http.StatusOK, nil
+}
+
+func verifyPersonalAccessToken(ctx *gin.Context, storage store.Storage, accessToken string, scopes []string) (*token.Payload, int, error) {
+	if len(scopes) == 0 {
+		err := errors.New("personal access tokens are not allowed for this resource")
+		return nil, http.StatusForbidden, err
+	}
+
+	pat, err := storage.GetPersonalAccessTokenByHash(ctx, token.HashPersonalAccessToken(accessToken))
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, http.StatusUnauthorized, token.ErrInvalidToken
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if pat.RevokedAt.Valid {
+		err := errors.New("token has been revoked")
+		return nil, http.StatusUnauthorized, err
+	}
+
+	if pat.ExpireAt.Valid && time.Now().After(pat.ExpireAt.Time) {
+		return nil, http.StatusUnauthorized, token.ErrExpiredToken
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(pat.Scopes, scope) {
+			err := fmt.Errorf("personal access token is missing scope %s", scope)
+			return nil, http.StatusForbidden, err
+		}
+	}
+
+	err = storage.TouchPersonalAccessToken(ctx, pat.ID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	payload := &token.Payload{
+		ID:       pat.ID,
+		UserID:   pat.UserID,
+		Username: pat.Username,
+		Role:     pat.Role,
+		Scopes:   pat.Scopes,
+		IssuedAt: pat.CreatedAt,
+		ExpireAt: pat.ExpireAt.Time,
+	}
+	return payload, http.StatusOK, nil
+}
+
+func requireRole(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+		if !slices.Contains(roles, authPayload.Role) {
+			err := fmt.Errorf("role %s is not allowed to access this resource", authPayload.Role)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// workspaceMiddleware sets the workspace a request is scoped by, taken from
+// the path or, for routes without one, from the X-Workspace-ID header. The
+// authenticated user must be a member of it. Requests without a workspace
+// address the personal tasks of the user.
+func workspaceMiddleware(storage store.Storage) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		value := ctx.Param(workspaceParamKey)
+		if len(value) == 0 {
+			value = ctx.GetHeader(workspaceHeaderKey)
+		}
+		if len(value) == 0 {
+			ctx.Next()
+			return
+		}
+
+		workspaceID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || workspaceID < 1 {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(errInvalidWorkspaceID))
+			return
+		}
+
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		member, err := storage.GetWorkspaceMember(ctx, store.GetWorkspaceMemberParams{
+			WorkspaceID: workspaceID,
+			UserID:      authPayload.UserID,
+		})
+		if err != nil {
+			if errors.Is(err, store.ErrRecordNotFound) {
+				// Workspaces of others are indistinguishable from missing ones.
+				ctx.AbortWithStatusJSON(http.StatusNotFound, errorResponse(errWorkspaceNotFound))
+				return
+			}
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		ctx.Set(workspaceMemberKey, member)
+		ctx.Next()
+	}
+}
+
+// requireWorkspaceRole only lets members with one of the given roles through.
+// It must follow workspaceMiddleware on a route with a workspace in its path.
+func requireWorkspaceRole(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		member := ctx.MustGet(workspaceMemberKey).(store.WorkspaceMember)
+
+		if !slices.Contains(roles, member.Role) {
+			err := fmt.Errorf("workspace role %s is not allowed to access this resource", member.Role)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// currentWorkspaceID returns the workspace set by workspaceMiddleware, which
+// is NULL for requests about personal tasks.
+func currentWorkspaceID(ctx *gin.Context) pgtype.Int8 {
+	member, ok := ctx.Get(workspaceMemberKey)
+	if !ok {
+		return pgtype.Int8{}
+	}
+	return pgtype.Int8{
+		Int64: member.(store.WorkspaceMember).WorkspaceID,
+		Valid: true,
+	}
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"database/sql"
 	"fmt"
 	"math/rand/v2"
 	"net/http"
@@ -9,9 +10,14 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
 	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
 	"github.com/nguyen-duc-loc/task-management/backend/util"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func addAuthorization(
@@ -21,28 +27,43 @@ func addAuthorization(
 	authorizationType string,
 	userID int64,
 	username string,
+	role string,
 	duration time.Duration,
 ) {
-	token, payload, err := tokenMaker.CreateToken(userID, username, duration)
+	accessToken, payload, err := tokenMaker.CreateToken(userID, username, role, token.TokenTypeAccess, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
-	authorizationHeader := fmt.Sprintf("%s %s", authorizationType, token)
+	authorizationHeader := fmt.Sprintf("%s %s", authorizationType, accessToken)
 	request.Header.Set(authorizationHeaderKey, authorizationHeader)
 }
 
+func stubTokenNotRevoked(storage *mockdb.MockStorage) {
+	storage.EXPECT().
+		IsTokenRevoked(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(false, nil)
+}
+
 func TestAuthMiddleware(t *testing.T) {
 	username := util.RandomUsername()
 
 	testCases := []struct {
 		name          string
 		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(storage *mockdb.MockStorage)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name: "OK",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, rand.Int64(), username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, rand.Int64(), username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					IsTokenRevoked(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(false, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -51,6 +72,11 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name:      "NoAuthorization",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					IsTokenRevoked(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
@@ -58,7 +84,12 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "UnsupportedAuthorization",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "unsupported", rand.Int64(), username, time.Minute)
+				addAuthorization(t, request, tokenMaker, "unsupported", rand.Int64(), username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					IsTokenRevoked(gomock.Any(), gomock.Any()).
+					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -67,7 +98,12 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "InvalidAuthorizationFormat",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "invalid", rand.Int64(), username, time.Minute)
+				addAuthorization(t, request, tokenMaker, "invalid", rand.Int64(), username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					IsTokenRevoked(gomock.Any(), gomock.Any()).
+					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -76,23 +112,80 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "ExpiredToken",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, rand.Int64(), username, -time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, rand.Int64(), username, util.UserRole, -time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					IsTokenRevoked(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "RefreshToken",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				refreshToken, _, err := tokenMaker.CreateToken(rand.Int64(), username, util.UserRole, token.TokenTypeRefresh, time.Minute)
+				require.NoError(t, err)
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, refreshToken))
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					IsTokenRevoked(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "RevokedToken",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, rand.Int64(), username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					IsTokenRevoked(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(true, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
+		{
+			name: "RevocationCheckError",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, rand.Int64(), username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					IsTokenRevoked(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(false, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			server, err := NewServer(nil)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			server, err := NewServer(storage)
 			require.NoError(t, err)
 
 			authPath := "/auth"
 			server.router.GET(
 				authPath,
-				authMiddleware(server.tokenMaker),
+				authMiddleware(server.tokenMaker, server.storage),
 				func(ctx *gin.Context) {
 					ctx.JSON(http.StatusOK, gin.H{})
 				},
@@ -108,3 +201,258 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestRequireRole(t *testing.T) {
+	username := util.RandomUsername()
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, rand.Int64(), username, util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "ForbiddenRole",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, rand.Int64(), username, util.UserRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NoRole",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, rand.Int64(), username, "", time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			stubTokenNotRevoked(storage)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+
+			adminPath := "/admin"
+			server.router.GET(
+				adminPath,
+				authMiddleware(server.tokenMaker, server.storage),
+				requireRole(util.AdminRole),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, adminPath, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func randomPersonalAccessToken(t *testing.T, scopes []string) (string, store.GetPersonalAccessTokenByHashRow) {
+	patToken, _, err := token.NewPersonalAccessToken()
+	require.NoError(t, err)
+
+	id, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	return patToken, store.GetPersonalAccessTokenByHashRow{
+		ID:        id,
+		UserID:    rand.Int64(),
+		Scopes:    scopes,
+		Username:  util.RandomUsername(),
+		Role:      util.UserRole,
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestAuthMiddlewarePersonalAccessToken(t *testing.T) {
+	testCases := []struct {
+		name          string
+		routeScopes   []string
+		buildToken    func(t *testing.T) (string, store.GetPersonalAccessTokenByHashRow)
+		buildStubs    func(storage *mockdb.MockStorage, patToken string, pat store.GetPersonalAccessTokenByHashRow)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "OK",
+			routeScopes: []string{scopeTasksRead},
+			buildToken: func(t *testing.T) (string, store.GetPersonalAccessTokenByHashRow) {
+				return randomPersonalAccessToken(t, []string{scopeTasksRead, scopeTasksWrite})
+			},
+			buildStubs: func(storage *mockdb.MockStorage, patToken string, pat store.GetPersonalAccessTokenByHashRow) {
+				storage.EXPECT().
+					GetPersonalAccessTokenByHash(gomock.Any(), gomock.Eq(token.HashPersonalAccessToken(patToken))).
+					Times(1).
+					Return(pat, nil)
+				storage.EXPECT().
+					TouchPersonalAccessToken(gomock.Any(), gomock.Eq(pat.ID)).
+					Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:        "NotAllowedForRoute",
+			routeScopes: nil,
+			buildToken: func(t *testing.T) (string, store.GetPersonalAccessTokenByHashRow) {
+				return randomPersonalAccessToken(t, []string{scopeTasksRead})
+			},
+			buildStubs: func(storage *mockdb.MockStorage, patToken string, pat store.GetPersonalAccessTokenByHashRow) {
+				storage.EXPECT().
+					GetPersonalAccessTokenByHash(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "MissingScope",
+			routeScopes: []string{scopeTasksWrite},
+			buildToken: func(t *testing.T) (string, store.GetPersonalAccessTokenByHashRow) {
+				return randomPersonalAccessToken(t, []string{scopeTasksRead})
+			},
+			buildStubs: func(storage *mockdb.MockStorage, patToken string, pat store.GetPersonalAccessTokenByHashRow) {
+				storage.EXPECT().
+					GetPersonalAccessTokenByHash(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(pat, nil)
+				storage.EXPECT().
+					TouchPersonalAccessToken(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "NotFound",
+			routeScopes: []string{scopeTasksRead},
+			buildToken: func(t *testing.T) (string, store.GetPersonalAccessTokenByHashRow) {
+				return randomPersonalAccessToken(t, []string{scopeTasksRead})
+			},
+			buildStubs: func(storage *mockdb.MockStorage, patToken string, pat store.GetPersonalAccessTokenByHashRow) {
+				storage.EXPECT().
+					GetPersonalAccessTokenByHash(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.GetPersonalAccessTokenByHashRow{}, store.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:        "Revoked",
+			routeScopes: []string{scopeTasksRead},
+			buildToken: func(t *testing.T) (string, store.GetPersonalAccessTokenByHashRow) {
+				patToken, pat := randomPersonalAccessToken(t, []string{scopeTasksRead})
+				pat.RevokedAt = pgtype.Timestamptz{
+					Time:  time.Now().Add(-time.Minute),
+					Valid: true,
+				}
+				return patToken, pat
+			},
+			buildStubs: func(storage *mockdb.MockStorage, patToken string, pat store.GetPersonalAccessTokenByHashRow) {
+				storage.EXPECT().
+					GetPersonalAccessTokenByHash(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(pat, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:        "Expired",
+			routeScopes: []string{scopeTasksRead},
+			buildToken: func(t *testing.T) (string, store.GetPersonalAccessTokenByHashRow) {
+				patToken, pat := randomPersonalAccessToken(t, []string{scopeTasksRead})
+				pat.ExpireAt = pgtype.Timestamptz{
+					Time:  time.Now().Add(-time.Minute),
+					Valid: true,
+				}
+				return patToken, pat
+			},
+			buildStubs: func(storage *mockdb.MockStorage, patToken string, pat store.GetPersonalAccessTokenByHashRow) {
+				storage.EXPECT().
+					GetPersonalAccessTokenByHash(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(pat, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:        "InternalError",
+			routeScopes: []string{scopeTasksRead},
+			buildToken: func(t *testing.T) (string, store.GetPersonalAccessTokenByHashRow) {
+				return randomPersonalAccessToken(t, []string{scopeTasksRead})
+			},
+			buildStubs: func(storage *mockdb.MockStorage, patToken string, pat store.GetPersonalAccessTokenByHashRow) {
+				storage.EXPECT().
+					GetPersonalAccessTokenByHash(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.GetPersonalAccessTokenByHashRow{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			patToken, pat := tc.buildToken(t)
+			tc.buildStubs(storage, patToken, pat)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+
+			authPath := "/auth"
+			server.router.GET(
+				authPath,
+				authMiddleware(server.tokenMaker, server.storage, tc.routeScopes...),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, authPath, nil)
+			require.NoError(t, err)
+
+			request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, patToken))
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+)
+
+var errNotificationNotFound = errors.New("notification not found")
+
+type listNotificationsRequest struct {
+	Unread bool  `form:"unread"`
+	Page   int32 `form:"page" binding:"omitempty,min=1"`
+	Limit  int32 `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+type notificationResponse struct {
+	ID        int64              `json:"id"`
+	Kind      string             `json:"kind"`
+	Subject   string             `json:"subject"`
+	Body      string             `json:"body"`
+	ReadAt    pgtype.Timestamptz `json:"read_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type listNotificationsResponse struct {
+	Total         int64                  `json:"total"`
+	Notifications []notificationResponse `json:"notifications"`
+}
+
+// listNotificationsHandler lists the in-app notifications of the current
+// user, newest first.
+func (s *Server) listNotificationsHandler(ctx *gin.Context) {
+	var req listNotificationsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	notifications, err := s.storage.ListUserNotifications(ctx, store.ListUserNotificationsParams{
+		UserID: authPayload.UserID,
+		Unread: req.Unread,
+		Limit:  req.Limit,
+		Offset: (req.Page - 1) * req.Limit,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := listNotificationsResponse{
+		Notifications: []notificationResponse{},
+	}
+	for _, notification := range notifications {
+		rsp.Total = notification.Total
+		rsp.Notifications = append(rsp.Notifications, notificationResponse{
+			ID:        notification.ID,
+			Kind:      notification.Kind,
+			Subject:   notification.Subject,
+			Body:      notification.Body,
+			ReadAt:    notification.ReadAt,
+			CreatedAt: notification.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp))
+}
+
+type markNotificationReadRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (s *Server) markNotificationReadHandler(ctx *gin.Context) {
+	var req markNotificationReadRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	rows, err := s.storage.MarkNotificationRead(ctx, store.MarkNotificationReadParams{
+		ID:     req.ID,
+		UserID: authPayload.UserID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if rows == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(errNotificationNotFound))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(nil))
+}
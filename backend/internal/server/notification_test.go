@@ -0,0 +1,90 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListNotificationsHandler(t *testing.T) {
+	user, _ := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mockdb.NewMockStorage(ctrl)
+	storage.EXPECT().
+		ListUserNotifications(gomock.Any(), gomock.Eq(store.ListUserNotificationsParams{
+			UserID: user.ID,
+			Unread: true,
+			Limit:  10,
+			Offset: 10,
+		})).
+		Times(1).
+		Return([]store.ListUserNotificationsRow{{
+			ID:        3,
+			UserID:    user.ID,
+			Kind:      "task_reminder",
+			Subject:   "Reminder: Send invoice",
+			Body:      "body",
+			CreatedAt: time.Now(),
+			Total:     11,
+		}}, nil)
+
+	recorder := serveAuthenticatedRequest(t, storage, user, http.MethodGet, "/users/me/notifications?unread=true&page=2&limit=10", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp struct {
+		Data listNotificationsResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, int64(11), rsp.Data.Total)
+	require.Len(t, rsp.Data.Notifications, 1)
+	require.Equal(t, "Reminder: Send invoice", rsp.Data.Notifications[0].Subject)
+}
+
+func TestMarkNotificationReadHandler(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name       string
+		rows       int64
+		expectCode int
+	}{
+		{
+			name:       "OK",
+			rows:       1,
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "NotFound",
+			rows:       0,
+			expectCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			storage.EXPECT().
+				MarkNotificationRead(gomock.Any(), gomock.Eq(store.MarkNotificationReadParams{
+					ID:     3,
+					UserID: user.ID,
+				})).
+				Times(1).
+				Return(tc.rows, nil)
+
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPost, "/users/me/notifications/3/read", nil)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package server
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/oidc"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+)
+
+const (
+	// How long the user has to sign in at the provider.
+	oidcAuthRequestDuration = 10 * time.Minute
+	// The state cookie ties the callback to the browser that started the
+	// login, so that nobody can log a victim into the attacker's account.
+	oidcStateCookieName   = "oidc_state"
+	oidcStateCookiePath   = "/auth/oidc"
+	oidcMaxUsernameLength = 30
+	// Attempts at finding a free username for a provisioned user.
+	oidcUsernameAttempts = 5
+)
+
+var (
+	errOIDCNotConfigured = errors.New("OpenID Connect login is not configured")
+	errInvalidOIDCState  = errors.New("invalid or expired OpenID Connect login state")
+	errOIDCLoginFailed   = errors.New("OpenID Connect login failed")
+)
+
+type oidcLoginRequest struct {
+	// UseCookies is remembered until the callback, which then sets the
+	// session as cookies like a password login with use_cookies does.
+	UseCookies bool `form:"use_cookies"`
+}
+
+func (s *Server) oidcLoginHandler(ctx *gin.Context) {
+	if s.oidcProvider == nil {
+		ctx.JSON(http.StatusNotFound, errorResponse(errOIDCNotConfigured))
+		return
+	}
+
+	var req oidcLoginRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// Abandoned logins are only cleaned up here, so a failure is harmless.
+	if err := s.storage.DeleteExpiredOIDCAuthRequests(ctx); err != nil {
+		log.Printf("cannot delete expired OpenID Connect auth requests: %v", err)
+	}
+
+	var values [3]string
+	for i := range values {
+		value, err := oidc.RandomString()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		values[i] = value
+	}
+	state, nonce, codeVerifier := values[0], values[1], values[2]
+
+	_, err := s.storage.CreateOIDCAuthRequest(ctx, store.CreateOIDCAuthRequestParams{
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		UseCookies:   req.UseCookies,
+		ExpireAt:     time.Now().Add(oidcAuthRequestDuration),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authCodeURL, err := s.oidcProvider.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		ctx.JSON(http.StatusBadGateway, errorResponse(err))
+		return
+	}
+
+	s.setOIDCStateCookie(ctx, state, int(oidcAuthRequestDuration.Seconds()))
+
+	ctx.Redirect(http.StatusFound, authCodeURL)
+}
+
+type oidcCallbackRequest struct {
+	Code             string `form:"code"`
+	State            string `form:"state" binding:"required"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
+
+// oidcCallbackHandler finishes a login started by oidcLoginHandler and
+// answers like loginUserHandler does after the password has been checked.
+func (s *Server) oidcCallbackHandler(ctx *gin.Context) {
+	if s.oidcProvider == nil {
+		ctx.JSON(http.StatusNotFound, errorResponse(errOIDCNotConfigured))
+		return
+	}
+
+	var req oidcCallbackRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	stateCookie, err := ctx.Cookie(oidcStateCookieName)
+	if err != nil || subtle.ConstantTimeCompare([]byte(stateCookie), []byte(req.State)) != 1 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidOIDCState))
+		return
+	}
+	s.setOIDCStateCookie(ctx, "", -1)
+
+	authRequest, err := s.storage.ConsumeOIDCAuthRequest(ctx, req.State)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidOIDCState))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if len(req.Error) > 0 {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("%w: %s %s", errOIDCLoginFailed, req.Error, req.ErrorDescription)))
+		return
+	}
+
+	if len(req.Code) == 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("authorization code is missing")))
+		return
+	}
+
+	rawIDToken, err := s.oidcProvider.Exchange(ctx, req.Code, authRequest.CodeVerifier)
+	if err != nil {
+		if errors.Is(err, oidc.ErrExchangeFailed) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusBadGateway, errorResponse(err))
+		return
+	}
+
+	claims, err := s.oidcProvider.VerifyIDToken(ctx, rawIDToken, authRequest.Nonce)
+	if err != nil {
+		if errors.Is(err, oidc.ErrInvalidIDToken) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusBadGateway, errorResponse(err))
+		return
+	}
+
+	user, err := s.findOrProvisionOIDCUser(ctx, claims)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	s.completeLogin(ctx, user, authRequest.UseCookies)
+}
+
+// setOIDCStateCookie remembers the state of a login in the browser until the
+// callback. It is sent along with the top-level redirect back from the
+// provider, which SameSite=Strict would not allow. A negative maxAge in
+// seconds deletes the cookie.
+func (s *Server) setOIDCStateCookie(ctx *gin.Context, state string, maxAge int) {
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     oidcStateCookieName,
+		Value:    state,
+		Path:     oidcStateCookiePath,
+		Domain:   s.cookieConfig.Domain,
+		MaxAge:   maxAge,
+		Secure:   s.cookieConfig.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// findOrProvisionOIDCUser returns the user linked to the provider subject. An
+// unknown subject is linked to the user with the same email if both sides
+// have verified it, and otherwise gets a new user.
+func (s *Server) findOrProvisionOIDCUser(ctx *gin.Context, claims *oidc.IDTokenClaims) (store.User, error) {
+	issuer := s.oidcProvider.Issuer()
+
+	identity, err := s.storage.GetUserIdentity(ctx, store.GetUserIdentityParams{
+		Issuer:  issuer,
+		Subject: claims.Subject,
+	})
+	if err == nil {
+		return s.storage.GetUserByID(ctx, identity.UserID)
+	}
+	if !errors.Is(err, store.ErrRecordNotFound) {
+		return store.User{}, err
+	}
+
+	var email pgtype.Text
+	if claims.EmailVerified && len(claims.Email) > 0 {
+		email = pgtype.Text{
+			String: normalizeEmail(claims.Email),
+			Valid:  true,
+		}
+
+		user, err := s.storage.GetUserByVerifiedEmail(ctx, email)
+		if err == nil {
+			_, err = s.storage.CreateUserIdentity(ctx, store.CreateUserIdentityParams{
+				UserID:  user.ID,
+				Issuer:  issuer,
+				Subject: claims.Subject,
+				Email:   email,
+			})
+			return user, err
+		}
+		if !errors.Is(err, store.ErrRecordNotFound) {
+			return store.User{}, err
+		}
+	}
+
+	// The user signs in through the provider only; nobody knows this
+	// password, though one can still be set through a password reset.
+	hashedPassword, err := util.HashPassword(util.RandomPrintableString(32))
+	if err != nil {
+		return store.User{}, err
+	}
+
+	username := oidcUsername(claims)
+	for attempt := 0; attempt < oidcUsernameAttempts; attempt++ {
+		user, err := s.storage.CreateUserWithIdentity(ctx, store.CreateUserWithIdentityParams{
+			Username:       username,
+			HashedPassword: hashedPassword,
+			Email:          email,
+			Issuer:         issuer,
+			Subject:        claims.Subject,
+		})
+		if err == nil {
+			s.recordAuditEvent(ctx, user.ID, user.Username, auditEventSignup, "oidc")
+			return store.User(user), nil
+		}
+
+		if store.ErrorCode(err) != store.UniqueViolation {
+			return store.User{}, err
+		}
+
+		switch store.ErrorConstraintName(err) {
+		case "users_username_key":
+			username = oidcUsername(claims) + util.RandomAlphaNumString(4)
+		case "users_email_key":
+			// Another user has claimed the address without verifying it.
+			email = pgtype.Text{}
+		default:
+			return store.User{}, err
+		}
+	}
+
+	return store.User{}, errors.New("cannot find a free username for the new user")
+}
+
+// oidcUsername derives an alphanumeric username from the claims, preferring
+// what the user goes by at the provider.
+func oidcUsername(claims *oidc.IDTokenClaims) string {
+	localPart, _, _ := strings.Cut(claims.Email, "@")
+
+	for _, candidate := range []string{claims.PreferredUsername, localPart, claims.Name} {
+		username := strings.Map(func(r rune) rune {
+			if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+				return r
+			}
+			return -1
+		}, candidate)
+
+		if len(username) > oidcMaxUsernameLength {
+			username = username[:oidcMaxUsernameLength]
+		}
+		if len(username) > 0 {
+			return username
+		}
+	}
+
+	return "user"
+}
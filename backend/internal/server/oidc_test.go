@@ -0,0 +1,589 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/oidc"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/oidc/oidctest"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestOIDCProvider(t *testing.T) (*oidc.Provider, *oidctest.Issuer) {
+	issuer, err := oidctest.NewIssuer("task-app", "s3cret")
+	require.NoError(t, err)
+	t.Cleanup(issuer.Close)
+
+	provider := oidc.NewProvider(util.OIDCConfig{
+		IssuerURL:    issuer.URL(),
+		ClientID:     "task-app",
+		ClientSecret: "s3cret",
+		RedirectURL:  "http://localhost:8080/auth/oidc/callback",
+		Scopes:       []string{"openid", "profile", "email"},
+	}, nil)
+	return provider, issuer
+}
+
+func randomOIDCAuthRequest(t *testing.T) store.OidcAuthRequest {
+	var values [3]string
+	for i := range values {
+		value, err := oidc.RandomString()
+		require.NoError(t, err)
+		values[i] = value
+	}
+
+	return store.OidcAuthRequest{
+		State:        values[0],
+		Nonce:        values[1],
+		CodeVerifier: values[2],
+		ExpireAt:     time.Now().Add(oidcAuthRequestDuration),
+	}
+}
+
+func TestOIDCLoginHandler(t *testing.T) {
+	provider, issuer := newTestOIDCProvider(t)
+
+	testCases := []struct {
+		name          string
+		provider      *oidc.Provider
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			provider: provider,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					DeleteExpiredOIDCAuthRequests(gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					CreateOIDCAuthRequest(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.CreateOIDCAuthRequestParams) (store.OidcAuthRequest, error) {
+						require.WithinDuration(t, time.Now().Add(oidcAuthRequestDuration), arg.ExpireAt, time.Second)
+						require.NotEqual(t, arg.State, arg.Nonce)
+						require.NotEqual(t, arg.State, arg.CodeVerifier)
+						return store.OidcAuthRequest{
+							State:        arg.State,
+							Nonce:        arg.Nonce,
+							CodeVerifier: arg.CodeVerifier,
+							ExpireAt:     arg.ExpireAt,
+						}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusFound, recorder.Code)
+
+				location, err := url.Parse(recorder.Header().Get("Location"))
+				require.NoError(t, err)
+				require.Equal(t, issuer.URL()+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+
+				query := location.Query()
+				require.Equal(t, "code", query.Get("response_type"))
+				require.Equal(t, "task-app", query.Get("client_id"))
+				require.Equal(t, "S256", query.Get("code_challenge_method"))
+				require.NotEmpty(t, query.Get("state"))
+				require.NotEmpty(t, query.Get("nonce"))
+				require.NotEmpty(t, query.Get("code_challenge"))
+
+				var stateCookie *http.Cookie
+				for _, cookie := range recorder.Result().Cookies() {
+					if cookie.Name == oidcStateCookieName {
+						stateCookie = cookie
+					}
+				}
+				require.NotNil(t, stateCookie)
+				require.Equal(t, query.Get("state"), stateCookie.Value)
+				require.True(t, stateCookie.HttpOnly)
+				require.Equal(t, http.SameSiteLaxMode, stateCookie.SameSite)
+			},
+		},
+		{
+			name:     "NotConfigured",
+			provider: nil,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateOIDCAuthRequest(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "InternalError",
+			provider: provider,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					DeleteExpiredOIDCAuthRequests(gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+				storage.EXPECT().
+					CreateOIDCAuthRequest(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.OidcAuthRequest{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			server.SetOIDCProvider(tc.provider)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/auth/oidc/login", nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestOIDCCallbackHandler(t *testing.T) {
+	provider, issuer := newTestOIDCProvider(t)
+	user, _ := randomUser(t)
+
+	identity := oidctest.Identity{
+		Subject:           "subject-" + util.RandomAlphaNumString(8),
+		Email:             "Jane.Doe@Example.com",
+		EmailVerified:     true,
+		PreferredUsername: "jane.doe",
+	}
+	unverifiedIdentity := identity
+	unverifiedIdentity.EmailVerified = false
+
+	stubNoIdentity := func(storage *mockdb.MockStorage) {
+		storage.EXPECT().
+			GetUserIdentity(gomock.Any(), gomock.Eq(store.GetUserIdentityParams{
+				Issuer:  issuer.URL(),
+				Subject: identity.Subject,
+			})).
+			Times(1).
+			Return(store.UserIdentity{}, store.ErrRecordNotFound)
+	}
+
+	requireLoggedIn := func(t *testing.T, recorder *httptest.ResponseRecorder, userID int64) {
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var rsp struct {
+			Data loginResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		require.NotEmpty(t, rsp.Data.AccessToken)
+		require.NotEmpty(t, rsp.Data.RefreshToken)
+		require.Equal(t, userID, rsp.Data.User.ID)
+	}
+
+	testCases := []struct {
+		name          string
+		identity      oidctest.Identity
+		modifyQuery   func(query url.Values)
+		stateCookie   func(state string) string
+		buildStubs    func(storage *mockdb.MockStorage, authRequest store.OidcAuthRequest)
+		checkResponse func(t *testing.T, recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "LinkedIdentity",
+			identity: identity,
+			buildStubs: func(storage *mockdb.MockStorage, authRequest store.OidcAuthRequest) {
+				storage.EXPECT().
+					ConsumeOIDCAuthRequest(gomock.Any(), gomock.Eq(authRequest.State)).
+					Times(1).
+					Return(authRequest, nil)
+				storage.EXPECT().
+					GetUserIdentity(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.UserIdentity{UserID: user.ID, Issuer: issuer.URL(), Subject: identity.Subject}, nil)
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					CreateUserWithIdentity(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					ClearLoginAttempts(gomock.Any(), gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireLoggedIn(t, recorder, user.ID)
+			},
+		},
+		{
+			name:     "LinkByVerifiedEmail",
+			identity: identity,
+			buildStubs: func(storage *mockdb.MockStorage, authRequest store.OidcAuthRequest) {
+				email := pgtype.Text{String: "jane.doe@example.com", Valid: true}
+
+				storage.EXPECT().
+					ConsumeOIDCAuthRequest(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(authRequest, nil)
+				stubNoIdentity(storage)
+				storage.EXPECT().
+					GetUserByVerifiedEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					CreateUserIdentity(gomock.Any(), gomock.Eq(store.CreateUserIdentityParams{
+						UserID:  user.ID,
+						Issuer:  issuer.URL(),
+						Subject: identity.Subject,
+						Email:   email,
+					})).
+					Times(1)
+				storage.EXPECT().
+					ClearLoginAttempts(gomock.Any(), gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireLoggedIn(t, recorder, user.ID)
+			},
+		},
+		{
+			name:     "ProvisionUser",
+			identity: identity,
+			buildStubs: func(storage *mockdb.MockStorage, authRequest store.OidcAuthRequest) {
+				storage.EXPECT().
+					ConsumeOIDCAuthRequest(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(authRequest, nil)
+				stubNoIdentity(storage)
+				storage.EXPECT().
+					GetUserByVerifiedEmail(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.User{}, store.ErrRecordNotFound)
+				storage.EXPECT().
+					CreateUserWithIdentity(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.CreateUserWithIdentityParams) (store.CreateUserWithIdentityRow, error) {
+						require.Equal(t, "janedoe", arg.Username)
+						require.Equal(t, pgtype.Text{String: "jane.doe@example.com", Valid: true}, arg.Email)
+						require.Equal(t, issuer.URL(), arg.Issuer)
+						require.Equal(t, identity.Subject, arg.Subject)
+						require.False(t, util.PasswordNeedsRehash(arg.HashedPassword))
+						return store.CreateUserWithIdentityRow{ID: 42, Username: arg.Username, Role: util.UserRole}, nil
+					})
+				storage.EXPECT().
+					ClearLoginAttempts(gomock.Any(), gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireLoggedIn(t, recorder, 42)
+			},
+		},
+		{
+			name:     "ProvisionUserWithTakenUsernameAndEmail",
+			identity: identity,
+			buildStubs: func(storage *mockdb.MockStorage, authRequest store.OidcAuthRequest) {
+				storage.EXPECT().
+					ConsumeOIDCAuthRequest(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(authRequest, nil)
+				stubNoIdentity(storage)
+				storage.EXPECT().
+					GetUserByVerifiedEmail(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.User{}, store.ErrRecordNotFound)
+
+				var calls []store.CreateUserWithIdentityParams
+				storage.EXPECT().
+					CreateUserWithIdentity(gomock.Any(), gomock.Any()).
+					Times(3).
+					DoAndReturn(func(_ any, arg store.CreateUserWithIdentityParams) (store.CreateUserWithIdentityRow, error) {
+						calls = append(calls, arg)
+						switch len(calls) {
+						case 1:
+							return store.CreateUserWithIdentityRow{}, &pgconn.PgError{Code: store.UniqueViolation, ConstraintName: "users_username_key"}
+						case 2:
+							require.NotEqual(t, calls[0].Username, arg.Username)
+							require.Regexp(t, "^janedoe[A-Za-z0-9]{4}$", arg.Username)
+							return store.CreateUserWithIdentityRow{}, &pgconn.PgError{Code: store.UniqueViolation, ConstraintName: "users_email_key"}
+						default:
+							require.False(t, arg.Email.Valid)
+							return store.CreateUserWithIdentityRow{ID: 42, Username: arg.Username}, nil
+						}
+					})
+				storage.EXPECT().
+					ClearLoginAttempts(gomock.Any(), gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireLoggedIn(t, recorder, 42)
+			},
+		},
+		{
+			name:     "UnverifiedEmailIsNotLinked",
+			identity: unverifiedIdentity,
+			buildStubs: func(storage *mockdb.MockStorage, authRequest store.OidcAuthRequest) {
+				storage.EXPECT().
+					ConsumeOIDCAuthRequest(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(authRequest, nil)
+				stubNoIdentity(storage)
+				storage.EXPECT().
+					GetUserByVerifiedEmail(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					CreateUserWithIdentity(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.CreateUserWithIdentityParams) (store.CreateUserWithIdentityRow, error) {
+						require.False(t, arg.Email.Valid)
+						return store.CreateUserWithIdentityRow{ID: 42, Username: arg.Username}, nil
+					})
+				storage.EXPECT().
+					ClearLoginAttempts(gomock.Any(), gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireLoggedIn(t, recorder, 42)
+			},
+		},
+		{
+			name:     "MFARequired",
+			identity: identity,
+			buildStubs: func(storage *mockdb.MockStorage, authRequest store.OidcAuthRequest) {
+				storage.EXPECT().
+					ConsumeOIDCAuthRequest(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(authRequest, nil)
+				storage.EXPECT().
+					GetUserIdentity(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.UserIdentity{UserID: user.ID}, nil)
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(randomTOTPCredential(t, user.ID, true), nil)
+				storage.EXPECT().
+					CreateMFAChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.CreateMFAChallengeParams) (store.MfaChallenge, error) {
+						return store.MfaChallenge{ID: arg.ID, UserID: arg.UserID, ExpireAt: arg.ExpireAt}, nil
+					})
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp struct {
+					Data mfaChallengeResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.True(t, rsp.Data.MFARequired)
+				require.NotEmpty(t, rsp.Data.MFAToken)
+			},
+		},
+		{
+			name:     "UnknownState",
+			identity: identity,
+			buildStubs: func(storage *mockdb.MockStorage, authRequest store.OidcAuthRequest) {
+				storage.EXPECT().
+					ConsumeOIDCAuthRequest(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.OidcAuthRequest{}, store.ErrRecordNotFound)
+				storage.EXPECT().
+					GetUserIdentity(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "MissingStateCookie",
+			identity:    identity,
+			stateCookie: func(state string) string { return "" },
+			buildStubs: func(storage *mockdb.MockStorage, authRequest store.OidcAuthRequest) {
+				storage.EXPECT().
+					ConsumeOIDCAuthRequest(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			// The callback of a login started in another browser.
+			name:     "MismatchedStateCookie",
+			identity: identity,
+			stateCookie: func(state string) string {
+				return randomOIDCAuthRequest(t).State
+			},
+			buildStubs: func(storage *mockdb.MockStorage, authRequest store.OidcAuthRequest) {
+				storage.EXPECT().
+					ConsumeOIDCAuthRequest(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "ProviderError",
+			identity: identity,
+			modifyQuery: func(query url.Values) {
+				query.Del("code")
+				query.Set("error", "access_denied")
+			},
+			buildStubs: func(storage *mockdb.MockStorage, authRequest store.OidcAuthRequest) {
+				storage.EXPECT().
+					ConsumeOIDCAuthRequest(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(authRequest, nil)
+				storage.EXPECT().
+					GetUserIdentity(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "access_denied")
+			},
+		},
+		{
+			name:     "WrongCodeVerifier",
+			identity: identity,
+			buildStubs: func(storage *mockdb.MockStorage, authRequest store.OidcAuthRequest) {
+				authRequest.CodeVerifier = randomOIDCAuthRequest(t).CodeVerifier
+				storage.EXPECT().
+					ConsumeOIDCAuthRequest(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(authRequest, nil)
+				storage.EXPECT().
+					GetUserIdentity(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "WrongNonce",
+			identity: identity,
+			buildStubs: func(storage *mockdb.MockStorage, authRequest store.OidcAuthRequest) {
+				authRequest.Nonce = randomOIDCAuthRequest(t).Nonce
+				storage.EXPECT().
+					ConsumeOIDCAuthRequest(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(authRequest, nil)
+				storage.EXPECT().
+					GetUserIdentity(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			authRequest := randomOIDCAuthRequest(t)
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage, authRequest)
+			stubAuditEvents(storage)
+			stubTOTPNotEnabled(storage)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			server.SetOIDCProvider(provider)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			// Sign in at the issuer the way the browser would after the
+			// redirect from oidcLoginHandler.
+			issuer.SetIdentity(tc.identity)
+			authCodeURL, err := provider.AuthCodeURL(context.Background(), authRequest.State, authRequest.Nonce, authRequest.CodeVerifier)
+			require.NoError(t, err)
+			callback, err := issuer.Authorize(authCodeURL)
+			require.NoError(t, err)
+
+			query := callback.Query()
+			if tc.modifyQuery != nil {
+				tc.modifyQuery(query)
+			}
+
+			request, err := http.NewRequest(http.MethodGet, "/auth/oidc/callback?"+query.Encode(), nil)
+			require.NoError(t, err)
+
+			stateCookie := authRequest.State
+			if tc.stateCookie != nil {
+				stateCookie = tc.stateCookie(stateCookie)
+			}
+			if len(stateCookie) > 0 {
+				request.AddCookie(&http.Cookie{Name: oidcStateCookieName, Value: stateCookie})
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestOIDCUsername(t *testing.T) {
+	testCases := []struct {
+		claims   oidc.IDTokenClaims
+		username string
+	}{
+		{oidc.IDTokenClaims{PreferredUsername: "jane.doe", Email: "jd@example.com"}, "janedoe"},
+		{oidc.IDTokenClaims{Email: "john_smith@example.com"}, "johnsmith"},
+		{oidc.IDTokenClaims{PreferredUsername: "Đức", Name: "Nguyen Duc"}, "c"},
+		{oidc.IDTokenClaims{PreferredUsername: "đ-.", Name: "Nguyen Duc"}, "NguyenDuc"},
+		{oidc.IDTokenClaims{}, "user"},
+		{oidc.IDTokenClaims{PreferredUsername: "abcdefghijklmnopqrstuvwxyz0123456789"}, "abcdefghijklmnopqrstuvwxyz0123"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.username, oidcUsername(&tc.claims))
+	}
+}
@@ -0,0 +1,293 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/notifier"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+)
+
+const passwordResetTokenDuration = 30 * time.Minute
+
+var (
+	errIncorrectPassword         = errors.New("current password is incorrect")
+	errInvalidPasswordResetToken = errors.New("invalid or expired password reset token")
+)
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required,min=6"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+	// RefreshToken identifies the caller's session so that it survives the
+	// change. Every other session of the user is blocked. Clients using
+	// cookie authentication have it read from the refresh token cookie.
+	RefreshToken string `json:"refresh_token" binding:"omitempty"`
+}
+
+type changePasswordResponse struct {
+	// AccessToken replaces the access token of the request, which is revoked
+	// along with every other token issued before the change. It is omitted
+	// for cookie sessions, which get it as a cookie.
+	AccessToken         string    `json:"access_token,omitempty"`
+	AccessTokenExpireAt time.Time `json:"access_token_expire_at"`
+	// PersonalAccessTokensKept tells the client that personal access tokens
+	// stay valid and have to be revoked one by one if they are not wanted.
+	PersonalAccessTokensKept bool `json:"personal_access_tokens_kept"`
+}
+
+// changePasswordHandler changes the password of the authenticated user and
+// signs out everywhere else: other sessions are blocked and access tokens
+// issued before the change are revoked, while the caller's session gets a new
+// access token. Personal access tokens are kept, since they are meant for
+// scripts that do not know the password.
+func (s *Server) changePasswordHandler(ctx *gin.Context) {
+	var req changePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	refreshToken, err := refreshTokenFromRequest(ctx, req.RefreshToken)
+	if err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	currentSessionID := uuid.Nil
+	if len(refreshToken) > 0 {
+		refreshPayload, err := s.tokenMaker.VerifyToken(refreshToken)
+		if err != nil {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		if refreshPayload.Type != token.TokenTypeRefresh {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errNotRefreshToken))
+			return
+		}
+
+		if refreshPayload.UserID != authPayload.UserID {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errSessionNotOwned))
+			return
+		}
+		currentSessionID = refreshPayload.ID
+	}
+
+	user, ok := s.getAuthenticatedUser(ctx, authPayload)
+	if !ok {
+		return
+	}
+
+	err = util.CheckPassword(req.CurrentPassword, user.HashedPassword)
+	if err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(errIncorrectPassword))
+		return
+	}
+
+	hashedPassword, err := util.HashPassword(req.NewPassword)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// The new password never takes effect while the old sessions and tokens
+	// stay valid.
+	err = s.storage.ExecTx(ctx, func(q store.Querier) error {
+		_, err := q.UpdateUserPassword(ctx, store.UpdateUserPasswordParams{
+			ID:             user.ID,
+			HashedPassword: hashedPassword,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.BlockOtherUserSessions(ctx, store.BlockOtherUserSessionsParams{
+			UserID:           user.ID,
+			CurrentSessionID: currentSessionID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.RevokeUserTokens(ctx, store.RevokeUserTokensParams{
+			UserID:        user.ID,
+			RevokedBefore: time.Now(),
+		})
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	jwtConfig, err := util.LoadJWTConfig()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	accessToken, accessPayload, err := s.tokenMaker.CreateToken(
+		user.ID,
+		user.Username,
+		user.Role,
+		token.TokenTypeAccess,
+		jwtConfig.AccessTokenDuration,
+	)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	s.recordAuditEvent(ctx, user.ID, user.Username, auditEventPasswordChanged, "")
+
+	rsp := changePasswordResponse{
+		AccessTokenExpireAt:      accessPayload.ExpireAt,
+		PersonalAccessTokensKept: true,
+	}
+
+	// Without an Authorization header the request was authenticated by the
+	// access token cookie.
+	if len(ctx.GetHeader(authorizationHeaderKey)) == 0 {
+		s.setCookie(ctx, accessTokenCookieName, accessToken, accessPayload.ExpireAt, true)
+	} else {
+		rsp.AccessToken = accessToken
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp))
+}
+
+type requestPasswordResetRequest struct {
+	Username string `json:"username" binding:"required,alphanum"`
+}
+
+// requestPasswordResetHandler always answers 202 for a well-formed request so
+// that it cannot be used to find out which usernames exist.
+func (s *Server) requestPasswordResetHandler(ctx *gin.Context) {
+	var req requestPasswordResetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := s.storage.GetUser(ctx, req.Username)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusAccepted, successResponse(nil))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	resetToken, hash, err := token.NewPasswordResetToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	resetTokenRecord, err := s.storage.CreatePasswordResetToken(ctx, store.CreatePasswordResetTokenParams{
+		ID:        id,
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpireAt:  time.Now().Add(passwordResetTokenDuration),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = s.notifier.Notify(ctx, notifier.Message{
+		UserID:  user.ID,
+		Kind:    notifier.KindPasswordReset,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Use this token to reset the password of %s: %s\nIt expires at %s and can only be used once.",
+			user.Username,
+			resetToken,
+			resetTokenRecord.ExpireAt.Format(time.RFC3339),
+		),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, successResponse(nil))
+}
+
+type resetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+func (s *Server) resetPasswordHandler(ctx *gin.Context) {
+	var req resetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	resetToken, err := s.storage.ConsumePasswordResetToken(ctx, token.HashPasswordResetToken(req.Token))
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidPasswordResetToken))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	hashedPassword, err := util.HashPassword(req.NewPassword)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	user, err := s.storage.UpdateUserPassword(ctx, store.UpdateUserPasswordParams{
+		ID:             resetToken.UserID,
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = s.storage.InvalidateUserPasswordResetTokens(ctx, resetToken.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Whoever knew the old password may still be signed in somewhere.
+	err = s.storage.BlockUserSessions(ctx, resetToken.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = s.storage.RevokeUserTokens(ctx, store.RevokeUserTokensParams{
+		UserID:        resetToken.UserID,
+		RevokedBefore: time.Now(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	s.recordAuditEvent(ctx, user.ID, user.Username, auditEventPasswordChanged, "reset")
+
+	ctx.JSON(http.StatusOK, successResponse(nil))
+}
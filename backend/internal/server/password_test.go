@@ -0,0 +1,592 @@
+package server
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/notifier"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestChangePasswordHandler(t *testing.T) {
+	user, password := randomUser(t)
+	newPassword := util.RandomPrintableString(8)
+
+	testCases := []struct {
+		name          string
+		buildBody     func(t *testing.T, tokenMaker token.Maker) gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildBody: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				return gin.H{
+					"current_password": password,
+					"new_password":     newPassword,
+				}
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					UpdateUserPassword(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.UpdateUserPasswordParams) (store.User, error) {
+						require.Equal(t, user.ID, arg.ID)
+						require.NoError(t, util.CheckPassword(newPassword, arg.HashedPassword))
+						return user, nil
+					})
+				storage.EXPECT().
+					BlockOtherUserSessions(gomock.Any(), gomock.Eq(store.BlockOtherUserSessionsParams{
+						UserID:           user.ID,
+						CurrentSessionID: uuid.Nil,
+					})).
+					Times(1)
+				storage.EXPECT().
+					RevokeUserTokens(gomock.Any(), gomock.Cond(func(arg store.RevokeUserTokensParams) bool {
+						return arg.UserID == user.ID && !arg.RevokedBefore.After(time.Now())
+					})).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp struct {
+					Data changePasswordResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.NotEmpty(t, rsp.Data.AccessToken)
+				require.True(t, rsp.Data.PersonalAccessTokensKept)
+			},
+		},
+		{
+			name: "KeepsCurrentSession",
+			buildBody: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				refreshToken, _, err := tokenMaker.CreateToken(user.ID, user.Username, user.Role, token.TokenTypeRefresh, time.Hour)
+				require.NoError(t, err)
+				return gin.H{
+					"current_password": password,
+					"new_password":     newPassword,
+					"refresh_token":    refreshToken,
+				}
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					UpdateUserPassword(gomock.Any(), gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					BlockOtherUserSessions(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.BlockOtherUserSessionsParams) error {
+						require.Equal(t, user.ID, arg.UserID)
+						require.NotEqual(t, uuid.Nil, arg.CurrentSessionID)
+						return nil
+					})
+				storage.EXPECT().
+					RevokeUserTokens(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "IncorrectPassword",
+			buildBody: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				return gin.H{
+					"current_password": "incorrect",
+					"new_password":     newPassword,
+				}
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					UpdateUserPassword(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "ForeignRefreshToken",
+			buildBody: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				refreshToken, _, err := tokenMaker.CreateToken(user.ID+1, util.RandomUsername(), util.UserRole, token.TokenTypeRefresh, time.Hour)
+				require.NoError(t, err)
+				return gin.H{
+					"current_password": password,
+					"new_password":     newPassword,
+					"refresh_token":    refreshToken,
+				}
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "AccessTokenAsRefreshToken",
+			buildBody: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				accessToken, _, err := tokenMaker.CreateToken(user.ID, user.Username, user.Role, token.TokenTypeAccess, time.Hour)
+				require.NoError(t, err)
+				return gin.H{
+					"current_password": password,
+					"new_password":     newPassword,
+					"refresh_token":    accessToken,
+				}
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					BlockOtherUserSessions(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "TooShortPassword",
+			buildBody: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				return gin.H{
+					"current_password": password,
+					"new_password":     "short",
+				}
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			buildBody: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				return gin.H{
+					"current_password": password,
+					"new_password":     newPassword,
+				}
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildBody: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				return gin.H{
+					"current_password": password,
+					"new_password":     newPassword,
+				}
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					UpdateUserPassword(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.User{}, sql.ErrConnDone)
+				storage.EXPECT().
+					BlockOtherUserSessions(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			// The password update is rolled back with the failed revocation,
+			// so that the old password keeps working with the old sessions.
+			name: "RevokeTokensError",
+			buildBody: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				return gin.H{
+					"current_password": password,
+					"new_password":     newPassword,
+				}
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					UpdateUserPassword(gomock.Any(), gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					BlockOtherUserSessions(gomock.Any(), gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					RevokeUserTokens(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+			stubAuditEvents(storage)
+			stubTokenNotRevoked(storage)
+			stubExecTx(storage)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.buildBody(t, server.tokenMaker))
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, "/users/me/password", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestRequestPasswordResetHandler(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"username": user.Username,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				var tokenHash string
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					CreatePasswordResetToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.CreatePasswordResetTokenParams) (store.PasswordResetToken, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.WithinDuration(t, time.Now().Add(passwordResetTokenDuration), arg.ExpireAt, time.Second)
+						tokenHash = arg.TokenHash
+						return store.PasswordResetToken{
+							ID:        arg.ID,
+							UserID:    arg.UserID,
+							TokenHash: arg.TokenHash,
+							ExpireAt:  arg.ExpireAt,
+						}, nil
+					})
+				storage.EXPECT().
+					CreateOutboxMessage(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.CreateOutboxMessageParams) (store.OutboxMessage, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.Equal(t, notifier.KindPasswordReset, arg.Kind)
+
+						// The message carries the token itself; only its hash is stored.
+						require.NotContains(t, arg.Body, tokenHash)
+						var resetToken string
+						for _, field := range bytes.Fields([]byte(arg.Body)) {
+							if bytes.HasPrefix(field, []byte(token.PasswordResetTokenPrefix)) {
+								resetToken = string(field)
+							}
+						}
+						require.Equal(t, tokenHash, token.HashPasswordResetToken(resetToken))
+						return store.OutboxMessage{}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownUsername",
+			body: gin.H{
+				"username": user.Username,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.User{}, store.ErrRecordNotFound)
+				storage.EXPECT().
+					CreatePasswordResetToken(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					CreateOutboxMessage(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidUsername",
+			body: gin.H{
+				"username": "invalid-user#1",
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotifierError",
+			body: gin.H{
+				"username": user.Username,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					CreatePasswordResetToken(gomock.Any(), gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					CreateOutboxMessage(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.OutboxMessage{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/password_reset", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestResetPasswordHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	newPassword := util.RandomPrintableString(8)
+
+	resetToken, hash, err := token.NewPasswordResetToken()
+	require.NoError(t, err)
+
+	resetTokenRecord := store.PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpireAt:  time.Now().Add(passwordResetTokenDuration),
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"token":        resetToken,
+				"new_password": newPassword,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					ConsumePasswordResetToken(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(resetTokenRecord, nil)
+				storage.EXPECT().
+					UpdateUserPassword(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.UpdateUserPasswordParams) (store.User, error) {
+						require.Equal(t, user.ID, arg.ID)
+						require.NoError(t, util.CheckPassword(newPassword, arg.HashedPassword))
+						return user, nil
+					})
+				storage.EXPECT().
+					InvalidateUserPasswordResetTokens(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1)
+				storage.EXPECT().
+					BlockUserSessions(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1)
+				storage.EXPECT().
+					RevokeUserTokens(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidToken",
+			body: gin.H{
+				"token":        resetToken,
+				"new_password": newPassword,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					ConsumePasswordResetToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.PasswordResetToken{}, store.ErrRecordNotFound)
+				storage.EXPECT().
+					UpdateUserPassword(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "TooShortPassword",
+			body: gin.H{
+				"token":        resetToken,
+				"new_password": "short",
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					ConsumePasswordResetToken(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{
+				"token":        resetToken,
+				"new_password": newPassword,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					ConsumePasswordResetToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.PasswordResetToken{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+			stubAuditEvents(storage)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/password_reset/confirm", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+)
+
+const (
+	scopeTasksRead  = "tasks:read"
+	scopeTasksWrite = "tasks:write"
+)
+
+var errPersonalAccessTokenNotFound = errors.New("personal access token not found")
+
+type createPersonalAccessTokenRequest struct {
+	Name     string   `json:"name" binding:"required,max=100"`
+	Scopes   []string `json:"scopes" binding:"required,min=1,dive,oneof=tasks:read tasks:write"`
+	ExpireAt string   `json:"expire_at" binding:"omitempty,iso8601"`
+}
+
+type personalAccessTokenResponse struct {
+	ID         uuid.UUID          `json:"id"`
+	Name       string             `json:"name"`
+	Scopes     []string           `json:"scopes"`
+	ExpireAt   pgtype.Timestamptz `json:"expire_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type createPersonalAccessTokenResponse struct {
+	personalAccessTokenResponse
+	Token string `json:"token"`
+}
+
+func newPersonalAccessTokenResponse(pat store.PersonalAccessToken) personalAccessTokenResponse {
+	return personalAccessTokenResponse{
+		ID:         pat.ID,
+		Name:       pat.Name,
+		Scopes:     pat.Scopes,
+		ExpireAt:   pat.ExpireAt,
+		LastUsedAt: pat.LastUsedAt,
+		CreatedAt:  pat.CreatedAt,
+	}
+}
+
+func (s *Server) createPersonalAccessTokenHandler(ctx *gin.Context) {
+	var req createPersonalAccessTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	patToken, hash, err := token.NewPersonalAccessToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := store.CreatePersonalAccessTokenParams{
+		ID:        id,
+		UserID:    authPayload.UserID,
+		Name:      req.Name,
+		TokenHash: hash,
+		Scopes:    req.Scopes,
+	}
+
+	if len(req.ExpireAt) > 0 {
+		expireAt, _ := time.Parse(time.RFC3339, req.ExpireAt)
+		arg.ExpireAt = pgtype.Timestamptz{
+			Time:  expireAt,
+			Valid: true,
+		}
+	}
+
+	pat, err := s.storage.CreatePersonalAccessToken(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, successResponse(createPersonalAccessTokenResponse{
+		personalAccessTokenResponse: newPersonalAccessTokenResponse(pat),
+		Token:                       patToken,
+	}))
+}
+
+func (s *Server) listPersonalAccessTokensHandler(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	pats, err := s.storage.ListPersonalAccessTokens(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := []personalAccessTokenResponse{}
+	for _, pat := range pats {
+		rsp = append(rsp, newPersonalAccessTokenResponse(pat))
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp))
+}
+
+type revokePersonalAccessTokenRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+func (s *Server) revokePersonalAccessTokenHandler(ctx *gin.Context) {
+	var req revokePersonalAccessTokenRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	rows, err := s.storage.RevokePersonalAccessToken(ctx, store.RevokePersonalAccessTokenParams{
+		ID:     uuid.MustParse(req.ID),
+		UserID: authPayload.UserID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if rows == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(errPersonalAccessTokenNotFound))
+		return
+	}
+
+	s.recordAuditEvent(ctx, authPayload.UserID, authPayload.Username, auditEventTokenRevoked, "personal_access_token")
+
+	ctx.JSON(http.StatusOK, successResponse(nil))
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+)
+
+var (
+	errUserNotFound      = errors.New("user not found")
+	errTokenUserMismatch = errors.New("token doesn't belong to the current user")
+	errOwnsWorkspace     = errors.New("delete the workspaces you own before deleting your account")
+)
+
+// getAuthenticatedUser loads the user behind an access token by ID, since a
+// username may be taken by a new account once the old one is deleted. It
+// writes the error response and reports false when the request cannot proceed.
+func (s *Server) getAuthenticatedUser(ctx *gin.Context, authPayload *token.Payload) (store.User, bool) {
+	user, err := s.storage.GetUserByID(ctx, authPayload.UserID)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errUserNotFound))
+			return store.User{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return store.User{}, false
+	}
+
+	if user.Username != authPayload.Username {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errTokenUserMismatch))
+		return store.User{}, false
+	}
+
+	return user, true
+}
+
+func (s *Server) getCurrentUserHandler(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	user, ok := s.getAuthenticatedUser(ctx, authPayload)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(newUserResponse(user)))
+}
+
+type updateCurrentUserRequest struct {
+	DisplayName *string `json:"display_name" binding:"omitempty,max=100"`
+	Timezone    *string `json:"timezone" binding:"omitempty,timezone"`
+	Locale      *string `json:"locale" binding:"omitempty,bcp47_language_tag"`
+}
+
+func (s *Server) updateCurrentUserHandler(ctx *gin.Context) {
+	var req updateCurrentUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := store.UpdateUserProfileParams{
+		ID: authPayload.UserID,
+	}
+
+	if req.DisplayName != nil {
+		arg.DisplayName = pgtype.Text{
+			String: *req.DisplayName,
+			Valid:  true,
+		}
+	}
+
+	if req.Timezone != nil {
+		arg.Timezone = pgtype.Text{
+			String: *req.Timezone,
+			Valid:  true,
+		}
+	}
+
+	if req.Locale != nil {
+		arg.Locale = pgtype.Text{
+			String: *req.Locale,
+			Valid:  true,
+		}
+	}
+
+	user, err := s.storage.UpdateUserProfile(ctx, arg)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errUserNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(newUserResponse(user)))
+}
+
+type deleteCurrentUserRequest struct {
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// deleteCurrentUserHandler removes the account. Tasks, sessions and tokens of
+// the user go with it through ON DELETE CASCADE foreign keys.
+func (s *Server) deleteCurrentUserHandler(ctx *gin.Context) {
+	var req deleteCurrentUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	user, ok := s.getAuthenticatedUser(ctx, authPayload)
+	if !ok {
+		return
+	}
+
+	err := util.CheckPassword(req.Password, user.HashedPassword)
+	if err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(errIncorrectPassword))
+		return
+	}
+
+	// Deleting the owner would leave their workspaces without one. The tasks
+	// the user created in other workspaces are handed to their owners.
+	workspaces, err := s.storage.ListUserWorkspaces(ctx, user.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	for _, workspace := range workspaces {
+		if workspace.Role == util.WorkspaceRoleOwner {
+			ctx.JSON(http.StatusConflict, errorResponse(errOwnsWorkspace))
+			return
+		}
+	}
+
+	rows, err := s.storage.DeleteUser(ctx, user.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if rows == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(errUserNotFound))
+		return
+	}
+
+	// Login attempts are keyed by username, which can be registered again.
+	err = s.storage.ClearLoginAttempts(ctx, store.ClearLoginAttemptsParams{
+		Scope: loginScopeUsername,
+		Key:   user.Username,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(nil))
+}
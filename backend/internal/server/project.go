@@ -0,0 +1,350 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+)
+
+const defaultProjectColor = "#808080"
+
+var (
+	errProjectNotFound     = errors.New("project not found")
+	errProjectNameConflict = errors.New("a project with this name already exists")
+	errEmptyProjectName    = errors.New("project name must not be empty")
+	errProjectArchived     = errors.New("tasks cannot be added to an archived project")
+)
+
+// projectTaskCounts summarizes the tasks of a project. Overdue tasks are
+// counted as open as well.
+type projectTaskCounts struct {
+	Open      int64 `json:"open"`
+	Overdue   int64 `json:"overdue"`
+	Completed int64 `json:"completed"`
+}
+
+type projectResponse struct {
+	store.Project
+	TaskCounts projectTaskCounts `json:"task_counts"`
+}
+
+// projectResponses loads the task counts of the given projects.
+func (s *Server) projectResponses(ctx *gin.Context, projects []store.Project) ([]projectResponse, error) {
+	projectIDs := make([]int64, 0, len(projects))
+	for _, project := range projects {
+		projectIDs = append(projectIDs, project.ID)
+	}
+
+	countsByProject := make(map[int64]projectTaskCounts, len(projects))
+	if len(projectIDs) > 0 {
+		rows, err := s.storage.GetProjectTaskCounts(ctx, projectIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			countsByProject[row.ProjectID] = projectTaskCounts{
+				Open:      row.Open,
+				Overdue:   row.Overdue,
+				Completed: row.Completed,
+			}
+		}
+	}
+
+	rsp := make([]projectResponse, 0, len(projects))
+	for _, project := range projects {
+		rsp = append(rsp, projectResponse{
+			Project:    project,
+			TaskCounts: countsByProject[project.ID],
+		})
+	}
+	return rsp, nil
+}
+
+type createProjectRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"omitempty,max=1000"`
+	Color       string `json:"color" binding:"omitempty,hexcolor"`
+}
+
+func (s *Server) createProjectHandler(ctx *gin.Context) {
+	var req createProjectRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if len(name) == 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errEmptyProjectName))
+		return
+	}
+
+	color := req.Color
+	if len(color) == 0 {
+		color = defaultProjectColor
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := store.CreateProjectParams{
+		UserID: authPayload.UserID,
+		Name:   name,
+		Color:  color,
+	}
+	if len(req.Description) > 0 {
+		arg.Description = pgtype.Text{
+			String: req.Description,
+			Valid:  true,
+		}
+	}
+
+	project, err := s.storage.CreateProject(ctx, arg)
+	if err != nil {
+		if store.ErrorCode(err) == store.UniqueViolation {
+			ctx.JSON(http.StatusConflict, errorResponse(errProjectNameConflict))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// A new project has no tasks yet.
+	ctx.JSON(http.StatusCreated, successResponse(projectResponse{
+		Project: project,
+	}))
+}
+
+type listProjectsRequest struct {
+	IncludeArchived bool `form:"include_archived"`
+}
+
+func (s *Server) listProjectsHandler(ctx *gin.Context) {
+	var req listProjectsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	projects, err := s.storage.ListProjects(ctx, store.ListProjectsParams{
+		UserID:          authPayload.UserID,
+		IncludeArchived: req.IncludeArchived,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := s.projectResponses(ctx, projects)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp))
+}
+
+type projectURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (s *Server) getProjectHandler(ctx *gin.Context) {
+	var uri projectURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	project, ok := s.getOwnedProject(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	rsp, err := s.projectResponses(ctx, []store.Project{project})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp[0]))
+}
+
+type updateProjectRequest struct {
+	Name        *string `json:"name" binding:"omitempty,max=100"`
+	Description *string `json:"description" binding:"omitempty,max=1000"`
+	Color       string  `json:"color" binding:"omitempty,hexcolor"`
+	Archived    *bool   `json:"archived"`
+}
+
+func (s *Server) updateProjectHandler(ctx *gin.Context) {
+	var uri projectURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateProjectRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := s.getOwnedProject(ctx, uri.ID); !ok {
+		return
+	}
+
+	arg := store.UpdateProjectParams{
+		ID: uri.ID,
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if len(name) == 0 {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errEmptyProjectName))
+			return
+		}
+		arg.Name = pgtype.Text{
+			String: name,
+			Valid:  true,
+		}
+	}
+
+	if req.Description != nil {
+		arg.Description = pgtype.Text{
+			String: *req.Description,
+			Valid:  true,
+		}
+	}
+
+	if len(req.Color) > 0 {
+		arg.Color = pgtype.Text{
+			String: req.Color,
+			Valid:  true,
+		}
+	}
+
+	if req.Archived != nil {
+		arg.Archived = pgtype.Bool{
+			Bool:  *req.Archived,
+			Valid: true,
+		}
+	}
+
+	project, err := s.storage.UpdateProject(ctx, arg)
+	if err != nil {
+		if store.ErrorCode(err) == store.UniqueViolation {
+			ctx.JSON(http.StatusConflict, errorResponse(errProjectNameConflict))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := s.projectResponses(ctx, []store.Project{project})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp[0]))
+}
+
+// deleteProjectHandler deletes a project. Its tasks are kept and no longer
+// belong to any project.
+func (s *Server) deleteProjectHandler(ctx *gin.Context) {
+	var uri projectURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := s.getOwnedProject(ctx, uri.ID); !ok {
+		return
+	}
+
+	err := s.storage.DeleteProject(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(nil))
+}
+
+// listProjectTasksHandler lists the tasks of a project. It accepts the same
+// filters as getTasksHandler.
+func (s *Server) listProjectTasksHandler(ctx *gin.Context) {
+	var uri projectURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req getTasksRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	project, ok := s.getOwnedProject(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	arg := newGetTasksParams(project.UserID, req)
+	arg.ProjectID = pgtype.Int8{
+		Int64: project.ID,
+		Valid: true,
+	}
+	s.listTasks(ctx, arg)
+}
+
+// getOwnedProject answers 404 for projects of other users as well as for
+// missing ones. It reports whether the request may proceed.
+func (s *Server) getOwnedProject(ctx *gin.Context, id int64) (store.Project, bool) {
+	project, err := s.storage.GetProject(ctx, id)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errProjectNotFound))
+			return store.Project{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return store.Project{}, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if project.UserID != authPayload.UserID {
+		ctx.JSON(http.StatusNotFound, errorResponse(errProjectNotFound))
+		return store.Project{}, false
+	}
+
+	return project, true
+}
+
+// getTaskProject checks a project named in a task request. It fails with
+// errProjectNotFound for projects of other users and with errProjectArchived
+// for archived ones.
+func (s *Server) getTaskProject(ctx *gin.Context, userID int64, id int64) (store.Project, error) {
+	project, err := s.storage.GetProject(ctx, id)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return store.Project{}, errProjectNotFound
+		}
+		return store.Project{}, err
+	}
+
+	if project.UserID != userID {
+		return store.Project{}, errProjectNotFound
+	}
+
+	if project.Archived {
+		return store.Project{}, errProjectArchived
+	}
+
+	return project, nil
+}
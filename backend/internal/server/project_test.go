@@ -0,0 +1,470 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func randomProject(userID int64) store.Project {
+	return store.Project{
+		ID:        rand.Int64N(1000) + 1,
+		UserID:    userID,
+		Name:      util.RandomAlphabetString(10),
+		Color:     defaultProjectColor,
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestCreateProjectHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	project := randomProject(user.ID)
+
+	testCases := []struct {
+		name       string
+		body       gin.H
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name: "OK",
+			body: gin.H{"name": "  " + project.Name + " ", "description": "Q3 invoices"},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateProject(gomock.Any(), gomock.Eq(store.CreateProjectParams{
+						UserID:      user.ID,
+						Name:        project.Name,
+						Description: pgtype.Text{String: "Q3 invoices", Valid: true},
+						Color:       defaultProjectColor,
+					})).
+					Times(1).
+					Return(project, nil)
+			},
+			expectCode: http.StatusCreated,
+		},
+		{
+			name: "NameConflict",
+			body: gin.H{"name": project.Name},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateProject(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.Project{}, store.ErrUniqueViolation)
+			},
+			expectCode: http.StatusConflict,
+		},
+		{
+			name: "EmptyName",
+			body: gin.H{"name": "   "},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateProject(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name: "InvalidColor",
+			body: gin.H{"name": project.Name, "color": "blue"},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateProject(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPost, "/projects", tc.body)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestListProjectsHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	busy := randomProject(user.ID)
+	idle := randomProject(user.ID)
+	idle.ID = busy.ID + 1
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mockdb.NewMockStorage(ctrl)
+	storage.EXPECT().
+		ListProjects(gomock.Any(), gomock.Eq(store.ListProjectsParams{
+			UserID:          user.ID,
+			IncludeArchived: true,
+		})).
+		Times(1).
+		Return([]store.Project{busy, idle}, nil)
+	storage.EXPECT().
+		GetProjectTaskCounts(gomock.Any(), gomock.Eq([]int64{busy.ID, idle.ID})).
+		Times(1).
+		Return([]store.GetProjectTaskCountsRow{{
+			ProjectID: busy.ID,
+			Open:      3,
+			Overdue:   1,
+			Completed: 2,
+		}}, nil)
+
+	recorder := serveAuthenticatedRequest(t, storage, user, http.MethodGet, "/projects?include_archived=true", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp struct {
+		Data []projectResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Len(t, rsp.Data, 2)
+	require.Equal(t, projectTaskCounts{Open: 3, Overdue: 1, Completed: 2}, rsp.Data[0].TaskCounts)
+	require.Equal(t, projectTaskCounts{}, rsp.Data[1].TaskCounts)
+}
+
+func TestGetProjectHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	project := randomProject(user.ID)
+	otherProject := randomProject(user.ID + 1)
+
+	testCases := []struct {
+		name       string
+		project    store.Project
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name:    "OK",
+			project: project,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetProjectTaskCounts(gomock.Any(), gomock.Eq([]int64{project.ID})).
+					Times(1).
+					Return([]store.GetProjectTaskCountsRow{}, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:    "OtherUser",
+			project: otherProject,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetProjectTaskCounts(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			storage.EXPECT().
+				GetProject(gomock.Any(), gomock.Eq(tc.project.ID)).
+				Times(1).
+				Return(tc.project, nil)
+			tc.buildStubs(storage)
+
+			url := fmt.Sprintf("/projects/%d", tc.project.ID)
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodGet, url, nil)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestUpdateProjectHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	project := randomProject(user.ID)
+	archived := project
+	archived.Archived = true
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mockdb.NewMockStorage(ctrl)
+	storage.EXPECT().
+		GetProject(gomock.Any(), gomock.Eq(project.ID)).
+		Times(1).
+		Return(project, nil)
+	storage.EXPECT().
+		UpdateProject(gomock.Any(), gomock.Eq(store.UpdateProjectParams{
+			ID:       project.ID,
+			Archived: pgtype.Bool{Bool: true, Valid: true},
+		})).
+		Times(1).
+		Return(archived, nil)
+	storage.EXPECT().
+		GetProjectTaskCounts(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]store.GetProjectTaskCountsRow{}, nil)
+
+	url := fmt.Sprintf("/projects/%d", project.ID)
+	recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPatch, url, gin.H{"archived": true})
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp struct {
+		Data projectResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.True(t, rsp.Data.Archived)
+}
+
+func TestDeleteProjectHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	project := randomProject(user.ID)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mockdb.NewMockStorage(ctrl)
+	storage.EXPECT().
+		GetProject(gomock.Any(), gomock.Eq(project.ID)).
+		Times(1).
+		Return(project, nil)
+	storage.EXPECT().
+		DeleteProject(gomock.Any(), gomock.Eq(project.ID)).
+		Times(1)
+
+	url := fmt.Sprintf("/projects/%d", project.ID)
+	recorder := serveAuthenticatedRequest(t, storage, user, http.MethodDelete, url, nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestListProjectTasksHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	project := randomProject(user.ID)
+	task := randomTask(t, user.ID)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mockdb.NewMockStorage(ctrl)
+	storage.EXPECT().
+		GetProject(gomock.Any(), gomock.Eq(project.ID)).
+		Times(1).
+		Return(project, nil)
+	storage.EXPECT().
+		GetTasks(gomock.Any(), gomock.Eq(store.GetTasksParams{
+			UserID:    user.ID,
+			Completed: pgtype.Bool{Bool: false, Valid: true},
+			ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+			Limit:     5,
+		})).
+		Times(1).
+		Return([]store.GetTasksRow{{
+			ID:        task.ID,
+			CreatorID: user.ID,
+			ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+			Total:     1,
+		}}, nil)
+	stubNoTaskTags(storage)
+
+	url := fmt.Sprintf("/projects/%d/tasks?completed=false", project.ID)
+	recorder := serveAuthenticatedRequest(t, storage, user, http.MethodGet, url, nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp struct {
+		Data getTasksResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Len(t, rsp.Data.Tasks, 1)
+	require.Equal(t, project.ID, rsp.Data.Tasks[0].ProjectID.Int64)
+}
+
+func TestTaskProject(t *testing.T) {
+	user, _ := randomUser(t)
+	project := randomProject(user.ID)
+	archivedProject := randomProject(user.ID)
+	archivedProject.ID = project.ID + 1
+	archivedProject.Archived = true
+	otherProject := randomProject(user.ID + 1)
+	otherProject.ID = project.ID + 2
+	task := randomTask(t, user.ID)
+	taskInProject := task
+	taskInProject.ProjectID = pgtype.Int8{Int64: project.ID, Valid: true}
+
+	stubProjects := func(storage *mockdb.MockStorage) {
+		for _, p := range []store.Project{project, archivedProject, otherProject} {
+			storage.EXPECT().
+				GetProject(gomock.Any(), gomock.Eq(p.ID)).
+				AnyTimes().
+				Return(p, nil)
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		method     string
+		body       gin.H
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name:   "CreateInProject",
+			method: http.MethodPost,
+			body: gin.H{
+				"title":      task.Title,
+				"deadline":   task.Deadline.Format(time.RFC3339),
+				"project_id": project.ID,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.CreateTaskParams) (store.Task, error) {
+						require.Equal(t, pgtype.Int8{Int64: project.ID, Valid: true}, arg.ProjectID)
+						return taskInProject, nil
+					})
+			},
+			expectCode: http.StatusCreated,
+		},
+		{
+			name:   "CreateInArchivedProject",
+			method: http.MethodPost,
+			body: gin.H{
+				"title":      task.Title,
+				"deadline":   task.Deadline.Format(time.RFC3339),
+				"project_id": archivedProject.ID,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:   "CreateInProjectOfOtherUser",
+			method: http.MethodPost,
+			body: gin.H{
+				"title":      task.Title,
+				"deadline":   task.Deadline.Format(time.RFC3339),
+				"project_id": otherProject.ID,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:   "MoveIntoProject",
+			method: http.MethodPut,
+			body:   gin.H{"project_id": project.ID},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					SetTaskProject(gomock.Any(), gomock.Eq(store.SetTaskProjectParams{
+						ID:        task.ID,
+						ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+					})).
+					Times(1).
+					Return(taskInProject, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:   "RemoveFromProject",
+			method: http.MethodPut,
+			body:   gin.H{"project_id": 0},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(taskInProject, nil)
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(taskInProject, nil)
+				storage.EXPECT().
+					SetTaskProject(gomock.Any(), gomock.Eq(store.SetTaskProjectParams{
+						ID: task.ID,
+					})).
+					Times(1).
+					Return(task, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:   "KeepProject",
+			method: http.MethodPut,
+			body:   gin.H{"project_id": project.ID},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(taskInProject, nil)
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(taskInProject, nil)
+				storage.EXPECT().
+					SetTaskProject(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:   "MoveIntoArchivedProject",
+			method: http.MethodPut,
+			body:   gin.H{"project_id": archivedProject.ID},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+			stubProjects(storage)
+			stubNoTaskTags(storage)
+
+			url := "/tasks"
+			if tc.method == http.MethodPut {
+				url += "/" + task.ID
+			}
+			recorder := serveAuthenticatedRequest(t, storage, user, tc.method, url, tc.body)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
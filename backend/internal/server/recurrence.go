@@ -0,0 +1,301 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/recurrence"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+)
+
+// upcomingOccurrences is the number of deadlines listed by
+// getTaskRecurrenceHandler.
+const upcomingOccurrences = 5
+
+// recurrenceScopeFuture applies an update to the task and to the occurrences
+// that follow it, rather than to the task alone.
+const recurrenceScopeFuture = "future"
+
+var (
+	errTaskNotRecurring    = errors.New("task does not recur")
+	errRecurrenceEnded     = errors.New("the recurrence has no further occurrences")
+	errOccurrenceCompleted = errors.New("a completed occurrence cannot be skipped")
+	errOccurrenceExists    = errors.New("the next occurrence already exists")
+)
+
+// taskSeries is the recurrence of a task together with its parsed rule.
+type taskSeries struct {
+	store.TaskRecurrence
+	rule     recurrence.Rule
+	location *time.Location
+}
+
+// nextDeadline returns the deadline of the occurrence after the given one.
+func (series taskSeries) nextDeadline(occurrence int32) (time.Time, bool) {
+	return series.rule.Occurrence(series.FirstDeadline, series.location, int(occurrence)+1)
+}
+
+// getTaskSeries loads the recurrence of a task through q. The task must recur.
+func (s *Server) getTaskSeries(ctx *gin.Context, q store.Querier, task store.Task) (taskSeries, error) {
+	rec, err := q.GetTaskRecurrence(ctx, task.RecurrenceID.Int64)
+	if err != nil {
+		return taskSeries{}, err
+	}
+
+	rule, err := recurrence.Parse(rec.Rule)
+	if err != nil {
+		return taskSeries{}, err
+	}
+
+	location, err := time.LoadLocation(rec.Timezone)
+	if err != nil {
+		return taskSeries{}, err
+	}
+
+	return taskSeries{
+		TaskRecurrence: rec,
+		rule:           rule,
+		location:       location,
+	}, nil
+}
+
+// getUserTimezone returns the timezone in which new series of the user are
+// expanded.
+func (s *Server) getUserTimezone(ctx *gin.Context, userID int64) (string, error) {
+	user, err := s.storage.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return user.Timezone, nil
+}
+
+// createNextOccurrence creates the occurrence that follows a task that has
+// just been completed, with the given tags of the task. It runs through q, the
+// transaction of the completion, so that the series is never left completed
+// without its next occurrence. It returns nil when the series has ended or
+// when the next occurrence was already created by an earlier completion.
+func (s *Server) createNextOccurrence(ctx *gin.Context, q store.Querier, task store.Task, tags []store.Tag) (*taskResponse, error) {
+	series, err := s.getTaskSeries(ctx, q, task)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline, ok := series.nextDeadline(task.Occurrence.Int32)
+	if !ok {
+		return nil, nil
+	}
+
+	id, err := gonanoid.New()
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := q.CreateTaskOccurrence(ctx, store.CreateTaskOccurrenceParams{
+		ID:          id,
+		Deadline:    deadline,
+		ParentID:    task.ParentID,
+		ProjectID:   task.ProjectID,
+		WorkspaceID: task.WorkspaceID,
+		AssigneeID:  task.AssigneeID,
+		Occurrence: pgtype.Int4{
+			Int32: task.Occurrence.Int32 + 1,
+			Valid: true,
+		},
+		RecurrenceID: series.ID,
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// The next occurrence keeps the tags and shares of the one that was
+	// completed, and its reminders relative to the deadline.
+	err = q.CopyTaskReminders(ctx, store.CopyTaskRemindersParams{
+		FromTaskID: task.ID,
+		ToTaskID:   next.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = q.CopyTaskShares(ctx, store.CopyTaskSharesParams{
+		FromTaskID: task.ID,
+		ToTaskID:   next.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tags) > 0 {
+		if err := s.setTaskTags(ctx, q, next.ID, tags); err != nil {
+			return nil, err
+		}
+	}
+
+	return &taskResponse{
+		Task: next,
+		Tags: tags,
+	}, nil
+}
+
+// updateTaskRecurrence applies the recurrence fields of an update request to
+// a task that has already been updated, and returns the task as stored. The
+// series is changed through q, the transaction of the update.
+func (s *Server) updateTaskRecurrence(ctx *gin.Context, q store.Querier, task store.Task, req updateTaskRequest, rule *recurrence.Rule) (store.Task, error) {
+	if req.RecurrenceRule != nil && rule == nil {
+		if !task.RecurrenceID.Valid {
+			return task, nil
+		}
+		return q.StopTaskRecurrence(ctx, task.ID)
+	}
+
+	var timezone string
+	if task.RecurrenceID.Valid {
+		series, err := s.getTaskSeries(ctx, q, task)
+		if err != nil {
+			return store.Task{}, err
+		}
+		timezone = series.Timezone
+
+		// Editing this and future occurrences keeps the rule, minus the
+		// occurrences that already belong to the old series.
+		if rule == nil {
+			continued := series.rule
+			if continued.Count > 0 {
+				continued.Count -= int(task.Occurrence.Int32) - 1
+			}
+			rule = &continued
+		}
+	} else {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		var err error
+		timezone, err = s.getUserTimezone(ctx, authPayload.UserID)
+		if err != nil {
+			return store.Task{}, err
+		}
+	}
+
+	return q.SplitTaskRecurrence(ctx, store.SplitTaskRecurrenceParams{
+		ID:       task.ID,
+		Rule:     rule.String(),
+		Timezone: timezone,
+	})
+}
+
+type taskRecurrenceRequest struct {
+	ID string `uri:"id" binding:"required"`
+}
+
+type taskRecurrenceResponse struct {
+	Rule          string    `json:"rule"`
+	Timezone      string    `json:"timezone"`
+	FirstDeadline time.Time `json:"first_deadline"`
+	// Occurrence is the position of the task in its series, starting at 1.
+	Occurrence int32 `json:"occurrence"`
+	// Upcoming lists the deadlines of the occurrences after the task.
+	Upcoming []time.Time `json:"upcoming"`
+}
+
+func (s *Server) getTaskRecurrenceHandler(ctx *gin.Context) {
+	var req taskRecurrenceRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	task, _, ok := s.getAccessibleTask(ctx, req.ID, taskRoleViewer)
+	if !ok {
+		return
+	}
+	if !task.RecurrenceID.Valid {
+		ctx.JSON(http.StatusNotFound, errorResponse(errTaskNotRecurring))
+		return
+	}
+
+	series, err := s.getTaskSeries(ctx, s.storage, task)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	upcoming := series.rule.Occurrences(series.FirstDeadline, series.location, int(task.Occurrence.Int32)+1, upcomingOccurrences)
+	for i := range upcoming {
+		upcoming[i] = upcoming[i].UTC()
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(taskRecurrenceResponse{
+		Rule:          series.Rule,
+		Timezone:      series.Timezone,
+		FirstDeadline: series.FirstDeadline,
+		Occurrence:    task.Occurrence.Int32,
+		Upcoming:      upcoming,
+	}))
+}
+
+// skipTaskOccurrenceHandler moves an open occurrence to the next deadline of
+// its series, as if the skipped occurrence had never existed.
+func (s *Server) skipTaskOccurrenceHandler(ctx *gin.Context) {
+	var req taskRecurrenceRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	task, _, ok := s.getAccessibleTask(ctx, req.ID, taskRoleEditor)
+	if !ok {
+		return
+	}
+	if !task.RecurrenceID.Valid {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errTaskNotRecurring))
+		return
+	}
+
+	if task.Completed {
+		ctx.JSON(http.StatusConflict, errorResponse(errOccurrenceCompleted))
+		return
+	}
+
+	series, err := s.getTaskSeries(ctx, s.storage, task)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	deadline, ok := series.nextDeadline(task.Occurrence.Int32)
+	if !ok {
+		ctx.JSON(http.StatusConflict, errorResponse(errRecurrenceEnded))
+		return
+	}
+
+	skippedTask, err := s.storage.SkipTaskOccurrence(ctx, store.SkipTaskOccurrenceParams{
+		ID:       task.ID,
+		Deadline: deadline,
+		Occurrence: pgtype.Int4{
+			Int32: task.Occurrence.Int32 + 1,
+			Valid: true,
+		},
+	})
+	if err != nil {
+		if store.ErrorCode(err) == store.UniqueViolation {
+			ctx.JSON(http.StatusConflict, errorResponse(errOccurrenceExists))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := s.taskResponses(ctx, []store.Task{skippedTask})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp[0]))
+}
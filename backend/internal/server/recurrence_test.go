@@ -0,0 +1,574 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func randomRecurrence(userID int64, rule string, timezone string, firstDeadline time.Time) store.TaskRecurrence {
+	return store.TaskRecurrence{
+		ID:            7,
+		CreatorID:     userID,
+		Rule:          rule,
+		FirstDeadline: firstDeadline,
+		Timezone:      timezone,
+		Title:         "Weekly report",
+		Priority:      "medium",
+	}
+}
+
+// randomOccurrence returns the given occurrence of the series as a task.
+func randomOccurrence(t *testing.T, rec store.TaskRecurrence, occurrence int32, deadline time.Time) store.Task {
+	task := randomTask(t, rec.CreatorID)
+	task.Title = rec.Title
+	task.Deadline = deadline
+	task.RecurrenceID = pgtype.Int8{Int64: rec.ID, Valid: true}
+	task.Occurrence = pgtype.Int4{Int32: occurrence, Valid: true}
+	return task
+}
+
+func TestCreateRecurringTask(t *testing.T) {
+	user, _ := randomUser(t)
+	user.Timezone = "Europe/Berlin"
+	deadline := time.Date(2025, time.October, 20, 7, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name       string
+		body       gin.H
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"title":           "Weekly report",
+				"deadline":        deadline.Format(time.RFC3339),
+				"recurrence_rule": "RRULE:freq=weekly;byday=mo;count=10",
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					CreateRecurringTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.CreateRecurringTaskParams) (store.Task, error) {
+						require.Equal(t, "FREQ=WEEKLY;COUNT=10;BYDAY=MO", arg.Rule)
+						require.Equal(t, "Europe/Berlin", arg.Timezone)
+						require.True(t, deadline.Equal(arg.Deadline))
+						return randomOccurrence(t, randomRecurrence(user.ID, arg.Rule, arg.Timezone, deadline), 1, deadline), nil
+					})
+				storage.EXPECT().
+					CreateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusCreated,
+		},
+		{
+			name: "InvalidRule",
+			body: gin.H{
+				"title":           "Weekly report",
+				"deadline":        deadline.Format(time.RFC3339),
+				"recurrence_rule": "FREQ=HOURLY",
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateRecurringTask(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					CreateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPost, "/tasks", tc.body)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestCompleteRecurringTask(t *testing.T) {
+	user, _ := randomUser(t)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// 09:00 in New York, the day before DST starts.
+	first := time.Date(2025, time.March, 8, 9, 0, 0, 0, newYork)
+	rec := randomRecurrence(user.ID, "FREQ=DAILY;COUNT=3", "America/New_York", first)
+	tag := randomTag(user.ID)
+
+	testCases := []struct {
+		name       string
+		occurrence int32
+		buildStubs func(storage *mockdb.MockStorage, task store.Task)
+		status     int
+		checkNext  func(t *testing.T, next *taskResponse)
+	}{
+		{
+			name:       "CreatesNextOccurrence",
+			occurrence: 1,
+			buildStubs: func(storage *mockdb.MockStorage, task store.Task) {
+				storage.EXPECT().
+					ListTagsForTasks(gomock.Any(), gomock.Any()).
+					AnyTimes().
+					Return([]store.ListTagsForTasksRow{{
+						TaskID: task.ID,
+						ID:     tag.ID,
+						UserID: tag.UserID,
+						Name:   tag.Name,
+						Color:  tag.Color,
+					}}, nil)
+				storage.EXPECT().
+					CreateTaskOccurrence(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.CreateTaskOccurrenceParams) (store.Task, error) {
+						// The next day is still 09:00 in New York, now at UTC-4.
+						require.Equal(t, time.Date(2025, time.March, 9, 13, 0, 0, 0, time.UTC), arg.Deadline.UTC())
+						require.Equal(t, int32(2), arg.Occurrence.Int32)
+						require.Equal(t, rec.ID, arg.RecurrenceID)
+						return randomOccurrence(t, rec, 2, arg.Deadline), nil
+					})
+				storage.EXPECT().
+					CopyTaskReminders(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.CopyTaskRemindersParams) error {
+						require.Equal(t, task.ID, arg.FromTaskID)
+						return nil
+					})
+				storage.EXPECT().
+					CopyTaskShares(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.CopyTaskSharesParams) error {
+						require.Equal(t, task.ID, arg.FromTaskID)
+						return nil
+					})
+				storage.EXPECT().
+					SetTaskTags(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.SetTaskTagsParams) error {
+						require.Equal(t, []int64{tag.ID}, arg.TagIds)
+						return nil
+					})
+			},
+			status: http.StatusOK,
+			checkNext: func(t *testing.T, next *taskResponse) {
+				require.NotNil(t, next)
+				require.Equal(t, int32(2), next.Occurrence.Int32)
+				require.Len(t, next.Tags, 1)
+			},
+		},
+		{
+			name:       "AlreadyCreated",
+			occurrence: 1,
+			buildStubs: func(storage *mockdb.MockStorage, task store.Task) {
+				stubNoTaskTags(storage)
+				storage.EXPECT().
+					CreateTaskOccurrence(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.Task{}, store.ErrRecordNotFound)
+			},
+			status: http.StatusOK,
+			checkNext: func(t *testing.T, next *taskResponse) {
+				require.Nil(t, next)
+			},
+		},
+		{
+			name:       "SeriesEnded",
+			occurrence: 3,
+			buildStubs: func(storage *mockdb.MockStorage, task store.Task) {
+				stubNoTaskTags(storage)
+				storage.EXPECT().
+					CreateTaskOccurrence(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			status: http.StatusOK,
+			checkNext: func(t *testing.T, next *taskResponse) {
+				require.Nil(t, next)
+			},
+		},
+		{
+			// The completion is rolled back, so that a retry creates the
+			// next occurrence again rather than ending the series.
+			name:       "CopySharesError",
+			occurrence: 1,
+			buildStubs: func(storage *mockdb.MockStorage, task store.Task) {
+				stubNoTaskTags(storage)
+				storage.EXPECT().
+					CreateTaskOccurrence(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.CreateTaskOccurrenceParams) (store.Task, error) {
+						return randomOccurrence(t, rec, 2, arg.Deadline), nil
+					})
+				storage.EXPECT().
+					CopyTaskReminders(gomock.Any(), gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					CopyTaskShares(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+				storage.EXPECT().
+					SetTaskTags(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			task := randomOccurrence(t, rec, tc.occurrence, first.AddDate(0, 0, int(tc.occurrence)-1))
+			completedTask := task
+			completedTask.Completed = true
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			storage.EXPECT().
+				GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+				Times(1).
+				Return(task, nil)
+			storage.EXPECT().
+				CountOpenBlockers(gomock.Any(), gomock.Eq(task.ID)).
+				Times(1).
+				Return(int64(0), nil)
+			storage.EXPECT().
+				UpdateTask(gomock.Any(), gomock.Any()).
+				Times(1).
+				Return(completedTask, nil)
+			storage.EXPECT().
+				GetTaskRecurrence(gomock.Any(), gomock.Eq(rec.ID)).
+				Times(1).
+				Return(rec, nil)
+			tc.buildStubs(storage, task)
+
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPut, "/tasks/"+task.ID, gin.H{"completed": true})
+			require.Equal(t, tc.status, recorder.Code)
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			var rsp struct {
+				Data taskResponse `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+			require.True(t, rsp.Data.Completed)
+			tc.checkNext(t, rsp.Data.NextOccurrence)
+		})
+	}
+}
+
+func TestUpdateTaskRecurrence(t *testing.T) {
+	user, _ := randomUser(t)
+	first := time.Date(2025, time.June, 2, 9, 0, 0, 0, time.UTC)
+	rec := randomRecurrence(user.ID, "FREQ=WEEKLY;COUNT=10", "UTC", first)
+	recurringTask := randomOccurrence(t, rec, 4, first.AddDate(0, 0, 21))
+	plainTask := randomTask(t, user.ID)
+
+	testCases := []struct {
+		name       string
+		task       store.Task
+		body       gin.H
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name: "ThisAndFuture",
+			task: recurringTask,
+			body: gin.H{"title": "Weekly summary", "recurrence_scope": "future"},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(recurringTask, nil)
+				storage.EXPECT().
+					GetTaskRecurrence(gomock.Any(), gomock.Eq(rec.ID)).
+					Times(1).
+					Return(rec, nil)
+				// Occurrences 1 to 3 stay with the old series.
+				storage.EXPECT().
+					SplitTaskRecurrence(gomock.Any(), gomock.Eq(store.SplitTaskRecurrenceParams{
+						ID:       recurringTask.ID,
+						Rule:     "FREQ=WEEKLY;COUNT=7",
+						Timezone: "UTC",
+					})).
+					Times(1).
+					Return(recurringTask, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name: "NewRule",
+			task: recurringTask,
+			body: gin.H{"recurrence_rule": "FREQ=MONTHLY;BYDAY=1MO"},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(recurringTask, nil)
+				storage.EXPECT().
+					GetTaskRecurrence(gomock.Any(), gomock.Eq(rec.ID)).
+					Times(1).
+					Return(rec, nil)
+				storage.EXPECT().
+					SplitTaskRecurrence(gomock.Any(), gomock.Eq(store.SplitTaskRecurrenceParams{
+						ID:       recurringTask.ID,
+						Rule:     "FREQ=MONTHLY;BYDAY=1MO",
+						Timezone: "UTC",
+					})).
+					Times(1).
+					Return(recurringTask, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name: "StartRecurring",
+			task: plainTask,
+			body: gin.H{"recurrence_rule": "FREQ=DAILY"},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(plainTask, nil)
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					SplitTaskRecurrence(gomock.Any(), gomock.Eq(store.SplitTaskRecurrenceParams{
+						ID:       plainTask.ID,
+						Rule:     "FREQ=DAILY",
+						Timezone: user.Timezone,
+					})).
+					Times(1).
+					Return(plainTask, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name: "Stop",
+			task: recurringTask,
+			body: gin.H{"recurrence_rule": ""},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(recurringTask, nil)
+				storage.EXPECT().
+					StopTaskRecurrence(gomock.Any(), gomock.Eq(recurringTask.ID)).
+					Times(1).
+					Return(plainTask, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name: "InvalidRule",
+			task: recurringTask,
+			body: gin.H{"recurrence_rule": "FREQ=WEEKLY;BYMONTHDAY=1"},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name: "FutureOfPlainTask",
+			task: plainTask,
+			body: gin.H{"title": "New title", "recurrence_scope": "future"},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name: "InvalidScope",
+			task: recurringTask,
+			body: gin.H{"recurrence_scope": "all"},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			storage.EXPECT().
+				GetTaskByID(gomock.Any(), gomock.Eq(tc.task.ID)).
+				Times(1).
+				Return(tc.task, nil)
+			tc.buildStubs(storage)
+			stubNoTaskTags(storage)
+
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPut, "/tasks/"+tc.task.ID, tc.body)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestSkipTaskOccurrenceHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	first := time.Date(2025, time.January, 31, 17, 0, 0, 0, time.UTC)
+	rec := randomRecurrence(user.ID, "FREQ=MONTHLY;COUNT=3", "UTC", first)
+	task := randomOccurrence(t, rec, 1, first)
+	lastTask := randomOccurrence(t, rec, 3, time.Date(2025, time.May, 31, 17, 0, 0, 0, time.UTC))
+	completedTask := task
+	completedTask.Completed = true
+	plainTask := randomTask(t, user.ID)
+
+	testCases := []struct {
+		name       string
+		task       store.Task
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name: "OK",
+			task: task,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskRecurrence(gomock.Any(), gomock.Eq(rec.ID)).
+					Times(1).
+					Return(rec, nil)
+				// February has no 31st.
+				storage.EXPECT().
+					SkipTaskOccurrence(gomock.Any(), gomock.Eq(store.SkipTaskOccurrenceParams{
+						ID:         task.ID,
+						Deadline:   time.Date(2025, time.March, 31, 17, 0, 0, 0, time.UTC),
+						Occurrence: pgtype.Int4{Int32: 2, Valid: true},
+					})).
+					Times(1).
+					Return(randomOccurrence(t, rec, 2, time.Date(2025, time.March, 31, 17, 0, 0, 0, time.UTC)), nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name: "LastOccurrence",
+			task: lastTask,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskRecurrence(gomock.Any(), gomock.Eq(rec.ID)).
+					Times(1).
+					Return(rec, nil)
+				storage.EXPECT().
+					SkipTaskOccurrence(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusConflict,
+		},
+		{
+			name: "Completed",
+			task: completedTask,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					SkipTaskOccurrence(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusConflict,
+		},
+		{
+			name: "NextOccurrenceExists",
+			task: task,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskRecurrence(gomock.Any(), gomock.Eq(rec.ID)).
+					Times(1).
+					Return(rec, nil)
+				storage.EXPECT().
+					SkipTaskOccurrence(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.Task{}, store.ErrUniqueViolation)
+			},
+			expectCode: http.StatusConflict,
+		},
+		{
+			name: "NotRecurring",
+			task: plainTask,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					SkipTaskOccurrence(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			storage.EXPECT().
+				GetTaskByID(gomock.Any(), gomock.Eq(tc.task.ID)).
+				Times(1).
+				Return(tc.task, nil)
+			tc.buildStubs(storage)
+			stubNoTaskTags(storage)
+
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPost, "/tasks/"+tc.task.ID+"/skip", nil)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestGetTaskRecurrenceHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	first := time.Date(2025, time.June, 2, 9, 0, 0, 0, time.UTC)
+	rec := randomRecurrence(user.ID, "FREQ=WEEKLY;COUNT=4", "UTC", first)
+	task := randomOccurrence(t, rec, 2, first.AddDate(0, 0, 7))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mockdb.NewMockStorage(ctrl)
+	storage.EXPECT().
+		GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+		Times(1).
+		Return(task, nil)
+	storage.EXPECT().
+		GetTaskRecurrence(gomock.Any(), gomock.Eq(rec.ID)).
+		Times(1).
+		Return(rec, nil)
+
+	recorder := serveAuthenticatedRequest(t, storage, user, http.MethodGet, "/tasks/"+task.ID+"/recurrence", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp struct {
+		Data taskRecurrenceResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, rec.Rule, rsp.Data.Rule)
+	require.Equal(t, int32(2), rsp.Data.Occurrence)
+	require.Equal(t, []time.Time{first.AddDate(0, 0, 14), first.AddDate(0, 0, 21)}, rsp.Data.Upcoming)
+}
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+)
+
+// maxReminderOffset bounds how long before its deadline a reminder may fire.
+const maxReminderOffset = 365 * 24 * time.Hour
+
+var (
+	errReminderTime     = errors.New("exactly one of remind_at and before is required")
+	errReminderInPast   = errors.New("remind_at must be in the future")
+	errReminderOffset   = fmt.Errorf("before must be a duration between 1m and %dd, e.g. 30m, 1h or 2d", int(maxReminderOffset.Hours()/24))
+	errReminderNotFound = errors.New("reminder not found")
+)
+
+type reminderResponse struct {
+	ID       int64              `json:"id"`
+	TaskID   string             `json:"task_id"`
+	RemindAt pgtype.Timestamptz `json:"remind_at"`
+	// OffsetSeconds is set for reminders relative to the deadline, which
+	// follow the deadline when it changes.
+	OffsetSeconds pgtype.Int4        `json:"offset_seconds"`
+	FireAt        time.Time          `json:"fire_at"`
+	SentAt        pgtype.Timestamptz `json:"sent_at"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+func newReminderResponse(reminder store.TaskReminder) reminderResponse {
+	return reminderResponse{
+		ID:            reminder.ID,
+		TaskID:        reminder.TaskID,
+		RemindAt:      reminder.RemindAt,
+		OffsetSeconds: reminder.OffsetSeconds,
+		FireAt:        reminder.FireAt,
+		SentAt:        reminder.SentAt,
+		CreatedAt:     reminder.CreatedAt,
+	}
+}
+
+// parseReminderOffset accepts Go durations such as "90m" or "1h30m" as well
+// as whole days and weeks such as "2d" or "1w".
+func parseReminderOffset(value string) (time.Duration, error) {
+	var offset time.Duration
+	if unit := value[len(value)-1:]; unit == "d" || unit == "w" {
+		n, err := strconv.Atoi(strings.TrimSuffix(value, unit))
+		if err != nil {
+			return 0, errReminderOffset
+		}
+		offset = time.Duration(n) * 24 * time.Hour
+		if unit == "w" {
+			offset *= 7
+		}
+	} else {
+		var err error
+		offset, err = time.ParseDuration(value)
+		if err != nil {
+			return 0, errReminderOffset
+		}
+	}
+
+	if offset < time.Minute || offset > maxReminderOffset {
+		return 0, errReminderOffset
+	}
+	return offset, nil
+}
+
+type taskRemindersURIRequest struct {
+	ID string `uri:"id" binding:"required"`
+}
+
+func (s *Server) listTaskRemindersHandler(ctx *gin.Context) {
+	var req taskRemindersURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	task, _, ok := s.getAccessibleTask(ctx, req.ID, taskRoleOwner)
+	if !ok {
+		return
+	}
+	reminders, err := s.storage.ListTaskReminders(ctx, task.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]reminderResponse, 0, len(reminders))
+	for _, reminder := range reminders {
+		rsp = append(rsp, newReminderResponse(reminder))
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp))
+}
+
+type createTaskReminderRequest struct {
+	// RemindAt is the time the reminder fires at.
+	RemindAt string `json:"remind_at" binding:"omitempty,iso8601"`
+	// Before is how long before the deadline the reminder fires, e.g. "1h".
+	Before string `json:"before" binding:"omitempty"`
+}
+
+func (s *Server) createTaskReminderHandler(ctx *gin.Context) {
+	var uri taskRemindersURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	task, _, ok := s.getAccessibleTask(ctx, uri.ID, taskRoleOwner)
+	if !ok {
+		return
+	}
+
+	var req createTaskReminderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if (len(req.RemindAt) > 0) == (len(req.Before) > 0) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errReminderTime))
+		return
+	}
+
+	arg := store.CreateTaskReminderParams{
+		TaskID: task.ID,
+	}
+
+	if len(req.RemindAt) > 0 {
+		remindAt, _ := time.Parse(time.RFC3339, req.RemindAt)
+		if !remindAt.After(time.Now()) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errReminderInPast))
+			return
+		}
+		arg.RemindAt = pgtype.Timestamptz{
+			Time:  remindAt,
+			Valid: true,
+		}
+	} else {
+		offset, err := parseReminderOffset(req.Before)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.OffsetSeconds = pgtype.Int4{
+			Int32: int32(offset / time.Second),
+			Valid: true,
+		}
+	}
+
+	reminder, err := s.storage.CreateTaskReminder(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, successResponse(newReminderResponse(reminder)))
+}
+
+type deleteTaskReminderRequest struct {
+	ID         string `uri:"id" binding:"required"`
+	ReminderID int64  `uri:"reminder_id" binding:"required,min=1"`
+}
+
+func (s *Server) deleteTaskReminderHandler(ctx *gin.Context) {
+	var req deleteTaskReminderRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	task, _, ok := s.getAccessibleTask(ctx, req.ID, taskRoleOwner)
+	if !ok {
+		return
+	}
+	rows, err := s.storage.DeleteTaskReminder(ctx, store.DeleteTaskReminderParams{
+		ID:     req.ReminderID,
+		TaskID: task.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if rows == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(errReminderNotFound))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(nil))
+}
@@ -0,0 +1,248 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func randomReminder(task store.Task, offset time.Duration) store.TaskReminder {
+	return store.TaskReminder{
+		ID:     1,
+		TaskID: task.ID,
+		OffsetSeconds: pgtype.Int4{
+			Int32: int32(offset / time.Second),
+			Valid: true,
+		},
+		FireAt:    task.Deadline.Add(-offset),
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestListTaskRemindersHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	task := randomTask(t, user.ID)
+	reminder := randomReminder(task, time.Hour)
+	reminder.ClaimedUntil = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mockdb.NewMockStorage(ctrl)
+	storage.EXPECT().
+		GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+		Times(1).
+		Return(task, nil)
+	storage.EXPECT().
+		ListTaskReminders(gomock.Any(), gomock.Eq(task.ID)).
+		Times(1).
+		Return([]store.TaskReminder{reminder}, nil)
+
+	recorder := serveAuthenticatedRequest(t, storage, user, http.MethodGet, "/tasks/"+task.ID+"/reminders", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp struct {
+		Data []map[string]any `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Len(t, rsp.Data, 1)
+	require.Equal(t, float64(3600), rsp.Data[0]["offset_seconds"])
+	// Leases are an implementation detail of the scheduler.
+	require.NotContains(t, rsp.Data[0], "claimed_until")
+}
+
+func TestCreateTaskReminderHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	task := randomTask(t, user.ID)
+	otherTask := randomTask(t, user.ID+1)
+	remindAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	testCases := []struct {
+		name       string
+		task       store.Task
+		body       gin.H
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name: "Before",
+			task: task,
+			body: gin.H{"before": "2d"},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateTaskReminder(gomock.Any(), gomock.Eq(store.CreateTaskReminderParams{
+						TaskID:        task.ID,
+						OffsetSeconds: pgtype.Int4{Int32: 2 * 24 * 3600, Valid: true},
+					})).
+					Times(1).
+					Return(randomReminder(task, 48*time.Hour), nil)
+			},
+			expectCode: http.StatusCreated,
+		},
+		{
+			name: "RemindAt",
+			task: task,
+			body: gin.H{"remind_at": remindAt.Format(time.RFC3339)},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateTaskReminder(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.CreateTaskReminderParams) (store.TaskReminder, error) {
+						require.True(t, remindAt.Equal(arg.RemindAt.Time))
+						require.False(t, arg.OffsetSeconds.Valid)
+						return store.TaskReminder{ID: 2, TaskID: task.ID, RemindAt: arg.RemindAt, FireAt: remindAt}, nil
+					})
+			},
+			expectCode: http.StatusCreated,
+		},
+		{
+			name: "RemindAtInPast",
+			task: task,
+			body: gin.H{"remind_at": time.Now().Add(-time.Hour).Format(time.RFC3339)},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateTaskReminder(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name: "BothTimes",
+			task: task,
+			body: gin.H{"before": "1h", "remind_at": remindAt.Format(time.RFC3339)},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateTaskReminder(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name: "NoTime",
+			task: task,
+			body: gin.H{},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateTaskReminder(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name: "InvalidOffset",
+			task: task,
+			body: gin.H{"before": "soon"},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateTaskReminder(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name: "Unauthorized",
+			task: otherTask,
+			body: gin.H{"before": "1h"},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskShare(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.TaskShare{}, store.ErrRecordNotFound)
+				storage.EXPECT().
+					CreateTaskReminder(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			storage.EXPECT().
+				GetTaskByID(gomock.Any(), gomock.Eq(tc.task.ID)).
+				Times(1).
+				Return(tc.task, nil)
+			tc.buildStubs(storage)
+
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPost, "/tasks/"+tc.task.ID+"/reminders", tc.body)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestDeleteTaskReminderHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	task := randomTask(t, user.ID)
+
+	testCases := []struct {
+		name       string
+		rows       int64
+		expectCode int
+	}{
+		{
+			name:       "OK",
+			rows:       1,
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "NotFound",
+			rows:       0,
+			expectCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			storage.EXPECT().
+				GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+				Times(1).
+				Return(task, nil)
+			storage.EXPECT().
+				DeleteTaskReminder(gomock.Any(), gomock.Eq(store.DeleteTaskReminderParams{
+					ID:     5,
+					TaskID: task.ID,
+				})).
+				Times(1).
+				Return(tc.rows, nil)
+
+			url := fmt.Sprintf("/tasks/%s/reminders/%d", task.ID, 5)
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodDelete, url, nil)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestParseReminderOffset(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"30m":   30 * time.Minute,
+		"1h30m": 90 * time.Minute,
+		"2d":    48 * time.Hour,
+		"1w":    7 * 24 * time.Hour,
+	} {
+		offset, err := parseReminderOffset(value)
+		require.NoError(t, err, value)
+		require.Equal(t, expected, offset, value)
+	}
+
+	for _, value := range []string{"d", "1.5d", "30s", "-1h", "0w", "400d", "soon"} {
+		_, err := parseReminderOffset(value)
+		require.ErrorIs(t, err, errReminderOffset, value)
+	}
+}
@@ -11,45 +11,157 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/mailer"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/notifier"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/oidc"
 	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
 	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
 	"github.com/nguyen-duc-loc/task-management/backend/util"
 )
 
 type Server struct {
-	Port       int
-	router     *gin.Engine
-	storage    store.Storage
-	tokenMaker token.Maker
+	Port          int
+	router        *gin.Engine
+	storage       store.Storage
+	tokenMaker    token.Maker
+	notifier      notifier.Notifier
+	mailer        mailer.Sender
+	emailSigner   *token.LinkSigner
+	mfaSigner     *token.LinkSigner
+	oidcProvider  *oidc.Provider
+	cookieConfig  util.CookieConfig
+	publicBaseURL string
 }
 
 func (s *Server) RegisterRoutes() http.Handler {
 	s.router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{fmt.Sprintf("http://localhost:%s", os.Getenv("FRONTEND_PORT"))},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type"},
+		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type", csrfHeaderKey, workspaceHeaderKey},
 		AllowCredentials: true, // Enable cookies/auth
 	}))
 
 	s.router.GET("/health", s.healthHandler)
+	s.router.GET("/.well-known/jwks.json", s.jwksHandler)
 
 	s.router.POST("/users", s.createUserHandler)
 	s.router.POST("/users/login", s.loginUserHandler)
+	s.router.POST("/users/login/mfa", s.loginMFAHandler)
+	s.router.GET("/users/email/verify", s.verifyEmailHandler)
+	s.router.POST("/users/password_reset", s.requestPasswordResetHandler)
+	s.router.POST("/users/password_reset/confirm", s.resetPasswordHandler)
+	s.router.POST("/tokens/renew_access", s.renewAccessTokenHandler)
+	s.router.GET("/auth/oidc/login", s.oidcLoginHandler)
+	s.router.GET("/auth/oidc/callback", s.oidcCallbackHandler)
 
-	authRoutes := s.router.Group("/").Use(authMiddleware(s.tokenMaker))
-	authRoutes.GET("/tasks", s.getTasksHandler)
-	authRoutes.GET("/tasks/:id", s.getTaskByIDHandler)
-	authRoutes.POST("/tasks", s.createTaskHandler)
-	authRoutes.PUT("/tasks/:id", s.updateTasksHandler)
-	authRoutes.DELETE("/tasks/:id", s.deleteTaskHandler)
+	authRoutes := s.router.Group("/").Use(authMiddleware(s.tokenMaker, s.storage))
+	authRoutes.POST("/users/logout", s.logoutUserHandler)
+	authRoutes.POST("/users/logout_all", s.logoutAllUserHandler)
+	authRoutes.GET("/users/me", s.getCurrentUserHandler)
+	authRoutes.PATCH("/users/me", s.updateCurrentUserHandler)
+	authRoutes.DELETE("/users/me", s.deleteCurrentUserHandler)
+	authRoutes.PUT("/users/me/email", s.changeEmailHandler)
+	authRoutes.POST("/users/me/email/verification", s.resendEmailVerificationHandler)
+	authRoutes.PUT("/users/me/password", s.changePasswordHandler)
+	authRoutes.GET("/users/me/security-events", s.listSecurityEventsHandler)
+	authRoutes.GET("/users/me/mfa", s.getMFAStatusHandler)
+	authRoutes.POST("/users/me/mfa/totp", s.enrollTOTPHandler)
+	authRoutes.POST("/users/me/mfa/totp/confirm", s.confirmTOTPHandler)
+	authRoutes.DELETE("/users/me/mfa/totp", s.disableTOTPHandler)
+	authRoutes.POST("/users/me/mfa/recovery_codes", s.regenerateRecoveryCodesHandler)
+	authRoutes.GET("/users/me/tokens", s.listPersonalAccessTokensHandler)
+	authRoutes.POST("/users/me/tokens", s.createPersonalAccessTokenHandler)
+	authRoutes.DELETE("/users/me/tokens/:id", s.revokePersonalAccessTokenHandler)
+	authRoutes.GET("/users/me/notifications", s.listNotificationsHandler)
+	authRoutes.POST("/users/me/notifications/:id/read", s.markNotificationReadHandler)
+
+	authRoutes.POST("/workspaces", s.createWorkspaceHandler)
+	authRoutes.GET("/workspaces", s.listWorkspacesHandler)
+	authRoutes.POST("/invitations/accept", s.acceptWorkspaceInvitationHandler)
+
+	workspaceRoutes := s.router.Group("/workspaces/:workspace_id").Use(authMiddleware(s.tokenMaker, s.storage), workspaceMiddleware(s.storage))
+	workspaceRoutes.GET("", s.getWorkspaceHandler)
+	workspaceRoutes.PATCH("", requireWorkspaceRole(util.WorkspaceRoleOwner, util.WorkspaceRoleAdmin), s.updateWorkspaceHandler)
+	workspaceRoutes.DELETE("", requireWorkspaceRole(util.WorkspaceRoleOwner), s.deleteWorkspaceHandler)
+	workspaceRoutes.GET("/members", s.listWorkspaceMembersHandler)
+	workspaceRoutes.PATCH("/members/:user_id", requireWorkspaceRole(util.WorkspaceRoleOwner, util.WorkspaceRoleAdmin), s.updateWorkspaceMemberHandler)
+	workspaceRoutes.DELETE("/members/:user_id", s.removeWorkspaceMemberHandler)
+	workspaceRoutes.GET("/invitations", requireWorkspaceRole(util.WorkspaceRoleOwner, util.WorkspaceRoleAdmin), s.listWorkspaceInvitationsHandler)
+	workspaceRoutes.POST("/invitations", requireWorkspaceRole(util.WorkspaceRoleOwner, util.WorkspaceRoleAdmin), s.createWorkspaceInvitationHandler)
+	workspaceRoutes.DELETE("/invitations/:invitation_id", requireWorkspaceRole(util.WorkspaceRoleOwner, util.WorkspaceRoleAdmin), s.revokeWorkspaceInvitationHandler)
+
+	taskReadRoutes := s.router.Group("/").Use(authMiddleware(s.tokenMaker, s.storage, scopeTasksRead))
+	taskReadRoutes.GET("/tags", s.listTagsHandler)
+	taskReadRoutes.GET("/projects", s.listProjectsHandler)
+	taskReadRoutes.GET("/projects/:id", s.getProjectHandler)
+	taskReadRoutes.GET("/projects/:id/tasks", s.listProjectTasksHandler)
+
+	taskWriteRoutes := s.router.Group("/").Use(authMiddleware(s.tokenMaker, s.storage, scopeTasksWrite))
+	taskWriteRoutes.POST("/tags", s.createTagHandler)
+	taskWriteRoutes.PATCH("/tags/:id", s.updateTagHandler)
+	taskWriteRoutes.DELETE("/tags/:id", s.deleteTagHandler)
+	taskWriteRoutes.POST("/tags/:id/merge", s.mergeTagHandler)
+	taskWriteRoutes.POST("/projects", s.createProjectHandler)
+	taskWriteRoutes.PATCH("/projects/:id", s.updateProjectHandler)
+	taskWriteRoutes.DELETE("/projects/:id", s.deleteProjectHandler)
+
+	// Task routes are scoped by the X-Workspace-ID header, or by the
+	// workspace in their path.
+	for _, prefix := range []string{"/", "/workspaces/:workspace_id"} {
+		s.registerTaskRoutes(
+			s.router.Group(prefix).Use(authMiddleware(s.tokenMaker, s.storage, scopeTasksRead), workspaceMiddleware(s.storage)),
+			s.router.Group(prefix).Use(authMiddleware(s.tokenMaker, s.storage, scopeTasksWrite), workspaceMiddleware(s.storage)),
+		)
+	}
+
+	adminRoutes := s.router.Group("/admin").Use(authMiddleware(s.tokenMaker, s.storage), requireRole(util.AdminRole))
+	adminRoutes.PUT("/users/:username/role", s.updateUserRoleHandler)
+	adminRoutes.POST("/users/:username/unlock", s.unlockUserLoginHandler)
 
 	return s.router
 }
 
+// registerTaskRoutes registers the task routes on groups that require the
+// tasks:read and tasks:write scopes respectively.
+func (s *Server) registerTaskRoutes(read gin.IRoutes, write gin.IRoutes) {
+	read.GET("/tasks", s.getTasksHandler)
+	read.GET("/tasks/:id", s.getTaskByIDHandler)
+	read.GET("/tasks/:id/children", s.listSubtasksHandler)
+	read.GET("/tasks/:id/dependencies", s.listTaskDependenciesHandler)
+	read.GET("/tasks/:id/recurrence", s.getTaskRecurrenceHandler)
+	read.GET("/tasks/:id/reminders", s.listTaskRemindersHandler)
+	read.GET("/tasks/:id/shares", s.listTaskSharesHandler)
+
+	write.POST("/tasks", s.createTaskHandler)
+	write.PUT("/tasks/:id", s.updateTasksHandler)
+	write.DELETE("/tasks/:id", s.deleteTaskHandler)
+	write.POST("/tasks/:id/move", s.moveTaskHandler)
+	write.POST("/tasks/:id/skip", s.skipTaskOccurrenceHandler)
+	write.POST("/tasks/:id/reminders", s.createTaskReminderHandler)
+	write.DELETE("/tasks/:id/reminders/:reminder_id", s.deleteTaskReminderHandler)
+	write.POST("/tasks/:id/dependencies", s.addTaskDependencyHandler)
+	write.DELETE("/tasks/:id/dependencies/:blocked_by_id", s.removeTaskDependencyHandler)
+	write.POST("/tasks/:id/shares", s.shareTaskHandler)
+	write.DELETE("/tasks/:id/shares/:user_id", s.unshareTaskHandler)
+}
+
 func (server *Server) Start(address string) error {
 	return server.router.Run(address)
 }
 
+func newTokenMaker(jwtConfig util.JWTConfig) (token.Maker, error) {
+	switch jwtConfig.TokenMaker {
+	case "jwt":
+		return token.NewJWTMaker(jwtConfig.SecretKey)
+	case "paseto":
+		return token.NewPasetoMaker(jwtConfig.SecretKey)
+	case "jwt_rs256":
+		return token.NewRSAJWTMakerFromDir(jwtConfig.KeysDir, jwtConfig.SigningKeyID)
+	default:
+		return nil, fmt.Errorf("unsupported token maker %s", jwtConfig.TokenMaker)
+	}
+}
+
 func NewServer(storage store.Storage) (*Server, error) {
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		// ISO8601 validator
@@ -64,17 +176,62 @@ func NewServer(storage store.Storage) (*Server, error) {
 		return nil, err
 	}
 
-	tokenMaker, err := token.NewJWTMaker(jwtConfig.SecretKey)
+	tokenMaker, err := newTokenMaker(jwtConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	mailConfig, err := util.LoadMailConfig()
 	if err != nil {
 		return nil, err
 	}
 
+	oidcConfig, err := util.LoadOIDCConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	cookieConfig, err := util.LoadCookieConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var oidcProvider *oidc.Provider
+	if len(oidcConfig.IssuerURL) > 0 {
+		oidcProvider = oidc.NewProvider(oidcConfig, nil)
+	}
+
 	port, _ := strconv.Atoi(os.Getenv("SERVER_PORT"))
 	newServer := &Server{
-		Port:       port,
-		router:     gin.Default(),
-		storage:    storage,
-		tokenMaker: tokenMaker,
+		Port:          port,
+		router:        gin.Default(),
+		storage:       storage,
+		tokenMaker:    tokenMaker,
+		notifier:      notifier.NewOutboxNotifier(storage),
+		mailer:        mailer.NewSMTPSender(mailConfig),
+		emailSigner:   token.NewLinkSigner(jwtConfig.SecretKey, "email-verification"),
+		mfaSigner:     token.NewLinkSigner(jwtConfig.SecretKey, "mfa-challenge"),
+		oidcProvider:  oidcProvider,
+		cookieConfig:  cookieConfig,
+		publicBaseURL: mailConfig.PublicBaseURL,
 	}
 	return newServer, nil
 }
+
+// SetNotifier replaces the default outbox notifier used to deliver messages
+// such as password reset tokens.
+func (s *Server) SetNotifier(n notifier.Notifier) {
+	s.notifier = n
+}
+
+// SetMailer replaces the SMTP sender used for emails such as address
+// verification links.
+func (s *Server) SetMailer(m mailer.Sender) {
+	s.mailer = m
+}
+
+// SetOIDCProvider enables login through an OpenID Connect provider, replacing
+// the one configured from the environment.
+func (s *Server) SetOIDCProvider(p *oidc.Provider) {
+	s.oidcProvider = p
+}
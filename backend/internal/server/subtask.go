@@ -0,0 +1,193 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+)
+
+// maxTaskDepth mirrors the limit enforced by the check_task_hierarchy trigger.
+const maxTaskDepth = 5
+
+var (
+	errParentTaskNotFound = errors.New("parent task not found")
+	errTaskCycle          = errors.New("a task cannot be moved under itself or one of its subtasks")
+	errTaskTooDeep        = fmt.Errorf("subtasks cannot be nested more than %d levels deep", maxTaskDepth)
+)
+
+// taskProgress rolls up the direct subtasks of a task.
+type taskProgress struct {
+	Completed int64 `json:"completed"`
+	Total     int64 `json:"total"`
+}
+
+type listSubtasksRequest struct {
+	ID string `uri:"id" binding:"required"`
+}
+
+func (s *Server) listSubtasksHandler(ctx *gin.Context) {
+	var req listSubtasksRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	task, _, ok := s.getAccessibleTask(ctx, req.ID, taskRoleViewer)
+	if !ok {
+		return
+	}
+	subtasks, err := s.storage.ListSubtasks(ctx, pgtype.Text{
+		String: task.ID,
+		Valid:  true,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := s.taskResponses(ctx, subtasks)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp))
+}
+
+type moveTaskRequest struct {
+	ID string `uri:"id" binding:"required"`
+	// ParentID is the new parent of the task; null moves it to the top level.
+	ParentID *string `json:"parent_id" binding:"omitempty,min=1"`
+}
+
+func (s *Server) moveTaskHandler(ctx *gin.Context) {
+	var req moveTaskRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	task, _, ok := s.getAccessibleTask(ctx, req.ID, taskRoleOwner)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := store.MoveTaskParams{
+		ID: task.ID,
+	}
+
+	if req.ParentID != nil {
+		if *req.ParentID == task.ID {
+			ctx.JSON(http.StatusConflict, errorResponse(errTaskCycle))
+			return
+		}
+
+		parent, err := s.getReferencedTask(ctx, authPayload.UserID, *req.ParentID, errParentTaskNotFound)
+		if err != nil {
+			if errors.Is(err, errParentTaskNotFound) {
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		arg.ParentID = pgtype.Text{
+			String: parent.ID,
+			Valid:  true,
+		}
+	}
+
+	movedTask, err := s.storage.MoveTask(ctx, arg)
+	if err != nil {
+		if hierarchyErr := taskHierarchyError(err); hierarchyErr != nil {
+			ctx.JSON(http.StatusConflict, errorResponse(hierarchyErr))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := s.taskResponses(ctx, []store.Task{movedTask})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp[0]))
+}
+
+// getReferencedTask loads a task named in a request body. In a workspace it
+// may be any task of the workspace; otherwise it must be a personal task of
+// the given user. It fails with errNotFound for other tasks as well as for
+// missing ones so that task IDs cannot be probed.
+func (s *Server) getReferencedTask(ctx *gin.Context, userID int64, id string, errNotFound error) (store.Task, error) {
+	task, err := s.storage.GetTaskByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return store.Task{}, errNotFound
+		}
+		return store.Task{}, err
+	}
+
+	workspaceID := currentWorkspaceID(ctx)
+	if task.WorkspaceID != workspaceID || (!workspaceID.Valid && task.CreatorID != userID) {
+		return store.Task{}, errNotFound
+	}
+
+	return task, nil
+}
+
+// taskHierarchyError translates the errors raised by the check_task_hierarchy
+// trigger. It returns nil for any other error.
+func taskHierarchyError(err error) error {
+	if store.ErrorCode(err) != store.CheckViolation {
+		return nil
+	}
+
+	switch store.ErrorConstraintName(err) {
+	case "tasks_parent_cycle_check":
+		return errTaskCycle
+	case "tasks_depth_check":
+		return errTaskTooDeep
+	}
+	return nil
+}
+
+// listSubtaskProgress returns the progress of each of the given tasks. Every
+// task has an entry, which is zero for tasks without subtasks.
+func (s *Server) listSubtaskProgress(ctx *gin.Context, taskIDs []string) (map[string]taskProgress, error) {
+	progressByTask := make(map[string]taskProgress, len(taskIDs))
+	for _, taskID := range taskIDs {
+		progressByTask[taskID] = taskProgress{}
+	}
+
+	if len(taskIDs) == 0 {
+		return progressByTask, nil
+	}
+
+	rows, err := s.storage.GetSubtaskProgress(ctx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		progressByTask[row.TaskID] = taskProgress{
+			Completed: row.Completed,
+			Total:     row.Total,
+		}
+	}
+
+	return progressByTask, nil
+}
@@ -0,0 +1,451 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func stubNoSubtasks(storage *mockdb.MockStorage) {
+	storage.EXPECT().
+		GetSubtaskProgress(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return([]store.GetSubtaskProgressRow{}, nil)
+}
+
+func randomSubtask(t *testing.T, parent store.Task) store.Task {
+	task := randomTask(t, parent.CreatorID)
+	task.ParentID = pgtype.Text{
+		String: parent.ID,
+		Valid:  true,
+	}
+	return task
+}
+
+func hierarchyViolation(constraint string) error {
+	return &pgconn.PgError{
+		Code:           store.CheckViolation,
+		ConstraintName: constraint,
+	}
+}
+
+func TestListSubtasksHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	parent := randomTask(t, user.ID)
+	child := randomSubtask(t, parent)
+	otherTask := randomTask(t, user.ID+1)
+
+	testCases := []struct {
+		name          string
+		taskID        string
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			taskID: parent.ID,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(parent.ID)).
+					Times(1).
+					Return(parent, nil)
+				storage.EXPECT().
+					ListSubtasks(gomock.Any(), gomock.Eq(pgtype.Text{String: parent.ID, Valid: true})).
+					Times(1).
+					Return([]store.Task{child}, nil)
+				storage.EXPECT().
+					GetSubtaskProgress(gomock.Any(), gomock.Eq([]string{child.ID})).
+					Times(1).
+					Return([]store.GetSubtaskProgressRow{{
+						TaskID:    child.ID,
+						Total:     3,
+						Completed: 2,
+					}}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp struct {
+					Data []taskResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Data, 1)
+				require.Equal(t, child.ID, rsp.Data[0].ID)
+				require.Equal(t, child.ParentID, rsp.Data[0].ParentID)
+				require.Equal(t, taskProgress{Completed: 2, Total: 3}, rsp.Data[0].Progress)
+				require.NotNil(t, rsp.Data[0].Tags)
+			},
+		},
+		{
+			name:   "NoSubtasks",
+			taskID: parent.ID,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(parent.ID)).
+					Times(1).
+					Return(parent, nil)
+				storage.EXPECT().
+					ListSubtasks(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]store.Task{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"success":true,"data":[]}`, recorder.Body.String())
+			},
+		},
+		{
+			name:   "Unauthorized",
+			taskID: otherTask.ID,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(otherTask.ID)).
+					Times(1).
+					Return(otherTask, nil)
+				storage.EXPECT().
+					GetTaskShare(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.TaskShare{}, store.ErrRecordNotFound)
+				storage.EXPECT().
+					ListSubtasks(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "NotFound",
+			taskID: parent.ID,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(parent.ID)).
+					Times(1).
+					Return(store.Task{}, store.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+			stubNoTaskTags(storage)
+
+			url := "/tasks/" + tc.taskID + "/children"
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodGet, url, nil)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestMoveTaskHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	task := randomTask(t, user.ID)
+	parent := randomTask(t, user.ID)
+	otherTask := randomTask(t, user.ID+1)
+
+	moved := task
+	moved.ParentID = pgtype.Text{
+		String: parent.ID,
+		Valid:  true,
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"parent_id": parent.ID},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(parent.ID)).
+					Times(1).
+					Return(parent, nil)
+				storage.EXPECT().
+					MoveTask(gomock.Any(), gomock.Eq(store.MoveTaskParams{
+						ID:       task.ID,
+						ParentID: moved.ParentID,
+					})).
+					Times(1).
+					Return(moved, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp struct {
+					Data taskResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, moved.ParentID, rsp.Data.ParentID)
+			},
+		},
+		{
+			name: "ToTopLevel",
+			body: gin.H{"parent_id": nil},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(moved, nil)
+				storage.EXPECT().
+					MoveTask(gomock.Any(), gomock.Eq(store.MoveTaskParams{
+						ID: task.ID,
+					})).
+					Times(1).
+					Return(task, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp struct {
+					Data taskResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.False(t, rsp.Data.ParentID.Valid)
+			},
+		},
+		{
+			name: "UnderItself",
+			body: gin.H{"parent_id": task.ID},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					MoveTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "UnderOwnSubtask",
+			body: gin.H{"parent_id": parent.ID},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(parent.ID)).
+					Times(1).
+					Return(parent, nil)
+				storage.EXPECT().
+					MoveTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.Task{}, hierarchyViolation("tasks_parent_cycle_check"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errTaskCycle.Error())
+			},
+		},
+		{
+			name: "TooDeep",
+			body: gin.H{"parent_id": parent.ID},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(parent.ID)).
+					Times(1).
+					Return(parent, nil)
+				storage.EXPECT().
+					MoveTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.Task{}, hierarchyViolation("tasks_depth_check"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errTaskTooDeep.Error())
+			},
+		},
+		{
+			name: "ParentOfOtherUser",
+			body: gin.H{"parent_id": otherTask.ID},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(otherTask.ID)).
+					Times(1).
+					Return(otherTask, nil)
+				storage.EXPECT().
+					MoveTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ParentNotFound",
+			body: gin.H{"parent_id": parent.ID},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(parent.ID)).
+					Times(1).
+					Return(store.Task{}, store.ErrRecordNotFound)
+				storage.EXPECT().
+					MoveTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{"parent_id": nil},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					MoveTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.Task{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+			stubNoTaskTags(storage)
+
+			url := "/tasks/" + task.ID + "/move"
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPost, url, tc.body)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestCreateSubtask(t *testing.T) {
+	user, _ := randomUser(t)
+	parent := randomTask(t, user.ID)
+	child := randomSubtask(t, parent)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(parent.ID)).
+					Times(1).
+					Return(parent, nil)
+				storage.EXPECT().
+					CreateTask(gomock.Any(), gomock.Cond(func(arg store.CreateTaskParams) bool {
+						return arg.ParentID == child.ParentID
+					})).
+					Times(1).
+					Return(child, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var rsp struct {
+					Data taskResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, child.ParentID, rsp.Data.ParentID)
+				require.Zero(t, rsp.Data.Progress)
+			},
+		},
+		{
+			name: "ParentNotFound",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(parent.ID)).
+					Times(1).
+					Return(store.Task{}, store.ErrRecordNotFound)
+				storage.EXPECT().
+					CreateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "TooDeep",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(parent.ID)).
+					Times(1).
+					Return(parent, nil)
+				storage.EXPECT().
+					CreateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.Task{}, hierarchyViolation("tasks_depth_check"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			body := gin.H{
+				"title":     child.Title,
+				"deadline":  child.Deadline,
+				"parent_id": parent.ID,
+			}
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPost, "/tasks", body)
+			tc.checkResponse(recorder)
+		})
+	}
+}
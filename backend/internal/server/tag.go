@@ -0,0 +1,327 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+)
+
+const (
+	defaultTagColor = "#808080"
+	// Upper bound on the tags of a single task and on the tags of a filter.
+	maxTagsPerTask = 20
+)
+
+var (
+	errTagNotFound     = errors.New("tag not found")
+	errTagNameConflict = errors.New("a tag with this name already exists")
+	errEmptyTagName    = errors.New("tag name must not be empty")
+	errUnknownTags     = errors.New("some tags don't exist")
+	errMergeTagItself  = errors.New("a tag cannot be merged into itself")
+)
+
+type createTagRequest struct {
+	Name  string `json:"name" binding:"required,max=50"`
+	Color string `json:"color" binding:"omitempty,hexcolor"`
+}
+
+func (s *Server) createTagHandler(ctx *gin.Context) {
+	var req createTagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if len(name) == 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errEmptyTagName))
+		return
+	}
+
+	color := req.Color
+	if len(color) == 0 {
+		color = defaultTagColor
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	tag, err := s.storage.CreateTag(ctx, store.CreateTagParams{
+		UserID: authPayload.UserID,
+		Name:   name,
+		Color:  color,
+	})
+	if err != nil {
+		if store.ErrorCode(err) == store.UniqueViolation {
+			ctx.JSON(http.StatusConflict, errorResponse(errTagNameConflict))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, successResponse(tag))
+}
+
+func (s *Server) listTagsHandler(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	tags, err := s.storage.ListTags(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(tags))
+}
+
+type tagURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type updateTagRequest struct {
+	Name  *string `json:"name" binding:"omitempty,max=50"`
+	Color string  `json:"color" binding:"omitempty,hexcolor"`
+}
+
+// updateTagHandler renames or recolors a tag.
+func (s *Server) updateTagHandler(ctx *gin.Context) {
+	var uri tagURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateTagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := s.getOwnedTag(ctx, uri.ID); !ok {
+		return
+	}
+
+	arg := store.UpdateTagParams{
+		ID: uri.ID,
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if len(name) == 0 {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errEmptyTagName))
+			return
+		}
+		arg.Name = pgtype.Text{
+			String: name,
+			Valid:  true,
+		}
+	}
+
+	if len(req.Color) > 0 {
+		arg.Color = pgtype.Text{
+			String: req.Color,
+			Valid:  true,
+		}
+	}
+
+	tag, err := s.storage.UpdateTag(ctx, arg)
+	if err != nil {
+		if store.ErrorCode(err) == store.UniqueViolation {
+			ctx.JSON(http.StatusConflict, errorResponse(errTagNameConflict))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(tag))
+}
+
+func (s *Server) deleteTagHandler(ctx *gin.Context) {
+	var uri tagURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := s.getOwnedTag(ctx, uri.ID); !ok {
+		return
+	}
+
+	err := s.storage.DeleteTag(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(nil))
+}
+
+type mergeTagRequest struct {
+	TargetID int64 `json:"target_id" binding:"required,min=1"`
+}
+
+// mergeTagHandler moves every task of the tag in the path to the target tag
+// and deletes the merged tag.
+func (s *Server) mergeTagHandler(ctx *gin.Context) {
+	var uri tagURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req mergeTagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if uri.ID == req.TargetID {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errMergeTagItself))
+		return
+	}
+
+	if _, ok := s.getOwnedTag(ctx, uri.ID); !ok {
+		return
+	}
+
+	target, ok := s.getOwnedTag(ctx, req.TargetID)
+	if !ok {
+		return
+	}
+
+	rows, err := s.storage.MergeTag(ctx, store.MergeTagParams{
+		SourceID: uri.ID,
+		TargetID: target.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// The source tag was deleted by a concurrent request.
+	if rows == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(errTagNotFound))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(target))
+}
+
+// getOwnedTag answers 404 for tags of other users as well as for missing ones
+// so that tag IDs of other users cannot be probed. It reports whether the
+// request may proceed.
+func (s *Server) getOwnedTag(ctx *gin.Context, id int64) (store.Tag, bool) {
+	tag, err := s.storage.GetTag(ctx, id)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errTagNotFound))
+			return store.Tag{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return store.Tag{}, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if tag.UserID != authPayload.UserID {
+		ctx.JSON(http.StatusNotFound, errorResponse(errTagNotFound))
+		return store.Tag{}, false
+	}
+
+	return tag, true
+}
+
+// getOwnedTags returns the tags with the given IDs ordered by name and fails
+// with errUnknownTags unless all of them belong to the user.
+func (s *Server) getOwnedTags(ctx *gin.Context, userID int64, ids []int64) ([]store.Tag, error) {
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	if len(ids) == 0 {
+		return []store.Tag{}, nil
+	}
+
+	tags, err := s.storage.GetUserTagsByIDs(ctx, store.GetUserTagsByIDsParams{
+		UserID: userID,
+		Ids:    ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tags) != len(ids) {
+		return nil, errUnknownTags
+	}
+
+	sortTags(tags)
+	return tags, nil
+}
+
+// setTaskTags replaces the tags of a task with tags already checked by
+// getOwnedTags, using q so that it can be part of a transaction.
+func (s *Server) setTaskTags(ctx *gin.Context, q store.Querier, taskID string, tags []store.Tag) error {
+	// A nil array would be NULL and leave the old tags in place.
+	tagIDs := make([]int64, 0, len(tags))
+	for _, tag := range tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+
+	return q.SetTaskTags(ctx, store.SetTaskTagsParams{
+		TaskID: taskID,
+		TagIds: tagIDs,
+	})
+}
+
+// listTaskTags returns the tags of each of the given tasks. Every task has an
+// entry, which is empty for untagged tasks.
+func (s *Server) listTaskTags(ctx *gin.Context, taskIDs []string) (map[string][]store.Tag, error) {
+	tagsByTask := make(map[string][]store.Tag, len(taskIDs))
+	for _, taskID := range taskIDs {
+		tagsByTask[taskID] = []store.Tag{}
+	}
+
+	if len(taskIDs) == 0 {
+		return tagsByTask, nil
+	}
+
+	rows, err := s.storage.ListTagsForTasks(ctx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		tagsByTask[row.TaskID] = append(tagsByTask[row.TaskID], store.Tag{
+			ID:        row.ID,
+			UserID:    row.UserID,
+			Name:      row.Name,
+			Color:     row.Color,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+
+	return tagsByTask, nil
+}
+
+func sortTags(tags []store.Tag) {
+	slices.SortFunc(tags, func(a, b store.Tag) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+}
+
+// normalizeTagNames lowercases and deduplicates the tag names of a filter,
+// matching how tag names are compared in the database.
+func normalizeTagNames(names []string) []string {
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if len(name) > 0 && !slices.Contains(normalized, name) {
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,882 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func stubNoTaskTags(storage *mockdb.MockStorage) {
+	storage.EXPECT().
+		ListTagsForTasks(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return([]store.ListTagsForTasksRow{}, nil)
+}
+
+// stubExecTx runs transactions against the mock itself, so that the
+// statements of a transaction are expected like any other call.
+func stubExecTx(storage *mockdb.MockStorage) {
+	storage.EXPECT().
+		ExecTx(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, fn func(store.Querier) error) error {
+			return fn(storage)
+		})
+}
+
+func randomTag(userID int64) store.Tag {
+	return store.Tag{
+		ID:        rand.Int64N(1000) + 1,
+		UserID:    userID,
+		Name:      util.RandomAlphabetString(10),
+		Color:     "#1e90ff",
+		CreatedAt: time.Now(),
+	}
+}
+
+func requireBodyMatchTag(t *testing.T, body *bytes.Buffer, tag store.Tag) {
+	var rsp struct {
+		Data store.Tag `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body.Bytes(), &rsp))
+	require.Equal(t, tag.ID, rsp.Data.ID)
+	require.Equal(t, tag.Name, rsp.Data.Name)
+	require.Equal(t, tag.Color, rsp.Data.Color)
+}
+
+func requireBodyMatchTaskTags(t *testing.T, body *bytes.Buffer, tags []store.Tag) {
+	var rsp struct {
+		Data taskResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body.Bytes(), &rsp))
+	require.Len(t, rsp.Data.Tags, len(tags))
+	for i := range tags {
+		require.Equal(t, tags[i].ID, rsp.Data.Tags[i].ID)
+		require.Equal(t, tags[i].Name, rsp.Data.Tags[i].Name)
+	}
+}
+
+// serveAuthenticatedRequest sends an authenticated request on behalf of the user.
+func serveAuthenticatedRequest(t *testing.T, storage *mockdb.MockStorage, user store.User, method string, url string, body any) *httptest.ResponseRecorder {
+	return serveAuthenticatedRequestWithHeader(t, storage, user, method, url, body, nil)
+}
+
+func serveAuthenticatedRequestWithHeader(t *testing.T, storage *mockdb.MockStorage, user store.User, method string, url string, body any, header http.Header) *httptest.ResponseRecorder {
+	stubTokenNotRevoked(storage)
+	stubNoSubtasks(storage)
+	stubExecTx(storage)
+
+	server, err := NewServer(storage)
+	require.NoError(t, err)
+	server.RegisterRoutes()
+	recorder := httptest.NewRecorder()
+
+	var data []byte
+	if body != nil {
+		data, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+
+	request, err := http.NewRequest(method, url, bytes.NewReader(data))
+	require.NoError(t, err)
+	for key, values := range header {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, user.Username, user.Role, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestCreateTagHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	tag := randomTag(user.ID)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"name": "  " + tag.Name + " ", "color": tag.Color},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateTag(gomock.Any(), gomock.Eq(store.CreateTagParams{
+						UserID: user.ID,
+						Name:   tag.Name,
+						Color:  tag.Color,
+					})).
+					Times(1).
+					Return(tag, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				requireBodyMatchTag(t, recorder.Body, tag)
+			},
+		},
+		{
+			name: "DefaultColor",
+			body: gin.H{"name": tag.Name},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateTag(gomock.Any(), gomock.Eq(store.CreateTagParams{
+						UserID: user.ID,
+						Name:   tag.Name,
+						Color:  defaultTagColor,
+					})).
+					Times(1).
+					Return(tag, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "NameConflict",
+			body: gin.H{"name": tag.Name},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateTag(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.Tag{}, store.ErrUniqueViolation)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "BlankName",
+			body: gin.H{"name": "   "},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateTag(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidColor",
+			body: gin.H{"name": tag.Name, "color": "blue"},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateTag(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{"name": tag.Name},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateTag(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.Tag{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPost, "/tags", tc.body)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListTagsHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	tag := randomTag(user.ID)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mockdb.NewMockStorage(ctrl)
+	storage.EXPECT().
+		ListTags(gomock.Any(), gomock.Eq(user.ID)).
+		Times(1).
+		Return([]store.ListTagsRow{{
+			ID:        tag.ID,
+			UserID:    tag.UserID,
+			Name:      tag.Name,
+			Color:     tag.Color,
+			CreatedAt: tag.CreatedAt,
+			TaskCount: 3,
+		}}, nil)
+
+	recorder := serveAuthenticatedRequest(t, storage, user, http.MethodGet, "/tags", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp struct {
+		Data []store.ListTagsRow `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Len(t, rsp.Data, 1)
+	require.Equal(t, tag.Name, rsp.Data[0].Name)
+	require.Equal(t, int64(3), rsp.Data[0].TaskCount)
+}
+
+func TestUpdateTagHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	tag := randomTag(user.ID)
+	otherTag := randomTag(user.ID + 1)
+
+	testCases := []struct {
+		name          string
+		tagID         int64
+		body          gin.H
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "Rename",
+			tagID: tag.ID,
+			body:  gin.H{"name": "renamed"},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTag(gomock.Any(), gomock.Eq(tag.ID)).
+					Times(1).
+					Return(tag, nil)
+
+				renamed := tag
+				renamed.Name = "renamed"
+				storage.EXPECT().
+					UpdateTag(gomock.Any(), gomock.Eq(store.UpdateTagParams{
+						ID:   tag.ID,
+						Name: pgtype.Text{String: "renamed", Valid: true},
+					})).
+					Times(1).
+					Return(renamed, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "Recolor",
+			tagID: tag.ID,
+			body:  gin.H{"color": "#fff"},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTag(gomock.Any(), gomock.Eq(tag.ID)).
+					Times(1).
+					Return(tag, nil)
+				storage.EXPECT().
+					UpdateTag(gomock.Any(), gomock.Eq(store.UpdateTagParams{
+						ID:    tag.ID,
+						Color: pgtype.Text{String: "#fff", Valid: true},
+					})).
+					Times(1).
+					Return(tag, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "NameConflict",
+			tagID: tag.ID,
+			body:  gin.H{"name": "taken"},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTag(gomock.Any(), gomock.Eq(tag.ID)).
+					Times(1).
+					Return(tag, nil)
+				storage.EXPECT().
+					UpdateTag(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.Tag{}, store.ErrUniqueViolation)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:  "BlankName",
+			tagID: tag.ID,
+			body:  gin.H{"name": " "},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTag(gomock.Any(), gomock.Eq(tag.ID)).
+					Times(1).
+					Return(tag, nil)
+				storage.EXPECT().
+					UpdateTag(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "NotFound",
+			tagID: tag.ID,
+			body:  gin.H{"name": "renamed"},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTag(gomock.Any(), gomock.Eq(tag.ID)).
+					Times(1).
+					Return(store.Tag{}, store.ErrRecordNotFound)
+				storage.EXPECT().
+					UpdateTag(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:  "OtherUsersTag",
+			tagID: otherTag.ID,
+			body:  gin.H{"name": "renamed"},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTag(gomock.Any(), gomock.Eq(otherTag.ID)).
+					Times(1).
+					Return(otherTag, nil)
+				storage.EXPECT().
+					UpdateTag(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			url := fmt.Sprintf("/tags/%d", tc.tagID)
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPatch, url, tc.body)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDeleteTagHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	tag := randomTag(user.ID)
+	otherTag := randomTag(user.ID + 1)
+
+	testCases := []struct {
+		name       string
+		tag        store.Tag
+		deletes    int
+		expectCode int
+	}{
+		{
+			name:       "OK",
+			tag:        tag,
+			deletes:    1,
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "OtherUsersTag",
+			tag:        otherTag,
+			deletes:    0,
+			expectCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			storage.EXPECT().
+				GetTag(gomock.Any(), gomock.Eq(tc.tag.ID)).
+				Times(1).
+				Return(tc.tag, nil)
+			storage.EXPECT().
+				DeleteTag(gomock.Any(), gomock.Eq(tc.tag.ID)).
+				Times(tc.deletes)
+
+			url := fmt.Sprintf("/tags/%d", tc.tag.ID)
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodDelete, url, nil)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestMergeTagHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	source := randomTag(user.ID)
+	target := randomTag(user.ID)
+	target.ID = source.ID + 1
+	otherTag := randomTag(user.ID + 1)
+	otherTag.ID = source.ID + 2
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"target_id": target.ID},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTag(gomock.Any(), gomock.Eq(source.ID)).
+					Times(1).
+					Return(source, nil)
+				storage.EXPECT().
+					GetTag(gomock.Any(), gomock.Eq(target.ID)).
+					Times(1).
+					Return(target, nil)
+				storage.EXPECT().
+					MergeTag(gomock.Any(), gomock.Eq(store.MergeTagParams{
+						SourceID: source.ID,
+						TargetID: target.ID,
+					})).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchTag(t, recorder.Body, target)
+			},
+		},
+		{
+			name: "IntoItself",
+			body: gin.H{"target_id": source.ID},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTag(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "IntoOtherUsersTag",
+			body: gin.H{"target_id": otherTag.ID},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTag(gomock.Any(), gomock.Eq(source.ID)).
+					Times(1).
+					Return(source, nil)
+				storage.EXPECT().
+					GetTag(gomock.Any(), gomock.Eq(otherTag.ID)).
+					Times(1).
+					Return(otherTag, nil)
+				storage.EXPECT().
+					MergeTag(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "SourceDeletedConcurrently",
+			body: gin.H{"target_id": target.ID},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTag(gomock.Any(), gomock.Eq(source.ID)).
+					Times(1).
+					Return(source, nil)
+				storage.EXPECT().
+					GetTag(gomock.Any(), gomock.Eq(target.ID)).
+					Times(1).
+					Return(target, nil)
+				storage.EXPECT().
+					MergeTag(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			url := fmt.Sprintf("/tags/%d/merge", source.ID)
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPost, url, tc.body)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestTaskTags(t *testing.T) {
+	user, _ := randomUser(t)
+	task := randomTask(t, user.ID)
+	tagA := randomTag(user.ID)
+	tagA.Name = "alpha"
+	tagB := randomTag(user.ID)
+	tagB.ID = tagA.ID + 1
+	tagB.Name = "Beta"
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		body          gin.H
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "CreateWithTags",
+			method: http.MethodPost,
+			url:    "/tasks",
+			body: gin.H{
+				"title":    task.Title,
+				"deadline": task.Deadline,
+				"tag_ids":  []int64{tagB.ID, tagA.ID, tagB.ID},
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUserTagsByIDs(gomock.Any(), gomock.Eq(store.GetUserTagsByIDsParams{
+						UserID: user.ID,
+						Ids:    []int64{tagA.ID, tagB.ID},
+					})).
+					Times(1).
+					Return([]store.Tag{tagB, tagA}, nil)
+				storage.EXPECT().
+					CreateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					SetTaskTags(gomock.Any(), gomock.Eq(store.SetTaskTagsParams{
+						TaskID: task.ID,
+						TagIds: []int64{tagA.ID, tagB.ID},
+					})).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				requireBodyMatchTaskTags(t, recorder.Body, []store.Tag{tagA, tagB})
+			},
+		},
+		{
+			name:   "CreateTagsError",
+			method: http.MethodPost,
+			url:    "/tasks",
+			body: gin.H{
+				"title":    task.Title,
+				"deadline": task.Deadline,
+				"tag_ids":  []int64{tagA.ID},
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUserTagsByIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]store.Tag{tagA}, nil)
+				// The task is created in the same transaction as its tags and
+				// is rolled back with them.
+				storage.EXPECT().
+					CreateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					SetTaskTags(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:   "CreateWithUnknownTag",
+			method: http.MethodPost,
+			url:    "/tasks",
+			body: gin.H{
+				"title":    task.Title,
+				"deadline": task.Deadline,
+				"tag_ids":  []int64{tagA.ID, tagB.ID},
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUserTagsByIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]store.Tag{tagA}, nil)
+				storage.EXPECT().
+					CreateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "CreateWithInvalidTagID",
+			method: http.MethodPost,
+			url:    "/tasks",
+			body: gin.H{
+				"title":    task.Title,
+				"deadline": task.Deadline,
+				"tag_ids":  []int64{0},
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "UpdateReplacesTags",
+			method: http.MethodPut,
+			url:    "/tasks/" + task.ID,
+			body:   gin.H{"tag_ids": []int64{tagA.ID}},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					GetUserTagsByIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]store.Tag{tagA}, nil)
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					SetTaskTags(gomock.Any(), gomock.Eq(store.SetTaskTagsParams{
+						TaskID: task.ID,
+						TagIds: []int64{tagA.ID},
+					})).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchTaskTags(t, recorder.Body, []store.Tag{tagA})
+			},
+		},
+		{
+			name:   "UpdateClearsTags",
+			method: http.MethodPut,
+			url:    "/tasks/" + task.ID,
+			body:   gin.H{"tag_ids": []int64{}},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					GetUserTagsByIDs(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					SetTaskTags(gomock.Any(), gomock.Eq(store.SetTaskTagsParams{
+						TaskID: task.ID,
+						TagIds: []int64{},
+					})).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchTaskTags(t, recorder.Body, []store.Tag{})
+			},
+		},
+		{
+			name:   "UpdateTagsError",
+			method: http.MethodPut,
+			url:    "/tasks/" + task.ID,
+			body:   gin.H{"title": task.Title, "tag_ids": []int64{tagA.ID}},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					GetUserTagsByIDs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]store.Tag{tagA}, nil)
+				// The title update runs in the same transaction as the tags
+				// and is rolled back with them.
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					SetTaskTags(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+				storage.EXPECT().
+					ListTagsForTasks(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:   "UpdateKeepsTags",
+			method: http.MethodPut,
+			url:    "/tasks/" + task.ID,
+			body:   gin.H{"completed": true},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+
+				storage.EXPECT().
+					CountOpenBlockers(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(int64(0), nil)
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					SetTaskTags(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					ListTagsForTasks(gomock.Any(), gomock.Eq([]string{task.ID})).
+					Times(1).
+					Return([]store.ListTagsForTasksRow{{
+						TaskID: task.ID,
+						ID:     tagB.ID,
+						UserID: tagB.UserID,
+						Name:   tagB.Name,
+						Color:  tagB.Color,
+					}}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchTaskTags(t, recorder.Body, []store.Tag{tagB})
+			},
+		},
+		{
+			name:   "FilterByAllTags",
+			method: http.MethodGet,
+			url:    "/tasks?tag=Alpha&tag=beta&tag=alpha&tag_mode=all",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTasks(gomock.Any(), gomock.Cond(func(arg store.GetTasksParams) bool {
+						return arg.MatchAllTags && len(arg.Tags) == 2 && arg.Tags[0] == "alpha" && arg.Tags[1] == "beta"
+					})).
+					Times(1).
+					Return([]store.GetTasksRow{{ID: task.ID, CreatorID: user.ID, Total: 1}}, nil)
+				storage.EXPECT().
+					ListTagsForTasks(gomock.Any(), gomock.Eq([]string{task.ID})).
+					Times(1).
+					Return([]store.ListTagsForTasksRow{
+						{TaskID: task.ID, ID: tagA.ID, Name: tagA.Name},
+						{TaskID: task.ID, ID: tagB.ID, Name: tagB.Name},
+					}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp struct {
+					Data getTasksResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Data.Tasks, 1)
+				require.Len(t, rsp.Data.Tasks[0].Tags, 2)
+			},
+		},
+		{
+			name:   "FilterByAnyTag",
+			method: http.MethodGet,
+			url:    "/tasks?tag=alpha",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTasks(gomock.Any(), gomock.Cond(func(arg store.GetTasksParams) bool {
+						return !arg.MatchAllTags && len(arg.Tags) == 1
+					})).
+					Times(1).
+					Return([]store.GetTasksRow{}, nil)
+				storage.EXPECT().
+					ListTagsForTasks(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "InvalidTagMode",
+			method: http.MethodGet,
+			url:    "/tasks?tag=alpha&tag_mode=none",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTasks(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			var body any
+			if tc.body != nil {
+				body = tc.body
+			}
+			recorder := serveAuthenticatedRequest(t, storage, user, tc.method, tc.url, body)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestNormalizeTagNames(t *testing.T) {
+	require.Equal(t, []string{"backend", "urgent-client"}, normalizeTagNames([]string{" Backend", "urgent-client", "BACKEND", ""}))
+	require.Empty(t, normalizeTagNames(nil))
+}
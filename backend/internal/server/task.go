@@ -8,14 +8,68 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgtype"
 	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/recurrence"
 	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
 	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
 )
 
+const sortTasksByPriority = "priority"
+
+// ownershipShared lists only the tasks shared with the user.
+const ownershipShared = "shared"
+
+// taskResponse is a task together with its tags and the progress of its
+// subtasks.
+type taskResponse struct {
+	store.Task
+	Tags     []store.Tag  `json:"tags"`
+	Progress taskProgress `json:"progress"`
+	// NextOccurrence is set when completing a recurring task created the
+	// next occurrence of its series.
+	NextOccurrence *taskResponse `json:"next_occurrence,omitempty"`
+}
+
+// taskResponses loads the tags and the subtask progress of the given tasks.
+func (s *Server) taskResponses(ctx *gin.Context, tasks []store.Task) ([]taskResponse, error) {
+	taskIDs := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+
+	tagsByTask, err := s.listTaskTags(ctx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	progressByTask, err := s.listSubtaskProgress(ctx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := make([]taskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		rsp = append(rsp, taskResponse{
+			Task:     task,
+			Tags:     tagsByTask[task.ID],
+			Progress: progressByTask[task.ID],
+		})
+	}
+	return rsp, nil
+}
+
 type createTaskRequest struct {
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description" binding:"omitempty"`
-	Deadline    string `json:"deadline" binding:"required,iso8601"`
+	Title       string  `json:"title" binding:"required"`
+	Description string  `json:"description" binding:"omitempty"`
+	Deadline    string  `json:"deadline" binding:"required,iso8601"`
+	Priority    string  `json:"priority" binding:"omitempty,oneof=none low medium high urgent"`
+	TagIDs      []int64 `json:"tag_ids" binding:"omitempty,max=20,dive,min=1"`
+	ParentID    string  `json:"parent_id" binding:"omitempty"`
+	ProjectID   int64   `json:"project_id" binding:"omitempty,min=1"`
+	AssigneeID  int64   `json:"assignee_id" binding:"omitempty,min=1"`
+	// RecurrenceRule is an RFC 5545 RRULE. The task becomes the first
+	// occurrence of the series, whose deadlines follow the user's timezone.
+	RecurrenceRule string `json:"recurrence_rule" binding:"omitempty,max=500"`
 }
 
 func (s *Server) createTaskHandler(ctx *gin.Context) {
@@ -26,18 +80,107 @@ func (s *Server) createTaskHandler(ctx *gin.Context) {
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	tags, err := s.getOwnedTags(ctx, authPayload.UserID, req.TagIDs)
+	if err != nil {
+		if errors.Is(err, errUnknownTags) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	var parentID pgtype.Text
+	if len(req.ParentID) > 0 {
+		parent, err := s.getReferencedTask(ctx, authPayload.UserID, req.ParentID, errParentTaskNotFound)
+		if err != nil {
+			if errors.Is(err, errParentTaskNotFound) {
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		parentID = pgtype.Text{
+			String: parent.ID,
+			Valid:  true,
+		}
+	}
+
+	workspaceID := currentWorkspaceID(ctx)
+
+	var projectID pgtype.Int8
+	if req.ProjectID > 0 {
+		if workspaceID.Valid {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errWorkspaceTaskProject))
+			return
+		}
+
+		project, err := s.getTaskProject(ctx, authPayload.UserID, req.ProjectID)
+		if err != nil {
+			if errors.Is(err, errProjectNotFound) || errors.Is(err, errProjectArchived) {
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		projectID = pgtype.Int8{
+			Int64: project.ID,
+			Valid: true,
+		}
+	}
+
+	var assigneeID pgtype.Int8
+	if req.AssigneeID > 0 {
+		newTask := store.Task{
+			CreatorID:   authPayload.UserID,
+			WorkspaceID: workspaceID,
+		}
+		if err := s.checkAssignee(ctx, newTask, req.AssigneeID); err != nil {
+			if errors.Is(err, errInvalidAssignee) {
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		assigneeID = pgtype.Int8{
+			Int64: req.AssigneeID,
+			Valid: true,
+		}
+	}
+
 	id, err := gonanoid.New()
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	var rule recurrence.Rule
+	if len(req.RecurrenceRule) > 0 {
+		rule, err = recurrence.Parse(req.RecurrenceRule)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
 	deadline, _ := time.Parse(time.RFC3339, req.Deadline)
 	arg := store.CreateTaskParams{
-		ID:        id,
-		CreatorID: authPayload.UserID,
-		Title:     req.Title,
-		Deadline:  deadline,
+		ID:          id,
+		CreatorID:   authPayload.UserID,
+		Title:       req.Title,
+		Deadline:    deadline,
+		Priority:    req.Priority,
+		ParentID:    parentID,
+		ProjectID:   projectID,
+		WorkspaceID: workspaceID,
+		AssigneeID:  assigneeID,
+	}
+	if len(arg.Priority) == 0 {
+		arg.Priority = util.PriorityNone
 	}
 	if len(req.Description) > 0 {
 		arg.Description = pgtype.Text{
@@ -46,23 +189,82 @@ func (s *Server) createTaskHandler(ctx *gin.Context) {
 		}
 	}
 
-	task, err := s.storage.CreateTask(ctx, arg)
+	var timezone string
+	if len(req.RecurrenceRule) > 0 {
+		timezone, err = s.getUserTimezone(ctx, authPayload.UserID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	// The task is created together with its tags, so that a failure does not
+	// leave an untagged task behind for the client's retry to duplicate.
+	var task store.Task
+	err = s.storage.ExecTx(ctx, func(q store.Querier) error {
+		var err error
+		if len(req.RecurrenceRule) > 0 {
+			task, err = q.CreateRecurringTask(ctx, store.CreateRecurringTaskParams{
+				ID:          arg.ID,
+				CreatorID:   arg.CreatorID,
+				Title:       arg.Title,
+				Description: arg.Description,
+				Deadline:    arg.Deadline,
+				Priority:    arg.Priority,
+				ParentID:    arg.ParentID,
+				ProjectID:   arg.ProjectID,
+				WorkspaceID: arg.WorkspaceID,
+				AssigneeID:  arg.AssigneeID,
+				Rule:        rule.String(),
+				Timezone:    timezone,
+			})
+		} else {
+			task, err = q.CreateTask(ctx, arg)
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(tags) > 0 {
+			return s.setTaskTags(ctx, q, task.ID, tags)
+		}
+		return nil
+	})
 	if err != nil {
+		if hierarchyErr := taskHierarchyError(err); hierarchyErr != nil {
+			ctx.JSON(http.StatusConflict, errorResponse(hierarchyErr))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, successResponse(task))
+	// A new task has no subtasks yet.
+	ctx.JSON(http.StatusCreated, successResponse(taskResponse{
+		Task: task,
+		Tags: tags,
+	}))
 }
 
 type getTasksRequest struct {
-	Title         string `form:"title" binding:"omitempty"`
-	Description   string `form:"description" binding:"omitempty"`
-	StartDeadline string `form:"start_deadline" binding:"omitempty,iso8601"`
-	EndDeadline   string `form:"end_deadline" binding:"omitempty,iso8601"`
-	Completed     *bool  `form:"completed" binding:"omitempty"`
-	Page          int32  `form:"page" binding:"omitempty,min=1"`
-	Limit         int32  `form:"limit" binding:"omitempty,min=1,max=20"`
+	Title         string   `form:"title" binding:"omitempty"`
+	Description   string   `form:"description" binding:"omitempty"`
+	StartDeadline string   `form:"start_deadline" binding:"omitempty,iso8601"`
+	EndDeadline   string   `form:"end_deadline" binding:"omitempty,iso8601"`
+	Completed     *bool    `form:"completed" binding:"omitempty"`
+	Priority      []string `form:"priority" binding:"omitempty,dive,oneof=none low medium high urgent"`
+	Sort          string   `form:"sort" binding:"omitempty,oneof=deadline priority"`
+	Tag           []string `form:"tag" binding:"omitempty,max=20,dive,max=50"`
+	TagMode       string   `form:"tag_mode" binding:"omitempty,oneof=any all"`
+	// Ownership narrows the listing to the tasks the user created ("mine")
+	// or to the tasks shared with them ("shared"). In a workspace, the tasks
+	// created by other members count as shared.
+	Ownership string `form:"ownership" binding:"omitempty,oneof=mine shared"`
+	// Assignee narrows the listing to the tasks assigned to the user ("me"),
+	// to another user by ID, or to unassigned tasks ("none").
+	Assignee string `form:"assignee" binding:"omitempty,oneof=me none|number"`
+	Page     int32  `form:"page" binding:"omitempty,min=1"`
+	Limit    int32  `form:"limit" binding:"omitempty,min=1,max=20"`
 }
 
 type GetTaskRow struct {
@@ -72,7 +274,19 @@ type GetTaskRow struct {
 	CreatorID   int64       `json:"creator_id"`
 	Deadline    time.Time   `json:"deadline"`
 	Completed   bool        `json:"completed"`
-	CreatedAt   time.Time   `json:"created_at"`
+	Priority    string      `json:"priority"`
+	ParentID    pgtype.Text `json:"parent_id"`
+	ProjectID   pgtype.Int8 `json:"project_id"`
+	WorkspaceID pgtype.Int8 `json:"workspace_id"`
+	AssigneeID  pgtype.Int8 `json:"assignee_id"`
+	// RecurrenceID and Occurrence place a recurring task in its series.
+	RecurrenceID pgtype.Int8  `json:"recurrence_id"`
+	Occurrence   pgtype.Int4  `json:"occurrence"`
+	CreatedAt    time.Time    `json:"created_at"`
+	Tags         []store.Tag  `json:"tags"`
+	Progress     taskProgress `json:"progress"`
+	// Blocked reports whether the task waits on tasks that are still open.
+	Blocked bool `json:"blocked"`
 }
 
 type getTasksResponse struct {
@@ -88,10 +302,18 @@ func (s *Server) getTasksHandler(ctx *gin.Context) {
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := newGetTasksParams(authPayload.UserID, req)
+	arg.WorkspaceID = currentWorkspaceID(ctx)
+	s.listTasks(ctx, arg)
+}
+
+// newGetTasksParams translates the filters of a task listing into a query
+// for the tasks the given user created or that are shared with them.
+func newGetTasksParams(userID int64, req getTasksRequest) store.GetTasksParams {
 	arg := store.GetTasksParams{
-		CreatorID: authPayload.UserID,
-		Limit:     req.Limit,
-		Offset:    (req.Page - 1) * req.Limit,
+		UserID: userID,
+		Limit:  req.Limit,
+		Offset: (req.Page - 1) * req.Limit,
 	}
 
 	if len(req.Title) > 0 {
@@ -131,6 +353,31 @@ func (s *Server) getTasksHandler(ctx *gin.Context) {
 		}
 	}
 
+	if len(req.Ownership) > 0 {
+		arg.Shared = pgtype.Bool{
+			Bool:  req.Ownership == ownershipShared,
+			Valid: true,
+		}
+	}
+
+	arg.AssigneeID, arg.Unassigned = newAssigneeFilter(userID, req.Assignee)
+
+	// Priority filters match any of the given priorities.
+	if len(req.Priority) > 0 {
+		arg.Priorities = req.Priority
+	}
+
+	// Repeated tags match tasks with any of them or, with tag_mode=all, only
+	// tasks that have every one of them.
+	if tags := normalizeTagNames(req.Tag); len(tags) > 0 {
+		arg.Tags = tags
+		arg.MatchAllTags = req.TagMode == "all"
+	}
+
+	// Open tasks always come before completed ones; within each group tasks
+	// are ordered by deadline, optionally after priority.
+	arg.SortByPriority = req.Sort == sortTasksByPriority
+
 	if arg.Limit == 0 {
 		arg.Limit = 5
 	}
@@ -139,35 +386,69 @@ func (s *Server) getTasksHandler(ctx *gin.Context) {
 		arg.Offset = 0
 	}
 
+	return arg
+}
+
+// listTasks responds with one page of the tasks matching arg.
+func (s *Server) listTasks(ctx *gin.Context, arg store.GetTasksParams) {
 	tasks, err := s.storage.GetTasks(ctx, arg)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	var rsp getTasksResponse
+	page := make([]store.Task, 0, len(tasks))
+	for _, task := range tasks {
+		page = append(page, store.Task{
+			ID:           task.ID,
+			Title:        task.Title,
+			Description:  task.Description,
+			CreatorID:    task.CreatorID,
+			Deadline:     task.Deadline,
+			Completed:    task.Completed,
+			CreatedAt:    task.CreatedAt,
+			Priority:     task.Priority,
+			ParentID:     task.ParentID,
+			RecurrenceID: task.RecurrenceID,
+			Occurrence:   task.Occurrence,
+			ProjectID:    task.ProjectID,
+			WorkspaceID:  task.WorkspaceID,
+			AssigneeID:   task.AssigneeID,
+		})
+	}
+
+	responses, err := s.taskResponses(ctx, page)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
 
-	if len(tasks) == 0 {
-		rsp = getTasksResponse{
-			Total: 0,
-			Tasks: []GetTaskRow{},
-		}
-	} else {
-		rsp = getTasksResponse{
-			Total: tasks[0].Total,
-			Tasks: []GetTaskRow{},
-		}
-		for _, task := range tasks {
-			rsp.Tasks = append(rsp.Tasks, GetTaskRow{
-				ID:          task.ID,
-				Title:       task.Title,
-				Description: task.Description,
-				CreatorID:   task.CreatorID,
-				Deadline:    task.Deadline,
-				Completed:   task.Completed,
-				CreatedAt:   task.CreatedAt,
-			})
-		}
+	rsp := getTasksResponse{
+		Tasks: make([]GetTaskRow, 0, len(tasks)),
+	}
+	if len(tasks) > 0 {
+		rsp.Total = tasks[0].Total
+	}
+	for i, task := range responses {
+		rsp.Tasks = append(rsp.Tasks, GetTaskRow{
+			ID:           task.ID,
+			Title:        task.Title,
+			Description:  task.Description,
+			CreatorID:    task.CreatorID,
+			Deadline:     task.Deadline,
+			Completed:    task.Completed,
+			Priority:     task.Priority,
+			ParentID:     task.ParentID,
+			ProjectID:    task.ProjectID,
+			WorkspaceID:  task.WorkspaceID,
+			AssigneeID:   task.AssigneeID,
+			RecurrenceID: task.RecurrenceID,
+			Occurrence:   task.Occurrence,
+			CreatedAt:    task.CreatedAt,
+			Tags:         task.Tags,
+			Progress:     task.Progress,
+			Blocked:      tasks[i].Blocked,
+		})
 	}
 
 	ctx.JSON(http.StatusOK, successResponse(rsp))
@@ -184,25 +465,18 @@ func (s *Server) getTaskByIDHandler(ctx *gin.Context) {
 		return
 	}
 
-	task, err := s.storage.GetTaskByID(ctx, req.ID)
-	if err != nil {
-		if errors.Is(err, store.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	task, _, ok := s.getAccessibleTask(ctx, req.ID, taskRoleViewer)
+	if !ok {
 		return
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if task.CreatorID != authPayload.UserID {
-		err := errors.New("task doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	rsp, err := s.taskResponses(ctx, []store.Task{task})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, successResponse(task))
+	ctx.JSON(http.StatusOK, successResponse(rsp[0]))
 }
 
 type updateTaskRequest struct {
@@ -211,6 +485,37 @@ type updateTaskRequest struct {
 	Description *string `json:"description" binding:"omitempty"`
 	Deadline    string  `json:"deadline" binding:"omitempty,iso8601"`
 	Completed   *bool   `json:"completed" binding:"omitempty"`
+	Priority    string  `json:"priority" binding:"omitempty,oneof=none low medium high urgent"`
+	// TagIDs replaces the tags of the task when present; an empty list
+	// removes all of them.
+	TagIDs *[]int64 `json:"tag_ids" binding:"omitempty,max=20,dive,min=1"`
+	// Force completes a task even though it is blocked by open tasks.
+	Force bool `json:"force"`
+	// ProjectID moves the task into a project; 0 removes it from its
+	// project.
+	ProjectID *int64 `json:"project_id" binding:"omitempty,min=0"`
+	// AssigneeID assigns the task to a user; 0 unassigns it.
+	AssigneeID *int64 `json:"assignee_id" binding:"omitempty,min=0"`
+	// RecurrenceRule makes the task and the occurrences after it follow a
+	// new RRULE; an empty rule stops the task from recurring.
+	RecurrenceRule *string `json:"recurrence_rule" binding:"omitempty,max=500"`
+	// RecurrenceScope is "future" to also apply the update to the
+	// occurrences that follow the task. It defaults to "this".
+	RecurrenceScope string `json:"recurrence_scope" binding:"omitempty,oneof=this future"`
+}
+
+// changesOnlyStatus reports whether an update at most completes or reopens
+// the task.
+func (req updateTaskRequest) changesOnlyStatus() bool {
+	return len(req.Title) == 0 &&
+		req.Description == nil &&
+		len(req.Deadline) == 0 &&
+		len(req.Priority) == 0 &&
+		req.TagIDs == nil &&
+		req.ProjectID == nil &&
+		req.AssigneeID == nil &&
+		req.RecurrenceRule == nil &&
+		req.RecurrenceScope != recurrenceScopeFuture
 }
 
 func (s *Server) updateTasksHandler(ctx *gin.Context) {
@@ -220,26 +525,42 @@ func (s *Server) updateTasksHandler(ctx *gin.Context) {
 		return
 	}
 
-	task, err := s.storage.GetTaskByID(ctx, req.ID)
-	if err != nil {
-		if errors.Is(err, store.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
+	task, role, ok := s.getAccessibleTask(ctx, req.ID, taskRoleAssignee)
+	if !ok {
+		return
+	}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if task.CreatorID != authPayload.UserID {
-		err := errors.New("task doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	if role < taskRoleEditor && !req.changesOnlyStatus() {
+		ctx.JSON(http.StatusForbidden, errorResponse(errAssigneeStatus))
 		return
 	}
 
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	// Tags, projects and series belong to the creator of the task, so editors
+	// may only change the task itself.
+	if role < taskRoleOwner && (req.TagIDs != nil || req.ProjectID != nil || req.RecurrenceRule != nil || req.RecurrenceScope == recurrenceScopeFuture) {
+		ctx.JSON(http.StatusForbidden, errorResponse(errTaskOwnerOnly))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	var rule *recurrence.Rule
+	if req.RecurrenceRule != nil && len(*req.RecurrenceRule) > 0 {
+		parsed, err := recurrence.Parse(*req.RecurrenceRule)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		rule = &parsed
+	}
+
+	if req.RecurrenceScope == recurrenceScopeFuture && !task.RecurrenceID.Valid {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errTaskNotRecurring))
 		return
 	}
 
@@ -276,13 +597,166 @@ func (s *Server) updateTasksHandler(ctx *gin.Context) {
 		}
 	}
 
-	newTask, err := s.storage.UpdateTask(ctx, arg)
+	if len(req.Priority) > 0 {
+		arg.Priority = pgtype.Text{
+			String: req.Priority,
+			Valid:  true,
+		}
+	}
+
+	var projectID pgtype.Int8
+	if req.ProjectID != nil && *req.ProjectID > 0 {
+		if task.WorkspaceID.Valid {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errWorkspaceTaskProject))
+			return
+		}
+
+		project, err := s.getTaskProject(ctx, authPayload.UserID, *req.ProjectID)
+		if err != nil {
+			if errors.Is(err, errProjectNotFound) || errors.Is(err, errProjectArchived) {
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		projectID = pgtype.Int8{
+			Int64: project.ID,
+			Valid: true,
+		}
+	}
+
+	var assigneeID pgtype.Int8
+	if req.AssigneeID != nil && *req.AssigneeID > 0 {
+		if err := s.checkAssignee(ctx, task, *req.AssigneeID); err != nil {
+			if errors.Is(err, errInvalidAssignee) {
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		assigneeID = pgtype.Int8{
+			Int64: *req.AssigneeID,
+			Valid: true,
+		}
+	}
+
+	var tags []store.Tag
+	if req.TagIDs != nil {
+		var err error
+		// Workspace admins may tag the tasks of other members, still from
+		// the tags of the creator.
+		tags, err = s.getOwnedTags(ctx, task.CreatorID, *req.TagIDs)
+		if err != nil {
+			if errors.Is(err, errUnknownTags) {
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	if req.TagIDs == nil {
+		tagsByTask, err := s.listTaskTags(ctx, []string{task.ID})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		tags = tagsByTask[task.ID]
+	}
+
+	// Everything else that can be rejected has been checked, and the writes
+	// below either all happen or none of them does.
+	var newTask store.Task
+	var nextOccurrence *taskResponse
+	var blockedErr error
+	err := s.storage.ExecTx(ctx, func(q store.Querier) error {
+		// The blockers are counted in the transaction, which keeps them from
+		// being reopened until the task is completed.
+		if req.Completed != nil && *req.Completed && !task.Completed && !req.Force {
+			openBlockers, err := q.CountOpenBlockers(ctx, task.ID)
+			if err != nil {
+				return err
+			}
+			if openBlockers > 0 {
+				blockedErr = errTaskBlocked(openBlockers)
+				return blockedErr
+			}
+		}
+
+		var err error
+		newTask, err = q.UpdateTask(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		if req.ProjectID != nil && projectID != newTask.ProjectID {
+			newTask, err = q.SetTaskProject(ctx, store.SetTaskProjectParams{
+				ID:        newTask.ID,
+				ProjectID: projectID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if req.AssigneeID != nil && assigneeID != newTask.AssigneeID {
+			newTask, err = q.SetTaskAssignee(ctx, store.SetTaskAssigneeParams{
+				ID:         newTask.ID,
+				AssigneeID: assigneeID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		// A new rule always applies to this and the following occurrences.
+		if req.RecurrenceRule != nil || req.RecurrenceScope == recurrenceScopeFuture {
+			newTask, err = s.updateTaskRecurrence(ctx, q, newTask, req, rule)
+			if err != nil {
+				return err
+			}
+		}
+
+		if req.TagIDs != nil {
+			if err := s.setTaskTags(ctx, q, newTask.ID, tags); err != nil {
+				return err
+			}
+		}
+
+		if newTask.RecurrenceID.Valid && newTask.Completed && !task.Completed {
+			nextOccurrence, err = s.createNextOccurrence(ctx, q, newTask, tags)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
+		if blockedErr != nil {
+			ctx.JSON(http.StatusConflict, errorResponse(blockedErr))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, successResponse(newTask))
+	progressByTask, err := s.listSubtaskProgress(ctx, []string{newTask.ID})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := taskResponse{
+		Task:           newTask,
+		Tags:           tags,
+		Progress:       progressByTask[newTask.ID],
+		NextOccurrence: nextOccurrence,
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp))
 }
 
 type deleteTaskRequest struct {
@@ -296,25 +770,12 @@ func (s *Server) deleteTaskHandler(ctx *gin.Context) {
 		return
 	}
 
-	task, err := s.storage.GetTaskByID(ctx, req.ID)
-	if err != nil {
-		if errors.Is(err, store.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if task.CreatorID != authPayload.UserID {
-		err := errors.New("task doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	// Editors may change a task but only its creator may delete it.
+	if _, _, ok := s.getAccessibleTask(ctx, req.ID, taskRoleOwner); !ok {
 		return
 	}
 
-	err = s.storage.DeleteTask(ctx, req.ID)
+	err := s.storage.DeleteTask(ctx, req.ID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
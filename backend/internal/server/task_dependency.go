@@ -0,0 +1,152 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+)
+
+var (
+	errBlockerTaskNotFound    = errors.New("blocking task not found")
+	errTaskDependsOnItself    = errors.New("a task cannot depend on itself")
+	errDependencyCycle        = errors.New("the dependency would create a cycle")
+	errTaskDependencyNotFound = errors.New("task dependency not found")
+)
+
+// errTaskBlocked is returned when completing a task that still waits on other
+// tasks. The request may be repeated with force=true.
+func errTaskBlocked(openBlockers int64) error {
+	return fmt.Errorf("task is blocked by %d open task(s); set force to complete it anyway", openBlockers)
+}
+
+type taskDependenciesURIRequest struct {
+	ID string `uri:"id" binding:"required"`
+}
+
+// listTaskDependenciesHandler lists the tasks blocking a task. Blockers the
+// user cannot view themselves are left out, so that sharing a task does not
+// reveal the other tasks of its owner.
+func (s *Server) listTaskDependenciesHandler(ctx *gin.Context) {
+	var req taskDependenciesURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	task, _, ok := s.getAccessibleTask(ctx, req.ID, taskRoleViewer)
+	if !ok {
+		return
+	}
+	blockers, err := s.storage.ListTaskBlockers(ctx, task.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	visible := make([]store.Task, 0, len(blockers))
+	for _, blocker := range blockers {
+		role, err := s.getTaskRole(ctx, blocker, authPayload.UserID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if role >= taskRoleViewer {
+			visible = append(visible, blocker)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(visible))
+}
+
+type addTaskDependencyRequest struct {
+	BlockedByID string `json:"blocked_by_id" binding:"required"`
+}
+
+// addTaskDependencyHandler records that the task in the path cannot start
+// until the task in the body is completed.
+func (s *Server) addTaskDependencyHandler(ctx *gin.Context) {
+	var uri taskDependenciesURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	task, _, ok := s.getAccessibleTask(ctx, uri.ID, taskRoleOwner)
+	if !ok {
+		return
+	}
+
+	var req addTaskDependencyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.BlockedByID == task.ID {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errTaskDependsOnItself))
+		return
+	}
+
+	blocker, err := s.getReferencedTask(ctx, task.CreatorID, req.BlockedByID, errBlockerTaskNotFound)
+	if err != nil {
+		if errors.Is(err, errBlockerTaskNotFound) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = s.storage.AddTaskDependency(ctx, store.AddTaskDependencyParams{
+		TaskID:      task.ID,
+		BlockedByID: blocker.ID,
+	})
+	if err != nil {
+		if store.ErrorConstraintName(err) == "task_dependencies_cycle_check" {
+			ctx.JSON(http.StatusConflict, errorResponse(errDependencyCycle))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, successResponse(blocker))
+}
+
+type removeTaskDependencyRequest struct {
+	ID          string `uri:"id" binding:"required"`
+	BlockedByID string `uri:"blocked_by_id" binding:"required"`
+}
+
+func (s *Server) removeTaskDependencyHandler(ctx *gin.Context) {
+	var req removeTaskDependencyRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	task, _, ok := s.getAccessibleTask(ctx, req.ID, taskRoleOwner)
+	if !ok {
+		return
+	}
+	rows, err := s.storage.RemoveTaskDependency(ctx, store.RemoveTaskDependencyParams{
+		TaskID:      task.ID,
+		BlockedByID: req.BlockedByID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if rows == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(errTaskDependencyNotFound))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(nil))
+}
@@ -0,0 +1,408 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListTaskDependenciesHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	viewer, _ := randomUser(t)
+	task := randomTask(t, user.ID)
+	sharedBlocker := randomTask(t, user.ID)
+	privateBlocker := randomTask(t, user.ID)
+
+	testCases := []struct {
+		name       string
+		user       store.User
+		buildStubs func(storage *mockdb.MockStorage)
+		expectIDs  []string
+	}{
+		{
+			name: "Owner",
+			user: user,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					GetTaskShare(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectIDs: []string{sharedBlocker.ID, privateBlocker.ID},
+		},
+		{
+			name: "SharedViewer",
+			user: viewer,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubTaskShare(storage, task, viewer.ID, util.ShareRoleViewer)
+				storage.EXPECT().
+					GetTaskShare(gomock.Any(), gomock.Eq(store.GetTaskShareParams{
+						TaskID: sharedBlocker.ID,
+						UserID: viewer.ID,
+					})).
+					Times(1).
+					Return(randomTaskShare(sharedBlocker, viewer.ID, util.ShareRoleViewer), nil)
+				storage.EXPECT().
+					GetTaskShare(gomock.Any(), gomock.Eq(store.GetTaskShareParams{
+						TaskID: privateBlocker.ID,
+						UserID: viewer.ID,
+					})).
+					Times(1).
+					Return(store.TaskShare{}, store.ErrRecordNotFound)
+			},
+			expectIDs: []string{sharedBlocker.ID},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+			storage.EXPECT().
+				ListTaskBlockers(gomock.Any(), gomock.Eq(task.ID)).
+				Times(1).
+				Return([]store.Task{sharedBlocker, privateBlocker}, nil)
+
+			recorder := serveAuthenticatedRequest(t, storage, tc.user, http.MethodGet, "/tasks/"+task.ID+"/dependencies", nil)
+			require.Equal(t, http.StatusOK, recorder.Code)
+
+			var rsp struct {
+				Data []store.Task `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+			ids := make([]string, 0, len(rsp.Data))
+			for _, blocker := range rsp.Data {
+				ids = append(ids, blocker.ID)
+			}
+			require.Equal(t, tc.expectIDs, ids)
+		})
+	}
+}
+
+func TestAddTaskDependencyHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	task := randomTask(t, user.ID)
+	blocker := randomTask(t, user.ID)
+	otherTask := randomTask(t, user.ID+1)
+
+	testCases := []struct {
+		name       string
+		taskID     string
+		body       gin.H
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name:   "OK",
+			taskID: task.ID,
+			body:   gin.H{"blocked_by_id": blocker.ID},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(blocker.ID)).
+					Times(1).
+					Return(blocker, nil)
+				storage.EXPECT().
+					AddTaskDependency(gomock.Any(), gomock.Eq(store.AddTaskDependencyParams{
+						TaskID:      task.ID,
+						BlockedByID: blocker.ID,
+					})).
+					Times(1)
+			},
+			expectCode: http.StatusCreated,
+		},
+		{
+			name:   "Cycle",
+			taskID: task.ID,
+			body:   gin.H{"blocked_by_id": blocker.ID},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(blocker.ID)).
+					Times(1).
+					Return(blocker, nil)
+				storage.EXPECT().
+					AddTaskDependency(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(&pgconn.PgError{
+						Code:           store.CheckViolation,
+						ConstraintName: "task_dependencies_cycle_check",
+					})
+			},
+			expectCode: http.StatusConflict,
+		},
+		{
+			name:   "DependsOnItself",
+			taskID: task.ID,
+			body:   gin.H{"blocked_by_id": task.ID},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					AddTaskDependency(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:   "BlockerOfOtherUser",
+			taskID: task.ID,
+			body:   gin.H{"blocked_by_id": otherTask.ID},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(otherTask.ID)).
+					Times(1).
+					Return(otherTask, nil)
+				storage.EXPECT().
+					AddTaskDependency(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:   "Unauthorized",
+			taskID: otherTask.ID,
+			body:   gin.H{"blocked_by_id": blocker.ID},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(otherTask.ID)).
+					Times(1).
+					Return(otherTask, nil)
+				storage.EXPECT().
+					GetTaskShare(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.TaskShare{}, store.ErrRecordNotFound)
+				storage.EXPECT().
+					AddTaskDependency(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusUnauthorized,
+		},
+		{
+			name:   "MissingBlocker",
+			taskID: task.ID,
+			body:   gin.H{},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					AddTaskDependency(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			url := "/tasks/" + tc.taskID + "/dependencies"
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPost, url, tc.body)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestRemoveTaskDependencyHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	task := randomTask(t, user.ID)
+	blocker := randomTask(t, user.ID)
+
+	testCases := []struct {
+		name       string
+		rows       int64
+		expectCode int
+	}{
+		{
+			name:       "OK",
+			rows:       1,
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "NotFound",
+			rows:       0,
+			expectCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			storage.EXPECT().
+				GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+				Times(1).
+				Return(task, nil)
+			storage.EXPECT().
+				RemoveTaskDependency(gomock.Any(), gomock.Eq(store.RemoveTaskDependencyParams{
+					TaskID:      task.ID,
+					BlockedByID: blocker.ID,
+				})).
+				Times(1).
+				Return(tc.rows, nil)
+
+			url := "/tasks/" + task.ID + "/dependencies/" + blocker.ID
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodDelete, url, nil)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestCompleteBlockedTask(t *testing.T) {
+	user, _ := randomUser(t)
+	task := randomTask(t, user.ID)
+	completedTask := task
+	completedTask.Completed = true
+
+	testCases := []struct {
+		name       string
+		task       store.Task
+		body       gin.H
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name: "Blocked",
+			task: task,
+			body: gin.H{"completed": true},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CountOpenBlockers(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(int64(2), nil)
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusConflict,
+		},
+		{
+			name: "Forced",
+			task: task,
+			body: gin.H{"completed": true, "force": true},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CountOpenBlockers(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(completedTask, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name: "AlreadyCompleted",
+			task: completedTask,
+			body: gin.H{"completed": true},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CountOpenBlockers(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(completedTask, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name: "Reopen",
+			task: completedTask,
+			body: gin.H{"completed": false},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CountOpenBlockers(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(task, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			storage.EXPECT().
+				GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+				Times(1).
+				Return(tc.task, nil)
+			tc.buildStubs(storage)
+			stubNoTaskTags(storage)
+
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPut, "/tasks/"+task.ID, tc.body)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestGetTasksMarksBlockedTasks(t *testing.T) {
+	user, _ := randomUser(t)
+	blocked := randomTask(t, user.ID)
+	free := randomTask(t, user.ID)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mockdb.NewMockStorage(ctrl)
+	storage.EXPECT().
+		GetTasks(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]store.GetTasksRow{
+			{ID: blocked.ID, CreatorID: user.ID, Blocked: true, Total: 2},
+			{ID: free.ID, CreatorID: user.ID, Total: 2},
+		}, nil)
+	stubNoTaskTags(storage)
+
+	recorder := serveAuthenticatedRequest(t, storage, user, http.MethodGet, "/tasks", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp struct {
+		Data getTasksResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Len(t, rsp.Data.Tasks, 2)
+	require.True(t, rsp.Data.Tasks[0].Blocked)
+	require.False(t, rsp.Data.Tasks[1].Blocked)
+}
@@ -0,0 +1,290 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+)
+
+// taskRole is the access a user has to a task. Each role allows everything
+// the roles before it do.
+type taskRole int
+
+const (
+	taskRoleNone taskRole = iota
+	taskRoleViewer
+	// taskRoleAssignee may also complete and reopen the task.
+	taskRoleAssignee
+	taskRoleEditor
+	taskRoleOwner
+)
+
+var (
+	errTaskNotAccessible  = errors.New("task doesn't belong to the authenticated user")
+	errTaskRoleTooLow     = errors.New("the task is shared with the authenticated user with a role that does not allow this")
+	errTaskOwnerOnly      = errors.New("only the creator of the task can change its tags, project and recurrence")
+	errShareWithCreator   = errors.New("a task cannot be shared with its creator")
+	errTaskShareNotFound  = errors.New("task is not shared with this user")
+	errShareWorkspaceTask = errors.New("tasks of a workspace are shared with its members and cannot be shared with other users")
+)
+
+// newShareRole maps the role of a share to the access it grants.
+func newShareRole(role string) taskRole {
+	switch role {
+	case util.ShareRoleViewer:
+		return taskRoleViewer
+	case util.ShareRoleEditor:
+		return taskRoleEditor
+	default:
+		return taskRoleNone
+	}
+}
+
+// newWorkspaceTaskRole maps the role of a workspace member to the access it
+// grants to the tasks of the workspace. Owners and admins manage every task,
+// and members may edit the tasks of others.
+func newWorkspaceTaskRole(role string) taskRole {
+	switch role {
+	case util.WorkspaceRoleOwner, util.WorkspaceRoleAdmin:
+		return taskRoleOwner
+	case util.WorkspaceRoleMember:
+		return taskRoleEditor
+	default:
+		return taskRoleNone
+	}
+}
+
+// getTaskRole returns the access a user has to a task.
+func (s *Server) getTaskRole(ctx *gin.Context, task store.Task, userID int64) (taskRole, error) {
+	if task.CreatorID == userID {
+		return taskRoleOwner, nil
+	}
+
+	// The workspace of the task is the one of the request, which
+	// workspaceMiddleware checked the user is a member of.
+	if task.WorkspaceID.Valid {
+		member := ctx.MustGet(workspaceMemberKey).(store.WorkspaceMember)
+		return newWorkspaceTaskRole(member.Role), nil
+	}
+
+	share, err := s.storage.GetTaskShare(ctx, store.GetTaskShareParams{
+		TaskID: task.ID,
+		UserID: userID,
+	})
+	if err != nil && !errors.Is(err, store.ErrRecordNotFound) {
+		return taskRoleNone, err
+	}
+
+	role := newShareRole(share.Role)
+	if task.AssigneeID.Valid && task.AssigneeID.Int64 == userID && role < taskRoleAssignee {
+		role = taskRoleAssignee
+	}
+	return role, nil
+}
+
+// getAccessibleTask loads a task the authenticated user holds at least the
+// required role on. It answers 404 for missing tasks and for tasks outside
+// the workspace of the request, 401 for tasks that are neither created by,
+// shared with nor assigned to the user, and 403 when their role grants too
+// little. It reports whether the request may proceed.
+func (s *Server) getAccessibleTask(ctx *gin.Context, id string, required taskRole) (store.Task, taskRole, bool) {
+	task, err := s.storage.GetTaskByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return store.Task{}, taskRoleNone, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return store.Task{}, taskRoleNone, false
+	}
+
+	if task.WorkspaceID != currentWorkspaceID(ctx) {
+		ctx.JSON(http.StatusNotFound, errorResponse(errTaskNotInWorkspace))
+		return store.Task{}, taskRoleNone, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	role, err := s.getTaskRole(ctx, task, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return store.Task{}, taskRoleNone, false
+	}
+
+	if role == taskRoleNone {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errTaskNotAccessible))
+		return store.Task{}, taskRoleNone, false
+	}
+
+	if role < required {
+		ctx.JSON(http.StatusForbidden, errorResponse(errTaskRoleTooLow))
+		return store.Task{}, taskRoleNone, false
+	}
+
+	return task, role, true
+}
+
+type taskShareResponse struct {
+	UserID    int64     `json:"user_id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type taskSharesURIRequest struct {
+	ID string `uri:"id" binding:"required"`
+}
+
+// listTaskSharesHandler lists the users a task is shared with. Everyone with
+// access to the task may see who else has access.
+func (s *Server) listTaskSharesHandler(ctx *gin.Context) {
+	var uri taskSharesURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, _, ok := s.getAccessibleTask(ctx, uri.ID, taskRoleViewer); !ok {
+		return
+	}
+
+	shares, err := s.storage.ListTaskShares(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]taskShareResponse, 0, len(shares))
+	for _, share := range shares {
+		rsp = append(rsp, taskShareResponse{
+			UserID:    share.UserID,
+			Username:  share.Username,
+			Role:      share.Role,
+			CreatedAt: share.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp))
+}
+
+type shareTaskRequest struct {
+	Username string `json:"username" binding:"required,alphanum"`
+	Role     string `json:"role" binding:"required,oneof=viewer editor"`
+}
+
+// shareTaskHandler shares a task with another user, or changes the role of a
+// user it is already shared with. Only the creator of the task may share it.
+func (s *Server) shareTaskHandler(ctx *gin.Context) {
+	var uri taskSharesURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req shareTaskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	task, _, ok := s.getAccessibleTask(ctx, uri.ID, taskRoleOwner)
+	if !ok {
+		return
+	}
+
+	if task.WorkspaceID.Valid {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errShareWorkspaceTask))
+		return
+	}
+
+	user, err := s.storage.GetUser(ctx, req.Username)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errUserNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if user.ID == task.CreatorID {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errShareWithCreator))
+		return
+	}
+
+	share, err := s.storage.ShareTask(ctx, store.ShareTaskParams{
+		TaskID: task.ID,
+		UserID: user.ID,
+		Role:   req.Role,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(taskShareResponse{
+		UserID:    share.UserID,
+		Username:  user.Username,
+		Role:      share.Role,
+		CreatedAt: share.CreatedAt,
+	}))
+}
+
+type unshareTaskRequest struct {
+	ID     string `uri:"id" binding:"required"`
+	UserID int64  `uri:"user_id" binding:"required,min=1"`
+}
+
+// unshareTaskHandler stops sharing a task with a user, who is also unassigned
+// from it. The creator of the task may remove anyone; other users may only
+// remove themselves.
+func (s *Server) unshareTaskHandler(ctx *gin.Context) {
+	var req unshareTaskRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	required := taskRoleOwner
+	if req.UserID == authPayload.UserID {
+		required = taskRoleViewer
+	}
+
+	task, _, ok := s.getAccessibleTask(ctx, req.ID, required)
+	if !ok {
+		return
+	}
+
+	n, err := s.storage.UnshareTask(ctx, store.UnshareTaskParams{
+		TaskID: req.ID,
+		UserID: req.UserID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if n == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(errTaskShareNotFound))
+		return
+	}
+
+	// The assignment would otherwise keep giving access to the task.
+	if task.AssigneeID.Valid && task.AssigneeID.Int64 == req.UserID {
+		_, err = s.storage.SetTaskAssignee(ctx, store.SetTaskAssigneeParams{
+			ID: task.ID,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(nil))
+}
@@ -0,0 +1,426 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func randomTaskShare(task store.Task, userID int64, role string) store.TaskShare {
+	return store.TaskShare{
+		TaskID:    task.ID,
+		UserID:    userID,
+		Role:      role,
+		CreatedAt: time.Now(),
+	}
+}
+
+// stubTaskShare makes the task shared with the user in the given role, or not
+// shared at all when role is empty.
+func stubTaskShare(storage *mockdb.MockStorage, task store.Task, userID int64, role string) {
+	storage.EXPECT().
+		GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+		Times(1).
+		Return(task, nil)
+
+	call := storage.EXPECT().
+		GetTaskShare(gomock.Any(), gomock.Eq(store.GetTaskShareParams{
+			TaskID: task.ID,
+			UserID: userID,
+		})).
+		Times(1)
+	if len(role) == 0 {
+		call.Return(store.TaskShare{}, store.ErrRecordNotFound)
+	} else {
+		call.Return(randomTaskShare(task, userID, role), nil)
+	}
+}
+
+func TestSharedTaskAccess(t *testing.T) {
+	creator, _ := randomUser(t)
+	user, _ := randomUser(t)
+	task := randomTask(t, creator.ID)
+	url := "/tasks/" + task.ID
+
+	testCases := []struct {
+		name       string
+		method     string
+		body       gin.H
+		role       string
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name:       "ViewerReads",
+			method:     http.MethodGet,
+			role:       util.ShareRoleViewer,
+			buildStubs: func(storage *mockdb.MockStorage) {},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "NotSharedReads",
+			method:     http.MethodGet,
+			buildStubs: func(storage *mockdb.MockStorage) {},
+			expectCode: http.StatusUnauthorized,
+		},
+		{
+			name:   "ViewerUpdates",
+			method: http.MethodPut,
+			body:   gin.H{"title": "Renamed"},
+			role:   util.ShareRoleViewer,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:   "EditorUpdates",
+			method: http.MethodPut,
+			body:   gin.H{"title": "Renamed", "completed": true},
+			role:   util.ShareRoleEditor,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CountOpenBlockers(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(int64(0), nil)
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Eq(store.UpdateTaskParams{
+						ID:        task.ID,
+						Title:     pgtype.Text{String: "Renamed", Valid: true},
+						Completed: pgtype.Bool{Bool: true, Valid: true},
+					})).
+					Times(1).
+					Return(task, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:   "EditorChangesTags",
+			method: http.MethodPut,
+			body:   gin.H{"tag_ids": []int64{}},
+			role:   util.ShareRoleEditor,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:   "EditorChangesProject",
+			method: http.MethodPut,
+			body:   gin.H{"project_id": 0},
+			role:   util.ShareRoleEditor,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:   "EditorDeletes",
+			method: http.MethodDelete,
+			role:   util.ShareRoleEditor,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					DeleteTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			stubTaskShare(storage, task, user.ID, tc.role)
+			stubNoTaskTags(storage)
+			tc.buildStubs(storage)
+
+			recorder := serveAuthenticatedRequest(t, storage, user, tc.method, url, tc.body)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestGetTasksOwnership(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		query  string
+		shared pgtype.Bool
+	}{
+		{query: "", shared: pgtype.Bool{}},
+		{query: "?ownership=mine", shared: pgtype.Bool{Bool: false, Valid: true}},
+		{query: "?ownership=shared", shared: pgtype.Bool{Bool: true, Valid: true}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			storage.EXPECT().
+				GetTasks(gomock.Any(), gomock.Eq(store.GetTasksParams{
+					UserID: user.ID,
+					Shared: tc.shared,
+					Limit:  5,
+				})).
+				Times(1).
+				Return([]store.GetTasksRow{}, nil)
+
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodGet, "/tasks"+tc.query, nil)
+			require.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mockdb.NewMockStorage(ctrl)
+	recorder := serveAuthenticatedRequest(t, storage, user, http.MethodGet, "/tasks?ownership=everyone", nil)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestShareTaskHandler(t *testing.T) {
+	creator, _ := randomUser(t)
+	other, _ := randomUser(t)
+	task := randomTask(t, creator.ID)
+	url := fmt.Sprintf("/tasks/%s/shares", task.ID)
+
+	testCases := []struct {
+		name       string
+		user       store.User
+		body       gin.H
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name: "OK",
+			user: creator,
+			body: gin.H{"username": other.Username, "role": util.ShareRoleEditor},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(other.Username)).
+					Times(1).
+					Return(other, nil)
+				storage.EXPECT().
+					ShareTask(gomock.Any(), gomock.Eq(store.ShareTaskParams{
+						TaskID: task.ID,
+						UserID: other.ID,
+						Role:   util.ShareRoleEditor,
+					})).
+					Times(1).
+					Return(randomTaskShare(task, other.ID, util.ShareRoleEditor), nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name: "InvalidRole",
+			user: creator,
+			body: gin.H{"username": other.Username, "role": "owner"},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name: "ShareWithCreator",
+			user: creator,
+			body: gin.H{"username": creator.Username, "role": util.ShareRoleViewer},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(creator.Username)).
+					Times(1).
+					Return(creator, nil)
+				storage.EXPECT().
+					ShareTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name: "UserNotFound",
+			user: creator,
+			body: gin.H{"username": other.Username, "role": util.ShareRoleViewer},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.User{}, store.ErrRecordNotFound)
+			},
+			expectCode: http.StatusNotFound,
+		},
+		{
+			name: "EditorShares",
+			user: other,
+			body: gin.H{"username": creator.Username, "role": util.ShareRoleViewer},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubTaskShare(storage, task, other.ID, util.ShareRoleEditor)
+				storage.EXPECT().
+					ShareTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			recorder := serveAuthenticatedRequest(t, storage, tc.user, http.MethodPost, url, tc.body)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestListTaskSharesHandler(t *testing.T) {
+	creator, _ := randomUser(t)
+	viewer, _ := randomUser(t)
+	task := randomTask(t, creator.ID)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mockdb.NewMockStorage(ctrl)
+	stubTaskShare(storage, task, viewer.ID, util.ShareRoleViewer)
+	storage.EXPECT().
+		ListTaskShares(gomock.Any(), gomock.Eq(task.ID)).
+		Times(1).
+		Return([]store.ListTaskSharesRow{{
+			TaskID:   task.ID,
+			UserID:   viewer.ID,
+			Username: viewer.Username,
+			Role:     util.ShareRoleViewer,
+		}}, nil)
+
+	url := fmt.Sprintf("/tasks/%s/shares", task.ID)
+	recorder := serveAuthenticatedRequest(t, storage, viewer, http.MethodGet, url, nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), viewer.Username)
+}
+
+func TestUnshareTaskHandler(t *testing.T) {
+	creator, _ := randomUser(t)
+	viewer, _ := randomUser(t)
+	other, _ := randomUser(t)
+	task := randomTask(t, creator.ID)
+
+	testCases := []struct {
+		name       string
+		user       store.User
+		unshared   store.User
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name:     "CreatorRemovesUser",
+			user:     creator,
+			unshared: viewer,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					UnshareTask(gomock.Any(), gomock.Eq(store.UnshareTaskParams{
+						TaskID: task.ID,
+						UserID: viewer.ID,
+					})).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:     "ViewerLeaves",
+			user:     viewer,
+			unshared: viewer,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubTaskShare(storage, task, viewer.ID, util.ShareRoleViewer)
+				storage.EXPECT().
+					UnshareTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:     "ViewerRemovesOtherUser",
+			user:     viewer,
+			unshared: other,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubTaskShare(storage, task, viewer.ID, util.ShareRoleViewer)
+				storage.EXPECT().
+					UnshareTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:     "NotShared",
+			user:     creator,
+			unshared: other,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					UnshareTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+			},
+			expectCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			url := fmt.Sprintf("/tasks/%s/shares/%d", task.ID, tc.unshared.ID)
+			recorder := serveAuthenticatedRequest(t, storage, tc.user, http.MethodDelete, url, nil)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
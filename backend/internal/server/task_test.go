@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strconv"
 	"testing"
 	"time"
@@ -36,6 +37,7 @@ func randomTask(t *testing.T, creatorID int64) store.Task {
 			Valid:  true,
 		},
 		Deadline: time.Now().Add(time.Hour),
+		Priority: util.PriorityMedium,
 	}
 }
 
@@ -61,6 +63,10 @@ func (e eqCreateTaskParamsMatcher) Matches(x interface{}) bool {
 		return false
 	}
 
+	if e.arg.Priority != arg.Priority {
+		return false
+	}
+
 	if e.arg.Deadline.Sub(arg.Deadline) > time.Second || arg.Deadline.Sub(e.arg.Deadline) > time.Second {
 		return false
 	}
@@ -96,6 +102,7 @@ func requireBodyMatchTask(t *testing.T, body *bytes.Buffer, task store.Task) {
 	require.Equal(t, gotTask.CreatorID, task.CreatorID)
 	require.Equal(t, gotTask.Title, task.Title)
 	require.Equal(t, gotTask.Description, task.Description)
+	require.Equal(t, gotTask.Priority, task.Priority)
 	require.WithinDuration(t, gotTask.Deadline, task.Deadline, time.Second)
 }
 
@@ -116,9 +123,10 @@ func TestCreateTaskHandler(t *testing.T) {
 				"title":       task.Title,
 				"description": task.Description.String,
 				"deadline":    task.Deadline,
+				"priority":    task.Priority,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				arg := store.CreateTaskParams{
@@ -127,6 +135,7 @@ func TestCreateTaskHandler(t *testing.T) {
 					Title:       task.Title,
 					Description: task.Description,
 					Deadline:    task.Deadline,
+					Priority:    task.Priority,
 				}
 				storage.EXPECT().
 					CreateTask(gomock.Any(), EqCreateTaskParams(arg)).
@@ -145,7 +154,7 @@ func TestCreateTaskHandler(t *testing.T) {
 				"deadline": task.Deadline,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				arg := store.CreateTaskParams{
@@ -153,6 +162,7 @@ func TestCreateTaskHandler(t *testing.T) {
 					CreatorID: task.CreatorID,
 					Title:     task.Title,
 					Deadline:  task.Deadline,
+					Priority:  util.PriorityNone,
 				}
 				storage.EXPECT().
 					CreateTask(gomock.Any(), EqCreateTaskParams(arg)).
@@ -188,7 +198,7 @@ func TestCreateTaskHandler(t *testing.T) {
 				"deadline": task.Deadline,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
@@ -200,6 +210,25 @@ func TestCreateTaskHandler(t *testing.T) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
+		{
+			name: "InvalidPriority",
+			body: gin.H{
+				"title":    task.Title,
+				"deadline": task.Deadline,
+				"priority": "critical",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "InvalidDeadline",
 			body: gin.H{
@@ -207,7 +236,7 @@ func TestCreateTaskHandler(t *testing.T) {
 				"deadline": "invalidDeadline",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
@@ -227,6 +256,8 @@ func TestCreateTaskHandler(t *testing.T) {
 
 			storage := mockdb.NewMockStorage(ctrl)
 			tc.buildStubs(storage)
+			stubTokenNotRevoked(storage)
+			stubExecTx(storage)
 
 			server, err := NewServer(storage)
 			require.NoError(t, err)
@@ -257,7 +288,11 @@ func (e eqGetTasksParamsMatcher) Matches(x interface{}) bool {
 		return false
 	}
 
-	if arg.CreatorID != e.arg.CreatorID {
+	if arg.UserID != e.arg.UserID || arg.Shared != e.arg.Shared || arg.WorkspaceID != e.arg.WorkspaceID {
+		return false
+	}
+
+	if arg.AssigneeID != e.arg.AssigneeID || arg.Unassigned != e.arg.Unassigned {
 		return false
 	}
 
@@ -289,6 +324,14 @@ func (e eqGetTasksParamsMatcher) Matches(x interface{}) bool {
 		return false
 	}
 
+	if !slices.Equal(arg.Priorities, e.arg.Priorities) {
+		return false
+	}
+
+	if arg.SortByPriority != e.arg.SortByPriority {
+		return false
+	}
+
 	return true
 }
 
@@ -321,6 +364,7 @@ func requireBodyMatchTasks(t *testing.T, body *bytes.Buffer, tasks []store.GetTa
 		require.Equal(t, tasks[i].Description, gotTasks[i].Description)
 		require.Equal(t, tasks[i].CreatorID, gotTasks[i].CreatorID)
 		require.Equal(t, tasks[i].Completed, gotTasks[i].Completed)
+		require.Equal(t, tasks[i].Priority, gotTasks[i].Priority)
 		require.WithinDuration(t, tasks[i].Deadline, gotTasks[i].Deadline, time.Second)
 		require.WithinDuration(t, tasks[i].CreatedAt, gotTasks[i].CreatedAt, time.Second)
 	}
@@ -339,6 +383,7 @@ func TestGetTasksHandler(t *testing.T) {
 		tasks[i].CreatorID = rt.CreatorID
 		tasks[i].Deadline = rt.Deadline
 		tasks[i].Completed = rt.Completed
+		tasks[i].Priority = rt.Priority
 		tasks[i].CreatedAt = rt.CreatedAt
 		tasks[i].Total = n
 	}
@@ -349,6 +394,8 @@ func TestGetTasksHandler(t *testing.T) {
 		StartDeadline string
 		EndDeadline   string
 		Completed     *bool
+		Priority      []string
+		Sort          string
 		Page          *int32
 		Limit         *int32
 	}
@@ -377,13 +424,13 @@ func TestGetTasksHandler(t *testing.T) {
 				Completed:     incomplete,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				arg := store.GetTasksParams{
-					CreatorID: user.ID,
-					Limit:     int32(n),
-					Offset:    0,
+					UserID: user.ID,
+					Limit:  int32(n),
+					Offset: 0,
 					Title: pgtype.Text{
 						String: "",
 						Valid:  true,
@@ -419,13 +466,13 @@ func TestGetTasksHandler(t *testing.T) {
 			name:  "NoQueryString",
 			query: Query{},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				arg := store.GetTasksParams{
-					CreatorID: user.ID,
-					Limit:     5,
-					Offset:    0,
+					UserID: user.ID,
+					Limit:  5,
+					Offset: 0,
 				}
 				storage.EXPECT().
 					GetTasks(gomock.Any(), EqGetTasksParams(arg)).
@@ -437,6 +484,90 @@ func TestGetTasksHandler(t *testing.T) {
 				requireBodyMatchTasks(t, recorder.Body, tasks)
 			},
 		},
+		{
+			name: "FilterAndSortByPriority",
+			query: Query{
+				Priority: []string{util.PriorityHigh, util.PriorityUrgent},
+				Sort:     "priority",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				arg := store.GetTasksParams{
+					UserID:         user.ID,
+					Limit:          5,
+					Offset:         0,
+					Priorities:     []string{util.PriorityHigh, util.PriorityUrgent},
+					SortByPriority: true,
+				}
+				storage.EXPECT().
+					GetTasks(gomock.Any(), EqGetTasksParams(arg)).
+					Times(1).
+					Return(tasks, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchTasks(t, recorder.Body, tasks)
+			},
+		},
+		{
+			name: "SortByDeadline",
+			query: Query{
+				Sort: "deadline",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				arg := store.GetTasksParams{
+					UserID: user.ID,
+					Limit:  5,
+					Offset: 0,
+				}
+				storage.EXPECT().
+					GetTasks(gomock.Any(), EqGetTasksParams(arg)).
+					Times(1).
+					Return(tasks, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidPriority",
+			query: Query{
+				Priority: []string{util.PriorityHigh, "critical"},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTasks(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidSort",
+			query: Query{
+				Sort: "title",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTasks(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name:  "UnauthorizedUser",
 			query: Query{},
@@ -455,7 +586,7 @@ func TestGetTasksHandler(t *testing.T) {
 			name:  "InternalError",
 			query: Query{},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
@@ -473,7 +604,7 @@ func TestGetTasksHandler(t *testing.T) {
 				StartDeadline: "invalid",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
@@ -493,6 +624,9 @@ func TestGetTasksHandler(t *testing.T) {
 
 			storage := mockdb.NewMockStorage(ctrl)
 			tc.buildStubs(storage)
+			stubNoTaskTags(storage)
+			stubNoSubtasks(storage)
+			stubTokenNotRevoked(storage)
 
 			server, err := NewServer(storage)
 			require.NoError(t, err)
@@ -525,6 +659,14 @@ func TestGetTasksHandler(t *testing.T) {
 				q.Add("completed", strconv.FormatBool(*tc.query.Completed))
 			}
 
+			for _, priority := range tc.query.Priority {
+				q.Add("priority", priority)
+			}
+
+			if len(tc.query.Sort) > 0 {
+				q.Add("sort", tc.query.Sort)
+			}
+
 			if tc.query.Page != nil {
 				q.Add("page", strconv.FormatInt(int64(*tc.query.Page), 10))
 			}
@@ -555,7 +697,7 @@ func TestGetTaskByIDHandler(t *testing.T) {
 		{
 			name: "OK",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
@@ -571,7 +713,7 @@ func TestGetTaskByIDHandler(t *testing.T) {
 		{
 			name: "NotFound",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
@@ -586,13 +728,17 @@ func TestGetTaskByIDHandler(t *testing.T) {
 		{
 			name: "UnauthorizedUser",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID-1, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID-1, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
 					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
 					Times(1).
 					Return(store.Task{}, nil)
+				storage.EXPECT().
+					GetTaskShare(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.TaskShare{}, store.ErrRecordNotFound)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -614,7 +760,7 @@ func TestGetTaskByIDHandler(t *testing.T) {
 		{
 			name: "InternalError",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
@@ -635,6 +781,9 @@ func TestGetTaskByIDHandler(t *testing.T) {
 
 			storage := mockdb.NewMockStorage(ctrl)
 			tc.buildStubs(storage)
+			stubNoTaskTags(storage)
+			stubNoSubtasks(storage)
+			stubTokenNotRevoked(storage)
 
 			server, err := NewServer(storage)
 			require.NoError(t, err)
@@ -674,6 +823,10 @@ func (e eqUpdateTaskParamsMatcher) Matches(x interface{}) bool {
 		return false
 	}
 
+	if e.arg.Priority != arg.Priority {
+		return false
+	}
+
 	if len(arg.ID) == 0 {
 		return false
 	}
@@ -696,6 +849,7 @@ func TestUpdateHandler(t *testing.T) {
 	newDescription := util.RandomPrintableString(300)
 	newDeadline := time.Now().Add(2 * time.Hour)
 	newCompleted := !task.Completed
+	newPriority := util.PriorityUrgent
 
 	testCases := []struct {
 		name          string
@@ -711,9 +865,10 @@ func TestUpdateHandler(t *testing.T) {
 				"description": newDescription,
 				"deadline":    newDeadline.Format(time.RFC3339),
 				"completed":   newCompleted,
+				"priority":    newPriority,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
@@ -721,6 +876,11 @@ func TestUpdateHandler(t *testing.T) {
 					Times(1).
 					Return(task, nil)
 
+				storage.EXPECT().
+					CountOpenBlockers(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(int64(0), nil)
+
 				newTask := store.Task{
 					ID:        task.ID,
 					CreatorID: task.CreatorID,
@@ -731,6 +891,7 @@ func TestUpdateHandler(t *testing.T) {
 					},
 					Deadline:  newDeadline,
 					Completed: newCompleted,
+					Priority:  newPriority,
 				}
 
 				arg := store.UpdateTaskParams{
@@ -748,6 +909,10 @@ func TestUpdateHandler(t *testing.T) {
 						Bool:  newTask.Completed,
 						Valid: true,
 					},
+					Priority: pgtype.Text{
+						String: newTask.Priority,
+						Valid:  true,
+					},
 				}
 
 				storage.EXPECT().
@@ -767,6 +932,7 @@ func TestUpdateHandler(t *testing.T) {
 					},
 					Deadline:  newDeadline,
 					Completed: newCompleted,
+					Priority:  newPriority,
 					CreatedAt: task.CreatedAt,
 				})
 			},
@@ -777,7 +943,7 @@ func TestUpdateHandler(t *testing.T) {
 				"deadline": newDeadline.Format(time.RFC3339),
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
@@ -826,7 +992,7 @@ func TestUpdateHandler(t *testing.T) {
 				"title": newTitle,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
@@ -875,7 +1041,7 @@ func TestUpdateHandler(t *testing.T) {
 				"description": newDescription,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
@@ -927,7 +1093,7 @@ func TestUpdateHandler(t *testing.T) {
 				"completed": newCompleted,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
@@ -935,6 +1101,11 @@ func TestUpdateHandler(t *testing.T) {
 					Times(1).
 					Return(task, nil)
 
+				storage.EXPECT().
+					CountOpenBlockers(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(int64(0), nil)
+
 				newTask := store.Task{
 					ID:          task.ID,
 					CreatorID:   task.CreatorID,
@@ -974,7 +1145,7 @@ func TestUpdateHandler(t *testing.T) {
 			name: "NotFound",
 			body: gin.H{},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
@@ -989,13 +1160,17 @@ func TestUpdateHandler(t *testing.T) {
 		{
 			name: "UnauthorizedUser",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID-1, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID-1, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
 					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
 					Times(1).
 					Return(store.Task{}, nil)
+				storage.EXPECT().
+					GetTaskShare(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.TaskShare{}, store.ErrRecordNotFound)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -1017,7 +1192,7 @@ func TestUpdateHandler(t *testing.T) {
 		{
 			name: "InternalError",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
@@ -1038,6 +1213,10 @@ func TestUpdateHandler(t *testing.T) {
 
 			storage := mockdb.NewMockStorage(ctrl)
 			tc.buildStubs(storage)
+			stubNoTaskTags(storage)
+			stubNoSubtasks(storage)
+			stubTokenNotRevoked(storage)
+			stubExecTx(storage)
 
 			server, err := NewServer(storage)
 			require.NoError(t, err)
@@ -1071,7 +1250,7 @@ func TestDeleteTaskHandler(t *testing.T) {
 		{
 			name: "OK",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
@@ -1089,7 +1268,7 @@ func TestDeleteTaskHandler(t *testing.T) {
 		{
 			name: "NotFound",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
@@ -1104,13 +1283,17 @@ func TestDeleteTaskHandler(t *testing.T) {
 		{
 			name: "UnauthorizedUser",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID-1, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID-1, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
 					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
 					Times(1).
 					Return(store.Task{}, nil)
+				storage.EXPECT().
+					GetTaskShare(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.TaskShare{}, store.ErrRecordNotFound)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -1132,7 +1315,7 @@ func TestDeleteTaskHandler(t *testing.T) {
 		{
 			name: "InternalError",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
 			},
 			buildStubs: func(storage *mockdb.MockStorage) {
 				storage.EXPECT().
@@ -1153,6 +1336,7 @@ func TestDeleteTaskHandler(t *testing.T) {
 
 			storage := mockdb.NewMockStorage(ctrl)
 			tc.buildStubs(storage)
+			stubTokenNotRevoked(storage)
 
 			server, err := NewServer(storage)
 			require.NoError(t, err)
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+)
+
+var (
+	errJWKSNotSupported      = errors.New("token maker doesn't publish verification keys")
+	errSessionNotFound       = errors.New("session not found")
+	errSessionBlocked        = errors.New("session is blocked")
+	errSessionExpired        = errors.New("session has expired")
+	errSessionUserMismatch   = errors.New("session doesn't belong to the token user")
+	errSessionTokenMismatch  = errors.New("mismatched session token")
+	errSessionClientMismatch = errors.New("session was created from a different client")
+	errMissingRefreshToken   = errors.New("refresh token is not provided")
+	errNotAccessToken        = errors.New("token is not an access token")
+	errNotRefreshToken       = errors.New("token is not a refresh token")
+)
+
+type renewAccessTokenRequest struct {
+	// RefreshToken is read from the refresh token cookie when omitted.
+	RefreshToken string `json:"refresh_token" binding:"omitempty"`
+}
+
+type renewAccessTokenResponse struct {
+	// AccessToken is omitted for cookie sessions, which get it as a cookie.
+	AccessToken         string    `json:"access_token,omitempty"`
+	AccessTokenExpireAt time.Time `json:"access_token_expire_at"`
+}
+
+func (s *Server) renewAccessTokenHandler(ctx *gin.Context) {
+	var req renewAccessTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	refreshToken, err := refreshTokenFromRequest(ctx, req.RefreshToken)
+	if err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if len(refreshToken) == 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errMissingRefreshToken))
+		return
+	}
+	fromCookie := len(req.RefreshToken) == 0
+
+	refreshPayload, err := s.tokenMaker.VerifyToken(refreshToken)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if refreshPayload.Type != token.TokenTypeRefresh {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errNotRefreshToken))
+		return
+	}
+
+	session, err := s.storage.GetSession(ctx, refreshPayload.ID)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errSessionNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if session.IsBlocked {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errSessionBlocked))
+		return
+	}
+
+	if session.UserID != refreshPayload.UserID {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errSessionUserMismatch))
+		return
+	}
+
+	if session.RefreshToken != refreshToken {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errSessionTokenMismatch))
+		return
+	}
+
+	if time.Now().After(session.ExpireAt) {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errSessionExpired))
+		return
+	}
+
+	if session.UserAgent != ctx.Request.UserAgent() || session.ClientIp != ctx.ClientIP() {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errSessionClientMismatch))
+		return
+	}
+
+	// The role may have changed since login, so it is read again rather than
+	// copied from the refresh token.
+	user, err := s.storage.GetUserByID(ctx, refreshPayload.UserID)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errUserNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	jwtConfig, err := util.LoadJWTConfig()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	accessToken, accessPayload, err := s.tokenMaker.CreateToken(
+		user.ID,
+		user.Username,
+		user.Role,
+		token.TokenTypeAccess,
+		jwtConfig.AccessTokenDuration,
+	)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if fromCookie {
+		s.setCookie(ctx, accessTokenCookieName, accessToken, accessPayload.ExpireAt, true)
+		ctx.JSON(http.StatusOK, successResponse(renewAccessTokenResponse{
+			AccessTokenExpireAt: accessPayload.ExpireAt,
+		}))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(renewAccessTokenResponse{
+		AccessToken:         accessToken,
+		AccessTokenExpireAt: accessPayload.ExpireAt,
+	}))
+}
+
+func (s *Server) jwksHandler(ctx *gin.Context) {
+	provider, ok := s.tokenMaker.(token.JWKSProvider)
+	if !ok {
+		ctx.JSON(http.StatusNotFound, errorResponse(errJWKSNotSupported))
+		return
+	}
+
+	// Served as a bare key set so standard JWKS clients can consume it.
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, provider.JWKS())
+}
@@ -0,0 +1,354 @@
+package server
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	testUserAgent = "task-management-test"
+	testClientIP  = "192.0.2.1"
+)
+
+func randomSession(t *testing.T, tokenMaker token.Maker, user store.User, duration time.Duration) (session store.Session, refreshToken string) {
+	refreshToken, payload, err := tokenMaker.CreateToken(user.ID, user.Username, user.Role, token.TokenTypeRefresh, duration)
+	require.NoError(t, err)
+
+	session = store.Session{
+		ID:           payload.ID,
+		UserID:       user.ID,
+		RefreshToken: refreshToken,
+		UserAgent:    testUserAgent,
+		ClientIp:     testClientIP,
+		ExpireAt:     payload.ExpireAt,
+	}
+
+	return
+}
+
+func TestRenewAccessTokenHandler(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		buildSession  func(t *testing.T, tokenMaker token.Maker) (store.Session, string)
+		buildStubs    func(storage *mockdb.MockStorage, session store.Session)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (store.Session, string) {
+				return randomSession(t, tokenMaker, user, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage, session store.Session) {
+				storage.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "UserNotFound",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (store.Session, string) {
+				return randomSession(t, tokenMaker, user, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage, session store.Session) {
+				storage.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				storage.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(store.User{}, store.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidRefreshToken",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (store.Session, string) {
+				session, _ := randomSession(t, tokenMaker, user, time.Minute)
+				return session, "invalid"
+			},
+			buildStubs: func(storage *mockdb.MockStorage, session store.Session) {
+				storage.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ExpiredRefreshToken",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (store.Session, string) {
+				return randomSession(t, tokenMaker, user, -time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage, session store.Session) {
+				storage.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "AccessToken",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (store.Session, string) {
+				session, _ := randomSession(t, tokenMaker, user, time.Minute)
+				accessToken, _, err := tokenMaker.CreateToken(user.ID, user.Username, user.Role, token.TokenTypeAccess, time.Minute)
+				require.NoError(t, err)
+				return session, accessToken
+			},
+			buildStubs: func(storage *mockdb.MockStorage, session store.Session) {
+				storage.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "SessionNotFound",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (store.Session, string) {
+				return randomSession(t, tokenMaker, user, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage, session store.Session) {
+				storage.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(store.Session{}, store.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "BlockedSession",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (store.Session, string) {
+				session, refreshToken := randomSession(t, tokenMaker, user, time.Minute)
+				session.IsBlocked = true
+				return session, refreshToken
+			},
+			buildStubs: func(storage *mockdb.MockStorage, session store.Session) {
+				storage.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "IncorrectSessionUser",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (store.Session, string) {
+				session, refreshToken := randomSession(t, tokenMaker, user, time.Minute)
+				session.UserID = user.ID - 1
+				return session, refreshToken
+			},
+			buildStubs: func(storage *mockdb.MockStorage, session store.Session) {
+				storage.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "MismatchedSessionToken",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (store.Session, string) {
+				session, refreshToken := randomSession(t, tokenMaker, user, time.Minute)
+				session.RefreshToken = "mismatched"
+				return session, refreshToken
+			},
+			buildStubs: func(storage *mockdb.MockStorage, session store.Session) {
+				storage.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ExpiredSession",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (store.Session, string) {
+				session, refreshToken := randomSession(t, tokenMaker, user, time.Minute)
+				session.ExpireAt = time.Now().Add(-time.Minute)
+				return session, refreshToken
+			},
+			buildStubs: func(storage *mockdb.MockStorage, session store.Session) {
+				storage.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "MismatchedUserAgent",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (store.Session, string) {
+				session, refreshToken := randomSession(t, tokenMaker, user, time.Minute)
+				session.UserAgent = "another-agent"
+				return session, refreshToken
+			},
+			buildStubs: func(storage *mockdb.MockStorage, session store.Session) {
+				storage.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "MismatchedClientIP",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (store.Session, string) {
+				session, refreshToken := randomSession(t, tokenMaker, user, time.Minute)
+				session.ClientIp = "198.51.100.1"
+				return session, refreshToken
+			},
+			buildStubs: func(storage *mockdb.MockStorage, session store.Session) {
+				storage.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (store.Session, string) {
+				return randomSession(t, tokenMaker, user, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage, session store.Session) {
+				storage.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.Session{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			session, refreshToken := tc.buildSession(t, server.tokenMaker)
+			tc.buildStubs(storage, session)
+
+			data, err := json.Marshal(gin.H{
+				"refresh_token": refreshToken,
+			})
+			require.NoError(t, err)
+
+			url := "/tokens/renew_access"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("User-Agent", testUserAgent)
+			request.RemoteAddr = testClientIP + ":1234"
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestJWKSHandler(t *testing.T) {
+	testCases := []struct {
+		name          string
+		buildMaker    func(t *testing.T, server *Server)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildMaker: func(t *testing.T, server *Server) {
+				key, err := rsa.GenerateKey(rand.Reader, 2048)
+				require.NoError(t, err)
+
+				tokenMaker, err := token.NewRSAJWTMaker("key-1", key, nil)
+				require.NoError(t, err)
+				server.tokenMaker = tokenMaker
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var keySet token.JSONWebKeySet
+				err := json.Unmarshal(recorder.Body.Bytes(), &keySet)
+				require.NoError(t, err)
+				require.Len(t, keySet.Keys, 1)
+				require.Equal(t, "key-1", keySet.Keys[0].Kid)
+			},
+		},
+		{
+			name: "SymmetricMaker",
+			buildMaker: func(t *testing.T, server *Server) {
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, err := NewServer(nil)
+			require.NoError(t, err)
+			tc.buildMaker(t, server)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			url := "/.well-known/jwks.json"
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -2,35 +2,54 @@ package server
 
 import (
 	"errors"
+	"io"
+	"log"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
 	"github.com/nguyen-duc-loc/task-management/backend/util"
 )
 
 var (
 	errUsernameConflict   = errors.New("username already exists")
 	errInvalidCredentials = errors.New("invalid credentials")
+	errSessionNotOwned    = errors.New("session doesn't belong to the authenticated user")
 )
 
 type createUserRequest struct {
 	Username string `json:"username" binding:"required,alphanum"`
 	Password string `json:"password" binding:"required,min=6"`
+	Email    string `json:"email" binding:"omitempty,email"`
 }
 
 type userResponse struct {
-	ID        int64     `json:"id"`
-	Username  string    `json:"username"`
-	CreatedAt time.Time `json:"created_at"`
+	ID              int64              `json:"id"`
+	Username        string             `json:"username"`
+	Role            string             `json:"role"`
+	Email           pgtype.Text        `json:"email"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
+	DisplayName     string             `json:"display_name"`
+	Timezone        string             `json:"timezone"`
+	Locale          string             `json:"locale"`
+	CreatedAt       time.Time          `json:"created_at"`
 }
 
 func newUserResponse(user store.User) userResponse {
 	return userResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		CreatedAt: user.CreatedAt,
+		ID:              user.ID,
+		Username:        user.Username,
+		Role:            user.Role,
+		Email:           user.Email,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		DisplayName:     user.DisplayName,
+		Timezone:        user.Timezone,
+		Locale:          user.Locale,
+		CreatedAt:       user.CreatedAt,
 	}
 }
 
@@ -52,9 +71,20 @@ func (s *Server) createUserHandler(ctx *gin.Context) {
 		HashedPassword: hashedPassword,
 	}
 
+	if len(req.Email) > 0 {
+		arg.Email = pgtype.Text{
+			String: normalizeEmail(req.Email),
+			Valid:  true,
+		}
+	}
+
 	user, err := s.storage.CreateUser(ctx, arg)
 	if err != nil {
 		if store.ErrorCode(err) == store.UniqueViolation {
+			if store.ErrorConstraintName(err) == "users_email_key" {
+				ctx.JSON(http.StatusConflict, errorResponse(errEmailConflict))
+				return
+			}
 			ctx.JSON(http.StatusConflict, errorResponse(errUsernameConflict))
 			return
 		}
@@ -62,18 +92,34 @@ func (s *Server) createUserHandler(ctx *gin.Context) {
 		return
 	}
 
+	s.recordAuditEvent(ctx, user.ID, user.Username, auditEventSignup, "")
+
+	// The account exists at this point; a failed delivery can be retried
+	// through the resend endpoint.
+	if user.Email.Valid {
+		if err := s.sendEmailVerification(ctx, user); err != nil {
+			log.Printf("cannot send verification email to user %d: %v", user.ID, err)
+		}
+	}
+
 	ctx.JSON(http.StatusCreated, successResponse(newUserResponse(user)))
 }
 
 type loginUserRequest struct {
 	Username string `json:"username" binding:"required,alphanum"`
 	Password string `json:"password" binding:"required,min=6"`
+	// UseCookies has the session set as HttpOnly cookies instead of being
+	// returned in the body.
+	UseCookies bool `json:"use_cookies"`
 }
 
 type loginResponse struct {
-	AccessToken         string       `json:"access_token"`
-	AccessTokenExpireAt time.Time    `json:"access_token_expire_at"`
-	User                userResponse `json:"user"`
+	SessionID            uuid.UUID    `json:"session_id"`
+	AccessToken          string       `json:"access_token"`
+	AccessTokenExpireAt  time.Time    `json:"access_token_expire_at"`
+	RefreshToken         string       `json:"refresh_token"`
+	RefreshTokenExpireAt time.Time    `json:"refresh_token_expire_at"`
+	User                 userResponse `json:"user"`
 }
 
 func (s *Server) loginUserHandler(ctx *gin.Context) {
@@ -83,10 +129,15 @@ func (s *Server) loginUserHandler(ctx *gin.Context) {
 		return
 	}
 
+	if !s.checkLoginLock(ctx, req.Username) {
+		return
+	}
+
 	user, err := s.storage.GetUser(ctx, req.Username)
 	if err != nil {
 		if errors.Is(err, store.ErrRecordNotFound) {
-			ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidCredentials))
+			s.recordAuditEvent(ctx, 0, req.Username, auditEventLoginFailed, "unknown_user")
+			s.rejectLogin(ctx, req.Username)
 			return
 		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
@@ -95,29 +146,206 @@ func (s *Server) loginUserHandler(ctx *gin.Context) {
 
 	err = util.CheckPassword(req.Password, user.HashedPassword)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidCredentials))
+		s.recordAuditEvent(ctx, user.ID, user.Username, auditEventLoginFailed, "invalid_password")
+		s.rejectLogin(ctx, req.Username)
 		return
 	}
 
-	jwtConfig, err := util.LoadJWTConfig()
+	if util.PasswordNeedsRehash(user.HashedPassword) {
+		s.rehashPassword(ctx, user, req.Password)
+	}
+
+	s.completeLogin(ctx, user, req.UseCookies)
+}
+
+// completeLogin answers a login whose first factor has been checked, either
+// with a session or, once TOTP is enabled, with an MFA challenge that
+// loginMFAHandler exchanges for the session.
+func (s *Server) completeLogin(ctx *gin.Context, user store.User, useCookies bool) {
+	totpEnabled, err := s.totpEnabled(ctx, user.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// The username counter is only cleared once a session is issued, so wrong
+	// MFA codes keep adding up across password logins.
+	if totpEnabled {
+		s.startMFAChallenge(ctx, user)
+		return
+	}
+
+	err = s.storage.ClearLoginAttempts(ctx, store.ClearLoginAttemptsParams{
+		Scope: loginScopeUsername,
+		Key:   user.Username,
+	})
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	rsp, err := s.createLoginSession(ctx, user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	s.respondLogin(ctx, rsp, useCookies)
+}
+
+// createLoginSession issues an access and refresh token pair for a user that
+// has completed every login step and records the session behind them.
+func (s *Server) createLoginSession(ctx *gin.Context, user store.User) (loginResponse, error) {
+	jwtConfig, err := util.LoadJWTConfig()
+	if err != nil {
+		return loginResponse{}, err
+	}
+
 	accessToken, accessPayload, err := s.tokenMaker.CreateToken(
 		user.ID,
 		user.Username,
+		user.Role,
+		token.TokenTypeAccess,
 		jwtConfig.AccessTokenDuration,
 	)
+	if err != nil {
+		return loginResponse{}, err
+	}
+
+	refreshToken, refreshPayload, err := s.tokenMaker.CreateToken(
+		user.ID,
+		user.Username,
+		user.Role,
+		token.TokenTypeRefresh,
+		jwtConfig.RefreshTokenDuration,
+	)
+	if err != nil {
+		return loginResponse{}, err
+	}
+
+	session, err := s.storage.CreateSession(ctx, store.CreateSessionParams{
+		ID:           refreshPayload.ID,
+		UserID:       user.ID,
+		RefreshToken: refreshToken,
+		UserAgent:    ctx.Request.UserAgent(),
+		ClientIp:     ctx.ClientIP(),
+		IsBlocked:    false,
+		ExpireAt:     refreshPayload.ExpireAt,
+	})
+	if err != nil {
+		return loginResponse{}, err
+	}
+
+	s.recordAuditEvent(ctx, user.ID, user.Username, auditEventLoginSucceeded, "")
+
+	return loginResponse{
+		SessionID:            session.ID,
+		AccessToken:          accessToken,
+		AccessTokenExpireAt:  accessPayload.ExpireAt,
+		RefreshToken:         refreshToken,
+		RefreshTokenExpireAt: refreshPayload.ExpireAt,
+		User:                 newUserResponse(user),
+	}, nil
+}
+
+// rehashPassword upgrades a stored hash to the current algorithm and
+// parameters. It runs after the password has been verified and a failure only
+// postpones the upgrade to the next login, so it never fails the request.
+func (s *Server) rehashPassword(ctx *gin.Context, user store.User, password string) {
+	hashedPassword, err := util.HashPassword(password)
+	if err != nil {
+		log.Printf("cannot rehash password of user %d: %v", user.ID, err)
+		return
+	}
+
+	_, err = s.storage.UpdateUserPassword(ctx, store.UpdateUserPasswordParams{
+		ID:             user.ID,
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		log.Printf("cannot rehash password of user %d: %v", user.ID, err)
+	}
+}
+
+type logoutUserRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"omitempty"`
+}
+
+func (s *Server) logoutUserHandler(ctx *gin.Context) {
+	var req logoutUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	refreshToken, err := refreshTokenFromRequest(ctx, req.RefreshToken)
+	if err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if len(refreshToken) > 0 {
+		refreshPayload, err := s.tokenMaker.VerifyToken(refreshToken)
+		if err != nil {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		if refreshPayload.Type != token.TokenTypeRefresh {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errNotRefreshToken))
+			return
+		}
+
+		if refreshPayload.UserID != authPayload.UserID {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errSessionNotOwned))
+			return
+		}
+
+		err = s.storage.BlockSession(ctx, refreshPayload.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	err = s.storage.RevokeToken(ctx, store.RevokeTokenParams{
+		ID:       authPayload.ID,
+		UserID:   authPayload.UserID,
+		ExpireAt: authPayload.ExpireAt,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	s.recordAuditEvent(ctx, authPayload.UserID, authPayload.Username, auditEventTokenRevoked, "logout")
+
+	s.clearAuthCookies(ctx)
+	ctx.JSON(http.StatusOK, successResponse(nil))
+}
+
+func (s *Server) logoutAllUserHandler(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	err := s.storage.BlockUserSessions(ctx, authPayload.UserID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, successResponse(loginResponse{
-		AccessToken:         accessToken,
-		AccessTokenExpireAt: accessPayload.ExpireAt,
-		User:                newUserResponse(user),
-	}))
+	err = s.storage.RevokeUserTokens(ctx, store.RevokeUserTokensParams{
+		UserID:        authPayload.UserID,
+		RevokedBefore: time.Now(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	s.recordAuditEvent(ctx, authPayload.UserID, authPayload.Username, auditEventTokenRevoked, "logout_all")
+
+	s.clearAuthCookies(ctx)
+	ctx.JSON(http.StatusOK, successResponse(nil))
 }
@@ -10,14 +10,18 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
 	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
 	"github.com/nguyen-duc-loc/task-management/backend/util"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func randomUser(t *testing.T) (user store.User, password string) {
@@ -28,7 +32,11 @@ func randomUser(t *testing.T) (user store.User, password string) {
 	user = store.User{
 		ID:             rand.Int64(),
 		Username:       util.RandomUsername(),
+		Role:           util.UserRole,
 		HashedPassword: hashedPassword,
+		DisplayName:    util.RandomPrintableString(10),
+		Timezone:       "UTC",
+		Locale:         "en",
 	}
 
 	return
@@ -78,6 +86,9 @@ func requireBodyMatchUser(t *testing.T, body *bytes.Buffer, user store.User) {
 
 	gotUser := response.Data
 	require.Equal(t, user.Username, gotUser.Username)
+	require.Equal(t, user.DisplayName, gotUser.DisplayName)
+	require.Equal(t, user.Timezone, gotUser.Timezone)
+	require.Equal(t, user.Locale, gotUser.Locale)
 	require.Empty(t, gotUser.HashedPassword)
 }
 
@@ -181,6 +192,7 @@ func TestCreateUserHandler(t *testing.T) {
 
 			storage := mockdb.NewMockStorage(ctrl)
 			tc.buildStubs(storage)
+			stubAuditEvents(storage)
 
 			server, err := NewServer(storage)
 			require.NoError(t, err)
@@ -200,6 +212,13 @@ func TestCreateUserHandler(t *testing.T) {
 	}
 }
 
+func stubLoginNotLocked(storage *mockdb.MockStorage) {
+	storage.EXPECT().
+		GetLoginLock(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(time.Time{}, store.ErrRecordNotFound)
+}
+
 func TestLoginUserHandler(t *testing.T) {
 	user, password := randomUser(t)
 
@@ -223,11 +242,259 @@ func TestLoginUserHandler(t *testing.T) {
 					GetUser(gomock.Any(), gomock.Eq(arg.Username)).
 					Times(1).
 					Return(user, nil)
+				storage.EXPECT().
+					ClearLoginAttempts(gomock.Any(), gomock.Eq(store.ClearLoginAttemptsParams{
+						Scope: loginScopeUsername,
+						Key:   user.Username,
+					})).
+					Times(1)
+				storage.EXPECT().
+					UpdateUserPassword(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "UseCookies",
+			body: gin.H{
+				"username":    user.Username,
+				"password":    password,
+				"use_cookies": true,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					ClearLoginAttempts(gomock.Any(), gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp struct {
+					Data map[string]any `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.NotContains(t, rsp.Data, "access_token")
+				require.NotContains(t, rsp.Data, "refresh_token")
+
+				for _, name := range []string{accessTokenCookieName, refreshTokenCookieName} {
+					cookie := findCookie(recorder, name)
+					require.NotNil(t, cookie)
+					require.NotEmpty(t, cookie.Value)
+					require.True(t, cookie.HttpOnly)
+					require.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+				}
+
+				csrfCookie := findCookie(recorder, csrfCookieName)
+				require.NotNil(t, csrfCookie)
+				require.False(t, csrfCookie.HttpOnly)
+				require.Equal(t, csrfCookie.Value, rsp.Data["csrf_token"])
+			},
+		},
+		{
+			name: "RehashBcryptPassword",
+			body: gin.H{
+				"username": user.Username,
+				"password": password,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+				require.NoError(t, err)
+
+				bcryptUser := user
+				bcryptUser.HashedPassword = string(hashedPassword)
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(bcryptUser, nil)
+				storage.EXPECT().
+					ClearLoginAttempts(gomock.Any(), gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					UpdateUserPassword(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.UpdateUserPasswordParams) (store.User, error) {
+						require.Equal(t, user.ID, arg.ID)
+						require.False(t, util.PasswordNeedsRehash(arg.HashedPassword))
+						require.NoError(t, util.CheckPassword(password, arg.HashedPassword))
+						return user, nil
+					})
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name: "RehashErrorDoesNotFailLogin",
+			body: gin.H{
+				"username": user.Username,
+				"password": password,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+				require.NoError(t, err)
+
+				bcryptUser := user
+				bcryptUser.HashedPassword = string(hashedPassword)
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(bcryptUser, nil)
+				storage.EXPECT().
+					ClearLoginAttempts(gomock.Any(), gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					UpdateUserPassword(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.User{}, sql.ErrConnDone)
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			// The username counter is only cleared once the MFA code is
+			// accepted, so codes cannot be guessed between password logins.
+			name: "MFARequired",
+			body: gin.H{
+				"username": user.Username,
+				"password": password,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					ClearLoginAttempts(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(randomTOTPCredential(t, user.ID, true), nil)
+				storage.EXPECT().
+					CreateMFAChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.CreateMFAChallengeParams) (store.MfaChallenge, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.WithinDuration(t, time.Now().Add(mfaChallengeDuration), arg.ExpireAt, time.Second)
+						return store.MfaChallenge{
+							ID:       arg.ID,
+							UserID:   arg.UserID,
+							ExpireAt: arg.ExpireAt,
+						}, nil
+					})
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp struct {
+					Data map[string]any `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, true, rsp.Data["mfa_required"])
+				require.NotEmpty(t, rsp.Data["mfa_token"])
+				require.NotContains(t, rsp.Data, "access_token")
+			},
+		},
+		{
+			name: "PendingTOTPDoesNotRequireMFA",
+			body: gin.H{
+				"username": user.Username,
+				"password": password,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					ClearLoginAttempts(gomock.Any(), gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(randomTOTPCredential(t, user.ID, false), nil)
+				storage.EXPECT().
+					CreateMFAChallenge(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "GetTOTPCredentialError",
+			body: gin.H{
+				"username": user.Username,
+				"password": password,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					ClearLoginAttempts(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					GetTOTPCredential(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.TotpCredential{}, sql.ErrConnDone)
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "CreateSessionError",
+			body: gin.H{
+				"username": user.Username,
+				"password": password,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					ClearLoginAttempts(gomock.Any(), gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(store.Session{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
 		{
 			name: "UserNotFound",
 			body: gin.H{
@@ -239,6 +506,10 @@ func TestLoginUserHandler(t *testing.T) {
 					GetUser(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(store.User{}, store.ErrRecordNotFound)
+				storage.EXPECT().
+					RecordLoginFailure(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(int32(1), nil)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -254,11 +525,118 @@ func TestLoginUserHandler(t *testing.T) {
 					GetUser(gomock.Any(), gomock.Eq(user.Username)).
 					Times(1).
 					Return(user, nil)
+				storage.EXPECT().
+					RecordLoginFailure(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(int32(1), nil)
+				storage.EXPECT().
+					LockLoginAttempts(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					ClearLoginAttempts(gomock.Any(), gomock.Any()).
+					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
+		{
+			name: "IncorrectPasswordLocksUsername",
+			body: gin.H{
+				"username": user.Username,
+				"password": "incorrect",
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					RecordLoginFailure(gomock.Any(), gomock.Any()).
+					Times(2).
+					DoAndReturn(func(_ any, arg store.RecordLoginFailureParams) (int32, error) {
+						if arg.Scope == loginScopeUsername {
+							require.Equal(t, user.Username, arg.Key)
+							return loginUsernameLockoutThreshold, nil
+						}
+						return 1, nil
+					})
+				storage.EXPECT().
+					LockLoginAttempts(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg store.LockLoginAttemptsParams) error {
+						require.Equal(t, loginScopeUsername, arg.Scope)
+						require.Equal(t, user.Username, arg.Key)
+						require.WithinDuration(t, time.Now().Add(loginLockoutDuration), arg.LockedUntil, time.Second)
+						return nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "RecordFailureError",
+			body: gin.H{
+				"username": user.Username,
+				"password": "incorrect",
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				storage.EXPECT().
+					RecordLoginFailure(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int32(0), sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "Locked",
+			body: gin.H{
+				"username": user.Username,
+				"password": password,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetLoginLock(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(time.Now().Add(90*time.Second), nil)
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+
+				retryAfter, err := strconv.Atoi(recorder.Header().Get("Retry-After"))
+				require.NoError(t, err)
+				require.InDelta(t, 90, retryAfter, 1)
+			},
+		},
+		{
+			name: "LockCheckError",
+			body: gin.H{
+				"username": user.Username,
+				"password": password,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetLoginLock(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(time.Time{}, sql.ErrConnDone)
+				storage.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
 		{
 			name: "InternalError",
 			body: gin.H{
@@ -314,6 +692,9 @@ func TestLoginUserHandler(t *testing.T) {
 
 			storage := mockdb.NewMockStorage(ctrl)
 			tc.buildStubs(storage)
+			stubAuditEvents(storage)
+			stubLoginNotLocked(storage)
+			stubTOTPNotEnabled(storage)
 
 			server, err := NewServer(storage)
 			require.NoError(t, err)
@@ -332,3 +713,252 @@ func TestLoginUserHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestLogoutUserHandler(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		buildBody     func(t *testing.T, tokenMaker token.Maker) gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildBody: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				return gin.H{}
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					BlockSession(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					RevokeToken(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "WithRefreshToken",
+			buildBody: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				refreshToken, _, err := tokenMaker.CreateToken(user.ID, user.Username, user.Role, token.TokenTypeRefresh, time.Hour)
+				require.NoError(t, err)
+				return gin.H{"refresh_token": refreshToken}
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					BlockSession(gomock.Any(), gomock.Any()).
+					Times(1)
+				storage.EXPECT().
+					RevokeToken(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "AccessTokenAsRefreshToken",
+			buildBody: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				accessToken, _, err := tokenMaker.CreateToken(user.ID, user.Username, user.Role, token.TokenTypeAccess, time.Hour)
+				require.NoError(t, err)
+				return gin.H{"refresh_token": accessToken}
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					BlockSession(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					RevokeToken(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "RefreshTokenOfAnotherUser",
+			buildBody: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				refreshToken, _, err := tokenMaker.CreateToken(user.ID-1, user.Username, user.Role, token.TokenTypeRefresh, time.Hour)
+				require.NoError(t, err)
+				return gin.H{"refresh_token": refreshToken}
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					BlockSession(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					RevokeToken(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			buildBody: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				return gin.H{}
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					RevokeToken(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildBody: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				return gin.H{}
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					RevokeToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+			stubAuditEvents(storage)
+			stubTokenNotRevoked(storage)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.buildBody(t, server.tokenMaker))
+			require.NoError(t, err)
+
+			url := "/users/logout"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestLogoutAllUserHandler(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(storage *mockdb.MockStorage)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					BlockUserSessions(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1)
+				storage.EXPECT().
+					RevokeUserTokens(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					BlockUserSessions(gomock.Any(), gomock.Any()).
+					Times(0)
+				storage.EXPECT().
+					RevokeUserTokens(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					BlockUserSessions(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+				storage.EXPECT().
+					RevokeUserTokens(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+			stubAuditEvents(storage)
+			stubTokenNotRevoked(storage)
+
+			server, err := NewServer(storage)
+			require.NoError(t, err)
+			server.RegisterRoutes()
+			recorder := httptest.NewRecorder()
+
+			url := "/users/logout_all"
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,503 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+)
+
+// defaultInvitationDuration is how long an invitation link can be used when
+// the request does not say otherwise.
+const defaultInvitationDuration = 7 * 24 * time.Hour
+
+var (
+	errInvalidWorkspaceID      = errors.New("workspace id must be a positive integer")
+	errWorkspaceNotFound       = errors.New("workspace not found")
+	errEmptyWorkspaceName      = errors.New("workspace name must not be empty")
+	errWorkspaceMemberNotFound = errors.New("user is not a member of the workspace")
+	errWorkspaceMemberRole     = errors.New("your workspace role does not allow managing this member")
+	errWorkspaceOwnerLeaves    = errors.New("the owner cannot leave the workspace; delete it instead")
+	errAlreadyWorkspaceMember  = errors.New("you are already a member of the workspace")
+	errInvitationNotFound      = errors.New("invitation not found or expired")
+	errTaskNotInWorkspace      = errors.New("task not found in this workspace")
+	errWorkspaceTaskProject    = errors.New("projects are personal and cannot hold tasks of a workspace")
+)
+
+// canManageWorkspaceMember reports whether a member with the role actor may
+// change the role of, or remove, a member with the role target. Owners manage
+// everyone else and admins manage plain members.
+func canManageWorkspaceMember(actor string, target string) bool {
+	switch actor {
+	case util.WorkspaceRoleOwner:
+		return target != util.WorkspaceRoleOwner
+	case util.WorkspaceRoleAdmin:
+		return target == util.WorkspaceRoleMember
+	default:
+		return false
+	}
+}
+
+// workspaceResponse is a workspace together with the role of the
+// authenticated user in it.
+type workspaceResponse struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type createWorkspaceRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+func (s *Server) createWorkspaceHandler(ctx *gin.Context) {
+	var req createWorkspaceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if len(name) == 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errEmptyWorkspaceName))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	workspace, err := s.storage.CreateWorkspace(ctx, store.CreateWorkspaceParams{
+		Name:    name,
+		OwnerID: authPayload.UserID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, successResponse(workspaceResponse{
+		ID:        workspace.ID,
+		Name:      workspace.Name,
+		Role:      util.WorkspaceRoleOwner,
+		CreatedAt: workspace.CreatedAt,
+	}))
+}
+
+func (s *Server) listWorkspacesHandler(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	workspaces, err := s.storage.ListUserWorkspaces(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]workspaceResponse, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		rsp = append(rsp, workspaceResponse{
+			ID:        workspace.ID,
+			Name:      workspace.Name,
+			Role:      workspace.Role,
+			CreatedAt: workspace.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp))
+}
+
+func (s *Server) getWorkspaceHandler(ctx *gin.Context) {
+	member := ctx.MustGet(workspaceMemberKey).(store.WorkspaceMember)
+
+	workspace, err := s.storage.GetWorkspace(ctx, member.WorkspaceID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(workspaceResponse{
+		ID:        workspace.ID,
+		Name:      workspace.Name,
+		Role:      member.Role,
+		CreatedAt: workspace.CreatedAt,
+	}))
+}
+
+type updateWorkspaceRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+func (s *Server) updateWorkspaceHandler(ctx *gin.Context) {
+	var req updateWorkspaceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if len(name) == 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errEmptyWorkspaceName))
+		return
+	}
+
+	member := ctx.MustGet(workspaceMemberKey).(store.WorkspaceMember)
+	workspace, err := s.storage.UpdateWorkspace(ctx, store.UpdateWorkspaceParams{
+		ID:   member.WorkspaceID,
+		Name: name,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(workspaceResponse{
+		ID:        workspace.ID,
+		Name:      workspace.Name,
+		Role:      member.Role,
+		CreatedAt: workspace.CreatedAt,
+	}))
+}
+
+// deleteWorkspaceHandler deletes a workspace together with its tasks.
+func (s *Server) deleteWorkspaceHandler(ctx *gin.Context) {
+	member := ctx.MustGet(workspaceMemberKey).(store.WorkspaceMember)
+
+	err := s.storage.DeleteWorkspace(ctx, member.WorkspaceID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(nil))
+}
+
+type workspaceMemberResponse struct {
+	UserID    int64     `json:"user_id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s *Server) listWorkspaceMembersHandler(ctx *gin.Context) {
+	member := ctx.MustGet(workspaceMemberKey).(store.WorkspaceMember)
+
+	members, err := s.storage.ListWorkspaceMembers(ctx, member.WorkspaceID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]workspaceMemberResponse, 0, len(members))
+	for _, m := range members {
+		rsp = append(rsp, workspaceMemberResponse{
+			UserID:    m.UserID,
+			Username:  m.Username,
+			Role:      m.Role,
+			CreatedAt: m.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp))
+}
+
+type workspaceMemberURIRequest struct {
+	UserID int64 `uri:"user_id" binding:"required,min=1"`
+}
+
+// getManagedWorkspaceMember loads a member of the workspace of the request
+// that the authenticated member may manage. It reports whether the request
+// may proceed.
+func (s *Server) getManagedWorkspaceMember(ctx *gin.Context, userID int64) (store.WorkspaceMember, bool) {
+	actor := ctx.MustGet(workspaceMemberKey).(store.WorkspaceMember)
+
+	target, err := s.storage.GetWorkspaceMember(ctx, store.GetWorkspaceMemberParams{
+		WorkspaceID: actor.WorkspaceID,
+		UserID:      userID,
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errWorkspaceMemberNotFound))
+			return store.WorkspaceMember{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return store.WorkspaceMember{}, false
+	}
+
+	if !canManageWorkspaceMember(actor.Role, target.Role) {
+		ctx.JSON(http.StatusForbidden, errorResponse(errWorkspaceMemberRole))
+		return store.WorkspaceMember{}, false
+	}
+
+	return target, true
+}
+
+type updateWorkspaceMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin member"`
+}
+
+func (s *Server) updateWorkspaceMemberHandler(ctx *gin.Context) {
+	var uri workspaceMemberURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateWorkspaceMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	target, ok := s.getManagedWorkspaceMember(ctx, uri.UserID)
+	if !ok {
+		return
+	}
+
+	member, err := s.storage.UpdateWorkspaceMemberRole(ctx, store.UpdateWorkspaceMemberRoleParams{
+		WorkspaceID: target.WorkspaceID,
+		UserID:      target.UserID,
+		Role:        req.Role,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(member))
+}
+
+// removeWorkspaceMemberHandler removes a member from a workspace. Members may
+// always leave, except for the owner; the tasks they created stay in the
+// workspace.
+func (s *Server) removeWorkspaceMemberHandler(ctx *gin.Context) {
+	var uri workspaceMemberURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	actor := ctx.MustGet(workspaceMemberKey).(store.WorkspaceMember)
+	if uri.UserID == actor.UserID {
+		if actor.Role == util.WorkspaceRoleOwner {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errWorkspaceOwnerLeaves))
+			return
+		}
+	} else if _, ok := s.getManagedWorkspaceMember(ctx, uri.UserID); !ok {
+		return
+	}
+
+	n, err := s.storage.RemoveWorkspaceMember(ctx, store.RemoveWorkspaceMemberParams{
+		WorkspaceID: actor.WorkspaceID,
+		UserID:      uri.UserID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if n == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(errWorkspaceMemberNotFound))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(nil))
+}
+
+type createWorkspaceInvitationRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin member"`
+	// ExpiresInHours defaults to a week and may be up to 30 days.
+	ExpiresInHours int `json:"expires_in_hours" binding:"omitempty,min=1,max=720"`
+}
+
+type workspaceInvitationResponse struct {
+	ID          uuid.UUID `json:"id"`
+	WorkspaceID int64     `json:"workspace_id"`
+	Role        string    `json:"role"`
+	CreatedBy   int64     `json:"created_by"`
+	ExpireAt    time.Time `json:"expire_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type createWorkspaceInvitationResponse struct {
+	workspaceInvitationResponse
+	Token string `json:"token"`
+}
+
+func newWorkspaceInvitationResponse(invitation store.WorkspaceInvitation) workspaceInvitationResponse {
+	return workspaceInvitationResponse{
+		ID:          invitation.ID,
+		WorkspaceID: invitation.WorkspaceID,
+		Role:        invitation.Role,
+		CreatedBy:   invitation.CreatedBy,
+		ExpireAt:    invitation.ExpireAt,
+		CreatedAt:   invitation.CreatedAt,
+	}
+}
+
+// createWorkspaceInvitationHandler creates an invitation link. Its token is
+// only returned here, and lets anyone who holds it join the workspace until
+// the invitation expires or is revoked.
+func (s *Server) createWorkspaceInvitationHandler(ctx *gin.Context) {
+	var req createWorkspaceInvitationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	invitationToken, hash, err := token.NewWorkspaceInvitationToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	duration := defaultInvitationDuration
+	if req.ExpiresInHours > 0 {
+		duration = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+
+	member := ctx.MustGet(workspaceMemberKey).(store.WorkspaceMember)
+	invitation, err := s.storage.CreateWorkspaceInvitation(ctx, store.CreateWorkspaceInvitationParams{
+		ID:          id,
+		WorkspaceID: member.WorkspaceID,
+		TokenHash:   hash,
+		Role:        req.Role,
+		CreatedBy:   member.UserID,
+		ExpireAt:    time.Now().Add(duration),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, successResponse(createWorkspaceInvitationResponse{
+		workspaceInvitationResponse: newWorkspaceInvitationResponse(invitation),
+		Token:                       invitationToken,
+	}))
+}
+
+func (s *Server) listWorkspaceInvitationsHandler(ctx *gin.Context) {
+	member := ctx.MustGet(workspaceMemberKey).(store.WorkspaceMember)
+
+	invitations, err := s.storage.ListWorkspaceInvitations(ctx, member.WorkspaceID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]workspaceInvitationResponse, 0, len(invitations))
+	for _, invitation := range invitations {
+		rsp = append(rsp, newWorkspaceInvitationResponse(invitation))
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp))
+}
+
+type revokeWorkspaceInvitationRequest struct {
+	ID string `uri:"invitation_id" binding:"required,uuid"`
+}
+
+func (s *Server) revokeWorkspaceInvitationHandler(ctx *gin.Context) {
+	var req revokeWorkspaceInvitationRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	member := ctx.MustGet(workspaceMemberKey).(store.WorkspaceMember)
+	n, err := s.storage.DeleteWorkspaceInvitation(ctx, store.DeleteWorkspaceInvitationParams{
+		ID:          uuid.MustParse(req.ID),
+		WorkspaceID: member.WorkspaceID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if n == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(errInvitationNotFound))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(nil))
+}
+
+type acceptWorkspaceInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// acceptWorkspaceInvitationHandler makes the authenticated user a member of
+// the workspace an invitation is for, with the role of the invitation.
+func (s *Server) acceptWorkspaceInvitationHandler(ctx *gin.Context) {
+	var req acceptWorkspaceInvitationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	invitation, err := s.storage.GetWorkspaceInvitationByHash(ctx, token.HashWorkspaceInvitationToken(req.Token))
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errInvitationNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// An invitation is only as good as the role of whoever created it.
+	creator, err := s.storage.GetWorkspaceMember(ctx, store.GetWorkspaceMemberParams{
+		WorkspaceID: invitation.WorkspaceID,
+		UserID:      invitation.CreatedBy,
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errInvitationNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if creator.Role == util.WorkspaceRoleMember {
+		ctx.JSON(http.StatusNotFound, errorResponse(errInvitationNotFound))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	member, err := s.storage.AddWorkspaceMember(ctx, store.AddWorkspaceMemberParams{
+		WorkspaceID: invitation.WorkspaceID,
+		UserID:      authPayload.UserID,
+		Role:        invitation.Role,
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			ctx.JSON(http.StatusConflict, errorResponse(errAlreadyWorkspaceMember))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	workspace, err := s.storage.GetWorkspace(ctx, invitation.WorkspaceID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(workspaceResponse{
+		ID:        workspace.ID,
+		Name:      workspace.Name,
+		Role:      member.Role,
+		CreatedAt: workspace.CreatedAt,
+	}))
+}
@@ -0,0 +1,897 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/nguyen-duc-loc/task-management/backend/internal/database/mock"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/store"
+	"github.com/nguyen-duc-loc/task-management/backend/internal/token"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func randomWorkspace() store.Workspace {
+	return store.Workspace{
+		ID:        rand.Int64N(1000) + 1,
+		Name:      util.RandomAlphabetString(10),
+		CreatedAt: time.Now(),
+	}
+}
+
+func randomWorkspaceTask(t *testing.T, creatorID int64, workspaceID int64) store.Task {
+	task := randomTask(t, creatorID)
+	task.WorkspaceID = pgtype.Int8{
+		Int64: workspaceID,
+		Valid: true,
+	}
+	return task
+}
+
+// stubWorkspaceMember stubs the membership lookup of workspaceMiddleware. An
+// empty role makes the user a non-member.
+func stubWorkspaceMember(storage *mockdb.MockStorage, workspaceID int64, userID int64, role string) {
+	call := storage.EXPECT().
+		GetWorkspaceMember(gomock.Any(), gomock.Eq(store.GetWorkspaceMemberParams{
+			WorkspaceID: workspaceID,
+			UserID:      userID,
+		})).
+		Times(1)
+	if len(role) == 0 {
+		call.Return(store.WorkspaceMember{}, store.ErrRecordNotFound)
+		return
+	}
+	call.Return(store.WorkspaceMember{
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		Role:        role,
+		CreatedAt:   time.Now(),
+	}, nil)
+}
+
+func TestCreateWorkspaceHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	workspace := randomWorkspace()
+
+	testCases := []struct {
+		name       string
+		body       gin.H
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name: "OK",
+			body: gin.H{"name": " " + workspace.Name + "  "},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateWorkspace(gomock.Any(), gomock.Eq(store.CreateWorkspaceParams{
+						Name:    workspace.Name,
+						OwnerID: user.ID,
+					})).
+					Times(1).
+					Return(store.CreateWorkspaceRow(workspace), nil)
+			},
+			expectCode: http.StatusCreated,
+		},
+		{
+			name: "EmptyName",
+			body: gin.H{"name": "   "},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					CreateWorkspace(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPost, "/workspaces", tc.body)
+			require.Equal(t, tc.expectCode, recorder.Code)
+			if tc.expectCode != http.StatusCreated {
+				return
+			}
+
+			var rsp struct {
+				Data workspaceResponse `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+			require.Equal(t, workspace.ID, rsp.Data.ID)
+			require.Equal(t, util.WorkspaceRoleOwner, rsp.Data.Role)
+		})
+	}
+}
+
+func TestWorkspaceMiddleware(t *testing.T) {
+	user, _ := randomUser(t)
+	workspace := randomWorkspace()
+	url := fmt.Sprintf("/workspaces/%d", workspace.ID)
+
+	testCases := []struct {
+		name       string
+		url        string
+		header     http.Header
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name: "Member",
+			url:  url,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubWorkspaceMember(storage, workspace.ID, user.ID, util.WorkspaceRoleMember)
+				storage.EXPECT().
+					GetWorkspace(gomock.Any(), gomock.Eq(workspace.ID)).
+					Times(1).
+					Return(workspace, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name: "NotMember",
+			url:  url,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubWorkspaceMember(storage, workspace.ID, user.ID, "")
+				storage.EXPECT().
+					GetWorkspace(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusNotFound,
+		},
+		{
+			name: "InvalidPathID",
+			url:  "/workspaces/abc",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetWorkspaceMember(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:   "InvalidHeaderID",
+			url:    "/tasks",
+			header: http.Header{workspaceHeaderKey: {"0"}},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTasks(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:   "HeaderNotMember",
+			url:    "/tasks",
+			header: http.Header{workspaceHeaderKey: {strconv.FormatInt(workspace.ID, 10)}},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubWorkspaceMember(storage, workspace.ID, user.ID, "")
+				storage.EXPECT().
+					GetTasks(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			recorder := serveAuthenticatedRequestWithHeader(t, storage, user, http.MethodGet, tc.url, nil, tc.header)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestWorkspaceRoles(t *testing.T) {
+	user, _ := randomUser(t)
+	workspace := randomWorkspace()
+	url := fmt.Sprintf("/workspaces/%d", workspace.ID)
+
+	testCases := []struct {
+		name       string
+		method     string
+		body       gin.H
+		role       string
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name:   "AdminRenames",
+			method: http.MethodPatch,
+			body:   gin.H{"name": "Platform"},
+			role:   util.WorkspaceRoleAdmin,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpdateWorkspace(gomock.Any(), gomock.Eq(store.UpdateWorkspaceParams{
+						ID:   workspace.ID,
+						Name: "Platform",
+					})).
+					Times(1).
+					Return(workspace, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:   "MemberRenames",
+			method: http.MethodPatch,
+			body:   gin.H{"name": "Platform"},
+			role:   util.WorkspaceRoleMember,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					UpdateWorkspace(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:   "OwnerDeletes",
+			method: http.MethodDelete,
+			role:   util.WorkspaceRoleOwner,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					DeleteWorkspace(gomock.Any(), gomock.Eq(workspace.ID)).
+					Times(1).
+					Return(nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:   "AdminDeletes",
+			method: http.MethodDelete,
+			role:   util.WorkspaceRoleAdmin,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					DeleteWorkspace(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			stubWorkspaceMember(storage, workspace.ID, user.ID, tc.role)
+			tc.buildStubs(storage)
+
+			recorder := serveAuthenticatedRequest(t, storage, user, tc.method, url, tc.body)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestUpdateWorkspaceMemberHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+	workspace := randomWorkspace()
+	url := fmt.Sprintf("/workspaces/%d/members/%d", workspace.ID, other.ID)
+
+	testCases := []struct {
+		name       string
+		role       string
+		otherRole  string
+		body       gin.H
+		expectCode int
+	}{
+		{
+			name:       "AdminPromotesMember",
+			role:       util.WorkspaceRoleAdmin,
+			otherRole:  util.WorkspaceRoleMember,
+			body:       gin.H{"role": util.WorkspaceRoleAdmin},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "OwnerDemotesAdmin",
+			role:       util.WorkspaceRoleOwner,
+			otherRole:  util.WorkspaceRoleAdmin,
+			body:       gin.H{"role": util.WorkspaceRoleMember},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "AdminDemotesAdmin",
+			role:       util.WorkspaceRoleAdmin,
+			otherRole:  util.WorkspaceRoleAdmin,
+			body:       gin.H{"role": util.WorkspaceRoleMember},
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:       "AdminDemotesOwner",
+			role:       util.WorkspaceRoleAdmin,
+			otherRole:  util.WorkspaceRoleOwner,
+			body:       gin.H{"role": util.WorkspaceRoleMember},
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:       "NotMember",
+			role:       util.WorkspaceRoleOwner,
+			body:       gin.H{"role": util.WorkspaceRoleMember},
+			expectCode: http.StatusNotFound,
+		},
+		{
+			name:       "OwnerRole",
+			role:       util.WorkspaceRoleOwner,
+			body:       gin.H{"role": util.WorkspaceRoleOwner},
+			expectCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			stubWorkspaceMember(storage, workspace.ID, user.ID, tc.role)
+			if tc.expectCode != http.StatusBadRequest {
+				stubWorkspaceMember(storage, workspace.ID, other.ID, tc.otherRole)
+			}
+
+			updates := 0
+			if tc.expectCode == http.StatusOK {
+				updates = 1
+			}
+			storage.EXPECT().
+				UpdateWorkspaceMemberRole(gomock.Any(), gomock.Eq(store.UpdateWorkspaceMemberRoleParams{
+					WorkspaceID: workspace.ID,
+					UserID:      other.ID,
+					Role:        fmt.Sprint(tc.body["role"]),
+				})).
+				Times(updates).
+				Return(store.WorkspaceMember{}, nil)
+
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPatch, url, tc.body)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestRemoveWorkspaceMemberHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+	workspace := randomWorkspace()
+
+	testCases := []struct {
+		name       string
+		role       string
+		target     int64
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name:   "MemberLeaves",
+			role:   util.WorkspaceRoleMember,
+			target: user.ID,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					RemoveWorkspaceMember(gomock.Any(), gomock.Eq(store.RemoveWorkspaceMemberParams{
+						WorkspaceID: workspace.ID,
+						UserID:      user.ID,
+					})).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:   "OwnerLeaves",
+			role:   util.WorkspaceRoleOwner,
+			target: user.ID,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					RemoveWorkspaceMember(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:   "AdminRemovesMember",
+			role:   util.WorkspaceRoleAdmin,
+			target: other.ID,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubWorkspaceMember(storage, workspace.ID, other.ID, util.WorkspaceRoleMember)
+				storage.EXPECT().
+					RemoveWorkspaceMember(gomock.Any(), gomock.Eq(store.RemoveWorkspaceMemberParams{
+						WorkspaceID: workspace.ID,
+						UserID:      other.ID,
+					})).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:   "MemberRemovesMember",
+			role:   util.WorkspaceRoleMember,
+			target: other.ID,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubWorkspaceMember(storage, workspace.ID, other.ID, util.WorkspaceRoleMember)
+				storage.EXPECT().
+					RemoveWorkspaceMember(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			stubWorkspaceMember(storage, workspace.ID, user.ID, tc.role)
+			tc.buildStubs(storage)
+
+			url := fmt.Sprintf("/workspaces/%d/members/%d", workspace.ID, tc.target)
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodDelete, url, nil)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestCreateWorkspaceInvitationHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	workspace := randomWorkspace()
+	url := fmt.Sprintf("/workspaces/%d/invitations", workspace.ID)
+
+	testCases := []struct {
+		name       string
+		role       string
+		body       gin.H
+		expireIn   time.Duration
+		expectCode int
+	}{
+		{
+			name:       "DefaultExpiry",
+			role:       util.WorkspaceRoleAdmin,
+			body:       gin.H{"role": util.WorkspaceRoleMember},
+			expireIn:   defaultInvitationDuration,
+			expectCode: http.StatusCreated,
+		},
+		{
+			name:       "CustomExpiry",
+			role:       util.WorkspaceRoleOwner,
+			body:       gin.H{"role": util.WorkspaceRoleAdmin, "expires_in_hours": 2},
+			expireIn:   2 * time.Hour,
+			expectCode: http.StatusCreated,
+		},
+		{
+			name:       "OwnerRole",
+			role:       util.WorkspaceRoleOwner,
+			body:       gin.H{"role": util.WorkspaceRoleOwner},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:       "ExpiryTooLong",
+			role:       util.WorkspaceRoleOwner,
+			body:       gin.H{"role": util.WorkspaceRoleMember, "expires_in_hours": 721},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:       "MemberInvites",
+			role:       util.WorkspaceRoleMember,
+			body:       gin.H{"role": util.WorkspaceRoleMember},
+			expectCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			stubWorkspaceMember(storage, workspace.ID, user.ID, tc.role)
+
+			var stored store.CreateWorkspaceInvitationParams
+			invitations := 0
+			if tc.expectCode == http.StatusCreated {
+				invitations = 1
+			}
+			storage.EXPECT().
+				CreateWorkspaceInvitation(gomock.Any(), gomock.Any()).
+				Times(invitations).
+				DoAndReturn(func(_ any, arg store.CreateWorkspaceInvitationParams) (store.WorkspaceInvitation, error) {
+					stored = arg
+					return store.WorkspaceInvitation{
+						ID:          arg.ID,
+						WorkspaceID: arg.WorkspaceID,
+						TokenHash:   arg.TokenHash,
+						Role:        arg.Role,
+						CreatedBy:   arg.CreatedBy,
+						ExpireAt:    arg.ExpireAt,
+						CreatedAt:   time.Now(),
+					}, nil
+				})
+
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPost, url, tc.body)
+			require.Equal(t, tc.expectCode, recorder.Code)
+			if tc.expectCode != http.StatusCreated {
+				return
+			}
+
+			var rsp struct {
+				Data createWorkspaceInvitationResponse `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+			require.True(t, strings.HasPrefix(rsp.Data.Token, "tmwi_"))
+			require.Equal(t, token.HashWorkspaceInvitationToken(rsp.Data.Token), stored.TokenHash)
+			require.Equal(t, workspace.ID, stored.WorkspaceID)
+			require.Equal(t, user.ID, stored.CreatedBy)
+			require.Equal(t, tc.body["role"], stored.Role)
+			require.WithinDuration(t, time.Now().Add(tc.expireIn), stored.ExpireAt, time.Minute)
+		})
+	}
+}
+
+func TestRevokeWorkspaceInvitationHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	workspace := randomWorkspace()
+	id := uuid.New()
+	url := fmt.Sprintf("/workspaces/%d/invitations/%s", workspace.ID, id)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mockdb.NewMockStorage(ctrl)
+	stubWorkspaceMember(storage, workspace.ID, user.ID, util.WorkspaceRoleAdmin)
+	storage.EXPECT().
+		DeleteWorkspaceInvitation(gomock.Any(), gomock.Eq(store.DeleteWorkspaceInvitationParams{
+			ID:          id,
+			WorkspaceID: workspace.ID,
+		})).
+		Times(1).
+		Return(int64(0), nil)
+
+	recorder := serveAuthenticatedRequest(t, storage, user, http.MethodDelete, url, nil)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestAcceptWorkspaceInvitationHandler(t *testing.T) {
+	user, _ := randomUser(t)
+	creator, _ := randomUser(t)
+	workspace := randomWorkspace()
+	invitationToken, hash, err := token.NewWorkspaceInvitationToken()
+	require.NoError(t, err)
+
+	invitation := store.WorkspaceInvitation{
+		ID:          uuid.New(),
+		WorkspaceID: workspace.ID,
+		TokenHash:   hash,
+		Role:        util.WorkspaceRoleAdmin,
+		CreatedBy:   creator.ID,
+		ExpireAt:    time.Now().Add(time.Hour),
+		CreatedAt:   time.Now(),
+	}
+	member := store.AddWorkspaceMemberParams{
+		WorkspaceID: workspace.ID,
+		UserID:      user.ID,
+		Role:        util.WorkspaceRoleAdmin,
+	}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name: "OK",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetWorkspaceInvitationByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(invitation, nil)
+				stubWorkspaceMember(storage, workspace.ID, creator.ID, util.WorkspaceRoleOwner)
+				storage.EXPECT().
+					AddWorkspaceMember(gomock.Any(), gomock.Eq(member)).
+					Times(1).
+					Return(store.WorkspaceMember{
+						WorkspaceID: member.WorkspaceID,
+						UserID:      member.UserID,
+						Role:        member.Role,
+						CreatedAt:   time.Now(),
+					}, nil)
+				storage.EXPECT().
+					GetWorkspace(gomock.Any(), gomock.Eq(workspace.ID)).
+					Times(1).
+					Return(workspace, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name: "ExpiredOrUnknown",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetWorkspaceInvitationByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(store.WorkspaceInvitation{}, store.ErrRecordNotFound)
+				storage.EXPECT().
+					AddWorkspaceMember(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusNotFound,
+		},
+		{
+			name: "AlreadyMember",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetWorkspaceInvitationByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(invitation, nil)
+				stubWorkspaceMember(storage, workspace.ID, creator.ID, util.WorkspaceRoleOwner)
+				storage.EXPECT().
+					AddWorkspaceMember(gomock.Any(), gomock.Eq(member)).
+					Times(1).
+					Return(store.WorkspaceMember{}, store.ErrRecordNotFound)
+			},
+			expectCode: http.StatusConflict,
+		},
+		{
+			name: "CreatorDemoted",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetWorkspaceInvitationByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(invitation, nil)
+				stubWorkspaceMember(storage, workspace.ID, creator.ID, util.WorkspaceRoleMember)
+				storage.EXPECT().
+					AddWorkspaceMember(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusNotFound,
+		},
+		{
+			name: "CreatorLeft",
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetWorkspaceInvitationByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(invitation, nil)
+				stubWorkspaceMember(storage, workspace.ID, creator.ID, "")
+				storage.EXPECT().
+					AddWorkspaceMember(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			recorder := serveAuthenticatedRequest(t, storage, user, http.MethodPost, "/invitations/accept", gin.H{"token": invitationToken})
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
+
+func TestWorkspaceTasks(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+	workspace := randomWorkspace()
+	workspaceID := pgtype.Int8{Int64: workspace.ID, Valid: true}
+	header := http.Header{workspaceHeaderKey: {strconv.FormatInt(workspace.ID, 10)}}
+	task := randomWorkspaceTask(t, other.ID, workspace.ID)
+	personalTask := randomTask(t, user.ID)
+
+	testCases := []struct {
+		name       string
+		method     string
+		url        string
+		header     http.Header
+		body       gin.H
+		buildStubs func(storage *mockdb.MockStorage)
+		expectCode int
+	}{
+		{
+			name:   "ListByHeader",
+			method: http.MethodGet,
+			url:    "/tasks",
+			header: header,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubWorkspaceMember(storage, workspace.ID, user.ID, util.WorkspaceRoleMember)
+				storage.EXPECT().
+					GetTasks(gomock.Any(), EqGetTasksParams(store.GetTasksParams{
+						UserID:      user.ID,
+						WorkspaceID: workspaceID,
+						Limit:       5,
+					})).
+					Times(1).
+					Return([]store.GetTasksRow{}, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:   "ListByPath",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/workspaces/%d/tasks?ownership=shared", workspace.ID),
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubWorkspaceMember(storage, workspace.ID, user.ID, util.WorkspaceRoleMember)
+				storage.EXPECT().
+					GetTasks(gomock.Any(), EqGetTasksParams(store.GetTasksParams{
+						UserID:      user.ID,
+						WorkspaceID: workspaceID,
+						Shared:      pgtype.Bool{Bool: true, Valid: true},
+						Limit:       5,
+					})).
+					Times(1).
+					Return([]store.GetTasksRow{}, nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:   "MemberReadsTaskOfOther",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/workspaces/%d/tasks/%s", workspace.ID, task.ID),
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubWorkspaceMember(storage, workspace.ID, user.ID, util.WorkspaceRoleMember)
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					GetTaskShare(gomock.Any(), gomock.Any()).
+					Times(0)
+				stubNoTaskTags(storage)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:   "MemberDeletesTaskOfOther",
+			method: http.MethodDelete,
+			url:    "/tasks/" + task.ID,
+			header: header,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubWorkspaceMember(storage, workspace.ID, user.ID, util.WorkspaceRoleMember)
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					DeleteTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:   "AdminDeletesTaskOfOther",
+			method: http.MethodDelete,
+			url:    "/tasks/" + task.ID,
+			header: header,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubWorkspaceMember(storage, workspace.ID, user.ID, util.WorkspaceRoleAdmin)
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					DeleteTask(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(nil)
+			},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:   "WorkspaceTaskWithoutWorkspace",
+			method: http.MethodGet,
+			url:    "/tasks/" + task.ID,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+			},
+			expectCode: http.StatusNotFound,
+		},
+		{
+			name:   "PersonalTaskInWorkspace",
+			method: http.MethodGet,
+			url:    "/tasks/" + personalTask.ID,
+			header: header,
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubWorkspaceMember(storage, workspace.ID, user.ID, util.WorkspaceRoleOwner)
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(personalTask.ID)).
+					Times(1).
+					Return(personalTask, nil)
+			},
+			expectCode: http.StatusNotFound,
+		},
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			url:    fmt.Sprintf("/workspaces/%d/tasks", workspace.ID),
+			body: gin.H{
+				"title":    task.Title,
+				"deadline": task.Deadline.Format(time.RFC3339),
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubWorkspaceMember(storage, workspace.ID, user.ID, util.WorkspaceRoleMember)
+				storage.EXPECT().
+					CreateTask(gomock.Any(), gomock.Cond(func(arg store.CreateTaskParams) bool {
+						return arg.CreatorID == user.ID && arg.WorkspaceID == workspaceID
+					})).
+					Times(1).
+					Return(task, nil)
+			},
+			expectCode: http.StatusCreated,
+		},
+		{
+			name:   "CreateInProject",
+			method: http.MethodPost,
+			url:    "/tasks",
+			header: header,
+			body: gin.H{
+				"title":      task.Title,
+				"deadline":   task.Deadline.Format(time.RFC3339),
+				"project_id": 1,
+			},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubWorkspaceMember(storage, workspace.ID, user.ID, util.WorkspaceRoleMember)
+				storage.EXPECT().
+					CreateTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:   "Share",
+			method: http.MethodPost,
+			url:    fmt.Sprintf("/workspaces/%d/tasks/%s/shares", workspace.ID, task.ID),
+			body:   gin.H{"username": other.Username, "role": util.ShareRoleViewer},
+			buildStubs: func(storage *mockdb.MockStorage) {
+				stubWorkspaceMember(storage, workspace.ID, user.ID, util.WorkspaceRoleOwner)
+				storage.EXPECT().
+					GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				storage.EXPECT().
+					ShareTask(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expectCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mockdb.NewMockStorage(ctrl)
+			tc.buildStubs(storage)
+
+			recorder := serveAuthenticatedRequestWithHeader(t, storage, user, tc.method, tc.url, tc.body, tc.header)
+			require.Equal(t, tc.expectCode, recorder.Code)
+		})
+	}
+}
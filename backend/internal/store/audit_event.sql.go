@@ -0,0 +1,116 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: audit_event.sql
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createAuditEvent = `-- name: CreateAuditEvent :one
+INSERT INTO audit_events (
+  user_id,
+  username,
+  event_type,
+  detail,
+  client_ip,
+  user_agent
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, user_id, username, event_type, detail, client_ip, user_agent, created_at
+`
+
+type CreateAuditEventParams struct {
+	UserID    pgtype.Int8 `json:"user_id"`
+	Username  string      `json:"username"`
+	EventType string      `json:"event_type"`
+	Detail    pgtype.Text `json:"detail"`
+	ClientIp  string      `json:"client_ip"`
+	UserAgent string      `json:"user_agent"`
+}
+
+func (q *Queries) CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error) {
+	row := q.db.QueryRow(ctx, createAuditEvent,
+		arg.UserID,
+		arg.Username,
+		arg.EventType,
+		arg.Detail,
+		arg.ClientIp,
+		arg.UserAgent,
+	)
+	var i AuditEvent
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Username,
+		&i.EventType,
+		&i.Detail,
+		&i.ClientIp,
+		&i.UserAgent,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listUserAuditEvents = `-- name: ListUserAuditEvents :many
+SELECT
+  id, user_id, username, event_type, detail, client_ip, user_agent, created_at,
+  COUNT(*) OVER() AS total
+FROM audit_events
+WHERE user_id = $1::bigint
+ORDER BY created_at DESC, id DESC
+LIMIT $3 OFFSET $2
+`
+
+type ListUserAuditEventsParams struct {
+	UserID int64 `json:"user_id"`
+	Offset int32 `json:"offset"`
+	Limit  int32 `json:"limit"`
+}
+
+type ListUserAuditEventsRow struct {
+	ID        int64       `json:"id"`
+	UserID    pgtype.Int8 `json:"user_id"`
+	Username  string      `json:"username"`
+	EventType string      `json:"event_type"`
+	Detail    pgtype.Text `json:"detail"`
+	ClientIp  string      `json:"client_ip"`
+	UserAgent string      `json:"user_agent"`
+	CreatedAt time.Time   `json:"created_at"`
+	Total     int64       `json:"total"`
+}
+
+func (q *Queries) ListUserAuditEvents(ctx context.Context, arg ListUserAuditEventsParams) ([]ListUserAuditEventsRow, error) {
+	rows, err := q.db.Query(ctx, listUserAuditEvents, arg.UserID, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUserAuditEventsRow{}
+	for rows.Next() {
+		var i ListUserAuditEventsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Username,
+			&i.EventType,
+			&i.Detail,
+			&i.ClientIp,
+			&i.UserAgent,
+			&i.CreatedAt,
+			&i.Total,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
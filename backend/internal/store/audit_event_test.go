@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomAuditEvent(t *testing.T, user User, eventType string) AuditEvent {
+	arg := CreateAuditEventParams{
+		UserID:    pgtype.Int8{Int64: user.ID, Valid: true},
+		Username:  user.Username,
+		EventType: eventType,
+		ClientIp:  "192.0.2.1",
+		UserAgent: "task-management-test",
+	}
+
+	event, err := testStore.CreateAuditEvent(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.UserID, event.UserID)
+	require.Equal(t, arg.EventType, event.EventType)
+	require.Equal(t, arg.ClientIp, event.ClientIp)
+	require.Equal(t, arg.UserAgent, event.UserAgent)
+	require.False(t, event.Detail.Valid)
+	require.NotZero(t, event.CreatedAt)
+	return event
+}
+
+func TestCreateAuditEventForUnknownUser(t *testing.T) {
+	event, err := testStore.CreateAuditEvent(context.Background(), CreateAuditEventParams{
+		Username:  "unknown",
+		EventType: "login_failed",
+		Detail:    pgtype.Text{String: "unknown_user", Valid: true},
+		ClientIp:  "192.0.2.1",
+		UserAgent: "task-management-test",
+	})
+	require.NoError(t, err)
+	require.False(t, event.UserID.Valid)
+	require.Equal(t, "unknown_user", event.Detail.String)
+}
+
+func TestListUserAuditEvents(t *testing.T) {
+	user := createRandomUser(t)
+	otherUser := createRandomUser(t)
+
+	event1 := createRandomAuditEvent(t, user, "signup")
+	event2 := createRandomAuditEvent(t, user, "login_succeeded")
+	createRandomAuditEvent(t, otherUser, "signup")
+
+	events, err := testStore.ListUserAuditEvents(context.Background(), ListUserAuditEventsParams{
+		UserID: user.ID,
+		Limit:  1,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, event2.ID, events[0].ID)
+	require.Equal(t, int64(2), events[0].Total)
+
+	events, err = testStore.ListUserAuditEvents(context.Background(), ListUserAuditEventsParams{
+		UserID: user.ID,
+		Limit:  1,
+		Offset: 1,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, event1.ID, events[0].ID)
+}
+
+func TestAuditEventsAreAppendOnly(t *testing.T) {
+	event := createRandomAuditEvent(t, createRandomUser(t), "signup")
+
+	connPool := testStore.(*SQLStorage).connPool
+	_, err := connPool.Exec(context.Background(), "UPDATE audit_events SET event_type = 'login_succeeded' WHERE id = $1", event.ID)
+	require.Error(t, err)
+}
@@ -0,0 +1,73 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: email_verification.sql
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const consumeEmailVerification = `-- name: ConsumeEmailVerification :one
+UPDATE email_verifications
+SET used_at = now()
+WHERE id = $1
+  AND used_at IS NULL
+  AND expire_at > now()
+RETURNING id, user_id, email, expire_at, used_at, created_at
+`
+
+func (q *Queries) ConsumeEmailVerification(ctx context.Context, id uuid.UUID) (EmailVerification, error) {
+	row := q.db.QueryRow(ctx, consumeEmailVerification, id)
+	var i EmailVerification
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Email,
+		&i.ExpireAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createEmailVerification = `-- name: CreateEmailVerification :one
+INSERT INTO email_verifications (
+  id,
+  user_id,
+  email,
+  expire_at
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, user_id, email, expire_at, used_at, created_at
+`
+
+type CreateEmailVerificationParams struct {
+	ID       uuid.UUID `json:"id"`
+	UserID   int64     `json:"user_id"`
+	Email    string    `json:"email"`
+	ExpireAt time.Time `json:"expire_at"`
+}
+
+func (q *Queries) CreateEmailVerification(ctx context.Context, arg CreateEmailVerificationParams) (EmailVerification, error) {
+	row := q.db.QueryRow(ctx, createEmailVerification,
+		arg.ID,
+		arg.UserID,
+		arg.Email,
+		arg.ExpireAt,
+	)
+	var i EmailVerification
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Email,
+		&i.ExpireAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
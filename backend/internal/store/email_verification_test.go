@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomEmailVerification(t *testing.T, expireAt time.Time) EmailVerification {
+	id, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	arg := CreateEmailVerificationParams{
+		ID:       id,
+		UserID:   createRandomUser(t).ID,
+		Email:    util.RandomAlphabetString(10) + "@example.com",
+		ExpireAt: expireAt,
+	}
+
+	verification, err := testStore.CreateEmailVerification(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.ID, verification.ID)
+	require.Equal(t, arg.UserID, verification.UserID)
+	require.Equal(t, arg.Email, verification.Email)
+	require.WithinDuration(t, arg.ExpireAt, verification.ExpireAt, time.Second)
+	require.False(t, verification.UsedAt.Valid)
+	require.NotZero(t, verification.CreatedAt)
+
+	return verification
+}
+
+func TestConsumeEmailVerification(t *testing.T) {
+	verification1 := createRandomEmailVerification(t, time.Now().Add(time.Hour))
+
+	verification2, err := testStore.ConsumeEmailVerification(context.Background(), verification1.ID)
+	require.NoError(t, err)
+	require.Equal(t, verification1.ID, verification2.ID)
+	require.True(t, verification2.UsedAt.Valid)
+
+	_, err = testStore.ConsumeEmailVerification(context.Background(), verification1.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestConsumeExpiredEmailVerification(t *testing.T) {
+	verification := createRandomEmailVerification(t, time.Now().Add(-time.Minute))
+
+	_, err := testStore.ConsumeEmailVerification(context.Background(), verification.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
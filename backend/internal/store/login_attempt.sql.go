@@ -0,0 +1,100 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: login_attempt.sql
+
+package store
+
+import (
+	"context"
+	"time"
+)
+
+const clearLoginAttempts = `-- name: ClearLoginAttempts :exec
+DELETE FROM login_attempts
+WHERE scope = $1 AND key = $2
+`
+
+type ClearLoginAttemptsParams struct {
+	Scope string `json:"scope"`
+	Key   string `json:"key"`
+}
+
+func (q *Queries) ClearLoginAttempts(ctx context.Context, arg ClearLoginAttemptsParams) error {
+	_, err := q.db.Exec(ctx, clearLoginAttempts, arg.Scope, arg.Key)
+	return err
+}
+
+const getLoginLock = `-- name: GetLoginLock :one
+SELECT locked_until::timestamptz FROM login_attempts
+WHERE (
+  (scope = 'username' AND key = $1)
+  OR (scope = 'ip' AND key = $2)
+) AND locked_until > now()
+ORDER BY locked_until DESC
+LIMIT 1
+`
+
+type GetLoginLockParams struct {
+	Username string `json:"username"`
+	ClientIp string `json:"client_ip"`
+}
+
+func (q *Queries) GetLoginLock(ctx context.Context, arg GetLoginLockParams) (time.Time, error) {
+	row := q.db.QueryRow(ctx, getLoginLock, arg.Username, arg.ClientIp)
+	var locked_until time.Time
+	err := row.Scan(&locked_until)
+	return locked_until, err
+}
+
+const lockLoginAttempts = `-- name: LockLoginAttempts :exec
+UPDATE login_attempts
+SET locked_until = $1::timestamptz
+WHERE scope = $2 AND key = $3
+`
+
+type LockLoginAttemptsParams struct {
+	LockedUntil time.Time `json:"locked_until"`
+	Scope       string    `json:"scope"`
+	Key         string    `json:"key"`
+}
+
+func (q *Queries) LockLoginAttempts(ctx context.Context, arg LockLoginAttemptsParams) error {
+	_, err := q.db.Exec(ctx, lockLoginAttempts, arg.LockedUntil, arg.Scope, arg.Key)
+	return err
+}
+
+const recordLoginFailure = `-- name: RecordLoginFailure :one
+WITH pruned AS (
+  DELETE FROM login_attempts
+  WHERE last_failed_at < $3
+    AND (locked_until IS NULL OR locked_until < now())
+    AND NOT (scope = $1 AND key = $2)
+)
+INSERT INTO login_attempts (
+  scope,
+  key,
+  failed_count
+) VALUES (
+  $1, $2, 1
+) ON CONFLICT (scope, key) DO UPDATE
+SET failed_count = CASE
+    WHEN login_attempts.last_failed_at < $3 THEN 1
+    ELSE login_attempts.failed_count + 1
+  END,
+  last_failed_at = now()
+RETURNING failed_count
+`
+
+type RecordLoginFailureParams struct {
+	Scope       string    `json:"scope"`
+	Key         string    `json:"key"`
+	WindowStart time.Time `json:"window_start"`
+}
+
+func (q *Queries) RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) (int32, error) {
+	row := q.db.QueryRow(ctx, recordLoginFailure, arg.Scope, arg.Key, arg.WindowStart)
+	var failed_count int32
+	err := row.Scan(&failed_count)
+	return failed_count, err
+}
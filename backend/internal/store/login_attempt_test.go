@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordLoginFailure(t *testing.T) {
+	username := util.RandomUsername()
+	arg := RecordLoginFailureParams{
+		Scope:       "username",
+		Key:         username,
+		WindowStart: time.Now().Add(-time.Minute),
+	}
+
+	for i := 1; i <= 3; i++ {
+		failedCount, err := testStore.RecordLoginFailure(context.Background(), arg)
+		require.NoError(t, err)
+		require.Equal(t, int32(i), failedCount)
+	}
+
+	// Failures that happened before the window are forgotten.
+	arg.WindowStart = time.Now().Add(time.Minute)
+	failedCount, err := testStore.RecordLoginFailure(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), failedCount)
+}
+
+func TestLoginLock(t *testing.T) {
+	username := util.RandomUsername()
+	clientIP := "10.0.0.1"
+
+	_, err := testStore.GetLoginLock(context.Background(), GetLoginLockParams{
+		Username: username,
+		ClientIp: clientIP,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	_, err = testStore.RecordLoginFailure(context.Background(), RecordLoginFailureParams{
+		Scope:       "username",
+		Key:         username,
+		WindowStart: time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	lockedUntil := time.Now().Add(time.Minute)
+	err = testStore.LockLoginAttempts(context.Background(), LockLoginAttemptsParams{
+		LockedUntil: lockedUntil,
+		Scope:       "username",
+		Key:         username,
+	})
+	require.NoError(t, err)
+
+	gotLockedUntil, err := testStore.GetLoginLock(context.Background(), GetLoginLockParams{
+		Username: username,
+		ClientIp: clientIP,
+	})
+	require.NoError(t, err)
+	require.WithinDuration(t, lockedUntil, gotLockedUntil, time.Second)
+
+	err = testStore.ClearLoginAttempts(context.Background(), ClearLoginAttemptsParams{
+		Scope: "username",
+		Key:   username,
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.GetLoginLock(context.Background(), GetLoginLockParams{
+		Username: username,
+		ClientIp: clientIP,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
@@ -0,0 +1,105 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: mfa_challenge.sql
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const consumeMFAChallenge = `-- name: ConsumeMFAChallenge :execrows
+UPDATE mfa_challenges
+SET used_at = now()
+WHERE id = $1
+  AND used_at IS NULL
+  AND expire_at > now()
+`
+
+func (q *Queries) ConsumeMFAChallenge(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, consumeMFAChallenge, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createMFAChallenge = `-- name: CreateMFAChallenge :one
+INSERT INTO mfa_challenges (
+  id,
+  user_id,
+  expire_at
+) VALUES (
+  $1, $2, $3
+) RETURNING id, user_id, failed_attempts, expire_at, used_at, created_at
+`
+
+type CreateMFAChallengeParams struct {
+	ID       uuid.UUID `json:"id"`
+	UserID   int64     `json:"user_id"`
+	ExpireAt time.Time `json:"expire_at"`
+}
+
+func (q *Queries) CreateMFAChallenge(ctx context.Context, arg CreateMFAChallengeParams) (MfaChallenge, error) {
+	row := q.db.QueryRow(ctx, createMFAChallenge, arg.ID, arg.UserID, arg.ExpireAt)
+	var i MfaChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FailedAttempts,
+		&i.ExpireAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getActiveMFAChallenge = `-- name: GetActiveMFAChallenge :one
+SELECT id, user_id, failed_attempts, expire_at, used_at, created_at FROM mfa_challenges
+WHERE id = $1
+  AND used_at IS NULL
+  AND expire_at > now()
+LIMIT 1
+`
+
+func (q *Queries) GetActiveMFAChallenge(ctx context.Context, id uuid.UUID) (MfaChallenge, error) {
+	row := q.db.QueryRow(ctx, getActiveMFAChallenge, id)
+	var i MfaChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FailedAttempts,
+		&i.ExpireAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const recordMFAChallengeFailure = `-- name: RecordMFAChallengeFailure :one
+UPDATE mfa_challenges
+SET failed_attempts = failed_attempts + 1,
+    used_at = CASE
+      WHEN failed_attempts + 1 >= $1::int THEN now()
+      ELSE used_at
+    END
+WHERE id = $2
+RETURNING failed_attempts
+`
+
+type RecordMFAChallengeFailureParams struct {
+	MaxAttempts int32     `json:"max_attempts"`
+	ID          uuid.UUID `json:"id"`
+}
+
+// The challenge is used up once the failures reach max_attempts.
+func (q *Queries) RecordMFAChallengeFailure(ctx context.Context, arg RecordMFAChallengeFailureParams) (int32, error) {
+	row := q.db.QueryRow(ctx, recordMFAChallengeFailure, arg.MaxAttempts, arg.ID)
+	var failed_attempts int32
+	err := row.Scan(&failed_attempts)
+	return failed_attempts, err
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomMFAChallenge(t *testing.T, expireAt time.Time) MfaChallenge {
+	id, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	arg := CreateMFAChallengeParams{
+		ID:       id,
+		UserID:   createRandomUser(t).ID,
+		ExpireAt: expireAt,
+	}
+
+	challenge, err := testStore.CreateMFAChallenge(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.ID, challenge.ID)
+	require.Equal(t, arg.UserID, challenge.UserID)
+	require.Zero(t, challenge.FailedAttempts)
+	require.WithinDuration(t, arg.ExpireAt, challenge.ExpireAt, time.Second)
+	require.False(t, challenge.UsedAt.Valid)
+
+	return challenge
+}
+
+func TestConsumeMFAChallenge(t *testing.T) {
+	challenge1 := createRandomMFAChallenge(t, time.Now().Add(time.Minute))
+
+	challenge2, err := testStore.GetActiveMFAChallenge(context.Background(), challenge1.ID)
+	require.NoError(t, err)
+	require.Equal(t, challenge1.ID, challenge2.ID)
+
+	rows, err := testStore.ConsumeMFAChallenge(context.Background(), challenge1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	rows, err = testStore.ConsumeMFAChallenge(context.Background(), challenge1.ID)
+	require.NoError(t, err)
+	require.Zero(t, rows)
+
+	_, err = testStore.GetActiveMFAChallenge(context.Background(), challenge1.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestExpiredMFAChallenge(t *testing.T) {
+	challenge := createRandomMFAChallenge(t, time.Now().Add(-time.Minute))
+
+	_, err := testStore.GetActiveMFAChallenge(context.Background(), challenge.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	rows, err := testStore.ConsumeMFAChallenge(context.Background(), challenge.ID)
+	require.NoError(t, err)
+	require.Zero(t, rows)
+}
+
+func TestRecordMFAChallengeFailure(t *testing.T) {
+	challenge := createRandomMFAChallenge(t, time.Now().Add(time.Minute))
+
+	for i := int32(1); i <= 3; i++ {
+		failedAttempts, err := testStore.RecordMFAChallengeFailure(context.Background(), RecordMFAChallengeFailureParams{
+			MaxAttempts: 3,
+			ID:          challenge.ID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, i, failedAttempts)
+	}
+
+	// The challenge is used up once the failures reach the maximum.
+	_, err := testStore.GetActiveMFAChallenge(context.Background(), challenge.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
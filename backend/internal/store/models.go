@@ -7,22 +7,253 @@ package store
 import (
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type Task struct {
-	ID          string      `json:"id"`
-	Title       string      `json:"title"`
+type AuditEvent struct {
+	ID        int64       `json:"id"`
+	UserID    pgtype.Int8 `json:"user_id"`
+	Username  string      `json:"username"`
+	EventType string      `json:"event_type"`
+	Detail    pgtype.Text `json:"detail"`
+	ClientIp  string      `json:"client_ip"`
+	UserAgent string      `json:"user_agent"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type EmailVerification struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    int64              `json:"user_id"`
+	Email     string             `json:"email"`
+	ExpireAt  time.Time          `json:"expire_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type LoginAttempt struct {
+	Scope        string             `json:"scope"`
+	Key          string             `json:"key"`
+	FailedCount  int32              `json:"failed_count"`
+	LockedUntil  pgtype.Timestamptz `json:"locked_until"`
+	LastFailedAt time.Time          `json:"last_failed_at"`
+}
+
+type MfaChallenge struct {
+	ID             uuid.UUID          `json:"id"`
+	UserID         int64              `json:"user_id"`
+	FailedAttempts int32              `json:"failed_attempts"`
+	ExpireAt       time.Time          `json:"expire_at"`
+	UsedAt         pgtype.Timestamptz `json:"used_at"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+type Notification struct {
+	ID        int64              `json:"id"`
+	UserID    int64              `json:"user_id"`
+	Kind      string             `json:"kind"`
+	Subject   string             `json:"subject"`
+	Body      string             `json:"body"`
+	DedupKey  pgtype.Text        `json:"dedup_key"`
+	ReadAt    pgtype.Timestamptz `json:"read_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type OidcAuthRequest struct {
+	State        string    `json:"state"`
+	Nonce        string    `json:"nonce"`
+	CodeVerifier string    `json:"code_verifier"`
+	ExpireAt     time.Time `json:"expire_at"`
+	CreatedAt    time.Time `json:"created_at"`
+	UseCookies   bool      `json:"use_cookies"`
+}
+
+type OutboxMessage struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Kind      string    `json:"kind"`
+	Subject   string    `json:"subject"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PasswordResetToken struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    int64              `json:"user_id"`
+	TokenHash string             `json:"token_hash"`
+	ExpireAt  time.Time          `json:"expire_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type PersonalAccessToken struct {
+	ID         uuid.UUID          `json:"id"`
+	UserID     int64              `json:"user_id"`
+	Name       string             `json:"name"`
+	TokenHash  string             `json:"token_hash"`
+	Scopes     []string           `json:"scopes"`
+	ExpireAt   pgtype.Timestamptz `json:"expire_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type Project struct {
+	ID          int64       `json:"id"`
+	UserID      int64       `json:"user_id"`
+	Name        string      `json:"name"`
 	Description pgtype.Text `json:"description"`
-	CreatorID   int64       `json:"creator_id"`
-	Deadline    time.Time   `json:"deadline"`
-	Completed   bool        `json:"completed"`
+	Color       string      `json:"color"`
+	Archived    bool        `json:"archived"`
 	CreatedAt   time.Time   `json:"created_at"`
 }
 
+type RecoveryCode struct {
+	ID        int64              `json:"id"`
+	UserID    int64              `json:"user_id"`
+	CodeHash  string             `json:"code_hash"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type RevokedToken struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    int64     `json:"user_id"`
+	ExpireAt  time.Time `json:"expire_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Session struct {
+	ID           uuid.UUID `json:"id"`
+	UserID       int64     `json:"user_id"`
+	RefreshToken string    `json:"refresh_token"`
+	UserAgent    string    `json:"user_agent"`
+	ClientIp     string    `json:"client_ip"`
+	IsBlocked    bool      `json:"is_blocked"`
+	ExpireAt     time.Time `json:"expire_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type Tag struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Task struct {
+	ID           string      `json:"id"`
+	Title        string      `json:"title"`
+	Description  pgtype.Text `json:"description"`
+	CreatorID    int64       `json:"creator_id"`
+	Deadline     time.Time   `json:"deadline"`
+	Completed    bool        `json:"completed"`
+	CreatedAt    time.Time   `json:"created_at"`
+	Priority     string      `json:"priority"`
+	ParentID     pgtype.Text `json:"parent_id"`
+	RecurrenceID pgtype.Int8 `json:"recurrence_id"`
+	Occurrence   pgtype.Int4 `json:"occurrence"`
+	ProjectID    pgtype.Int8 `json:"project_id"`
+	WorkspaceID  pgtype.Int8 `json:"workspace_id"`
+	AssigneeID   pgtype.Int8 `json:"assignee_id"`
+}
+
+type TaskDependency struct {
+	TaskID      string    `json:"task_id"`
+	BlockedByID string    `json:"blocked_by_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type TaskRecurrence struct {
+	ID            int64       `json:"id"`
+	CreatorID     int64       `json:"creator_id"`
+	Rule          string      `json:"rule"`
+	FirstDeadline time.Time   `json:"first_deadline"`
+	Timezone      string      `json:"timezone"`
+	Title         string      `json:"title"`
+	Description   pgtype.Text `json:"description"`
+	Priority      string      `json:"priority"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+type TaskReminder struct {
+	ID            int64              `json:"id"`
+	TaskID        string             `json:"task_id"`
+	RemindAt      pgtype.Timestamptz `json:"remind_at"`
+	OffsetSeconds pgtype.Int4        `json:"offset_seconds"`
+	FireAt        time.Time          `json:"fire_at"`
+	ClaimedUntil  pgtype.Timestamptz `json:"claimed_until"`
+	SentAt        pgtype.Timestamptz `json:"sent_at"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+type TaskShare struct {
+	TaskID    string    `json:"task_id"`
+	UserID    int64     `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TaskTag struct {
+	TaskID string `json:"task_id"`
+	TagID  int64  `json:"tag_id"`
+}
+
+type TotpCredential struct {
+	UserID       int64              `json:"user_id"`
+	Secret       string             `json:"secret"`
+	ConfirmedAt  pgtype.Timestamptz `json:"confirmed_at"`
+	LastUsedStep int64              `json:"last_used_step"`
+	CreatedAt    time.Time          `json:"created_at"`
+}
+
 type User struct {
-	ID             int64     `json:"id"`
-	Username       string    `json:"username"`
-	HashedPassword string    `json:"hashed_password"`
-	CreatedAt      time.Time `json:"created_at"`
+	ID              int64              `json:"id"`
+	Username        string             `json:"username"`
+	HashedPassword  string             `json:"hashed_password"`
+	CreatedAt       time.Time          `json:"created_at"`
+	Role            string             `json:"role"`
+	DisplayName     string             `json:"display_name"`
+	Timezone        string             `json:"timezone"`
+	Locale          string             `json:"locale"`
+	Email           pgtype.Text        `json:"email"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
+}
+
+type UserIdentity struct {
+	ID        int64       `json:"id"`
+	UserID    int64       `json:"user_id"`
+	Issuer    string      `json:"issuer"`
+	Subject   string      `json:"subject"`
+	Email     pgtype.Text `json:"email"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type UserTokenRevocation struct {
+	UserID        int64     `json:"user_id"`
+	RevokedBefore time.Time `json:"revoked_before"`
+}
+
+type Workspace struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WorkspaceInvitation struct {
+	ID          uuid.UUID `json:"id"`
+	WorkspaceID int64     `json:"workspace_id"`
+	TokenHash   string    `json:"token_hash"`
+	Role        string    `json:"role"`
+	CreatedBy   int64     `json:"created_by"`
+	ExpireAt    time.Time `json:"expire_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type WorkspaceMember struct {
+	WorkspaceID int64     `json:"workspace_id"`
+	UserID      int64     `json:"user_id"`
+	Role        string    `json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
 }
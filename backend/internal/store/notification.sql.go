@@ -0,0 +1,130 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: notification.sql
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createNotification = `-- name: CreateNotification :exec
+INSERT INTO notifications (
+  user_id,
+  kind,
+  subject,
+  body,
+  dedup_key
+) VALUES (
+  $1, $2, $3, $4, $5
+) ON CONFLICT (dedup_key) DO NOTHING
+`
+
+type CreateNotificationParams struct {
+	UserID   int64       `json:"user_id"`
+	Kind     string      `json:"kind"`
+	Subject  string      `json:"subject"`
+	Body     string      `json:"body"`
+	DedupKey pgtype.Text `json:"dedup_key"`
+}
+
+// CreateNotification does nothing when a notification with the same
+// dedup_key already exists.
+func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotificationParams) error {
+	_, err := q.db.Exec(ctx, createNotification,
+		arg.UserID,
+		arg.Kind,
+		arg.Subject,
+		arg.Body,
+		arg.DedupKey,
+	)
+	return err
+}
+
+const listUserNotifications = `-- name: ListUserNotifications :many
+SELECT
+  id, user_id, kind, subject, body, dedup_key, read_at, created_at,
+  COUNT(*) OVER() AS total
+FROM notifications
+WHERE user_id = $1::bigint
+  AND (NOT $2::bool OR read_at IS NULL)
+ORDER BY created_at DESC, id DESC
+LIMIT $4 OFFSET $3
+`
+
+type ListUserNotificationsParams struct {
+	UserID int64 `json:"user_id"`
+	Unread bool  `json:"unread"`
+	Offset int32 `json:"offset"`
+	Limit  int32 `json:"limit"`
+}
+
+type ListUserNotificationsRow struct {
+	ID        int64              `json:"id"`
+	UserID    int64              `json:"user_id"`
+	Kind      string             `json:"kind"`
+	Subject   string             `json:"subject"`
+	Body      string             `json:"body"`
+	DedupKey  pgtype.Text        `json:"dedup_key"`
+	ReadAt    pgtype.Timestamptz `json:"read_at"`
+	CreatedAt time.Time          `json:"created_at"`
+	Total     int64              `json:"total"`
+}
+
+func (q *Queries) ListUserNotifications(ctx context.Context, arg ListUserNotificationsParams) ([]ListUserNotificationsRow, error) {
+	rows, err := q.db.Query(ctx, listUserNotifications,
+		arg.UserID,
+		arg.Unread,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUserNotificationsRow{}
+	for rows.Next() {
+		var i ListUserNotificationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Kind,
+			&i.Subject,
+			&i.Body,
+			&i.DedupKey,
+			&i.ReadAt,
+			&i.CreatedAt,
+			&i.Total,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markNotificationRead = `-- name: MarkNotificationRead :execrows
+UPDATE notifications
+SET read_at = COALESCE(read_at, now())
+WHERE id = $1 AND user_id = $2
+`
+
+type MarkNotificationReadParams struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+}
+
+func (q *Queries) MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markNotificationRead, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
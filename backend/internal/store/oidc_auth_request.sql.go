@@ -0,0 +1,81 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: oidc_auth_request.sql
+
+package store
+
+import (
+	"context"
+	"time"
+)
+
+const consumeOIDCAuthRequest = `-- name: ConsumeOIDCAuthRequest :one
+DELETE FROM oidc_auth_requests
+WHERE state = $1 AND expire_at > now()
+RETURNING state, nonce, code_verifier, expire_at, created_at, use_cookies
+`
+
+func (q *Queries) ConsumeOIDCAuthRequest(ctx context.Context, state string) (OidcAuthRequest, error) {
+	row := q.db.QueryRow(ctx, consumeOIDCAuthRequest, state)
+	var i OidcAuthRequest
+	err := row.Scan(
+		&i.State,
+		&i.Nonce,
+		&i.CodeVerifier,
+		&i.ExpireAt,
+		&i.CreatedAt,
+		&i.UseCookies,
+	)
+	return i, err
+}
+
+const createOIDCAuthRequest = `-- name: CreateOIDCAuthRequest :one
+INSERT INTO oidc_auth_requests (
+  state,
+  nonce,
+  code_verifier,
+  use_cookies,
+  expire_at
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING state, nonce, code_verifier, expire_at, created_at, use_cookies
+`
+
+type CreateOIDCAuthRequestParams struct {
+	State        string    `json:"state"`
+	Nonce        string    `json:"nonce"`
+	CodeVerifier string    `json:"code_verifier"`
+	UseCookies   bool      `json:"use_cookies"`
+	ExpireAt     time.Time `json:"expire_at"`
+}
+
+func (q *Queries) CreateOIDCAuthRequest(ctx context.Context, arg CreateOIDCAuthRequestParams) (OidcAuthRequest, error) {
+	row := q.db.QueryRow(ctx, createOIDCAuthRequest,
+		arg.State,
+		arg.Nonce,
+		arg.CodeVerifier,
+		arg.UseCookies,
+		arg.ExpireAt,
+	)
+	var i OidcAuthRequest
+	err := row.Scan(
+		&i.State,
+		&i.Nonce,
+		&i.CodeVerifier,
+		&i.ExpireAt,
+		&i.CreatedAt,
+		&i.UseCookies,
+	)
+	return i, err
+}
+
+const deleteExpiredOIDCAuthRequests = `-- name: DeleteExpiredOIDCAuthRequests :exec
+DELETE FROM oidc_auth_requests
+WHERE expire_at <= now()
+`
+
+func (q *Queries) DeleteExpiredOIDCAuthRequests(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteExpiredOIDCAuthRequests)
+	return err
+}
@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomOIDCAuthRequest(t *testing.T, expireAt time.Time) OidcAuthRequest {
+	arg := CreateOIDCAuthRequestParams{
+		State:        util.RandomAlphaNumString(43),
+		Nonce:        util.RandomAlphaNumString(43),
+		CodeVerifier: util.RandomAlphaNumString(43),
+		ExpireAt:     expireAt,
+	}
+
+	authRequest, err := testStore.CreateOIDCAuthRequest(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.State, authRequest.State)
+	require.Equal(t, arg.Nonce, authRequest.Nonce)
+	require.Equal(t, arg.CodeVerifier, authRequest.CodeVerifier)
+	require.WithinDuration(t, arg.ExpireAt, authRequest.ExpireAt, time.Second)
+
+	return authRequest
+}
+
+func TestConsumeOIDCAuthRequest(t *testing.T) {
+	authRequest1 := createRandomOIDCAuthRequest(t, time.Now().Add(time.Minute))
+
+	authRequest2, err := testStore.ConsumeOIDCAuthRequest(context.Background(), authRequest1.State)
+	require.NoError(t, err)
+	require.Equal(t, authRequest1.CodeVerifier, authRequest2.CodeVerifier)
+
+	_, err = testStore.ConsumeOIDCAuthRequest(context.Background(), authRequest1.State)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestDeleteExpiredOIDCAuthRequests(t *testing.T) {
+	expired := createRandomOIDCAuthRequest(t, time.Now().Add(-time.Minute))
+	active := createRandomOIDCAuthRequest(t, time.Now().Add(time.Minute))
+
+	_, err := testStore.ConsumeOIDCAuthRequest(context.Background(), expired.State)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	err = testStore.DeleteExpiredOIDCAuthRequests(context.Background())
+	require.NoError(t, err)
+
+	_, err = testStore.ConsumeOIDCAuthRequest(context.Background(), active.State)
+	require.NoError(t, err)
+}
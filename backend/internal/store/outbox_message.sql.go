@@ -0,0 +1,80 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: outbox_message.sql
+
+package store
+
+import (
+	"context"
+)
+
+const createOutboxMessage = `-- name: CreateOutboxMessage :one
+INSERT INTO outbox_messages (
+  user_id,
+  kind,
+  subject,
+  body
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, user_id, kind, subject, body, created_at
+`
+
+type CreateOutboxMessageParams struct {
+	UserID  int64  `json:"user_id"`
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+func (q *Queries) CreateOutboxMessage(ctx context.Context, arg CreateOutboxMessageParams) (OutboxMessage, error) {
+	row := q.db.QueryRow(ctx, createOutboxMessage,
+		arg.UserID,
+		arg.Kind,
+		arg.Subject,
+		arg.Body,
+	)
+	var i OutboxMessage
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Kind,
+		&i.Subject,
+		&i.Body,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listOutboxMessages = `-- name: ListOutboxMessages :many
+SELECT id, user_id, kind, subject, body, created_at FROM outbox_messages
+WHERE user_id = $1
+ORDER BY id DESC
+`
+
+func (q *Queries) ListOutboxMessages(ctx context.Context, userID int64) ([]OutboxMessage, error) {
+	rows, err := q.db.Query(ctx, listOutboxMessages, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OutboxMessage{}
+	for rows.Next() {
+		var i OutboxMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Kind,
+			&i.Subject,
+			&i.Body,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,34 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxMessages(t *testing.T) {
+	user := createRandomUser(t)
+
+	arg := CreateOutboxMessageParams{
+		UserID:  user.ID,
+		Kind:    "password_reset",
+		Subject: util.RandomPrintableString(10),
+		Body:    util.RandomPrintableString(50),
+	}
+
+	message, err := testStore.CreateOutboxMessage(context.Background(), arg)
+	require.NoError(t, err)
+	require.Positive(t, message.ID)
+	require.Equal(t, arg.UserID, message.UserID)
+	require.Equal(t, arg.Kind, message.Kind)
+	require.Equal(t, arg.Subject, message.Subject)
+	require.Equal(t, arg.Body, message.Body)
+	require.NotZero(t, message.CreatedAt)
+
+	messages, err := testStore.ListOutboxMessages(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	require.Equal(t, message, messages[0])
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPasswordResetToken(t *testing.T, user User, expireAt time.Time) PasswordResetToken {
+	id, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	arg := CreatePasswordResetTokenParams{
+		ID:        id,
+		UserID:    user.ID,
+		TokenHash: util.RandomAlphaNumString(64),
+		ExpireAt:  expireAt,
+	}
+
+	resetToken, err := testStore.CreatePasswordResetToken(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.ID, resetToken.ID)
+	require.Equal(t, arg.UserID, resetToken.UserID)
+	require.Equal(t, arg.TokenHash, resetToken.TokenHash)
+	require.WithinDuration(t, arg.ExpireAt, resetToken.ExpireAt, time.Second)
+	require.False(t, resetToken.UsedAt.Valid)
+	require.NotZero(t, resetToken.CreatedAt)
+
+	return resetToken
+}
+
+func TestConsumePasswordResetToken(t *testing.T) {
+	resetToken1 := createRandomPasswordResetToken(t, createRandomUser(t), time.Now().Add(time.Hour))
+
+	resetToken2, err := testStore.ConsumePasswordResetToken(context.Background(), resetToken1.TokenHash)
+	require.NoError(t, err)
+	require.Equal(t, resetToken1.ID, resetToken2.ID)
+	require.True(t, resetToken2.UsedAt.Valid)
+
+	// Tokens are single use.
+	_, err = testStore.ConsumePasswordResetToken(context.Background(), resetToken1.TokenHash)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestConsumeExpiredPasswordResetToken(t *testing.T) {
+	resetToken := createRandomPasswordResetToken(t, createRandomUser(t), time.Now().Add(-time.Minute))
+
+	_, err := testStore.ConsumePasswordResetToken(context.Background(), resetToken.TokenHash)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestInvalidateUserPasswordResetTokens(t *testing.T) {
+	user := createRandomUser(t)
+	resetToken := createRandomPasswordResetToken(t, user, time.Now().Add(time.Hour))
+
+	err := testStore.InvalidateUserPasswordResetTokens(context.Background(), user.ID)
+	require.NoError(t, err)
+
+	_, err = testStore.ConsumePasswordResetToken(context.Background(), resetToken.TokenHash)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
@@ -0,0 +1,101 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPersonalAccessToken(t *testing.T, user User) PersonalAccessToken {
+	id, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	arg := CreatePersonalAccessTokenParams{
+		ID:        id,
+		UserID:    user.ID,
+		Name:      util.RandomPrintableString(10),
+		TokenHash: util.RandomAlphaNumString(64),
+		Scopes:    []string{"tasks:read"},
+		ExpireAt: pgtype.Timestamptz{
+			Time:  time.Now().Add(time.Hour),
+			Valid: true,
+		},
+	}
+
+	pat, err := testStore.CreatePersonalAccessToken(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, pat)
+
+	require.Equal(t, arg.ID, pat.ID)
+	require.Equal(t, arg.UserID, pat.UserID)
+	require.Equal(t, arg.Name, pat.Name)
+	require.Equal(t, arg.TokenHash, pat.TokenHash)
+	require.Equal(t, arg.Scopes, pat.Scopes)
+	require.WithinDuration(t, arg.ExpireAt.Time, pat.ExpireAt.Time, time.Second)
+	require.False(t, pat.LastUsedAt.Valid)
+	require.False(t, pat.RevokedAt.Valid)
+	require.NotZero(t, pat.CreatedAt)
+
+	return pat
+}
+
+func TestCreatePersonalAccessToken(t *testing.T) {
+	createRandomPersonalAccessToken(t, createRandomUser(t))
+}
+
+func TestGetPersonalAccessTokenByHash(t *testing.T) {
+	user := createRandomUser(t)
+	pat := createRandomPersonalAccessToken(t, user)
+
+	row, err := testStore.GetPersonalAccessTokenByHash(context.Background(), pat.TokenHash)
+	require.NoError(t, err)
+	require.Equal(t, pat.ID, row.ID)
+	require.Equal(t, user.ID, row.UserID)
+	require.Equal(t, user.Username, row.Username)
+	require.Equal(t, user.Role, row.Role)
+	require.Equal(t, pat.Scopes, row.Scopes)
+}
+
+func TestTouchPersonalAccessToken(t *testing.T) {
+	user := createRandomUser(t)
+	pat := createRandomPersonalAccessToken(t, user)
+
+	err := testStore.TouchPersonalAccessToken(context.Background(), pat.ID)
+	require.NoError(t, err)
+
+	pats, err := testStore.ListPersonalAccessTokens(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Len(t, pats, 1)
+	require.True(t, pats[0].LastUsedAt.Valid)
+	require.WithinDuration(t, time.Now(), pats[0].LastUsedAt.Time, time.Second)
+}
+
+func TestRevokePersonalAccessToken(t *testing.T) {
+	user := createRandomUser(t)
+	pat1 := createRandomPersonalAccessToken(t, user)
+	pat2 := createRandomPersonalAccessToken(t, user)
+
+	rows, err := testStore.RevokePersonalAccessToken(context.Background(), RevokePersonalAccessTokenParams{
+		ID:     pat1.ID,
+		UserID: createRandomUser(t).ID,
+	})
+	require.NoError(t, err)
+	require.Zero(t, rows)
+
+	rows, err = testStore.RevokePersonalAccessToken(context.Background(), RevokePersonalAccessTokenParams{
+		ID:     pat1.ID,
+		UserID: user.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	pats, err := testStore.ListPersonalAccessTokens(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Len(t, pats, 1)
+	require.Equal(t, pat2.ID, pats[0].ID)
+}
@@ -0,0 +1,245 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: project.sql
+
+package store
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createProject = `-- name: CreateProject :one
+INSERT INTO projects (
+  user_id,
+  name,
+  description,
+  color
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, user_id, name, description, color, archived, created_at
+`
+
+type CreateProjectParams struct {
+	UserID      int64       `json:"user_id"`
+	Name        string      `json:"name"`
+	Description pgtype.Text `json:"description"`
+	Color       string      `json:"color"`
+}
+
+func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (Project, error) {
+	row := q.db.QueryRow(ctx, createProject,
+		arg.UserID,
+		arg.Name,
+		arg.Description,
+		arg.Color,
+	)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.Color,
+		&i.Archived,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteProject = `-- name: DeleteProject :exec
+DELETE FROM projects
+WHERE id = $1
+`
+
+func (q *Queries) DeleteProject(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, deleteProject, id)
+	return err
+}
+
+const getProject = `-- name: GetProject :one
+SELECT id, user_id, name, description, color, archived, created_at FROM projects
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetProject(ctx context.Context, id int64) (Project, error) {
+	row := q.db.QueryRow(ctx, getProject, id)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.Color,
+		&i.Archived,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getProjectTaskCounts = `-- name: GetProjectTaskCounts :many
+SELECT
+  project_id::bigint AS project_id,
+  COUNT(*) FILTER (WHERE NOT completed) AS open,
+  COUNT(*) FILTER (WHERE NOT completed AND deadline < now()) AS overdue,
+  COUNT(*) FILTER (WHERE completed) AS completed
+FROM tasks
+WHERE project_id = ANY($1::bigint[])
+GROUP BY project_id
+`
+
+type GetProjectTaskCountsRow struct {
+	ProjectID int64 `json:"project_id"`
+	Open      int64 `json:"open"`
+	Overdue   int64 `json:"overdue"`
+	Completed int64 `json:"completed"`
+}
+
+// GetProjectTaskCounts counts the tasks of each of the given projects.
+// Overdue tasks are open tasks past their deadline, so they are counted as
+// open too. Projects without tasks have no row.
+func (q *Queries) GetProjectTaskCounts(ctx context.Context, projectIds []int64) ([]GetProjectTaskCountsRow, error) {
+	rows, err := q.db.Query(ctx, getProjectTaskCounts, projectIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetProjectTaskCountsRow{}
+	for rows.Next() {
+		var i GetProjectTaskCountsRow
+		if err := rows.Scan(
+			&i.ProjectID,
+			&i.Open,
+			&i.Overdue,
+			&i.Completed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProjects = `-- name: ListProjects :many
+SELECT id, user_id, name, description, color, archived, created_at FROM projects
+WHERE
+  user_id = $1
+  AND ($2::bool OR NOT archived)
+ORDER BY archived ASC, lower(name) ASC
+`
+
+type ListProjectsParams struct {
+	UserID          int64 `json:"user_id"`
+	IncludeArchived bool  `json:"include_archived"`
+}
+
+// ListProjects leaves out archived projects unless include_archived is set.
+func (q *Queries) ListProjects(ctx context.Context, arg ListProjectsParams) ([]Project, error) {
+	rows, err := q.db.Query(ctx, listProjects, arg.UserID, arg.IncludeArchived)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Project{}
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Description,
+			&i.Color,
+			&i.Archived,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setTaskProject = `-- name: SetTaskProject :one
+UPDATE tasks
+SET project_id = $1
+WHERE id = $2
+RETURNING id, title, description, creator_id, deadline, completed, created_at, priority, parent_id, recurrence_id, occurrence, project_id, workspace_id, assignee_id
+`
+
+type SetTaskProjectParams struct {
+	ProjectID pgtype.Int8 `json:"project_id"`
+	ID        string      `json:"id"`
+}
+
+// SetTaskProject moves a task into a project, or out of any project when
+// project_id is NULL.
+func (q *Queries) SetTaskProject(ctx context.Context, arg SetTaskProjectParams) (Task, error) {
+	row := q.db.QueryRow(ctx, setTaskProject, arg.ProjectID, arg.ID)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.CreatorID,
+		&i.Deadline,
+		&i.Completed,
+		&i.CreatedAt,
+		&i.Priority,
+		&i.ParentID,
+		&i.RecurrenceID,
+		&i.Occurrence,
+		&i.ProjectID,
+		&i.WorkspaceID,
+		&i.AssigneeID,
+	)
+	return i, err
+}
+
+const updateProject = `-- name: UpdateProject :one
+UPDATE projects
+SET
+  name = COALESCE($2, name),
+  description = COALESCE($3, description),
+  color = COALESCE($4, color),
+  archived = COALESCE($5, archived)
+WHERE
+  id = $1
+RETURNING id, user_id, name, description, color, archived, created_at
+`
+
+type UpdateProjectParams struct {
+	ID          int64       `json:"id"`
+	Name        pgtype.Text `json:"name"`
+	Description pgtype.Text `json:"description"`
+	Color       pgtype.Text `json:"color"`
+	Archived    pgtype.Bool `json:"archived"`
+}
+
+func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (Project, error) {
+	row := q.db.QueryRow(ctx, updateProject,
+		arg.ID,
+		arg.Name,
+		arg.Description,
+		arg.Color,
+		arg.Archived,
+	)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.Color,
+		&i.Archived,
+		&i.CreatedAt,
+	)
+	return i, err
+}
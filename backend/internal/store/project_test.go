@@ -0,0 +1,129 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomProject(t *testing.T, userID int64) Project {
+	arg := CreateProjectParams{
+		UserID: userID,
+		Name:   util.RandomAlphabetString(10),
+		Description: pgtype.Text{
+			String: util.RandomPrintableString(100),
+			Valid:  true,
+		},
+		Color: "#6b7280",
+	}
+
+	project, err := testStore.CreateProject(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, project.ID)
+	require.Equal(t, arg.UserID, project.UserID)
+	require.Equal(t, arg.Name, project.Name)
+	require.Equal(t, arg.Description, project.Description)
+	require.Equal(t, arg.Color, project.Color)
+	require.False(t, project.Archived)
+	require.NotZero(t, project.CreatedAt)
+
+	return project
+}
+
+func moveTaskToProject(t *testing.T, task Task, projectID int64) Task {
+	moved, err := testStore.SetTaskProject(context.Background(), SetTaskProjectParams{
+		ID:        task.ID,
+		ProjectID: pgtype.Int8{Int64: projectID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, projectID, moved.ProjectID.Int64)
+	return moved
+}
+
+func TestCreateProjectNameIsCaseInsensitiveUnique(t *testing.T) {
+	project := createRandomProject(t, createRandomUser(t).ID)
+
+	_, err := testStore.CreateProject(context.Background(), CreateProjectParams{
+		UserID: project.UserID,
+		Name:   strings.ToUpper(project.Name),
+		Color:  project.Color,
+	})
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+}
+
+func TestListProjectsLeavesOutArchived(t *testing.T) {
+	userID := createRandomUser(t).ID
+	active := createRandomProject(t, userID)
+	archived := createRandomProject(t, userID)
+
+	archived, err := testStore.UpdateProject(context.Background(), UpdateProjectParams{
+		ID:       archived.ID,
+		Archived: pgtype.Bool{Bool: true, Valid: true},
+	})
+	require.NoError(t, err)
+	require.True(t, archived.Archived)
+
+	projects, err := testStore.ListProjects(context.Background(), ListProjectsParams{UserID: userID})
+	require.NoError(t, err)
+	require.Equal(t, []Project{active}, projects)
+
+	projects, err = testStore.ListProjects(context.Background(), ListProjectsParams{
+		UserID:          userID,
+		IncludeArchived: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Project{active, archived}, projects)
+}
+
+func TestGetProjectTaskCounts(t *testing.T) {
+	task := createRandomTask(t)
+	project := createRandomProject(t, task.CreatorID)
+	empty := createRandomProject(t, task.CreatorID)
+	moveTaskToProject(t, task, project.ID)
+
+	overdue := createRandomTask(t)
+	moveTaskToProject(t, overdue, project.ID)
+	_, err := testStore.UpdateTask(context.Background(), UpdateTaskParams{
+		ID:       overdue.ID,
+		Deadline: pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+
+	completed := createRandomTask(t)
+	moveTaskToProject(t, completed, project.ID)
+	_, err = testStore.UpdateTask(context.Background(), UpdateTaskParams{
+		ID:        completed.ID,
+		Completed: pgtype.Bool{Bool: true, Valid: true},
+	})
+	require.NoError(t, err)
+
+	counts, err := testStore.GetProjectTaskCounts(context.Background(), []int64{project.ID, empty.ID})
+	require.NoError(t, err)
+	require.Equal(t, []GetProjectTaskCountsRow{{
+		ProjectID: project.ID,
+		Open:      2,
+		Overdue:   1,
+		Completed: 1,
+	}}, counts)
+}
+
+func TestDeleteProjectKeepsTasks(t *testing.T) {
+	task := createRandomTask(t)
+	project := createRandomProject(t, task.CreatorID)
+	moveTaskToProject(t, task, project.ID)
+
+	err := testStore.DeleteProject(context.Background(), project.ID)
+	require.NoError(t, err)
+
+	_, err = testStore.GetProject(context.Background(), project.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	task, err = testStore.GetTaskByID(context.Background(), task.ID)
+	require.NoError(t, err)
+	require.False(t, task.ProjectID.Valid)
+}
@@ -6,16 +6,180 @@ package store
 
 import (
 	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
+	AddTaskDependency(ctx context.Context, arg AddTaskDependencyParams) error
+	// AddWorkspaceMember returns no rows when the user already is a member.
+	AddWorkspaceMember(ctx context.Context, arg AddWorkspaceMemberParams) (WorkspaceMember, error)
+	BlockOtherUserSessions(ctx context.Context, arg BlockOtherUserSessionsParams) error
+	BlockSession(ctx context.Context, id uuid.UUID) error
+	BlockUserSessions(ctx context.Context, userID int64) error
+	// ClaimDueReminders leases up to limit due reminders of open tasks to the
+	// caller. Concurrent callers never claim the same reminder: locked rows are
+	// skipped and a claimed reminder is only due again once its lease expires.
+	ClaimDueReminders(ctx context.Context, arg ClaimDueRemindersParams) ([]ClaimDueRemindersRow, error)
+	ClearLoginAttempts(ctx context.Context, arg ClearLoginAttemptsParams) error
+	ConfirmTOTPCredential(ctx context.Context, arg ConfirmTOTPCredentialParams) (TotpCredential, error)
+	ConsumeEmailVerification(ctx context.Context, id uuid.UUID) (EmailVerification, error)
+	ConsumeMFAChallenge(ctx context.Context, id uuid.UUID) (int64, error)
+	ConsumeOIDCAuthRequest(ctx context.Context, state string) (OidcAuthRequest, error)
+	ConsumePasswordResetToken(ctx context.Context, tokenHash string) (PasswordResetToken, error)
+	// CopyTaskReminders gives a task the reminders of another one that are
+	// relative to its deadline, e.g. for the next occurrence of a recurring task.
+	CopyTaskReminders(ctx context.Context, arg CopyTaskRemindersParams) error
+	// CopyTaskShares shares a task with the users another one is shared with,
+	// e.g. for the next occurrence of a recurring task.
+	CopyTaskShares(ctx context.Context, arg CopyTaskSharesParams) error
+	// The blockers stay locked until the end of the transaction, so that none of
+	// them can be reopened while the task is being completed.
+	CountOpenBlockers(ctx context.Context, taskID string) (int64, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID int64) (int64, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error)
+	CreateEmailVerification(ctx context.Context, arg CreateEmailVerificationParams) (EmailVerification, error)
+	CreateMFAChallenge(ctx context.Context, arg CreateMFAChallengeParams) (MfaChallenge, error)
+	// CreateNotification does nothing when a notification with the same
+	// dedup_key already exists.
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) error
+	CreateOIDCAuthRequest(ctx context.Context, arg CreateOIDCAuthRequestParams) (OidcAuthRequest, error)
+	CreateOutboxMessage(ctx context.Context, arg CreateOutboxMessageParams) (OutboxMessage, error)
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) (PasswordResetToken, error)
+	CreatePersonalAccessToken(ctx context.Context, arg CreatePersonalAccessTokenParams) (PersonalAccessToken, error)
+	CreateProject(ctx context.Context, arg CreateProjectParams) (Project, error)
+	CreateRecoveryCodes(ctx context.Context, arg CreateRecoveryCodesParams) error
+	// CreateRecurringTask starts a series whose first occurrence is the new task.
+	CreateRecurringTask(ctx context.Context, arg CreateRecurringTaskParams) (Task, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error)
 	CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error)
+	// CreateTaskOccurrence returns no rows when the occurrence already exists,
+	// for example because a completed occurrence was reopened and completed again.
+	CreateTaskOccurrence(ctx context.Context, arg CreateTaskOccurrenceParams) (Task, error)
+	// CreateTaskReminder computes fire_at from the deadline of the task for
+	// reminders given as an offset.
+	CreateTaskReminder(ctx context.Context, arg CreateTaskReminderParams) (TaskReminder, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error)
+	// Provisions a user for an external identity in one statement so that no
+	// user is left without the identity it was created for. An email is only
+	// passed when the provider has verified it, so it is stored as verified.
+	CreateUserWithIdentity(ctx context.Context, arg CreateUserWithIdentityParams) (CreateUserWithIdentityRow, error)
+	// CreateWorkspace creates a workspace owned by the given user.
+	CreateWorkspace(ctx context.Context, arg CreateWorkspaceParams) (CreateWorkspaceRow, error)
+	CreateWorkspaceInvitation(ctx context.Context, arg CreateWorkspaceInvitationParams) (WorkspaceInvitation, error)
+	DeleteExpiredOIDCAuthRequests(ctx context.Context) error
+	DeleteProject(ctx context.Context, id int64) error
+	DeleteRecoveryCodes(ctx context.Context, userID int64) error
+	DeleteTOTPCredential(ctx context.Context, userID int64) error
+	DeleteTag(ctx context.Context, id int64) error
 	DeleteTask(ctx context.Context, id string) error
+	DeleteTaskReminder(ctx context.Context, arg DeleteTaskReminderParams) (int64, error)
+	DeleteUser(ctx context.Context, id int64) (int64, error)
+	DeleteWorkspace(ctx context.Context, id int64) error
+	DeleteWorkspaceInvitation(ctx context.Context, arg DeleteWorkspaceInvitationParams) (int64, error)
+	GetActiveMFAChallenge(ctx context.Context, id uuid.UUID) (MfaChallenge, error)
+	GetLoginLock(ctx context.Context, arg GetLoginLockParams) (time.Time, error)
+	GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (GetPersonalAccessTokenByHashRow, error)
+	GetProject(ctx context.Context, id int64) (Project, error)
+	// GetProjectTaskCounts counts the tasks of each of the given projects.
+	// Overdue tasks are open tasks past their deadline, so they are counted as
+	// open too. Projects without tasks have no row.
+	GetProjectTaskCounts(ctx context.Context, projectIds []int64) ([]GetProjectTaskCountsRow, error)
+	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	// GetSubtaskProgress counts the direct subtasks of each of the given tasks.
+	// Tasks without subtasks have no row.
+	GetSubtaskProgress(ctx context.Context, taskIds []string) ([]GetSubtaskProgressRow, error)
+	GetTOTPCredential(ctx context.Context, userID int64) (TotpCredential, error)
+	GetTag(ctx context.Context, id int64) (Tag, error)
 	GetTaskByID(ctx context.Context, id string) (Task, error)
+	GetTaskRecurrence(ctx context.Context, id int64) (TaskRecurrence, error)
+	GetTaskShare(ctx context.Context, arg GetTaskShareParams) (TaskShare, error)
 	GetTasks(ctx context.Context, arg GetTasksParams) ([]GetTasksRow, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserByID(ctx context.Context, id int64) (User, error)
+	GetUserByVerifiedEmail(ctx context.Context, email pgtype.Text) (User, error)
+	GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error)
+	GetUserTagsByIDs(ctx context.Context, arg GetUserTagsByIDsParams) ([]Tag, error)
+	GetWorkspace(ctx context.Context, id int64) (Workspace, error)
+	// GetWorkspaceInvitationByHash returns no rows for expired invitations.
+	GetWorkspaceInvitationByHash(ctx context.Context, tokenHash string) (WorkspaceInvitation, error)
+	GetWorkspaceMember(ctx context.Context, arg GetWorkspaceMemberParams) (WorkspaceMember, error)
+	InvalidateUserPasswordResetTokens(ctx context.Context, userID int64) error
+	IsTokenRevoked(ctx context.Context, arg IsTokenRevokedParams) (bool, error)
+	ListOutboxMessages(ctx context.Context, userID int64) ([]OutboxMessage, error)
+	ListPersonalAccessTokens(ctx context.Context, userID int64) ([]PersonalAccessToken, error)
+	// ListProjects leaves out archived projects unless include_archived is set.
+	ListProjects(ctx context.Context, arg ListProjectsParams) ([]Project, error)
+	ListSubtasks(ctx context.Context, parentID pgtype.Text) ([]Task, error)
+	ListTags(ctx context.Context, userID int64) ([]ListTagsRow, error)
+	ListTagsForTasks(ctx context.Context, taskIds []string) ([]ListTagsForTasksRow, error)
+	ListTaskBlockers(ctx context.Context, taskID string) ([]Task, error)
+	ListTaskReminders(ctx context.Context, taskID string) ([]TaskReminder, error)
+	ListTaskShares(ctx context.Context, taskID string) ([]ListTaskSharesRow, error)
+	ListUserAuditEvents(ctx context.Context, arg ListUserAuditEventsParams) ([]ListUserAuditEventsRow, error)
+	ListUserNotifications(ctx context.Context, arg ListUserNotificationsParams) ([]ListUserNotificationsRow, error)
+	ListUserWorkspaces(ctx context.Context, userID int64) ([]ListUserWorkspacesRow, error)
+	// ListWorkspaceInvitations leaves out expired invitations.
+	ListWorkspaceInvitations(ctx context.Context, workspaceID int64) ([]WorkspaceInvitation, error)
+	ListWorkspaceMembers(ctx context.Context, workspaceID int64) ([]ListWorkspaceMembersRow, error)
+	LockLoginAttempts(ctx context.Context, arg LockLoginAttemptsParams) error
+	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (int64, error)
+	MarkReminderSent(ctx context.Context, id int64) error
+	// MergeTag moves the tasks of the source tag to the target tag and deletes the
+	// source tag in a single statement. Tasks that already have both tags keep a
+	// single assignment.
+	MergeTag(ctx context.Context, arg MergeTagParams) (int64, error)
+	// MoveTask makes a task a subtask of another one, or a top-level task when
+	// parent_id is NULL.
+	MoveTask(ctx context.Context, arg MoveTaskParams) (Task, error)
+	RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) (int32, error)
+	// The challenge is used up once the failures reach max_attempts.
+	RecordMFAChallengeFailure(ctx context.Context, arg RecordMFAChallengeFailureParams) (int32, error)
+	RemoveTaskDependency(ctx context.Context, arg RemoveTaskDependencyParams) (int64, error)
+	RemoveWorkspaceMember(ctx context.Context, arg RemoveWorkspaceMemberParams) (int64, error)
+	RevokePersonalAccessToken(ctx context.Context, arg RevokePersonalAccessTokenParams) (int64, error)
+	RevokeToken(ctx context.Context, arg RevokeTokenParams) error
+	RevokeUserTokens(ctx context.Context, arg RevokeUserTokensParams) error
+	// SetTaskAssignee assigns a task to a user, or unassigns it when assignee_id
+	// is NULL.
+	SetTaskAssignee(ctx context.Context, arg SetTaskAssigneeParams) (Task, error)
+	// SetTaskProject moves a task into a project, or out of any project when
+	// project_id is NULL.
+	SetTaskProject(ctx context.Context, arg SetTaskProjectParams) (Task, error)
+	// SetTaskTags replaces the tags of a task with the given ones.
+	SetTaskTags(ctx context.Context, arg SetTaskTagsParams) error
+	// ShareTask gives a user a role on a task, replacing the role they had.
+	ShareTask(ctx context.Context, arg ShareTaskParams) (TaskShare, error)
+	// SkipTaskOccurrence turns an open occurrence into a later one of its series.
+	SkipTaskOccurrence(ctx context.Context, arg SkipTaskOccurrenceParams) (Task, error)
+	// SplitTaskRecurrence starts a new series at the task, which becomes its first
+	// occurrence. Earlier occurrences stay with the old series.
+	SplitTaskRecurrence(ctx context.Context, arg SplitTaskRecurrenceParams) (Task, error)
+	// StopTaskRecurrence detaches a task from its series so that completing it
+	// no longer creates another occurrence.
+	StopTaskRecurrence(ctx context.Context, id string) (Task, error)
+	TouchPersonalAccessToken(ctx context.Context, id uuid.UUID) error
+	UnshareTask(ctx context.Context, arg UnshareTaskParams) (int64, error)
+	UpdateProject(ctx context.Context, arg UpdateProjectParams) (Project, error)
+	UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, error)
 	UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, error)
+	UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (User, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error)
+	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	UpdateWorkspace(ctx context.Context, arg UpdateWorkspaceParams) (Workspace, error)
+	UpdateWorkspaceMemberRole(ctx context.Context, arg UpdateWorkspaceMemberRoleParams) (WorkspaceMember, error)
+	// Starts (or restarts) an enrollment. A confirmed credential is left
+	// untouched and no row is returned.
+	UpsertPendingTOTPCredential(ctx context.Context, arg UpsertPendingTOTPCredentialParams) (TotpCredential, error)
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error)
+	// Only moves forward, so every code is accepted at most once.
+	UseTOTPStep(ctx context.Context, arg UseTOTPStepParams) (int64, error)
+	VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error)
 }
 
 var _ Querier = (*Queries)(nil)
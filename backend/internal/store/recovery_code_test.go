@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoveryCodes(t *testing.T) {
+	user := createRandomUser(t)
+
+	hashes := []string{
+		util.RandomAlphabetString(64),
+		util.RandomAlphabetString(64),
+		util.RandomAlphabetString(64),
+	}
+
+	err := testStore.CreateRecoveryCodes(context.Background(), CreateRecoveryCodesParams{
+		UserID:     user.ID,
+		CodeHashes: hashes,
+	})
+	require.NoError(t, err)
+
+	count, err := testStore.CountUnusedRecoveryCodes(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+
+	rows, err := testStore.UseRecoveryCode(context.Background(), UseRecoveryCodeParams{
+		UserID:   user.ID,
+		CodeHash: hashes[0],
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	// Each code works once and only for its owner.
+	rows, err = testStore.UseRecoveryCode(context.Background(), UseRecoveryCodeParams{
+		UserID:   user.ID,
+		CodeHash: hashes[0],
+	})
+	require.NoError(t, err)
+	require.Zero(t, rows)
+
+	rows, err = testStore.UseRecoveryCode(context.Background(), UseRecoveryCodeParams{
+		UserID:   createRandomUser(t).ID,
+		CodeHash: hashes[1],
+	})
+	require.NoError(t, err)
+	require.Zero(t, rows)
+
+	count, err = testStore.CountUnusedRecoveryCodes(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	err = testStore.DeleteRecoveryCodes(context.Background(), user.ID)
+	require.NoError(t, err)
+
+	count, err = testStore.CountUnusedRecoveryCodes(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
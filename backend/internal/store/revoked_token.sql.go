@@ -0,0 +1,91 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: revoked_token.sql
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const isTokenRevoked = `-- name: IsTokenRevoked :one
+SELECT (
+  EXISTS (
+    SELECT 1 FROM revoked_tokens rt
+    WHERE rt.id = $1
+  )
+  OR EXISTS (
+    SELECT 1 FROM user_token_revocations utr
+    WHERE utr.user_id = $2
+      AND utr.revoked_before >= $3
+  )
+  -- Revocations are deleted along with the user, so the tokens of a
+  -- deleted user are rejected explicitly.
+  OR NOT EXISTS (
+    SELECT 1 FROM users u
+    WHERE u.id = $2
+  )
+)::bool AS revoked
+`
+
+type IsTokenRevokedParams struct {
+	ID       uuid.UUID `json:"id"`
+	UserID   int64     `json:"user_id"`
+	IssuedAt time.Time `json:"issued_at"`
+}
+
+func (q *Queries) IsTokenRevoked(ctx context.Context, arg IsTokenRevokedParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isTokenRevoked, arg.ID, arg.UserID, arg.IssuedAt)
+	var revoked bool
+	err := row.Scan(&revoked)
+	return revoked, err
+}
+
+const revokeToken = `-- name: RevokeToken :exec
+WITH pruned AS (
+  DELETE FROM revoked_tokens
+  WHERE expire_at < now()
+)
+INSERT INTO revoked_tokens (
+  id,
+  user_id,
+  expire_at
+) VALUES (
+  $1, $2, $3
+) ON CONFLICT (id) DO NOTHING
+`
+
+type RevokeTokenParams struct {
+	ID       uuid.UUID `json:"id"`
+	UserID   int64     `json:"user_id"`
+	ExpireAt time.Time `json:"expire_at"`
+}
+
+func (q *Queries) RevokeToken(ctx context.Context, arg RevokeTokenParams) error {
+	_, err := q.db.Exec(ctx, revokeToken, arg.ID, arg.UserID, arg.ExpireAt)
+	return err
+}
+
+const revokeUserTokens = `-- name: RevokeUserTokens :exec
+INSERT INTO user_token_revocations (
+  user_id,
+  revoked_before
+) VALUES (
+  $1, $2
+) ON CONFLICT (user_id) DO UPDATE
+SET revoked_before = EXCLUDED.revoked_before
+`
+
+type RevokeUserTokensParams struct {
+	UserID        int64     `json:"user_id"`
+	RevokedBefore time.Time `json:"revoked_before"`
+}
+
+func (q *Queries) RevokeUserTokens(ctx context.Context, arg RevokeUserTokensParams) error {
+	_, err := q.db.Exec(ctx, revokeUserTokens, arg.UserID, arg.RevokedBefore)
+	return err
+}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokeToken(t *testing.T) {
+	user := createRandomUser(t)
+	id, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	arg := IsTokenRevokedParams{
+		ID:       id,
+		UserID:   user.ID,
+		IssuedAt: time.Now(),
+	}
+
+	revoked, err := testStore.IsTokenRevoked(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	err = testStore.RevokeToken(context.Background(), RevokeTokenParams{
+		ID:       id,
+		UserID:   user.ID,
+		ExpireAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	revoked, err = testStore.IsTokenRevoked(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, revoked)
+}
+
+func TestRevokeTokenPrunesExpired(t *testing.T) {
+	user := createRandomUser(t)
+	expiredID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	err = testStore.RevokeToken(context.Background(), RevokeTokenParams{
+		ID:       expiredID,
+		UserID:   user.ID,
+		ExpireAt: time.Now().Add(-time.Hour),
+	})
+	require.NoError(t, err)
+
+	id, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	err = testStore.RevokeToken(context.Background(), RevokeTokenParams{
+		ID:       id,
+		UserID:   user.ID,
+		ExpireAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	revoked, err := testStore.IsTokenRevoked(context.Background(), IsTokenRevokedParams{
+		ID:       expiredID,
+		UserID:   user.ID,
+		IssuedAt: time.Now(),
+	})
+	require.NoError(t, err)
+	require.False(t, revoked)
+}
+
+func TestRevokeUserTokens(t *testing.T) {
+	user := createRandomUser(t)
+	id, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	issuedAt := time.Now()
+	err = testStore.RevokeUserTokens(context.Background(), RevokeUserTokensParams{
+		UserID:        user.ID,
+		RevokedBefore: issuedAt.Add(time.Second),
+	})
+	require.NoError(t, err)
+
+	revoked, err := testStore.IsTokenRevoked(context.Background(), IsTokenRevokedParams{
+		ID:       id,
+		UserID:   user.ID,
+		IssuedAt: issuedAt,
+	})
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	revoked, err = testStore.IsTokenRevoked(context.Background(), IsTokenRevokedParams{
+		ID:       id,
+		UserID:   user.ID,
+		IssuedAt: issuedAt.Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.False(t, revoked)
+}
+
+func TestIsTokenRevokedForDeletedUser(t *testing.T) {
+	user := createRandomUser(t)
+	arg := IsTokenRevokedParams{
+		ID:       uuid.New(),
+		UserID:   user.ID,
+		IssuedAt: time.Now(),
+	}
+
+	_, err := testStore.DeleteUser(context.Background(), user.ID)
+	require.NoError(t, err)
+
+	revoked, err := testStore.IsTokenRevoked(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, revoked)
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomSession(t *testing.T) Session {
+	id, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	arg := CreateSessionParams{
+		ID:           id,
+		UserID:       createRandomUser(t).ID,
+		RefreshToken: util.RandomAlphaNumString(64),
+		UserAgent:    util.RandomPrintableString(20),
+		ClientIp:     "127.0.0.1",
+		IsBlocked:    false,
+		ExpireAt:     time.Now().Add(time.Hour),
+	}
+
+	session, err := testStore.CreateSession(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, session)
+
+	require.Equal(t, arg.ID, session.ID)
+	require.Equal(t, arg.UserID, session.UserID)
+	require.Equal(t, arg.RefreshToken, session.RefreshToken)
+	require.Equal(t, arg.UserAgent, session.UserAgent)
+	require.Equal(t, arg.ClientIp, session.ClientIp)
+	require.Equal(t, arg.IsBlocked, session.IsBlocked)
+	require.WithinDuration(t, arg.ExpireAt, session.ExpireAt, time.Second)
+
+	require.NotZero(t, session.CreatedAt)
+
+	return session
+}
+
+func TestCreateSession(t *testing.T) {
+	createRandomSession(t)
+}
+
+func TestGetSession(t *testing.T) {
+	session1 := createRandomSession(t)
+	session2, err := testStore.GetSession(context.Background(), session1.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, session2)
+	require.Equal(t, session1, session2)
+}
+
+func TestBlockSession(t *testing.T) {
+	session1 := createRandomSession(t)
+	err := testStore.BlockSession(context.Background(), session1.ID)
+	require.NoError(t, err)
+
+	session2, err := testStore.GetSession(context.Background(), session1.ID)
+	require.NoError(t, err)
+	require.True(t, session2.IsBlocked)
+}
+
+func TestBlockUserSessions(t *testing.T) {
+	session1 := createRandomSession(t)
+	err := testStore.BlockUserSessions(context.Background(), session1.UserID)
+	require.NoError(t, err)
+
+	session2, err := testStore.GetSession(context.Background(), session1.ID)
+	require.NoError(t, err)
+	require.True(t, session2.IsBlocked)
+}
+
+func TestBlockOtherUserSessions(t *testing.T) {
+	session1 := createRandomSession(t)
+
+	id, err := uuid.NewRandom()
+	require.NoError(t, err)
+	session2, err := testStore.CreateSession(context.Background(), CreateSessionParams{
+		ID:           id,
+		UserID:       session1.UserID,
+		RefreshToken: util.RandomAlphaNumString(64),
+		UserAgent:    util.RandomPrintableString(20),
+		ClientIp:     "127.0.0.1",
+		ExpireAt:     time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	err = testStore.BlockOtherUserSessions(context.Background(), BlockOtherUserSessionsParams{
+		UserID:           session1.UserID,
+		CurrentSessionID: session1.ID,
+	})
+	require.NoError(t, err)
+
+	session1, err = testStore.GetSession(context.Background(), session1.ID)
+	require.NoError(t, err)
+	require.False(t, session1.IsBlocked)
+
+	session2, err = testStore.GetSession(context.Background(), session2.ID)
+	require.NoError(t, err)
+	require.True(t, session2.IsBlocked)
+}
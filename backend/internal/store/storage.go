@@ -1,11 +1,17 @@
 package store
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Storage interface {
 	Querier
+	// ExecTx runs fn with queries bound to a single transaction, which is
+	// committed when fn succeeds and rolled back otherwise.
+	ExecTx(ctx context.Context, fn func(Querier) error) error
 	Health() map[string]string
 }
 
@@ -20,3 +26,19 @@ func NewStorage(connPool *pgxpool.Pool) Storage {
 		Queries:  New(connPool),
 	}
 }
+
+func (s *SQLStorage) ExecTx(ctx context.Context, fn func(Querier) error) error {
+	tx, err := s.connPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(s.WithTx(tx)); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
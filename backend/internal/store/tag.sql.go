@@ -0,0 +1,281 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: tag.sql
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createTag = `-- name: CreateTag :one
+INSERT INTO tags (
+  user_id,
+  name,
+  color
+) VALUES (
+  $1, $2, $3
+) RETURNING id, user_id, name, color, created_at
+`
+
+type CreateTagParams struct {
+	UserID int64  `json:"user_id"`
+	Name   string `json:"name"`
+	Color  string `json:"color"`
+}
+
+func (q *Queries) CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error) {
+	row := q.db.QueryRow(ctx, createTag, arg.UserID, arg.Name, arg.Color)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Color,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteTag = `-- name: DeleteTag :exec
+DELETE FROM tags
+WHERE id = $1
+`
+
+func (q *Queries) DeleteTag(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, deleteTag, id)
+	return err
+}
+
+const getTag = `-- name: GetTag :one
+SELECT id, user_id, name, color, created_at FROM tags
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetTag(ctx context.Context, id int64) (Tag, error) {
+	row := q.db.QueryRow(ctx, getTag, id)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Color,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserTagsByIDs = `-- name: GetUserTagsByIDs :many
+SELECT id, user_id, name, color, created_at FROM tags
+WHERE
+  user_id = $1
+  AND id = ANY($2::bigint[])
+`
+
+type GetUserTagsByIDsParams struct {
+	UserID int64   `json:"user_id"`
+	Ids    []int64 `json:"ids"`
+}
+
+func (q *Queries) GetUserTagsByIDs(ctx context.Context, arg GetUserTagsByIDsParams) ([]Tag, error) {
+	rows, err := q.db.Query(ctx, getUserTagsByIDs, arg.UserID, arg.Ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Tag{}
+	for rows.Next() {
+		var i Tag
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Color,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTags = `-- name: ListTags :many
+SELECT
+  tags.id, tags.user_id, tags.name, tags.color, tags.created_at,
+  COUNT(task_tags.task_id) AS task_count
+FROM tags
+LEFT JOIN task_tags ON task_tags.tag_id = tags.id
+WHERE tags.user_id = $1
+GROUP BY tags.id
+ORDER BY lower(tags.name) ASC
+`
+
+type ListTagsRow struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+	TaskCount int64     `json:"task_count"`
+}
+
+func (q *Queries) ListTags(ctx context.Context, userID int64) ([]ListTagsRow, error) {
+	rows, err := q.db.Query(ctx, listTags, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTagsRow{}
+	for rows.Next() {
+		var i ListTagsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Color,
+			&i.CreatedAt,
+			&i.TaskCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTagsForTasks = `-- name: ListTagsForTasks :many
+SELECT
+  task_tags.task_id,
+  tags.id, tags.user_id, tags.name, tags.color, tags.created_at
+FROM task_tags
+JOIN tags ON tags.id = task_tags.tag_id
+WHERE task_tags.task_id = ANY($1::varchar[])
+ORDER BY lower(tags.name) ASC
+`
+
+type ListTagsForTasksRow struct {
+	TaskID    string    `json:"task_id"`
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) ListTagsForTasks(ctx context.Context, taskIds []string) ([]ListTagsForTasksRow, error) {
+	rows, err := q.db.Query(ctx, listTagsForTasks, taskIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTagsForTasksRow{}
+	for rows.Next() {
+		var i ListTagsForTasksRow
+		if err := rows.Scan(
+			&i.TaskID,
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Color,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const mergeTag = `-- name: MergeTag :execrows
+WITH moved AS (
+  INSERT INTO task_tags (task_id, tag_id)
+  SELECT task_id, $2::bigint
+  FROM task_tags
+  WHERE tag_id = $1::bigint
+  ON CONFLICT DO NOTHING
+)
+DELETE FROM tags
+WHERE id = $1::bigint
+`
+
+type MergeTagParams struct {
+	SourceID int64 `json:"source_id"`
+	TargetID int64 `json:"target_id"`
+}
+
+// MergeTag moves the tasks of the source tag to the target tag and deletes the
+// source tag in a single statement. Tasks that already have both tags keep a
+// single assignment.
+func (q *Queries) MergeTag(ctx context.Context, arg MergeTagParams) (int64, error) {
+	result, err := q.db.Exec(ctx, mergeTag, arg.SourceID, arg.TargetID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setTaskTags = `-- name: SetTaskTags :exec
+WITH removed AS (
+  DELETE FROM task_tags
+  WHERE
+    task_tags.task_id = $1
+    AND task_tags.tag_id <> ALL($2::bigint[])
+)
+INSERT INTO task_tags (task_id, tag_id)
+SELECT $1, unnest($2::bigint[])
+ON CONFLICT DO NOTHING
+`
+
+type SetTaskTagsParams struct {
+	TaskID string  `json:"task_id"`
+	TagIds []int64 `json:"tag_ids"`
+}
+
+// SetTaskTags replaces the tags of a task with the given ones.
+func (q *Queries) SetTaskTags(ctx context.Context, arg SetTaskTagsParams) error {
+	_, err := q.db.Exec(ctx, setTaskTags, arg.TaskID, arg.TagIds)
+	return err
+}
+
+const updateTag = `-- name: UpdateTag :one
+UPDATE tags
+SET
+  name = COALESCE($2, name),
+  color = COALESCE($3, color)
+WHERE
+  id = $1
+RETURNING id, user_id, name, color, created_at
+`
+
+type UpdateTagParams struct {
+	ID    int64       `json:"id"`
+	Name  pgtype.Text `json:"name"`
+	Color pgtype.Text `json:"color"`
+}
+
+func (q *Queries) UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, error) {
+	row := q.db.QueryRow(ctx, updateTag, arg.ID, arg.Name, arg.Color)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Color,
+		&i.CreatedAt,
+	)
+	return i, err
+}
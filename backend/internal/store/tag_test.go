@@ -0,0 +1,206 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTag(t *testing.T, userID int64) Tag {
+	arg := CreateTagParams{
+		UserID: userID,
+		Name:   util.RandomAlphabetString(10),
+		Color:  "#1e90ff",
+	}
+
+	tag, err := testStore.CreateTag(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, tag.ID)
+	require.Equal(t, arg.UserID, tag.UserID)
+	require.Equal(t, arg.Name, tag.Name)
+	require.Equal(t, arg.Color, tag.Color)
+	require.NotZero(t, tag.CreatedAt)
+
+	return tag
+}
+
+func TestCreateTagNameIsCaseInsensitiveUnique(t *testing.T) {
+	tag := createRandomTag(t, createRandomUser(t).ID)
+
+	_, err := testStore.CreateTag(context.Background(), CreateTagParams{
+		UserID: tag.UserID,
+		Name:   strings.ToUpper(tag.Name),
+		Color:  tag.Color,
+	})
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+
+	// Other users may use the same name.
+	other, err := testStore.CreateTag(context.Background(), CreateTagParams{
+		UserID: createRandomUser(t).ID,
+		Name:   tag.Name,
+		Color:  tag.Color,
+	})
+	require.NoError(t, err)
+	require.Equal(t, tag.Name, other.Name)
+}
+
+func TestUpdateTag(t *testing.T) {
+	tag := createRandomTag(t, createRandomUser(t).ID)
+
+	updated, err := testStore.UpdateTag(context.Background(), UpdateTagParams{
+		ID:    tag.ID,
+		Color: pgtype.Text{String: "#000000", Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, tag.Name, updated.Name)
+	require.Equal(t, "#000000", updated.Color)
+}
+
+func TestSetTaskTagsAndListTags(t *testing.T) {
+	task := createRandomTask(t)
+	tagA := createRandomTag(t, task.CreatorID)
+	tagB := createRandomTag(t, task.CreatorID)
+	tagC := createRandomTag(t, task.CreatorID)
+
+	err := testStore.SetTaskTags(context.Background(), SetTaskTagsParams{
+		TaskID: task.ID,
+		TagIds: []int64{tagA.ID, tagB.ID},
+	})
+	require.NoError(t, err)
+
+	err = testStore.SetTaskTags(context.Background(), SetTaskTagsParams{
+		TaskID: task.ID,
+		TagIds: []int64{tagB.ID, tagC.ID},
+	})
+	require.NoError(t, err)
+
+	rows, err := testStore.ListTagsForTasks(context.Background(), []string{task.ID})
+	require.NoError(t, err)
+	tagIDs := make([]int64, len(rows))
+	for i, row := range rows {
+		require.Equal(t, task.ID, row.TaskID)
+		tagIDs[i] = row.ID
+	}
+	require.ElementsMatch(t, []int64{tagB.ID, tagC.ID}, tagIDs)
+
+	tags, err := testStore.ListTags(context.Background(), task.CreatorID)
+	require.NoError(t, err)
+	require.Len(t, tags, 3)
+	for _, tag := range tags {
+		if tag.ID == tagA.ID {
+			require.Zero(t, tag.TaskCount)
+		} else {
+			require.Equal(t, int64(1), tag.TaskCount)
+		}
+	}
+
+	err = testStore.SetTaskTags(context.Background(), SetTaskTagsParams{
+		TaskID: task.ID,
+		TagIds: []int64{},
+	})
+	require.NoError(t, err)
+
+	rows, err = testStore.ListTagsForTasks(context.Background(), []string{task.ID})
+	require.NoError(t, err)
+	require.Empty(t, rows)
+}
+
+func TestGetUserTagsByIDs(t *testing.T) {
+	tag := createRandomTag(t, createRandomUser(t).ID)
+	otherTag := createRandomTag(t, createRandomUser(t).ID)
+
+	tags, err := testStore.GetUserTagsByIDs(context.Background(), GetUserTagsByIDsParams{
+		UserID: tag.UserID,
+		Ids:    []int64{tag.ID, otherTag.ID},
+	})
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	require.Equal(t, tag.ID, tags[0].ID)
+}
+
+func TestMergeTag(t *testing.T) {
+	taskA := createRandomTask(t)
+	taskB := createRandomTask(t)
+	source := createRandomTag(t, taskA.CreatorID)
+	target := createRandomTag(t, taskA.CreatorID)
+
+	for _, arg := range []SetTaskTagsParams{
+		{TaskID: taskA.ID, TagIds: []int64{source.ID, target.ID}},
+		{TaskID: taskB.ID, TagIds: []int64{source.ID}},
+	} {
+		require.NoError(t, testStore.SetTaskTags(context.Background(), arg))
+	}
+
+	rows, err := testStore.MergeTag(context.Background(), MergeTagParams{
+		SourceID: source.ID,
+		TargetID: target.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	_, err = testStore.GetTag(context.Background(), source.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	tagRows, err := testStore.ListTagsForTasks(context.Background(), []string{taskA.ID, taskB.ID})
+	require.NoError(t, err)
+	require.Len(t, tagRows, 2)
+	for _, row := range tagRows {
+		require.Equal(t, target.ID, row.ID)
+	}
+
+	rows, err = testStore.MergeTag(context.Background(), MergeTagParams{
+		SourceID: source.ID,
+		TargetID: target.ID,
+	})
+	require.NoError(t, err)
+	require.Zero(t, rows)
+}
+
+func TestGetTasksByTags(t *testing.T) {
+	both := createRandomTask(t)
+	creatorID := both.CreatorID
+	tagA := createRandomTag(t, creatorID)
+	tagB := createRandomTag(t, creatorID)
+
+	onlyA, err := testStore.CreateTask(context.Background(), CreateTaskParams{
+		ID:        both.ID + "a",
+		Title:     util.RandomPrintableString(50),
+		CreatorID: creatorID,
+		Deadline:  both.Deadline,
+		Priority:  util.PriorityNone,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, testStore.SetTaskTags(context.Background(), SetTaskTagsParams{
+		TaskID: both.ID,
+		TagIds: []int64{tagA.ID, tagB.ID},
+	}))
+	require.NoError(t, testStore.SetTaskTags(context.Background(), SetTaskTagsParams{
+		TaskID: onlyA.ID,
+		TagIds: []int64{tagA.ID},
+	}))
+
+	names := []string{strings.ToLower(tagA.Name), strings.ToLower(tagB.Name)}
+
+	tasks, err := testStore.GetTasks(context.Background(), GetTasksParams{
+		UserID: creatorID,
+		Limit:  10,
+		Tags:   names,
+	})
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+
+	tasks, err = testStore.GetTasks(context.Background(), GetTasksParams{
+		UserID:       creatorID,
+		Limit:        10,
+		Tags:         names,
+		MatchAllTags: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	require.Equal(t, both.ID, tasks[0].ID)
+}
@@ -18,10 +18,15 @@ INSERT INTO tasks (
   creator_id,
   title,
   description,
-  deadline
+  deadline,
+  priority,
+  parent_id,
+  project_id,
+  workspace_id,
+  assignee_id
 ) VALUES (
-  $1, $2, $3, $4, $5
-) RETURNING id, title, description, creator_id, deadline, completed, created_at
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING id, title, description, creator_id, deadline, completed, created_at, priority, parent_id, recurrence_id, occurrence, project_id, workspace_id, assignee_id
 `
 
 type CreateTaskParams struct {
@@ -30,6 +35,11 @@ type CreateTaskParams struct {
 	Title       string      `json:"title"`
 	Description pgtype.Text `json:"description"`
 	Deadline    time.Time   `json:"deadline"`
+	Priority    string      `json:"priority"`
+	ParentID    pgtype.Text `json:"parent_id"`
+	ProjectID   pgtype.Int8 `json:"project_id"`
+	WorkspaceID pgtype.Int8 `json:"workspace_id"`
+	AssigneeID  pgtype.Int8 `json:"assignee_id"`
 }
 
 func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error) {
@@ -39,6 +49,11 @@ func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, e
 		arg.Title,
 		arg.Description,
 		arg.Deadline,
+		arg.Priority,
+		arg.ParentID,
+		arg.ProjectID,
+		arg.WorkspaceID,
+		arg.AssigneeID,
 	)
 	var i Task
 	err := row.Scan(
@@ -49,6 +64,13 @@ func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, e
 		&i.Deadline,
 		&i.Completed,
 		&i.CreatedAt,
+		&i.Priority,
+		&i.ParentID,
+		&i.RecurrenceID,
+		&i.Occurrence,
+		&i.ProjectID,
+		&i.WorkspaceID,
+		&i.AssigneeID,
 	)
 	return i, err
 }
@@ -63,8 +85,46 @@ func (q *Queries) DeleteTask(ctx context.Context, id string) error {
 	return err
 }
 
+const getSubtaskProgress = `-- name: GetSubtaskProgress :many
+SELECT
+  parent_id::varchar AS task_id,
+  COUNT(*) AS total,
+  COUNT(*) FILTER (WHERE completed) AS completed
+FROM tasks
+WHERE parent_id = ANY($1::varchar[])
+GROUP BY parent_id
+`
+
+type GetSubtaskProgressRow struct {
+	TaskID    string `json:"task_id"`
+	Total     int64  `json:"total"`
+	Completed int64  `json:"completed"`
+}
+
+// GetSubtaskProgress counts the direct subtasks of each of the given tasks.
+// Tasks without subtasks have no row.
+func (q *Queries) GetSubtaskProgress(ctx context.Context, taskIds []string) ([]GetSubtaskProgressRow, error) {
+	rows, err := q.db.Query(ctx, getSubtaskProgress, taskIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetSubtaskProgressRow{}
+	for rows.Next() {
+		var i GetSubtaskProgressRow
+		if err := rows.Scan(&i.TaskID, &i.Total, &i.Completed); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTaskByID = `-- name: GetTaskByID :one
-SELECT id, title, description, creator_id, deadline, completed, created_at FROM tasks
+SELECT id, title, description, creator_id, deadline, completed, created_at, priority, parent_id, recurrence_id, occurrence, project_id, workspace_id, assignee_id FROM tasks
 WHERE id = $1 LIMIT 1
 `
 
@@ -79,17 +139,61 @@ func (q *Queries) GetTaskByID(ctx context.Context, id string) (Task, error) {
 		&i.Deadline,
 		&i.Completed,
 		&i.CreatedAt,
+		&i.Priority,
+		&i.ParentID,
+		&i.RecurrenceID,
+		&i.Occurrence,
+		&i.ProjectID,
+		&i.WorkspaceID,
+		&i.AssigneeID,
 	)
 	return i, err
 }
 
 const getTasks = `-- name: GetTasks :many
 SELECT 
-  id, title, description, creator_id, deadline, completed, created_at,
+  id, title, description, creator_id, deadline, completed, created_at, priority, parent_id, recurrence_id, occurrence, project_id, workspace_id, assignee_id,
+  EXISTS (
+    SELECT 1 FROM task_dependencies
+    JOIN tasks AS blockers ON blockers.id = task_dependencies.blocked_by_id
+    WHERE
+      task_dependencies.task_id = tasks.id
+      AND NOT blockers.completed
+  ) AS blocked,
   COUNT(*) OVER() AS total
 FROM tasks
 WHERE 
-  creator_id = $1
+  -- Without a workspace, the personal tasks the user can see: those they
+  -- created and those shared with or assigned to them. In a workspace, which
+  -- the user must be a member of, all of its tasks, the ones created by
+  -- others counting as shared. shared is NULL for both kinds of tasks, false
+  -- for the tasks the user created and true for the others.
+  (
+    CASE
+      WHEN $1::bigint IS NULL THEN tasks.workspace_id IS NULL
+      ELSE tasks.workspace_id = $1::bigint
+    END
+  )
+  AND (
+    (
+      $2::bool IS NOT TRUE
+      AND tasks.creator_id = $3::bigint
+    )
+    OR (
+      $2::bool IS NOT FALSE
+      AND tasks.creator_id <> $3::bigint
+      AND (
+        tasks.workspace_id IS NOT NULL
+        OR tasks.assignee_id = $3::bigint
+        OR EXISTS (
+          SELECT 1 FROM task_shares
+          WHERE
+            task_shares.task_id = tasks.id
+            AND task_shares.user_id = $3::bigint
+        )
+      )
+    )
+  )
   AND (
     title ILIKE '%' || COALESCE($4, '') || '%'
     OR 
@@ -107,42 +211,109 @@ WHERE
     $8::bool IS NULL 
     OR completed = $8::bool
   )
-  ORDER BY completed ASC, deadline ASC
-  LIMIT $2 OFFSET $3
+  AND (
+    $9::bigint IS NULL
+    OR project_id = $9
+  )
+  AND (
+    $10::bigint IS NULL
+    OR assignee_id = $10
+  )
+  AND (
+    NOT $11::bool
+    OR assignee_id IS NULL
+  )
+  AND (
+    $12::varchar[] IS NULL
+    OR priority = ANY($12::varchar[])
+  )
+  AND (
+    $13::varchar[] IS NULL
+    OR (
+      SELECT COUNT(DISTINCT lower(tags.name))
+      FROM task_tags
+      JOIN tags ON tags.id = task_tags.tag_id
+      WHERE
+        task_tags.task_id = tasks.id
+        AND lower(tags.name) = ANY($13::varchar[])
+    ) >= CASE
+      WHEN $14::bool THEN cardinality($13::varchar[])
+      ELSE 1
+    END
+  )
+  ORDER BY
+    completed ASC,
+    CASE WHEN $15::bool THEN
+      CASE priority
+        WHEN 'urgent' THEN 4
+        WHEN 'high' THEN 3
+        WHEN 'medium' THEN 2
+        WHEN 'low' THEN 1
+        ELSE 0
+      END
+    END DESC,
+    deadline ASC
+  LIMIT $17::int OFFSET $16::int
 `
 
 type GetTasksParams struct {
-	CreatorID     int64              `json:"creator_id"`
-	Limit         int32              `json:"limit"`
-	Offset        int32              `json:"offset"`
-	Title         pgtype.Text        `json:"title"`
-	Description   pgtype.Text        `json:"description"`
-	StartDeadline pgtype.Timestamptz `json:"start_deadline"`
-	EndDeadline   pgtype.Timestamptz `json:"end_deadline"`
-	Completed     pgtype.Bool        `json:"completed"`
+	WorkspaceID    pgtype.Int8        `json:"workspace_id"`
+	Shared         pgtype.Bool        `json:"shared"`
+	UserID         int64              `json:"user_id"`
+	Title          pgtype.Text        `json:"title"`
+	Description    pgtype.Text        `json:"description"`
+	StartDeadline  pgtype.Timestamptz `json:"start_deadline"`
+	EndDeadline    pgtype.Timestamptz `json:"end_deadline"`
+	Completed      pgtype.Bool        `json:"completed"`
+	ProjectID      pgtype.Int8        `json:"project_id"`
+	AssigneeID     pgtype.Int8        `json:"assignee_id"`
+	Unassigned     bool               `json:"unassigned"`
+	Priorities     []string           `json:"priorities"`
+	Tags           []string           `json:"tags"`
+	MatchAllTags   bool               `json:"match_all_tags"`
+	SortByPriority bool               `json:"sort_by_priority"`
+	Offset         int32              `json:"offset"`
+	Limit          int32              `json:"limit"`
 }
 
 type GetTasksRow struct {
-	ID          string      `json:"id"`
-	Title       string      `json:"title"`
-	Description pgtype.Text `json:"description"`
-	CreatorID   int64       `json:"creator_id"`
-	Deadline    time.Time   `json:"deadline"`
-	Completed   bool        `json:"completed"`
-	CreatedAt   time.Time   `json:"created_at"`
-	Total       int64       `json:"total"`
+	ID           string      `json:"id"`
+	Title        string      `json:"title"`
+	Description  pgtype.Text `json:"description"`
+	CreatorID    int64       `json:"creator_id"`
+	Deadline     time.Time   `json:"deadline"`
+	Completed    bool        `json:"completed"`
+	CreatedAt    time.Time   `json:"created_at"`
+	Priority     string      `json:"priority"`
+	ParentID     pgtype.Text `json:"parent_id"`
+	RecurrenceID pgtype.Int8 `json:"recurrence_id"`
+	Occurrence   pgtype.Int4 `json:"occurrence"`
+	ProjectID    pgtype.Int8 `json:"project_id"`
+	WorkspaceID  pgtype.Int8 `json:"workspace_id"`
+	AssigneeID   pgtype.Int8 `json:"assignee_id"`
+	Blocked      bool        `json:"blocked"`
+	Total        int64       `json:"total"`
 }
 
 func (q *Queries) GetTasks(ctx context.Context, arg GetTasksParams) ([]GetTasksRow, error) {
 	rows, err := q.db.Query(ctx, getTasks,
-		arg.CreatorID,
-		arg.Limit,
-		arg.Offset,
+		arg.WorkspaceID,
+		arg.Shared,
+		arg.UserID,
 		arg.Title,
 		arg.Description,
 		arg.StartDeadline,
 		arg.EndDeadline,
 		arg.Completed,
+		arg.ProjectID,
+		arg.AssigneeID,
+		arg.Unassigned,
+		arg.Priorities,
+		arg.Tags,
+		arg.MatchAllTags,
+		arg.SortByPriority,
+		arg.Offset,
+		arg.Limit,
 	)
 	if err != nil {
 		return nil, err
@@ -159,6 +330,14 @@ func (q *Queries) GetTasks(ctx context.Context, arg GetTasksParams) ([]GetTasksR
 			&i.Deadline,
 			&i.Completed,
 			&i.CreatedAt,
+			&i.Priority,
+			&i.ParentID,
+			&i.RecurrenceID,
+			&i.Occurrence,
+			&i.ProjectID,
+			&i.WorkspaceID,
+			&i.AssigneeID,
+			&i.Blocked,
 			&i.Total,
 		); err != nil {
 			return nil, err
@@ -171,16 +350,130 @@ func (q *Queries) GetTasks(ctx context.Context, arg GetTasksParams) ([]GetTasksR
 	return items, nil
 }
 
+const listSubtasks = `-- name: ListSubtasks :many
+SELECT id, title, description, creator_id, deadline, completed, created_at, priority, parent_id, recurrence_id, occurrence, project_id, workspace_id, assignee_id FROM tasks
+WHERE parent_id = $1
+ORDER BY completed ASC, deadline ASC
+`
+
+func (q *Queries) ListSubtasks(ctx context.Context, parentID pgtype.Text) ([]Task, error) {
+	rows, err := q.db.Query(ctx, listSubtasks, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.CreatorID,
+			&i.Deadline,
+			&i.Completed,
+			&i.CreatedAt,
+			&i.Priority,
+			&i.ParentID,
+			&i.RecurrenceID,
+			&i.Occurrence,
+			&i.ProjectID,
+			&i.WorkspaceID,
+			&i.AssigneeID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveTask = `-- name: MoveTask :one
+UPDATE tasks
+SET parent_id = $1
+WHERE id = $2
+RETURNING id, title, description, creator_id, deadline, completed, created_at, priority, parent_id, recurrence_id, occurrence, project_id, workspace_id, assignee_id
+`
+
+type MoveTaskParams struct {
+	ParentID pgtype.Text `json:"parent_id"`
+	ID       string      `json:"id"`
+}
+
+// MoveTask makes a task a subtask of another one, or a top-level task when
+// parent_id is NULL.
+func (q *Queries) MoveTask(ctx context.Context, arg MoveTaskParams) (Task, error) {
+	row := q.db.QueryRow(ctx, moveTask, arg.ParentID, arg.ID)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.CreatorID,
+		&i.Deadline,
+		&i.Completed,
+		&i.CreatedAt,
+		&i.Priority,
+		&i.ParentID,
+		&i.RecurrenceID,
+		&i.Occurrence,
+		&i.ProjectID,
+		&i.WorkspaceID,
+		&i.AssigneeID,
+	)
+	return i, err
+}
+
+const setTaskAssignee = `-- name: SetTaskAssignee :one
+UPDATE tasks
+SET assignee_id = $1
+WHERE id = $2
+RETURNING id, title, description, creator_id, deadline, completed, created_at, priority, parent_id, recurrence_id, occurrence, project_id, workspace_id, assignee_id
+`
+
+type SetTaskAssigneeParams struct {
+	AssigneeID pgtype.Int8 `json:"assignee_id"`
+	ID         string      `json:"id"`
+}
+
+// SetTaskAssignee assigns a task to a user, or unassigns it when assignee_id
+// is NULL.
+func (q *Queries) SetTaskAssignee(ctx context.Context, arg SetTaskAssigneeParams) (Task, error) {
+	row := q.db.QueryRow(ctx, setTaskAssignee, arg.AssigneeID, arg.ID)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.CreatorID,
+		&i.Deadline,
+		&i.Completed,
+		&i.CreatedAt,
+		&i.Priority,
+		&i.ParentID,
+		&i.RecurrenceID,
+		&i.Occurrence,
+		&i.ProjectID,
+		&i.WorkspaceID,
+		&i.AssigneeID,
+	)
+	return i, err
+}
+
 const updateTask = `-- name: UpdateTask :one
 UPDATE tasks
 SET
   title = COALESCE($2, title),
   description = COALESCE($3, description),
   deadline = COALESCE($4, deadline),
-  completed = COALESCE($5, completed)
+  completed = COALESCE($5, completed),
+  priority = COALESCE($6, priority)
 WHERE
   id = $1
-RETURNING id, title, description, creator_id, deadline, completed, created_at
+RETURNING id, title, description, creator_id, deadline, completed, created_at, priority, parent_id, recurrence_id, occurrence, project_id, workspace_id, assignee_id
 `
 
 type UpdateTaskParams struct {
@@ -189,6 +482,7 @@ type UpdateTaskParams struct {
 	Description pgtype.Text        `json:"description"`
 	Deadline    pgtype.Timestamptz `json:"deadline"`
 	Completed   pgtype.Bool        `json:"completed"`
+	Priority    pgtype.Text        `json:"priority"`
 }
 
 func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, error) {
@@ -198,6 +492,7 @@ func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, e
 		arg.Description,
 		arg.Deadline,
 		arg.Completed,
+		arg.Priority,
 	)
 	var i Task
 	err := row.Scan(
@@ -208,6 +503,13 @@ func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, e
 		&i.Deadline,
 		&i.Completed,
 		&i.CreatedAt,
+		&i.Priority,
+		&i.ParentID,
+		&i.RecurrenceID,
+		&i.Occurrence,
+		&i.ProjectID,
+		&i.WorkspaceID,
+		&i.AssigneeID,
 	)
 	return i, err
 }
@@ -0,0 +1,107 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: task_dependency.sql
+
+package store
+
+import (
+	"context"
+)
+
+const addTaskDependency = `-- name: AddTaskDependency :exec
+INSERT INTO task_dependencies (
+  task_id,
+  blocked_by_id
+) VALUES (
+  $1, $2
+) ON CONFLICT DO NOTHING
+`
+
+type AddTaskDependencyParams struct {
+	TaskID      string `json:"task_id"`
+	BlockedByID string `json:"blocked_by_id"`
+}
+
+func (q *Queries) AddTaskDependency(ctx context.Context, arg AddTaskDependencyParams) error {
+	_, err := q.db.Exec(ctx, addTaskDependency, arg.TaskID, arg.BlockedByID)
+	return err
+}
+
+const countOpenBlockers = `-- name: CountOpenBlockers :one
+SELECT COUNT(*) FILTER (WHERE NOT blockers.completed) FROM (
+  SELECT tasks.completed FROM task_dependencies
+  JOIN tasks ON tasks.id = task_dependencies.blocked_by_id
+  WHERE task_dependencies.task_id = $1
+  FOR SHARE OF tasks
+) AS blockers
+`
+
+// The blockers stay locked until the end of the transaction, so that none of
+// them can be reopened while the task is being completed.
+func (q *Queries) CountOpenBlockers(ctx context.Context, taskID string) (int64, error) {
+	row := q.db.QueryRow(ctx, countOpenBlockers, taskID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const listTaskBlockers = `-- name: ListTaskBlockers :many
+SELECT tasks.id, tasks.title, tasks.description, tasks.creator_id, tasks.deadline, tasks.completed, tasks.created_at, tasks.priority, tasks.parent_id, tasks.recurrence_id, tasks.occurrence, tasks.project_id, tasks.workspace_id, tasks.assignee_id FROM task_dependencies
+JOIN tasks ON tasks.id = task_dependencies.blocked_by_id
+WHERE task_dependencies.task_id = $1
+ORDER BY tasks.completed ASC, tasks.deadline ASC
+`
+
+func (q *Queries) ListTaskBlockers(ctx context.Context, taskID string) ([]Task, error) {
+	rows, err := q.db.Query(ctx, listTaskBlockers, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.CreatorID,
+			&i.Deadline,
+			&i.Completed,
+			&i.CreatedAt,
+			&i.Priority,
+			&i.ParentID,
+			&i.RecurrenceID,
+			&i.Occurrence,
+			&i.ProjectID,
+			&i.WorkspaceID,
+			&i.AssigneeID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeTaskDependency = `-- name: RemoveTaskDependency :execrows
+DELETE FROM task_dependencies
+WHERE task_id = $1 AND blocked_by_id = $2
+`
+
+type RemoveTaskDependencyParams struct {
+	TaskID      string `json:"task_id"`
+	BlockedByID string `json:"blocked_by_id"`
+}
+
+func (q *Queries) RemoveTaskDependency(ctx context.Context, arg RemoveTaskDependencyParams) (int64, error) {
+	result, err := q.db.Exec(ctx, removeTaskDependency, arg.TaskID, arg.BlockedByID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
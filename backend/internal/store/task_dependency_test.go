@@ -0,0 +1,74 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskDependencies(t *testing.T) {
+	task := createRandomTask(t)
+	blocker := createSubtask(t, task)
+
+	arg := AddTaskDependencyParams{
+		TaskID:      task.ID,
+		BlockedByID: blocker.ID,
+	}
+	require.NoError(t, testStore.AddTaskDependency(context.Background(), arg))
+	// Adding the same dependency twice is a no-op.
+	require.NoError(t, testStore.AddTaskDependency(context.Background(), arg))
+
+	blockers, err := testStore.ListTaskBlockers(context.Background(), task.ID)
+	require.NoError(t, err)
+	require.Len(t, blockers, 1)
+	require.Equal(t, blocker.ID, blockers[0].ID)
+
+	openBlockers, err := testStore.CountOpenBlockers(context.Background(), task.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), openBlockers)
+
+	tasks, err := testStore.GetTasks(context.Background(), GetTasksParams{
+		UserID: task.CreatorID,
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	for _, row := range tasks {
+		require.Equal(t, row.ID == task.ID, row.Blocked)
+	}
+
+	_, err = testStore.UpdateTask(context.Background(), UpdateTaskParams{
+		ID:        blocker.ID,
+		Completed: pgtype.Bool{Bool: true, Valid: true},
+	})
+	require.NoError(t, err)
+
+	openBlockers, err = testStore.CountOpenBlockers(context.Background(), task.ID)
+	require.NoError(t, err)
+	require.Zero(t, openBlockers)
+
+	rows, err := testStore.RemoveTaskDependency(context.Background(), RemoveTaskDependencyParams(arg))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	rows, err = testStore.RemoveTaskDependency(context.Background(), RemoveTaskDependencyParams(arg))
+	require.NoError(t, err)
+	require.Zero(t, rows)
+}
+
+func TestTaskDependencyCycles(t *testing.T) {
+	a := createRandomTask(t)
+	b := createSubtask(t, a)
+	c := createSubtask(t, a)
+
+	err := testStore.AddTaskDependency(context.Background(), AddTaskDependencyParams{TaskID: a.ID, BlockedByID: a.ID})
+	require.Equal(t, CheckViolation, ErrorCode(err))
+
+	require.NoError(t, testStore.AddTaskDependency(context.Background(), AddTaskDependencyParams{TaskID: a.ID, BlockedByID: b.ID}))
+	require.NoError(t, testStore.AddTaskDependency(context.Background(), AddTaskDependencyParams{TaskID: b.ID, BlockedByID: c.ID}))
+
+	err = testStore.AddTaskDependency(context.Background(), AddTaskDependencyParams{TaskID: c.ID, BlockedByID: a.ID})
+	require.Equal(t, CheckViolation, ErrorCode(err))
+	require.Equal(t, "task_dependencies_cycle_check", ErrorConstraintName(err))
+}
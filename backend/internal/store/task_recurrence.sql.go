@@ -0,0 +1,348 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: task_recurrence.sql
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createRecurringTask = `-- name: CreateRecurringTask :one
+WITH recurrence AS (
+  INSERT INTO task_recurrences (
+    creator_id,
+    rule,
+    first_deadline,
+    timezone,
+    title,
+    description,
+    priority
+  ) VALUES (
+    $2,
+    $11,
+    $5,
+    $12,
+    $3,
+    $4,
+    $6
+  ) RETURNING id
+)
+INSERT INTO tasks (
+  id,
+  creator_id,
+  title,
+  description,
+  deadline,
+  priority,
+  parent_id,
+  project_id,
+  workspace_id,
+  assignee_id,
+  recurrence_id,
+  occurrence
+)
+SELECT
+  $1,
+  $2,
+  $3,
+  $4,
+  $5,
+  $6,
+  $7,
+  $8,
+  $9,
+  $10,
+  recurrence.id,
+  1
+FROM recurrence
+RETURNING id, title, description, creator_id, deadline, completed, created_at, priority, parent_id, recurrence_id, occurrence, project_id, workspace_id, assignee_id
+`
+
+type CreateRecurringTaskParams struct {
+	ID          string      `json:"id"`
+	CreatorID   int64       `json:"creator_id"`
+	Title       string      `json:"title"`
+	Description pgtype.Text `json:"description"`
+	Deadline    time.Time   `json:"deadline"`
+	Priority    string      `json:"priority"`
+	ParentID    pgtype.Text `json:"parent_id"`
+	ProjectID   pgtype.Int8 `json:"project_id"`
+	WorkspaceID pgtype.Int8 `json:"workspace_id"`
+	AssigneeID  pgtype.Int8 `json:"assignee_id"`
+	Rule        string      `json:"rule"`
+	Timezone    string      `json:"timezone"`
+}
+
+// CreateRecurringTask starts a series whose first occurrence is the new task.
+func (q *Queries) CreateRecurringTask(ctx context.Context, arg CreateRecurringTaskParams) (Task, error) {
+	row := q.db.QueryRow(ctx, createRecurringTask,
+		arg.ID,
+		arg.CreatorID,
+		arg.Title,
+		arg.Description,
+		arg.Deadline,
+		arg.Priority,
+		arg.ParentID,
+		arg.ProjectID,
+		arg.WorkspaceID,
+		arg.AssigneeID,
+		arg.Rule,
+		arg.Timezone,
+	)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.CreatorID,
+		&i.Deadline,
+		&i.Completed,
+		&i.CreatedAt,
+		&i.Priority,
+		&i.ParentID,
+		&i.RecurrenceID,
+		&i.Occurrence,
+		&i.ProjectID,
+		&i.WorkspaceID,
+		&i.AssigneeID,
+	)
+	return i, err
+}
+
+const createTaskOccurrence = `-- name: CreateTaskOccurrence :one
+INSERT INTO tasks (
+  id,
+  creator_id,
+  title,
+  description,
+  deadline,
+  priority,
+  parent_id,
+  project_id,
+  workspace_id,
+  assignee_id,
+  recurrence_id,
+  occurrence
+)
+SELECT
+  $1,
+  task_recurrences.creator_id,
+  task_recurrences.title,
+  task_recurrences.description,
+  $2,
+  task_recurrences.priority,
+  $3,
+  $4,
+  $5,
+  $6,
+  task_recurrences.id,
+  $7
+FROM task_recurrences
+WHERE task_recurrences.id = $8
+ON CONFLICT (recurrence_id, occurrence) DO NOTHING
+RETURNING id, title, description, creator_id, deadline, completed, created_at, priority, parent_id, recurrence_id, occurrence, project_id, workspace_id, assignee_id
+`
+
+type CreateTaskOccurrenceParams struct {
+	ID           string      `json:"id"`
+	Deadline     time.Time   `json:"deadline"`
+	ParentID     pgtype.Text `json:"parent_id"`
+	ProjectID    pgtype.Int8 `json:"project_id"`
+	WorkspaceID  pgtype.Int8 `json:"workspace_id"`
+	AssigneeID   pgtype.Int8 `json:"assignee_id"`
+	Occurrence   pgtype.Int4 `json:"occurrence"`
+	RecurrenceID int64       `json:"recurrence_id"`
+}
+
+// CreateTaskOccurrence returns no rows when the occurrence already exists,
+// for example because a completed occurrence was reopened and completed again.
+func (q *Queries) CreateTaskOccurrence(ctx context.Context, arg CreateTaskOccurrenceParams) (Task, error) {
+	row := q.db.QueryRow(ctx, createTaskOccurrence,
+		arg.ID,
+		arg.Deadline,
+		arg.ParentID,
+		arg.ProjectID,
+		arg.WorkspaceID,
+		arg.AssigneeID,
+		arg.Occurrence,
+		arg.RecurrenceID,
+	)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.CreatorID,
+		&i.Deadline,
+		&i.Completed,
+		&i.CreatedAt,
+		&i.Priority,
+		&i.ParentID,
+		&i.RecurrenceID,
+		&i.Occurrence,
+		&i.ProjectID,
+		&i.WorkspaceID,
+		&i.AssigneeID,
+	)
+	return i, err
+}
+
+const getTaskRecurrence = `-- name: GetTaskRecurrence :one
+SELECT id, creator_id, rule, first_deadline, timezone, title, description, priority, created_at FROM task_recurrences
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetTaskRecurrence(ctx context.Context, id int64) (TaskRecurrence, error) {
+	row := q.db.QueryRow(ctx, getTaskRecurrence, id)
+	var i TaskRecurrence
+	err := row.Scan(
+		&i.ID,
+		&i.CreatorID,
+		&i.Rule,
+		&i.FirstDeadline,
+		&i.Timezone,
+		&i.Title,
+		&i.Description,
+		&i.Priority,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const skipTaskOccurrence = `-- name: SkipTaskOccurrence :one
+UPDATE tasks
+SET
+  deadline = $1,
+  occurrence = $2
+WHERE id = $3
+RETURNING id, title, description, creator_id, deadline, completed, created_at, priority, parent_id, recurrence_id, occurrence, project_id, workspace_id, assignee_id
+`
+
+type SkipTaskOccurrenceParams struct {
+	Deadline   time.Time   `json:"deadline"`
+	Occurrence pgtype.Int4 `json:"occurrence"`
+	ID         string      `json:"id"`
+}
+
+// SkipTaskOccurrence turns an open occurrence into a later one of its series.
+func (q *Queries) SkipTaskOccurrence(ctx context.Context, arg SkipTaskOccurrenceParams) (Task, error) {
+	row := q.db.QueryRow(ctx, skipTaskOccurrence, arg.Deadline, arg.Occurrence, arg.ID)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.CreatorID,
+		&i.Deadline,
+		&i.Completed,
+		&i.CreatedAt,
+		&i.Priority,
+		&i.ParentID,
+		&i.RecurrenceID,
+		&i.Occurrence,
+		&i.ProjectID,
+		&i.WorkspaceID,
+		&i.AssigneeID,
+	)
+	return i, err
+}
+
+const splitTaskRecurrence = `-- name: SplitTaskRecurrence :one
+WITH recurrence AS (
+  INSERT INTO task_recurrences (
+    creator_id,
+    rule,
+    first_deadline,
+    timezone,
+    title,
+    description,
+    priority
+  )
+  SELECT
+    creator_id,
+    $2,
+    deadline,
+    $3,
+    title,
+    description,
+    priority
+  FROM tasks
+  WHERE id = $1
+  RETURNING id
+)
+UPDATE tasks
+SET
+  recurrence_id = recurrence.id,
+  occurrence = 1
+FROM recurrence
+WHERE tasks.id = $1
+RETURNING tasks.id, tasks.title, tasks.description, tasks.creator_id, tasks.deadline, tasks.completed, tasks.created_at, tasks.priority, tasks.parent_id, tasks.recurrence_id, tasks.occurrence, tasks.project_id, tasks.workspace_id, tasks.assignee_id
+`
+
+type SplitTaskRecurrenceParams struct {
+	ID       string `json:"id"`
+	Rule     string `json:"rule"`
+	Timezone string `json:"timezone"`
+}
+
+// SplitTaskRecurrence starts a new series at the task, which becomes its first
+// occurrence. Earlier occurrences stay with the old series.
+func (q *Queries) SplitTaskRecurrence(ctx context.Context, arg SplitTaskRecurrenceParams) (Task, error) {
+	row := q.db.QueryRow(ctx, splitTaskRecurrence, arg.ID, arg.Rule, arg.Timezone)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.CreatorID,
+		&i.Deadline,
+		&i.Completed,
+		&i.CreatedAt,
+		&i.Priority,
+		&i.ParentID,
+		&i.RecurrenceID,
+		&i.Occurrence,
+		&i.ProjectID,
+		&i.WorkspaceID,
+		&i.AssigneeID,
+	)
+	return i, err
+}
+
+const stopTaskRecurrence = `-- name: StopTaskRecurrence :one
+UPDATE tasks
+SET
+  recurrence_id = NULL,
+  occurrence = NULL
+WHERE id = $1
+RETURNING id, title, description, creator_id, deadline, completed, created_at, priority, parent_id, recurrence_id, occurrence, project_id, workspace_id, assignee_id
+`
+
+// StopTaskRecurrence detaches a task from its series so that completing it
+// no longer creates another occurrence.
+func (q *Queries) StopTaskRecurrence(ctx context.Context, id string) (Task, error) {
+	row := q.db.QueryRow(ctx, stopTaskRecurrence, id)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.CreatorID,
+		&i.Deadline,
+		&i.Completed,
+		&i.CreatedAt,
+		&i.Priority,
+		&i.ParentID,
+		&i.RecurrenceID,
+		&i.Occurrence,
+		&i.ProjectID,
+		&i.WorkspaceID,
+		&i.AssigneeID,
+	)
+	return i, err
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomRecurringTask(t *testing.T) Task {
+	id, err := gonanoid.New()
+	require.NoError(t, err)
+
+	arg := CreateRecurringTaskParams{
+		ID:        id,
+		CreatorID: createRandomUser(t).ID,
+		Title:     util.RandomPrintableString(50),
+		Deadline:  time.Now().Add(time.Hour),
+		Priority:  util.PriorityHigh,
+		Rule:      "FREQ=WEEKLY",
+		Timezone:  "Europe/Berlin",
+	}
+
+	task, err := testStore.CreateRecurringTask(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.ID, task.ID)
+	require.True(t, task.RecurrenceID.Valid)
+	require.Equal(t, int32(1), task.Occurrence.Int32)
+
+	rec, err := testStore.GetTaskRecurrence(context.Background(), task.RecurrenceID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, arg.CreatorID, rec.CreatorID)
+	require.Equal(t, arg.Rule, rec.Rule)
+	require.Equal(t, arg.Timezone, rec.Timezone)
+	require.Equal(t, arg.Title, rec.Title)
+	require.Equal(t, arg.Priority, rec.Priority)
+	require.WithinDuration(t, arg.Deadline, rec.FirstDeadline, time.Second)
+
+	return task
+}
+
+func TestCreateTaskOccurrence(t *testing.T) {
+	task := createRandomRecurringTask(t)
+
+	id, err := gonanoid.New()
+	require.NoError(t, err)
+
+	arg := CreateTaskOccurrenceParams{
+		ID:           id,
+		Deadline:     task.Deadline.AddDate(0, 0, 7),
+		Occurrence:   pgtype.Int4{Int32: 2, Valid: true},
+		RecurrenceID: task.RecurrenceID.Int64,
+	}
+	next, err := testStore.CreateTaskOccurrence(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, task.Title, next.Title)
+	require.Equal(t, task.CreatorID, next.CreatorID)
+	require.Equal(t, task.Priority, next.Priority)
+	require.Equal(t, task.RecurrenceID, next.RecurrenceID)
+	require.False(t, next.Completed)
+
+	// Each occurrence is created only once.
+	arg.ID, err = gonanoid.New()
+	require.NoError(t, err)
+	_, err = testStore.CreateTaskOccurrence(context.Background(), arg)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestSkipTaskOccurrence(t *testing.T) {
+	task := createRandomRecurringTask(t)
+
+	deadline := task.Deadline.AddDate(0, 0, 7)
+	skipped, err := testStore.SkipTaskOccurrence(context.Background(), SkipTaskOccurrenceParams{
+		ID:         task.ID,
+		Deadline:   deadline,
+		Occurrence: pgtype.Int4{Int32: 2, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), skipped.Occurrence.Int32)
+	require.WithinDuration(t, deadline, skipped.Deadline, time.Second)
+}
+
+func TestSplitAndStopTaskRecurrence(t *testing.T) {
+	task := createRandomRecurringTask(t)
+
+	split, err := testStore.SplitTaskRecurrence(context.Background(), SplitTaskRecurrenceParams{
+		ID:       task.ID,
+		Rule:     "FREQ=DAILY",
+		Timezone: "UTC",
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, task.RecurrenceID.Int64, split.RecurrenceID.Int64)
+	require.Equal(t, int32(1), split.Occurrence.Int32)
+
+	rec, err := testStore.GetTaskRecurrence(context.Background(), split.RecurrenceID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, "FREQ=DAILY", rec.Rule)
+	require.Equal(t, task.Title, rec.Title)
+	require.WithinDuration(t, task.Deadline, rec.FirstDeadline, time.Second)
+
+	stopped, err := testStore.StopTaskRecurrence(context.Background(), task.ID)
+	require.NoError(t, err)
+	require.False(t, stopped.RecurrenceID.Valid)
+	require.False(t, stopped.Occurrence.Valid)
+}
@@ -0,0 +1,225 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: task_reminder.sql
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimDueReminders = `-- name: ClaimDueReminders :many
+UPDATE task_reminders
+SET claimed_until = now() + $1::int * interval '1 second'
+FROM tasks, users
+WHERE task_reminders.id IN (
+  SELECT due.id
+  FROM task_reminders AS due
+  JOIN tasks AS due_tasks ON due_tasks.id = due.task_id
+  WHERE due.sent_at IS NULL
+    AND due.fire_at <= now()
+    AND (due.claimed_until IS NULL OR due.claimed_until < now())
+    AND NOT due_tasks.completed
+  ORDER BY due.fire_at
+  LIMIT $2
+  FOR UPDATE OF due SKIP LOCKED
+)
+  AND tasks.id = task_reminders.task_id
+  AND users.id = tasks.creator_id
+RETURNING
+  task_reminders.id,
+  task_reminders.fire_at,
+  tasks.id AS task_id,
+  tasks.creator_id,
+  tasks.title,
+  tasks.deadline,
+  users.timezone
+`
+
+type ClaimDueRemindersParams struct {
+	LeaseSeconds int32 `json:"lease_seconds"`
+	Limit        int32 `json:"limit"`
+}
+
+type ClaimDueRemindersRow struct {
+	ID        int64     `json:"id"`
+	FireAt    time.Time `json:"fire_at"`
+	TaskID    string    `json:"task_id"`
+	CreatorID int64     `json:"creator_id"`
+	Title     string    `json:"title"`
+	Deadline  time.Time `json:"deadline"`
+	Timezone  string    `json:"timezone"`
+}
+
+// ClaimDueReminders leases up to limit due reminders of open tasks to the
+// caller. Concurrent callers never claim the same reminder: locked rows are
+// skipped and a claimed reminder is only due again once its lease expires.
+func (q *Queries) ClaimDueReminders(ctx context.Context, arg ClaimDueRemindersParams) ([]ClaimDueRemindersRow, error) {
+	rows, err := q.db.Query(ctx, claimDueReminders, arg.LeaseSeconds, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ClaimDueRemindersRow{}
+	for rows.Next() {
+		var i ClaimDueRemindersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FireAt,
+			&i.TaskID,
+			&i.CreatorID,
+			&i.Title,
+			&i.Deadline,
+			&i.Timezone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const copyTaskReminders = `-- name: CopyTaskReminders :exec
+INSERT INTO task_reminders (
+  task_id,
+  offset_seconds,
+  fire_at
+)
+SELECT
+  tasks.id,
+  task_reminders.offset_seconds,
+  tasks.deadline - make_interval(secs => task_reminders.offset_seconds)
+FROM task_reminders, tasks
+WHERE task_reminders.task_id = $1
+  AND task_reminders.offset_seconds IS NOT NULL
+  AND tasks.id = $2
+`
+
+type CopyTaskRemindersParams struct {
+	FromTaskID string `json:"from_task_id"`
+	ToTaskID   string `json:"to_task_id"`
+}
+
+// CopyTaskReminders gives a task the reminders of another one that are
+// relative to its deadline, e.g. for the next occurrence of a recurring task.
+func (q *Queries) CopyTaskReminders(ctx context.Context, arg CopyTaskRemindersParams) error {
+	_, err := q.db.Exec(ctx, copyTaskReminders, arg.FromTaskID, arg.ToTaskID)
+	return err
+}
+
+const createTaskReminder = `-- name: CreateTaskReminder :one
+INSERT INTO task_reminders (
+  task_id,
+  remind_at,
+  offset_seconds,
+  fire_at
+)
+SELECT
+  tasks.id,
+  $1,
+  $2,
+  COALESCE(
+    $1,
+    tasks.deadline - make_interval(secs => $2::int)
+  )
+FROM tasks
+WHERE tasks.id = $3
+RETURNING id, task_id, remind_at, offset_seconds, fire_at, claimed_until, sent_at, created_at
+`
+
+type CreateTaskReminderParams struct {
+	RemindAt      pgtype.Timestamptz `json:"remind_at"`
+	OffsetSeconds pgtype.Int4        `json:"offset_seconds"`
+	TaskID        string             `json:"task_id"`
+}
+
+// CreateTaskReminder computes fire_at from the deadline of the task for
+// reminders given as an offset.
+func (q *Queries) CreateTaskReminder(ctx context.Context, arg CreateTaskReminderParams) (TaskReminder, error) {
+	row := q.db.QueryRow(ctx, createTaskReminder, arg.RemindAt, arg.OffsetSeconds, arg.TaskID)
+	var i TaskReminder
+	err := row.Scan(
+		&i.ID,
+		&i.TaskID,
+		&i.RemindAt,
+		&i.OffsetSeconds,
+		&i.FireAt,
+		&i.ClaimedUntil,
+		&i.SentAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteTaskReminder = `-- name: DeleteTaskReminder :execrows
+DELETE FROM task_reminders
+WHERE id = $1 AND task_id = $2
+`
+
+type DeleteTaskReminderParams struct {
+	ID     int64  `json:"id"`
+	TaskID string `json:"task_id"`
+}
+
+func (q *Queries) DeleteTaskReminder(ctx context.Context, arg DeleteTaskReminderParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteTaskReminder, arg.ID, arg.TaskID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const listTaskReminders = `-- name: ListTaskReminders :many
+SELECT id, task_id, remind_at, offset_seconds, fire_at, claimed_until, sent_at, created_at FROM task_reminders
+WHERE task_id = $1
+ORDER BY fire_at, id
+`
+
+func (q *Queries) ListTaskReminders(ctx context.Context, taskID string) ([]TaskReminder, error) {
+	rows, err := q.db.Query(ctx, listTaskReminders, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TaskReminder{}
+	for rows.Next() {
+		var i TaskReminder
+		if err := rows.Scan(
+			&i.ID,
+			&i.TaskID,
+			&i.RemindAt,
+			&i.OffsetSeconds,
+			&i.FireAt,
+			&i.ClaimedUntil,
+			&i.SentAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markReminderSent = `-- name: MarkReminderSent :exec
+UPDATE task_reminders
+SET
+  sent_at = now(),
+  claimed_until = NULL
+WHERE id = $1
+`
+
+func (q *Queries) MarkReminderSent(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, markReminderSent, id)
+	return err
+}
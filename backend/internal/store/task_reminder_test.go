@@ -0,0 +1,145 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskReminderFollowsDeadline(t *testing.T) {
+	task := createRandomTask(t)
+
+	reminder, err := testStore.CreateTaskReminder(context.Background(), CreateTaskReminderParams{
+		TaskID:        task.ID,
+		OffsetSeconds: pgtype.Int4{Int32: 600, Valid: true},
+	})
+	require.NoError(t, err)
+	require.WithinDuration(t, task.Deadline.Add(-10*time.Minute), reminder.FireAt, time.Second)
+
+	deadline := task.Deadline.Add(24 * time.Hour)
+	_, err = testStore.UpdateTask(context.Background(), UpdateTaskParams{
+		ID:       task.ID,
+		Deadline: pgtype.Timestamptz{Time: deadline, Valid: true},
+	})
+	require.NoError(t, err)
+
+	reminders, err := testStore.ListTaskReminders(context.Background(), task.ID)
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	require.WithinDuration(t, deadline.Add(-10*time.Minute), reminders[0].FireAt, time.Second)
+
+	rows, err := testStore.DeleteTaskReminder(context.Background(), DeleteTaskReminderParams{
+		ID:     reminder.ID,
+		TaskID: task.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+}
+
+func TestClaimDueReminders(t *testing.T) {
+	task := createRandomTask(t)
+
+	// The deadline is an hour away, so a two hour offset is already due.
+	reminder, err := testStore.CreateTaskReminder(context.Background(), CreateTaskReminderParams{
+		TaskID:        task.ID,
+		OffsetSeconds: pgtype.Int4{Int32: 7200, Valid: true},
+	})
+	require.NoError(t, err)
+
+	require.True(t, claimReminder(t, reminder.ID))
+
+	// A claimed reminder is not handed out again while its lease lasts.
+	require.False(t, claimReminder(t, reminder.ID))
+
+	require.NoError(t, testStore.MarkReminderSent(context.Background(), reminder.ID))
+	reminders, err := testStore.ListTaskReminders(context.Background(), task.ID)
+	require.NoError(t, err)
+	require.True(t, reminders[0].SentAt.Valid)
+}
+
+// claimReminder reports whether claiming due reminders returned the given one.
+func claimReminder(t *testing.T, id int64) bool {
+	rows, err := testStore.ClaimDueReminders(context.Background(), ClaimDueRemindersParams{
+		LeaseSeconds: 60,
+		Limit:        1000,
+	})
+	require.NoError(t, err)
+
+	for _, row := range rows {
+		if row.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCopyTaskReminders(t *testing.T) {
+	task := createRandomTask(t)
+	next := createSubtask(t, task)
+
+	_, err := testStore.CreateTaskReminder(context.Background(), CreateTaskReminderParams{
+		TaskID:        task.ID,
+		OffsetSeconds: pgtype.Int4{Int32: 3600, Valid: true},
+	})
+	require.NoError(t, err)
+	_, err = testStore.CreateTaskReminder(context.Background(), CreateTaskReminderParams{
+		TaskID:   task.ID,
+		RemindAt: pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true},
+	})
+	require.NoError(t, err)
+
+	err = testStore.CopyTaskReminders(context.Background(), CopyTaskRemindersParams{
+		FromTaskID: task.ID,
+		ToTaskID:   next.ID,
+	})
+	require.NoError(t, err)
+
+	// Only reminders relative to the deadline are copied.
+	reminders, err := testStore.ListTaskReminders(context.Background(), next.ID)
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	require.Equal(t, int32(3600), reminders[0].OffsetSeconds.Int32)
+	require.WithinDuration(t, next.Deadline.Add(-time.Hour), reminders[0].FireAt, time.Second)
+}
+
+func TestNotifications(t *testing.T) {
+	user := createRandomUser(t)
+
+	arg := CreateNotificationParams{
+		UserID:   user.ID,
+		Kind:     "task_reminder",
+		Subject:  "subject",
+		Body:     "body",
+		DedupKey: pgtype.Text{String: user.Username, Valid: true},
+	}
+	require.NoError(t, testStore.CreateNotification(context.Background(), arg))
+	// The same key is delivered only once.
+	require.NoError(t, testStore.CreateNotification(context.Background(), arg))
+
+	notifications, err := testStore.ListUserNotifications(context.Background(), ListUserNotificationsParams{
+		UserID: user.ID,
+		Unread: true,
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	require.Equal(t, int64(1), notifications[0].Total)
+
+	rows, err := testStore.MarkNotificationRead(context.Background(), MarkNotificationReadParams{
+		ID:     notifications[0].ID,
+		UserID: user.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	notifications, err = testStore.ListUserNotifications(context.Background(), ListUserNotificationsParams{
+		UserID: user.ID,
+		Unread: true,
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, notifications)
+}
@@ -0,0 +1,153 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: task_share.sql
+
+package store
+
+import (
+	"context"
+	"time"
+)
+
+const copyTaskShares = `-- name: CopyTaskShares :exec
+INSERT INTO task_shares (
+  task_id,
+  user_id,
+  role
+)
+SELECT
+  $1::varchar,
+  task_shares.user_id,
+  task_shares.role
+FROM task_shares
+WHERE task_shares.task_id = $2
+ON CONFLICT DO NOTHING
+`
+
+type CopyTaskSharesParams struct {
+	ToTaskID   string `json:"to_task_id"`
+	FromTaskID string `json:"from_task_id"`
+}
+
+// CopyTaskShares shares a task with the users another one is shared with,
+// e.g. for the next occurrence of a recurring task.
+func (q *Queries) CopyTaskShares(ctx context.Context, arg CopyTaskSharesParams) error {
+	_, err := q.db.Exec(ctx, copyTaskShares, arg.ToTaskID, arg.FromTaskID)
+	return err
+}
+
+const getTaskShare = `-- name: GetTaskShare :one
+SELECT task_id, user_id, role, created_at FROM task_shares
+WHERE task_id = $1 AND user_id = $2 LIMIT 1
+`
+
+type GetTaskShareParams struct {
+	TaskID string `json:"task_id"`
+	UserID int64  `json:"user_id"`
+}
+
+func (q *Queries) GetTaskShare(ctx context.Context, arg GetTaskShareParams) (TaskShare, error) {
+	row := q.db.QueryRow(ctx, getTaskShare, arg.TaskID, arg.UserID)
+	var i TaskShare
+	err := row.Scan(
+		&i.TaskID,
+		&i.UserID,
+		&i.Role,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listTaskShares = `-- name: ListTaskShares :many
+SELECT
+  task_shares.task_id, task_shares.user_id, task_shares.role, task_shares.created_at,
+  users.username
+FROM task_shares
+JOIN users ON users.id = task_shares.user_id
+WHERE task_shares.task_id = $1
+ORDER BY task_shares.created_at ASC
+`
+
+type ListTaskSharesRow struct {
+	TaskID    string    `json:"task_id"`
+	UserID    int64     `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	Username  string    `json:"username"`
+}
+
+func (q *Queries) ListTaskShares(ctx context.Context, taskID string) ([]ListTaskSharesRow, error) {
+	rows, err := q.db.Query(ctx, listTaskShares, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTaskSharesRow{}
+	for rows.Next() {
+		var i ListTaskSharesRow
+		if err := rows.Scan(
+			&i.TaskID,
+			&i.UserID,
+			&i.Role,
+			&i.CreatedAt,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const shareTask = `-- name: ShareTask :one
+INSERT INTO task_shares (
+  task_id,
+  user_id,
+  role
+) VALUES (
+  $1, $2, $3
+) ON CONFLICT (task_id, user_id) DO UPDATE
+SET role = EXCLUDED.role
+RETURNING task_id, user_id, role, created_at
+`
+
+type ShareTaskParams struct {
+	TaskID string `json:"task_id"`
+	UserID int64  `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// ShareTask gives a user a role on a task, replacing the role they had.
+func (q *Queries) ShareTask(ctx context.Context, arg ShareTaskParams) (TaskShare, error) {
+	row := q.db.QueryRow(ctx, shareTask, arg.TaskID, arg.UserID, arg.Role)
+	var i TaskShare
+	err := row.Scan(
+		&i.TaskID,
+		&i.UserID,
+		&i.Role,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const unshareTask = `-- name: UnshareTask :execrows
+DELETE FROM task_shares
+WHERE task_id = $1 AND user_id = $2
+`
+
+type UnshareTaskParams struct {
+	TaskID string `json:"task_id"`
+	UserID int64  `json:"user_id"`
+}
+
+func (q *Queries) UnshareTask(ctx context.Context, arg UnshareTaskParams) (int64, error) {
+	result, err := q.db.Exec(ctx, unshareTask, arg.TaskID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
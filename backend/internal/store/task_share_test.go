@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func shareRandomTask(t *testing.T, role string) (Task, User) {
+	task := createRandomTask(t)
+	user := createRandomUser(t)
+
+	share, err := testStore.ShareTask(context.Background(), ShareTaskParams{
+		TaskID: task.ID,
+		UserID: user.ID,
+		Role:   role,
+	})
+	require.NoError(t, err)
+	require.Equal(t, task.ID, share.TaskID)
+	require.Equal(t, user.ID, share.UserID)
+	require.Equal(t, role, share.Role)
+	require.NotZero(t, share.CreatedAt)
+
+	return task, user
+}
+
+func TestShareTaskReplacesRole(t *testing.T) {
+	task, user := shareRandomTask(t, util.ShareRoleViewer)
+
+	_, err := testStore.ShareTask(context.Background(), ShareTaskParams{
+		TaskID: task.ID,
+		UserID: user.ID,
+		Role:   util.ShareRoleEditor,
+	})
+	require.NoError(t, err)
+
+	share, err := testStore.GetTaskShare(context.Background(), GetTaskShareParams{
+		TaskID: task.ID,
+		UserID: user.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.ShareRoleEditor, share.Role)
+
+	shares, err := testStore.ListTaskShares(context.Background(), task.ID)
+	require.NoError(t, err)
+	require.Len(t, shares, 1)
+	require.Equal(t, user.Username, shares[0].Username)
+
+	_, err = testStore.ShareTask(context.Background(), ShareTaskParams{
+		TaskID: task.ID,
+		UserID: user.ID,
+		Role:   "owner",
+	})
+	require.Equal(t, CheckViolation, ErrorCode(err))
+}
+
+func TestGetTasksIncludesSharedTasks(t *testing.T) {
+	shared, user := shareRandomTask(t, util.ShareRoleViewer)
+
+	id, err := gonanoid.New()
+	require.NoError(t, err)
+	own, err := testStore.CreateTask(context.Background(), CreateTaskParams{
+		ID:        id,
+		CreatorID: user.ID,
+		Title:     util.RandomPrintableString(20),
+		Deadline:  shared.Deadline,
+		Priority:  util.PriorityNone,
+	})
+	require.NoError(t, err)
+
+	ids := func(shared pgtype.Bool) []string {
+		tasks, err := testStore.GetTasks(context.Background(), GetTasksParams{
+			UserID: user.ID,
+			Shared: shared,
+			Limit:  10,
+		})
+		require.NoError(t, err)
+
+		ids := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	require.ElementsMatch(t, []string{shared.ID, own.ID}, ids(pgtype.Bool{}))
+	require.Equal(t, []string{own.ID}, ids(pgtype.Bool{Bool: false, Valid: true}))
+	require.Equal(t, []string{shared.ID}, ids(pgtype.Bool{Bool: true, Valid: true}))
+}
+
+func TestCopyTaskShares(t *testing.T) {
+	task, user := shareRandomTask(t, util.ShareRoleEditor)
+	next := createRandomTask(t)
+
+	err := testStore.CopyTaskShares(context.Background(), CopyTaskSharesParams{
+		FromTaskID: task.ID,
+		ToTaskID:   next.ID,
+	})
+	require.NoError(t, err)
+
+	share, err := testStore.GetTaskShare(context.Background(), GetTaskShareParams{
+		TaskID: next.ID,
+		UserID: user.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.ShareRoleEditor, share.Role)
+}
+
+func TestUnshareTask(t *testing.T) {
+	task, user := shareRandomTask(t, util.ShareRoleViewer)
+	arg := UnshareTaskParams{
+		TaskID: task.ID,
+		UserID: user.ID,
+	}
+
+	n, err := testStore.UnshareTask(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+
+	n, err = testStore.UnshareTask(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	_, err = testStore.GetTaskShare(context.Background(), GetTaskShareParams(arg))
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
@@ -25,6 +25,7 @@ func createRandomTask(t *testing.T) Task {
 		},
 		CreatorID: createRandomUser(t).ID,
 		Deadline:  time.Now().Add(time.Hour),
+		Priority:  util.PriorityNone,
 	}
 
 	task, err := testStore.CreateTask(context.Background(), arg)
@@ -35,6 +36,7 @@ func createRandomTask(t *testing.T) Task {
 	require.Equal(t, arg.Title, task.Title)
 	require.Equal(t, arg.Description, task.Description)
 	require.Equal(t, arg.CreatorID, task.CreatorID)
+	require.Equal(t, arg.Priority, task.Priority)
 	require.WithinDuration(t, arg.Deadline, task.Deadline, time.Second)
 
 	require.NotZero(t, task.CreatedAt)
@@ -49,7 +51,7 @@ func TestCreateTask(t *testing.T) {
 func TestGetTasks(t *testing.T) {
 	task := createRandomTask(t)
 	arg := GetTasksParams{
-		CreatorID: task.CreatorID,
+		UserID: task.CreatorID,
 		Title: pgtype.Text{
 			String: task.Title[12:41],
 			Valid:  true,
@@ -86,9 +88,9 @@ func TestGetTasks(t *testing.T) {
 func TestGetTasksWithNullValue(t *testing.T) {
 	task1 := createRandomTask(t)
 	arg := GetTasksParams{
-		CreatorID: task1.CreatorID,
-		Limit:     5,
-		Offset:    0,
+		UserID: task1.CreatorID,
+		Limit:  5,
+		Offset: 0,
 	}
 
 	tasks, err := testStore.GetTasks(context.Background(), arg)
@@ -229,3 +231,237 @@ func TestDeleteTask(t *testing.T) {
 	require.Error(t, err)
 	require.Empty(t, task2)
 }
+
+func TestCreateTaskInvalidPriority(t *testing.T) {
+	id, err := gonanoid.New()
+	require.NoError(t, err)
+
+	_, err = testStore.CreateTask(context.Background(), CreateTaskParams{
+		ID:        id,
+		Title:     util.RandomPrintableString(50),
+		CreatorID: createRandomUser(t).ID,
+		Deadline:  time.Now().Add(time.Hour),
+		Priority:  "critical",
+	})
+	require.Equal(t, CheckViolation, ErrorCode(err))
+}
+
+func TestUpdateTaskOnlyPriority(t *testing.T) {
+	oldTask := createRandomTask(t)
+
+	newTask, err := testStore.UpdateTask(context.Background(), UpdateTaskParams{
+		ID: oldTask.ID,
+		Priority: pgtype.Text{
+			String: util.PriorityHigh,
+			Valid:  true,
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.PriorityHigh, newTask.Priority)
+	require.Equal(t, oldTask.Title, newTask.Title)
+	require.WithinDuration(t, oldTask.Deadline, newTask.Deadline, time.Second)
+}
+
+func TestGetTasksByPriority(t *testing.T) {
+	creatorID := createRandomUser(t).ID
+
+	create := func(priority string, deadline time.Duration) Task {
+		id, err := gonanoid.New()
+		require.NoError(t, err)
+
+		task, err := testStore.CreateTask(context.Background(), CreateTaskParams{
+			ID:        id,
+			Title:     util.RandomPrintableString(50),
+			CreatorID: creatorID,
+			Deadline:  time.Now().Add(deadline),
+			Priority:  priority,
+		})
+		require.NoError(t, err)
+		return task
+	}
+
+	lowSoon := create(util.PriorityLow, time.Hour)
+	urgentLater := create(util.PriorityUrgent, 3*time.Hour)
+	urgentSoon := create(util.PriorityUrgent, 2*time.Hour)
+	noneSoonest := create(util.PriorityNone, time.Minute)
+
+	ids := func(tasks []GetTasksRow) []string {
+		result := make([]string, len(tasks))
+		for i, task := range tasks {
+			result[i] = task.ID
+		}
+		return result
+	}
+
+	tasks, err := testStore.GetTasks(context.Background(), GetTasksParams{
+		UserID:         creatorID,
+		Limit:          10,
+		SortByPriority: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{urgentSoon.ID, urgentLater.ID, lowSoon.ID, noneSoonest.ID}, ids(tasks))
+
+	tasks, err = testStore.GetTasks(context.Background(), GetTasksParams{
+		UserID: creatorID,
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{noneSoonest.ID, lowSoon.ID, urgentSoon.ID, urgentLater.ID}, ids(tasks))
+
+	tasks, err = testStore.GetTasks(context.Background(), GetTasksParams{
+		UserID:     creatorID,
+		Limit:      10,
+		Priorities: []string{util.PriorityLow, util.PriorityNone},
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{noneSoonest.ID, lowSoon.ID}, ids(tasks))
+}
+
+func createSubtask(t *testing.T, parent Task) Task {
+	id, err := gonanoid.New()
+	require.NoError(t, err)
+
+	task, err := testStore.CreateTask(context.Background(), CreateTaskParams{
+		ID:        id,
+		Title:     util.RandomPrintableString(50),
+		CreatorID: parent.CreatorID,
+		Deadline:  time.Now().Add(time.Hour),
+		Priority:  util.PriorityNone,
+		ParentID:  pgtype.Text{String: parent.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, parent.ID, task.ParentID.String)
+	return task
+}
+
+func TestSubtaskProgress(t *testing.T) {
+	parent := createRandomTask(t)
+	done := createSubtask(t, parent)
+	createSubtask(t, parent)
+
+	_, err := testStore.UpdateTask(context.Background(), UpdateTaskParams{
+		ID:        done.ID,
+		Completed: pgtype.Bool{Bool: true, Valid: true},
+	})
+	require.NoError(t, err)
+
+	subtasks, err := testStore.ListSubtasks(context.Background(), pgtype.Text{String: parent.ID, Valid: true})
+	require.NoError(t, err)
+	require.Len(t, subtasks, 2)
+
+	progress, err := testStore.GetSubtaskProgress(context.Background(), []string{parent.ID, done.ID})
+	require.NoError(t, err)
+	require.Equal(t, []GetSubtaskProgressRow{{TaskID: parent.ID, Total: 2, Completed: 1}}, progress)
+}
+
+func TestMoveTaskRejectsCycles(t *testing.T) {
+	parent := createRandomTask(t)
+	child := createSubtask(t, parent)
+	grandchild := createSubtask(t, child)
+
+	for _, arg := range []MoveTaskParams{
+		{ID: parent.ID, ParentID: pgtype.Text{String: parent.ID, Valid: true}},
+		{ID: parent.ID, ParentID: pgtype.Text{String: grandchild.ID, Valid: true}},
+	} {
+		_, err := testStore.MoveTask(context.Background(), arg)
+		require.Equal(t, CheckViolation, ErrorCode(err))
+		require.Equal(t, "tasks_parent_cycle_check", ErrorConstraintName(err))
+	}
+
+	moved, err := testStore.MoveTask(context.Background(), MoveTaskParams{ID: grandchild.ID})
+	require.NoError(t, err)
+	require.False(t, moved.ParentID.Valid)
+}
+
+func TestSubtaskDepthLimit(t *testing.T) {
+	task := createRandomTask(t)
+	for range 4 {
+		task = createSubtask(t, task)
+	}
+
+	id, err := gonanoid.New()
+	require.NoError(t, err)
+	_, err = testStore.CreateTask(context.Background(), CreateTaskParams{
+		ID:        id,
+		Title:     util.RandomPrintableString(50),
+		CreatorID: task.CreatorID,
+		Deadline:  time.Now().Add(time.Hour),
+		Priority:  util.PriorityNone,
+		ParentID:  pgtype.Text{String: task.ID, Valid: true},
+	})
+	require.Equal(t, CheckViolation, ErrorCode(err))
+	require.Equal(t, "tasks_depth_check", ErrorConstraintName(err))
+
+	// Moving a subtree counts its own height as well.
+	other := createRandomTask(t)
+	createSubtask(t, createSubtask(t, other))
+	_, err = testStore.MoveTask(context.Background(), MoveTaskParams{
+		ID:       other.ID,
+		ParentID: pgtype.Text{String: task.ParentID.String, Valid: true},
+	})
+	require.Equal(t, "tasks_depth_check", ErrorConstraintName(err))
+}
+
+func TestDeleteTaskDeletesSubtasks(t *testing.T) {
+	parent := createRandomTask(t)
+	child := createSubtask(t, parent)
+
+	require.NoError(t, testStore.DeleteTask(context.Background(), parent.ID))
+
+	_, err := testStore.GetTaskByID(context.Background(), child.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestGetTasksByAssignee(t *testing.T) {
+	assigned := createRandomTask(t)
+	assignee := createRandomUser(t)
+
+	updated, err := testStore.SetTaskAssignee(context.Background(), SetTaskAssigneeParams{
+		ID:         assigned.ID,
+		AssigneeID: pgtype.Int8{Int64: assignee.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, assignee.ID, updated.AssigneeID.Int64)
+
+	unassigned, err := testStore.CreateTask(context.Background(), CreateTaskParams{
+		ID:        util.RandomAlphabetString(21),
+		Title:     util.RandomPrintableString(50),
+		CreatorID: assigned.CreatorID,
+		Deadline:  time.Now().Add(time.Hour),
+		Priority:  util.PriorityNone,
+	})
+	require.NoError(t, err)
+
+	ids := func(arg GetTasksParams) []string {
+		arg.Limit = 10
+		tasks, err := testStore.GetTasks(context.Background(), arg)
+		require.NoError(t, err)
+
+		ids := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	// The assignee sees the task as a task shared with them.
+	require.Equal(t, []string{assigned.ID}, ids(GetTasksParams{
+		UserID: assignee.ID,
+		Shared: pgtype.Bool{Bool: true, Valid: true},
+	}))
+	require.Equal(t, []string{assigned.ID}, ids(GetTasksParams{
+		UserID:     assigned.CreatorID,
+		AssigneeID: pgtype.Int8{Int64: assignee.ID, Valid: true},
+	}))
+	require.Equal(t, []string{unassigned.ID}, ids(GetTasksParams{
+		UserID:     assigned.CreatorID,
+		Unassigned: true,
+	}))
+
+	// Deleting the assignee leaves the task unassigned.
+	_, err = testStore.DeleteUser(context.Background(), assignee.ID)
+	require.NoError(t, err)
+	task, err := testStore.GetTaskByID(context.Background(), assigned.ID)
+	require.NoError(t, err)
+	require.False(t, task.AssigneeID.Valid)
+}
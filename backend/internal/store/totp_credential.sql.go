@@ -0,0 +1,121 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: totp_credential.sql
+
+package store
+
+import (
+	"context"
+)
+
+const confirmTOTPCredential = `-- name: ConfirmTOTPCredential :one
+UPDATE totp_credentials
+SET confirmed_at = now(),
+    last_used_step = $1
+WHERE user_id = $2 AND confirmed_at IS NULL
+RETURNING user_id, secret, confirmed_at, last_used_step, created_at
+`
+
+type ConfirmTOTPCredentialParams struct {
+	Step   int64 `json:"step"`
+	UserID int64 `json:"user_id"`
+}
+
+func (q *Queries) ConfirmTOTPCredential(ctx context.Context, arg ConfirmTOTPCredentialParams) (TotpCredential, error) {
+	row := q.db.QueryRow(ctx, confirmTOTPCredential, arg.Step, arg.UserID)
+	var i TotpCredential
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteTOTPCredential = `-- name: DeleteTOTPCredential :exec
+DELETE FROM totp_credentials
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteTOTPCredential(ctx context.Context, userID int64) error {
+	_, err := q.db.Exec(ctx, deleteTOTPCredential, userID)
+	return err
+}
+
+const getTOTPCredential = `-- name: GetTOTPCredential :one
+SELECT user_id, secret, confirmed_at, last_used_step, created_at FROM totp_credentials
+WHERE user_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetTOTPCredential(ctx context.Context, userID int64) (TotpCredential, error) {
+	row := q.db.QueryRow(ctx, getTOTPCredential, userID)
+	var i TotpCredential
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const upsertPendingTOTPCredential = `-- name: UpsertPendingTOTPCredential :one
+INSERT INTO totp_credentials (
+  user_id,
+  secret
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret,
+    last_used_step = 0,
+    created_at = now()
+WHERE totp_credentials.confirmed_at IS NULL
+RETURNING user_id, secret, confirmed_at, last_used_step, created_at
+`
+
+type UpsertPendingTOTPCredentialParams struct {
+	UserID int64  `json:"user_id"`
+	Secret string `json:"secret"`
+}
+
+// Starts (or restarts) an enrollment. A confirmed credential is left
+// untouched and no row is returned.
+func (q *Queries) UpsertPendingTOTPCredential(ctx context.Context, arg UpsertPendingTOTPCredentialParams) (TotpCredential, error) {
+	row := q.db.QueryRow(ctx, upsertPendingTOTPCredential, arg.UserID, arg.Secret)
+	var i TotpCredential
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const useTOTPStep = `-- name: UseTOTPStep :execrows
+UPDATE totp_credentials
+SET last_used_step = $1
+WHERE user_id = $2
+  AND confirmed_at IS NOT NULL
+  AND last_used_step < $1
+`
+
+type UseTOTPStepParams struct {
+	Step   int64 `json:"step"`
+	UserID int64 `json:"user_id"`
+}
+
+// Only moves forward, so every code is accepted at most once.
+func (q *Queries) UseTOTPStep(ctx context.Context, arg UseTOTPStepParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useTOTPStep, arg.Step, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
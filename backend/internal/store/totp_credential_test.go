@@ -0,0 +1,88 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTPCredentialLifecycle(t *testing.T) {
+	user := createRandomUser(t)
+
+	_, err := testStore.GetTOTPCredential(context.Background(), user.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	credential1, err := testStore.UpsertPendingTOTPCredential(context.Background(), UpsertPendingTOTPCredentialParams{
+		UserID: user.ID,
+		Secret: "JBSWY3DPEHPK3PXP",
+	})
+	require.NoError(t, err)
+	require.Equal(t, user.ID, credential1.UserID)
+	require.False(t, credential1.ConfirmedAt.Valid)
+
+	// Enrolling again before confirmation replaces the secret.
+	credential2, err := testStore.UpsertPendingTOTPCredential(context.Background(), UpsertPendingTOTPCredentialParams{
+		UserID: user.ID,
+		Secret: "KRSXG5CTMVRXEZLU",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "KRSXG5CTMVRXEZLU", credential2.Secret)
+
+	credential3, err := testStore.ConfirmTOTPCredential(context.Background(), ConfirmTOTPCredentialParams{
+		Step:   100,
+		UserID: user.ID,
+	})
+	require.NoError(t, err)
+	require.True(t, credential3.ConfirmedAt.Valid)
+	require.Equal(t, int64(100), credential3.LastUsedStep)
+
+	// A confirmed credential can neither be re-enrolled nor confirmed again.
+	_, err = testStore.UpsertPendingTOTPCredential(context.Background(), UpsertPendingTOTPCredentialParams{
+		UserID: user.ID,
+		Secret: "JBSWY3DPEHPK3PXP",
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	_, err = testStore.ConfirmTOTPCredential(context.Background(), ConfirmTOTPCredentialParams{
+		Step:   101,
+		UserID: user.ID,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	err = testStore.DeleteTOTPCredential(context.Background(), user.ID)
+	require.NoError(t, err)
+
+	_, err = testStore.GetTOTPCredential(context.Background(), user.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestUseTOTPStep(t *testing.T) {
+	user := createRandomUser(t)
+
+	_, err := testStore.UpsertPendingTOTPCredential(context.Background(), UpsertPendingTOTPCredentialParams{
+		UserID: user.ID,
+		Secret: "JBSWY3DPEHPK3PXP",
+	})
+	require.NoError(t, err)
+
+	// Steps are not recorded before the credential is confirmed.
+	rows, err := testStore.UseTOTPStep(context.Background(), UseTOTPStepParams{Step: 100, UserID: user.ID})
+	require.NoError(t, err)
+	require.Zero(t, rows)
+
+	_, err = testStore.ConfirmTOTPCredential(context.Background(), ConfirmTOTPCredentialParams{Step: 100, UserID: user.ID})
+	require.NoError(t, err)
+
+	rows, err = testStore.UseTOTPStep(context.Background(), UseTOTPStepParams{Step: 100, UserID: user.ID})
+	require.NoError(t, err)
+	require.Zero(t, rows)
+
+	rows, err = testStore.UseTOTPStep(context.Background(), UseTOTPStepParams{Step: 101, UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	rows, err = testStore.UseTOTPStep(context.Background(), UseTOTPStepParams{Step: 101, UserID: user.ID})
+	require.NoError(t, err)
+	require.Zero(t, rows)
+}
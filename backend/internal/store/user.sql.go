@@ -7,36 +7,59 @@ package store
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
   username,
-  hashed_password
+  hashed_password,
+  email
 ) VALUES (
-  $1, $2
-) RETURNING id, username, hashed_password, created_at
+  $1, $2, $3
+) RETURNING id, username, hashed_password, created_at, role, display_name, timezone, locale, email, email_verified_at
 `
 
 type CreateUserParams struct {
-	Username       string `json:"username"`
-	HashedPassword string `json:"hashed_password"`
+	Username       string      `json:"username"`
+	HashedPassword string      `json:"hashed_password"`
+	Email          pgtype.Text `json:"email"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRow(ctx, createUser, arg.Username, arg.HashedPassword)
+	row := q.db.QueryRow(ctx, createUser, arg.Username, arg.HashedPassword, arg.Email)
 	var i User
 	err := row.Scan(
 		&i.ID,
 		&i.Username,
 		&i.HashedPassword,
 		&i.CreatedAt,
+		&i.Role,
+		&i.DisplayName,
+		&i.Timezone,
+		&i.Locale,
+		&i.Email,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
 
+const deleteUser = `-- name: DeleteUser :execrows
+DELETE FROM users
+WHERE id = $1
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getUser = `-- name: GetUser :one
-SELECT id, username, hashed_password, created_at FROM users
+SELECT id, username, hashed_password, created_at, role, display_name, timezone, locale, email, email_verified_at FROM users
 WHERE username = $1 LIMIT 1
 `
 
@@ -48,6 +71,222 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.Username,
 		&i.HashedPassword,
 		&i.CreatedAt,
+		&i.Role,
+		&i.DisplayName,
+		&i.Timezone,
+		&i.Locale,
+		&i.Email,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT id, username, hashed_password, created_at, role, display_name, timezone, locale, email, email_verified_at FROM users
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserByID(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.Role,
+		&i.DisplayName,
+		&i.Timezone,
+		&i.Locale,
+		&i.Email,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const getUserByVerifiedEmail = `-- name: GetUserByVerifiedEmail :one
+SELECT id, username, hashed_password, created_at, role, display_name, timezone, locale, email, email_verified_at FROM users
+WHERE email = $1 AND email_verified_at IS NOT NULL
+LIMIT 1
+`
+
+func (q *Queries) GetUserByVerifiedEmail(ctx context.Context, email pgtype.Text) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByVerifiedEmail, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.Role,
+		&i.DisplayName,
+		&i.Timezone,
+		&i.Locale,
+		&i.Email,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const updateUserEmail = `-- name: UpdateUserEmail :one
+UPDATE users
+SET
+  email = $2,
+  email_verified_at = NULL
+WHERE id = $1
+RETURNING id, username, hashed_password, created_at, role, display_name, timezone, locale, email, email_verified_at
+`
+
+type UpdateUserEmailParams struct {
+	ID    int64       `json:"id"`
+	Email pgtype.Text `json:"email"`
+}
+
+func (q *Queries) UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserEmail, arg.ID, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.Role,
+		&i.DisplayName,
+		&i.Timezone,
+		&i.Locale,
+		&i.Email,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :one
+UPDATE users
+SET hashed_password = $2
+WHERE id = $1
+RETURNING id, username, hashed_password, created_at, role, display_name, timezone, locale, email, email_verified_at
+`
+
+type UpdateUserPasswordParams struct {
+	ID             int64  `json:"id"`
+	HashedPassword string `json:"hashed_password"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserPassword, arg.ID, arg.HashedPassword)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.Role,
+		&i.DisplayName,
+		&i.Timezone,
+		&i.Locale,
+		&i.Email,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE users
+SET
+  display_name = COALESCE($2, display_name),
+  timezone = COALESCE($3, timezone),
+  locale = COALESCE($4, locale)
+WHERE
+  id = $1
+RETURNING id, username, hashed_password, created_at, role, display_name, timezone, locale, email, email_verified_at
+`
+
+type UpdateUserProfileParams struct {
+	ID          int64       `json:"id"`
+	DisplayName pgtype.Text `json:"display_name"`
+	Timezone    pgtype.Text `json:"timezone"`
+	Locale      pgtype.Text `json:"locale"`
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserProfile,
+		arg.ID,
+		arg.DisplayName,
+		arg.Timezone,
+		arg.Locale,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.Role,
+		&i.DisplayName,
+		&i.Timezone,
+		&i.Locale,
+		&i.Email,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :one
+UPDATE users
+SET role = $2
+WHERE username = $1
+RETURNING id, username, hashed_password, created_at, role, display_name, timezone, locale, email, email_verified_at
+`
+
+type UpdateUserRoleParams struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserRole, arg.Username, arg.Role)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.Role,
+		&i.DisplayName,
+		&i.Timezone,
+		&i.Locale,
+		&i.Email,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :one
+UPDATE users
+SET email_verified_at = now()
+WHERE id = $1 AND email = $2
+RETURNING id, username, hashed_password, created_at, role, display_name, timezone, locale, email, email_verified_at
+`
+
+type VerifyUserEmailParams struct {
+	ID    int64       `json:"id"`
+	Email pgtype.Text `json:"email"`
+}
+
+func (q *Queries) VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error) {
+	row := q.db.QueryRow(ctx, verifyUserEmail, arg.ID, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.Role,
+		&i.DisplayName,
+		&i.Timezone,
+		&i.Locale,
+		&i.Email,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
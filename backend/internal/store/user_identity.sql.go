@@ -0,0 +1,148 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: user_identity.sql
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createUserIdentity = `-- name: CreateUserIdentity :one
+INSERT INTO user_identities (
+  user_id,
+  issuer,
+  subject,
+  email
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, user_id, issuer, subject, email, created_at
+`
+
+type CreateUserIdentityParams struct {
+	UserID  int64       `json:"user_id"`
+	Issuer  string      `json:"issuer"`
+	Subject string      `json:"subject"`
+	Email   pgtype.Text `json:"email"`
+}
+
+func (q *Queries) CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error) {
+	row := q.db.QueryRow(ctx, createUserIdentity,
+		arg.UserID,
+		arg.Issuer,
+		arg.Subject,
+		arg.Email,
+	)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Issuer,
+		&i.Subject,
+		&i.Email,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createUserWithIdentity = `-- name: CreateUserWithIdentity :one
+WITH new_user AS (
+  INSERT INTO users (
+    username,
+    hashed_password,
+    email,
+    email_verified_at
+  ) VALUES (
+    $1,
+    $2,
+    $3,
+    CASE WHEN $3::varchar IS NULL THEN NULL ELSE now() END
+  ) RETURNING id, username, hashed_password, created_at, role, display_name, timezone, locale, email, email_verified_at
+), new_identity AS (
+  INSERT INTO user_identities (
+    user_id,
+    issuer,
+    subject,
+    email
+  )
+  SELECT id, $4, $5, email
+  FROM new_user
+)
+SELECT id, username, hashed_password, created_at, role, display_name, timezone, locale, email, email_verified_at FROM new_user
+`
+
+type CreateUserWithIdentityParams struct {
+	Username       string      `json:"username"`
+	HashedPassword string      `json:"hashed_password"`
+	Email          pgtype.Text `json:"email"`
+	Issuer         string      `json:"issuer"`
+	Subject        string      `json:"subject"`
+}
+
+type CreateUserWithIdentityRow struct {
+	ID              int64              `json:"id"`
+	Username        string             `json:"username"`
+	HashedPassword  string             `json:"hashed_password"`
+	CreatedAt       time.Time          `json:"created_at"`
+	Role            string             `json:"role"`
+	DisplayName     string             `json:"display_name"`
+	Timezone        string             `json:"timezone"`
+	Locale          string             `json:"locale"`
+	Email           pgtype.Text        `json:"email"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
+}
+
+// Provisions a user for an external identity in one statement so that no
+// user is left without the identity it was created for. An email is only
+// passed when the provider has verified it, so it is stored as verified.
+func (q *Queries) CreateUserWithIdentity(ctx context.Context, arg CreateUserWithIdentityParams) (CreateUserWithIdentityRow, error) {
+	row := q.db.QueryRow(ctx, createUserWithIdentity,
+		arg.Username,
+		arg.HashedPassword,
+		arg.Email,
+		arg.Issuer,
+		arg.Subject,
+	)
+	var i CreateUserWithIdentityRow
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.Role,
+		&i.DisplayName,
+		&i.Timezone,
+		&i.Locale,
+		&i.Email,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const getUserIdentity = `-- name: GetUserIdentity :one
+SELECT id, user_id, issuer, subject, email, created_at FROM user_identities
+WHERE issuer = $1 AND subject = $2 LIMIT 1
+`
+
+type GetUserIdentityParams struct {
+	Issuer  string `json:"issuer"`
+	Subject string `json:"subject"`
+}
+
+func (q *Queries) GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error) {
+	row := q.db.QueryRow(ctx, getUserIdentity, arg.Issuer, arg.Subject)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Issuer,
+		&i.Subject,
+		&i.Email,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateUserIdentity(t *testing.T) {
+	user := createRandomUser(t)
+
+	arg := CreateUserIdentityParams{
+		UserID:  user.ID,
+		Issuer:  "https://sso.example.com",
+		Subject: util.RandomAlphaNumString(16),
+	}
+
+	identity1, err := testStore.CreateUserIdentity(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.UserID, identity1.UserID)
+	require.Equal(t, arg.Subject, identity1.Subject)
+
+	identity2, err := testStore.GetUserIdentity(context.Background(), GetUserIdentityParams{
+		Issuer:  arg.Issuer,
+		Subject: arg.Subject,
+	})
+	require.NoError(t, err)
+	require.Equal(t, identity1.ID, identity2.ID)
+
+	// A subject of an issuer belongs to a single user.
+	arg.UserID = createRandomUser(t).ID
+	_, err = testStore.CreateUserIdentity(context.Background(), arg)
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+}
+
+func TestCreateUserWithIdentity(t *testing.T) {
+	hashedPassword, err := util.HashPassword(util.RandomPrintableString(8))
+	require.NoError(t, err)
+
+	arg := CreateUserWithIdentityParams{
+		Username:       util.RandomAlphabetString(10),
+		HashedPassword: hashedPassword,
+		Email: pgtype.Text{
+			String: util.RandomAlphabetString(10) + "@example.com",
+			Valid:  true,
+		},
+		Issuer:  "https://sso.example.com",
+		Subject: util.RandomAlphaNumString(16),
+	}
+
+	user, err := testStore.CreateUserWithIdentity(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, user.Username)
+	require.Equal(t, arg.Email, user.Email)
+	require.True(t, user.EmailVerifiedAt.Valid)
+
+	identity, err := testStore.GetUserIdentity(context.Background(), GetUserIdentityParams{
+		Issuer:  arg.Issuer,
+		Subject: arg.Subject,
+	})
+	require.NoError(t, err)
+	require.Equal(t, user.ID, identity.UserID)
+	require.Equal(t, arg.Email, identity.Email)
+
+	// Neither the user nor the identity is created when one of them fails.
+	username := util.RandomAlphabetString(10)
+	_, err = testStore.CreateUserWithIdentity(context.Background(), CreateUserWithIdentityParams{
+		Username:       username,
+		HashedPassword: hashedPassword,
+		Issuer:         arg.Issuer,
+		Subject:        arg.Subject,
+	})
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+
+	_, err = testStore.GetUser(context.Background(), username)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestCreateUserWithIdentityWithoutEmail(t *testing.T) {
+	hashedPassword, err := util.HashPassword(util.RandomPrintableString(8))
+	require.NoError(t, err)
+
+	user, err := testStore.CreateUserWithIdentity(context.Background(), CreateUserWithIdentityParams{
+		Username:       util.RandomAlphabetString(10),
+		HashedPassword: hashedPassword,
+		Issuer:         "https://sso.example.com",
+		Subject:        util.RandomAlphaNumString(16),
+	})
+	require.NoError(t, err)
+	require.False(t, user.Email.Valid)
+	require.False(t, user.EmailVerifiedAt.Valid)
+}
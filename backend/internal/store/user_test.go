@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/nguyen-duc-loc/task-management/backend/util"
 	"github.com/stretchr/testify/require"
 )
@@ -25,6 +26,10 @@ func createRandomUser(t *testing.T) User {
 	require.Positive(t, user.ID)
 	require.Equal(t, arg.Username, user.Username)
 	require.Equal(t, arg.HashedPassword, user.HashedPassword)
+	require.Equal(t, util.UserRole, user.Role)
+	require.Empty(t, user.DisplayName)
+	require.Equal(t, "UTC", user.Timezone)
+	require.Equal(t, "en", user.Locale)
 
 	require.NotZero(t, user.CreatedAt)
 
@@ -45,3 +50,149 @@ func TestGetUser(t *testing.T) {
 	require.Equal(t, user1.HashedPassword, user2.HashedPassword)
 	require.Equal(t, user1.CreatedAt, user2.CreatedAt, time.Second)
 }
+
+func TestUpdateUserRole(t *testing.T) {
+	user1 := createRandomUser(t)
+	user2, err := testStore.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Username: user1.Username,
+		Role:     util.AdminRole,
+	})
+	require.NoError(t, err)
+	require.Equal(t, user1.ID, user2.ID)
+	require.Equal(t, util.AdminRole, user2.Role)
+}
+
+func TestUpdateUserPassword(t *testing.T) {
+	user1 := createRandomUser(t)
+
+	hashedPassword, err := util.HashPassword(util.RandomPrintableString(8))
+	require.NoError(t, err)
+
+	user2, err := testStore.UpdateUserPassword(context.Background(), UpdateUserPasswordParams{
+		ID:             user1.ID,
+		HashedPassword: hashedPassword,
+	})
+	require.NoError(t, err)
+	require.Equal(t, user1.ID, user2.ID)
+	require.Equal(t, hashedPassword, user2.HashedPassword)
+	require.NotEqual(t, user1.HashedPassword, user2.HashedPassword)
+}
+
+func TestUpdateUserProfile(t *testing.T) {
+	user1 := createRandomUser(t)
+
+	displayName := util.RandomPrintableString(10)
+	user2, err := testStore.UpdateUserProfile(context.Background(), UpdateUserProfileParams{
+		ID: user1.ID,
+		DisplayName: pgtype.Text{
+			String: displayName,
+			Valid:  true,
+		},
+		Timezone: pgtype.Text{
+			String: "Asia/Ho_Chi_Minh",
+			Valid:  true,
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, displayName, user2.DisplayName)
+	require.Equal(t, "Asia/Ho_Chi_Minh", user2.Timezone)
+	require.Equal(t, user1.Locale, user2.Locale)
+}
+
+func TestDeleteUser(t *testing.T) {
+	task := createRandomTask(t)
+
+	rows, err := testStore.DeleteUser(context.Background(), task.CreatorID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	// Tasks of the user are removed with it.
+	_, err = testStore.GetTaskByID(context.Background(), task.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	rows, err = testStore.DeleteUser(context.Background(), task.CreatorID)
+	require.NoError(t, err)
+	require.Zero(t, rows)
+}
+
+func TestUpdateAndVerifyUserEmail(t *testing.T) {
+	user1 := createRandomUser(t)
+	require.False(t, user1.Email.Valid)
+
+	email := pgtype.Text{
+		String: util.RandomAlphabetString(10) + "@example.com",
+		Valid:  true,
+	}
+
+	user2, err := testStore.UpdateUserEmail(context.Background(), UpdateUserEmailParams{
+		ID:    user1.ID,
+		Email: email,
+	})
+	require.NoError(t, err)
+	require.Equal(t, email, user2.Email)
+	require.False(t, user2.EmailVerifiedAt.Valid)
+
+	// Verification only applies to the address the user currently has.
+	_, err = testStore.VerifyUserEmail(context.Background(), VerifyUserEmailParams{
+		ID: user1.ID,
+		Email: pgtype.Text{
+			String: "other@example.com",
+			Valid:  true,
+		},
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	user3, err := testStore.VerifyUserEmail(context.Background(), VerifyUserEmailParams{
+		ID:    user1.ID,
+		Email: email,
+	})
+	require.NoError(t, err)
+	require.True(t, user3.EmailVerifiedAt.Valid)
+
+	// Another user cannot take the same address.
+	_, err = testStore.UpdateUserEmail(context.Background(), UpdateUserEmailParams{
+		ID:    createRandomUser(t).ID,
+		Email: email,
+	})
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+	require.Equal(t, "users_email_key", ErrorConstraintName(err))
+}
+
+func TestGetUserByID(t *testing.T) {
+	user1 := createRandomUser(t)
+
+	user2, err := testStore.GetUserByID(context.Background(), user1.ID)
+	require.NoError(t, err)
+	require.Equal(t, user1.Username, user2.Username)
+
+	_, err = testStore.GetUserByID(context.Background(), -1)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestGetUserByVerifiedEmail(t *testing.T) {
+	user := createRandomUser(t)
+	email := pgtype.Text{
+		String: util.RandomAlphabetString(10) + "@example.com",
+		Valid:  true,
+	}
+
+	_, err := testStore.UpdateUserEmail(context.Background(), UpdateUserEmailParams{
+		ID:    user.ID,
+		Email: email,
+	})
+	require.NoError(t, err)
+
+	// Unverified addresses are not matched.
+	_, err = testStore.GetUserByVerifiedEmail(context.Background(), email)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	_, err = testStore.VerifyUserEmail(context.Background(), VerifyUserEmailParams{
+		ID:    user.ID,
+		Email: email,
+	})
+	require.NoError(t, err)
+
+	found, err := testStore.GetUserByVerifiedEmail(context.Background(), email)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, found.ID)
+}
@@ -0,0 +1,136 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: workspace_invitation.sql
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createWorkspaceInvitation = `-- name: CreateWorkspaceInvitation :one
+INSERT INTO workspace_invitations (
+  id,
+  workspace_id,
+  token_hash,
+  role,
+  created_by,
+  expire_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, workspace_id, token_hash, role, created_by, expire_at, created_at
+`
+
+type CreateWorkspaceInvitationParams struct {
+	ID          uuid.UUID `json:"id"`
+	WorkspaceID int64     `json:"workspace_id"`
+	TokenHash   string    `json:"token_hash"`
+	Role        string    `json:"role"`
+	CreatedBy   int64     `json:"created_by"`
+	ExpireAt    time.Time `json:"expire_at"`
+}
+
+func (q *Queries) CreateWorkspaceInvitation(ctx context.Context, arg CreateWorkspaceInvitationParams) (WorkspaceInvitation, error) {
+	row := q.db.QueryRow(ctx, createWorkspaceInvitation,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.TokenHash,
+		arg.Role,
+		arg.CreatedBy,
+		arg.ExpireAt,
+	)
+	var i WorkspaceInvitation
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.TokenHash,
+		&i.Role,
+		&i.CreatedBy,
+		&i.ExpireAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteWorkspaceInvitation = `-- name: DeleteWorkspaceInvitation :execrows
+DELETE FROM workspace_invitations
+WHERE id = $1 AND workspace_id = $2
+`
+
+type DeleteWorkspaceInvitationParams struct {
+	ID          uuid.UUID `json:"id"`
+	WorkspaceID int64     `json:"workspace_id"`
+}
+
+func (q *Queries) DeleteWorkspaceInvitation(ctx context.Context, arg DeleteWorkspaceInvitationParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteWorkspaceInvitation, arg.ID, arg.WorkspaceID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getWorkspaceInvitationByHash = `-- name: GetWorkspaceInvitationByHash :one
+SELECT id, workspace_id, token_hash, role, created_by, expire_at, created_at FROM workspace_invitations
+WHERE
+  token_hash = $1
+  AND expire_at > now()
+LIMIT 1
+`
+
+// GetWorkspaceInvitationByHash returns no rows for expired invitations.
+func (q *Queries) GetWorkspaceInvitationByHash(ctx context.Context, tokenHash string) (WorkspaceInvitation, error) {
+	row := q.db.QueryRow(ctx, getWorkspaceInvitationByHash, tokenHash)
+	var i WorkspaceInvitation
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.TokenHash,
+		&i.Role,
+		&i.CreatedBy,
+		&i.ExpireAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listWorkspaceInvitations = `-- name: ListWorkspaceInvitations :many
+SELECT id, workspace_id, token_hash, role, created_by, expire_at, created_at FROM workspace_invitations
+WHERE
+  workspace_id = $1
+  AND expire_at > now()
+ORDER BY created_at DESC
+`
+
+// ListWorkspaceInvitations leaves out expired invitations.
+func (q *Queries) ListWorkspaceInvitations(ctx context.Context, workspaceID int64) ([]WorkspaceInvitation, error) {
+	rows, err := q.db.Query(ctx, listWorkspaceInvitations, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []WorkspaceInvitation{}
+	for rows.Next() {
+		var i WorkspaceInvitation
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.TokenHash,
+			&i.Role,
+			&i.CreatedBy,
+			&i.ExpireAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,213 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomWorkspace(t *testing.T) (CreateWorkspaceRow, User) {
+	owner := createRandomUser(t)
+
+	workspace, err := testStore.CreateWorkspace(context.Background(), CreateWorkspaceParams{
+		Name:    util.RandomAlphabetString(10),
+		OwnerID: owner.ID,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, workspace.ID)
+	require.NotZero(t, workspace.CreatedAt)
+
+	return workspace, owner
+}
+
+func createWorkspaceTask(t *testing.T, workspaceID int64, creatorID int64) Task {
+	id, err := gonanoid.New()
+	require.NoError(t, err)
+
+	task, err := testStore.CreateTask(context.Background(), CreateTaskParams{
+		ID:        id,
+		CreatorID: creatorID,
+		Title:     util.RandomPrintableString(20),
+		Deadline:  time.Now().Add(time.Hour),
+		Priority:  util.PriorityNone,
+		WorkspaceID: pgtype.Int8{
+			Int64: workspaceID,
+			Valid: true,
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, workspaceID, task.WorkspaceID.Int64)
+
+	return task
+}
+
+func TestCreateWorkspaceAddsOwner(t *testing.T) {
+	workspace, owner := createRandomWorkspace(t)
+
+	member, err := testStore.GetWorkspaceMember(context.Background(), GetWorkspaceMemberParams{
+		WorkspaceID: workspace.ID,
+		UserID:      owner.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.WorkspaceRoleOwner, member.Role)
+
+	workspaces, err := testStore.ListUserWorkspaces(context.Background(), owner.ID)
+	require.NoError(t, err)
+	require.Len(t, workspaces, 1)
+	require.Equal(t, workspace.ID, workspaces[0].ID)
+	require.Equal(t, util.WorkspaceRoleOwner, workspaces[0].Role)
+
+	// A workspace has a single owner.
+	admin, err := testStore.AddWorkspaceMember(context.Background(), AddWorkspaceMemberParams{
+		WorkspaceID: workspace.ID,
+		UserID:      createRandomUser(t).ID,
+		Role:        util.WorkspaceRoleAdmin,
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.UpdateWorkspaceMemberRole(context.Background(), UpdateWorkspaceMemberRoleParams{
+		WorkspaceID: workspace.ID,
+		UserID:      admin.UserID,
+		Role:        util.WorkspaceRoleOwner,
+	})
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+}
+
+func TestAddWorkspaceMember(t *testing.T) {
+	workspace, _ := createRandomWorkspace(t)
+	user := createRandomUser(t)
+	arg := AddWorkspaceMemberParams{
+		WorkspaceID: workspace.ID,
+		UserID:      user.ID,
+		Role:        util.WorkspaceRoleMember,
+	}
+
+	member, err := testStore.AddWorkspaceMember(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, util.WorkspaceRoleMember, member.Role)
+
+	// Joining twice keeps the existing membership.
+	arg.Role = util.WorkspaceRoleAdmin
+	_, err = testStore.AddWorkspaceMember(context.Background(), arg)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	members, err := testStore.ListWorkspaceMembers(context.Background(), workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+
+	n, err := testStore.RemoveWorkspaceMember(context.Background(), RemoveWorkspaceMemberParams{
+		WorkspaceID: workspace.ID,
+		UserID:      user.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+}
+
+func TestWorkspaceInvitationExpiry(t *testing.T) {
+	workspace, owner := createRandomWorkspace(t)
+
+	create := func(hash string, expireAt time.Time) WorkspaceInvitation {
+		invitation, err := testStore.CreateWorkspaceInvitation(context.Background(), CreateWorkspaceInvitationParams{
+			ID:          uuid.New(),
+			WorkspaceID: workspace.ID,
+			TokenHash:   hash,
+			Role:        util.WorkspaceRoleMember,
+			CreatedBy:   owner.ID,
+			ExpireAt:    expireAt,
+		})
+		require.NoError(t, err)
+		return invitation
+	}
+
+	valid := create(util.RandomAlphabetString(32), time.Now().Add(time.Hour))
+	expired := create(util.RandomAlphabetString(32), time.Now().Add(-time.Minute))
+
+	invitation, err := testStore.GetWorkspaceInvitationByHash(context.Background(), valid.TokenHash)
+	require.NoError(t, err)
+	require.Equal(t, valid.ID, invitation.ID)
+
+	_, err = testStore.GetWorkspaceInvitationByHash(context.Background(), expired.TokenHash)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	invitations, err := testStore.ListWorkspaceInvitations(context.Background(), workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, invitations, 1)
+	require.Equal(t, valid.ID, invitations[0].ID)
+}
+
+func TestGetTasksInWorkspace(t *testing.T) {
+	workspace, owner := createRandomWorkspace(t)
+	member := createRandomUser(t)
+	_, err := testStore.AddWorkspaceMember(context.Background(), AddWorkspaceMemberParams{
+		WorkspaceID: workspace.ID,
+		UserID:      member.ID,
+		Role:        util.WorkspaceRoleMember,
+	})
+	require.NoError(t, err)
+
+	mine := createWorkspaceTask(t, workspace.ID, member.ID)
+	theirs := createWorkspaceTask(t, workspace.ID, owner.ID)
+
+	// A personal task of the member stays out of the workspace.
+	personal, err := testStore.CreateTask(context.Background(), CreateTaskParams{
+		ID:        util.RandomAlphabetString(21),
+		CreatorID: member.ID,
+		Title:     util.RandomPrintableString(20),
+		Deadline:  time.Now().Add(time.Hour),
+		Priority:  util.PriorityNone,
+	})
+	require.NoError(t, err)
+
+	ids := func(workspaceID pgtype.Int8, shared pgtype.Bool) []string {
+		tasks, err := testStore.GetTasks(context.Background(), GetTasksParams{
+			UserID:      member.ID,
+			WorkspaceID: workspaceID,
+			Shared:      shared,
+			Limit:       10,
+		})
+		require.NoError(t, err)
+
+		ids := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	workspaceID := pgtype.Int8{Int64: workspace.ID, Valid: true}
+	require.ElementsMatch(t, []string{mine.ID, theirs.ID}, ids(workspaceID, pgtype.Bool{}))
+	require.ElementsMatch(t, []string{theirs.ID}, ids(workspaceID, pgtype.Bool{Bool: true, Valid: true}))
+	require.ElementsMatch(t, []string{personal.ID}, ids(pgtype.Int8{}, pgtype.Bool{}))
+
+	// Deleting the workspace deletes its tasks.
+	require.NoError(t, testStore.DeleteWorkspace(context.Background(), workspace.ID))
+	_, err = testStore.GetTaskByID(context.Background(), mine.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestDeleteUserReassignsWorkspaceTasks(t *testing.T) {
+	workspace, owner := createRandomWorkspace(t)
+	member := createRandomUser(t)
+	_, err := testStore.AddWorkspaceMember(context.Background(), AddWorkspaceMemberParams{
+		WorkspaceID: workspace.ID,
+		UserID:      member.ID,
+		Role:        util.WorkspaceRoleMember,
+	})
+	require.NoError(t, err)
+
+	task := createWorkspaceTask(t, workspace.ID, member.ID)
+
+	_, err = testStore.DeleteUser(context.Background(), member.ID)
+	require.NoError(t, err)
+
+	// The task stays in the workspace and is handed to its owner.
+	task, err = testStore.GetTaskByID(context.Background(), task.ID)
+	require.NoError(t, err)
+	require.Equal(t, owner.ID, task.CreatorID)
+}
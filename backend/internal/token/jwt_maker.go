@@ -21,8 +21,8 @@ func NewJWTMaker(secretKey string) (Maker, error) {
 	return &JWTMaker{secretKey}, nil
 }
 
-func (maker *JWTMaker) CreateToken(userID int64, username string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(userID, username, duration)
+func (maker *JWTMaker) CreateToken(userID int64, username string, role string, tokenType string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(userID, username, role, tokenType, duration)
 	if err != nil {
 		return "", payload, err
 	}
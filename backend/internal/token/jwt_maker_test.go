@@ -16,11 +16,12 @@ func TestJWTMaker(t *testing.T) {
 
 	userID := rand.Int64()
 	username := util.RandomUsername()
+	role := util.UserRole
 	duration := time.Minute
 	issuedAt := time.Now()
 	expiredAt := issuedAt.Add(duration)
 
-	token, payload, err := maker.CreateToken(userID, username, duration)
+	token, payload, err := maker.CreateToken(userID, username, role, TokenTypeAccess, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -32,6 +33,8 @@ func TestJWTMaker(t *testing.T) {
 	require.NotZero(t, payload.ID)
 	require.Equal(t, userID, payload.UserID)
 	require.Equal(t, username, payload.Username)
+	require.Equal(t, role, payload.Role)
+	require.Equal(t, TokenTypeAccess, payload.Type)
 	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
 	require.WithinDuration(t, expiredAt, payload.ExpireAt, time.Second)
 }
@@ -42,9 +45,10 @@ func TestExpiredJWTToken(t *testing.T) {
 
 	userID := rand.Int64()
 	username := util.RandomUsername()
+	role := util.UserRole
 	duration := -time.Minute
 
-	token, payload, err := maker.CreateToken(userID, username, duration)
+	token, payload, err := maker.CreateToken(userID, username, role, TokenTypeAccess, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -58,9 +62,10 @@ func TestExpiredJWTToken(t *testing.T) {
 func TestInvalidJWTTokenAlgNone(t *testing.T) {
 	userID := rand.Int64()
 	username := util.RandomUsername()
+	role := util.UserRole
 	duration := time.Minute
 
-	payload, err := NewPayload(userID, username, duration)
+	payload, err := NewPayload(userID, username, role, TokenTypeAccess, duration)
 	require.NoError(t, err)
 
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, payload)
@@ -0,0 +1,63 @@
+package token
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkSigner(t *testing.T) {
+	secretKey := util.RandomPrintableString(32)
+	signer := NewLinkSigner(secretKey, "email-verification")
+
+	id := uuid.New()
+	signed := signer.Sign(id, time.Now().Add(time.Hour))
+
+	gotID, err := signer.Verify(signed)
+	require.NoError(t, err)
+	require.Equal(t, id, gotID)
+
+	// Same secret, different purpose.
+	_, err = NewLinkSigner(secretKey, "password-reset").Verify(signed)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// Different secret, same purpose.
+	_, err = NewLinkSigner(util.RandomPrintableString(32), "email-verification").Verify(signed)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestLinkSignerExpired(t *testing.T) {
+	signer := NewLinkSigner(util.RandomPrintableString(32), "email-verification")
+
+	signed := signer.Sign(uuid.New(), time.Now().Add(-time.Minute))
+
+	_, err := signer.Verify(signed)
+	require.ErrorIs(t, err, ErrExpiredToken)
+}
+
+func TestLinkSignerTampered(t *testing.T) {
+	signer := NewLinkSigner(util.RandomPrintableString(32), "email-verification")
+	signed := signer.Sign(uuid.New(), time.Now().Add(time.Hour))
+
+	otherSigned := signer.Sign(uuid.New(), time.Now().Add(time.Hour))
+	payload, _, _ := strings.Cut(signed, ".")
+	_, otherSignature, _ := strings.Cut(otherSigned, ".")
+
+	testCases := []string{
+		"",
+		"no-dot",
+		payload + "." + otherSignature,
+		payload + ".!!!",
+		"!!!." + otherSignature,
+		"AAAA." + otherSignature,
+	}
+
+	for _, tc := range testCases {
+		_, err := signer.Verify(tc)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	}
+}
@@ -5,6 +5,6 @@ import (
 )
 
 type Maker interface {
-	CreateToken(userID int64, username string, duration time.Duration) (string, *Payload, error)
+	CreateToken(userID int64, username string, role string, tokenType string, duration time.Duration) (string, *Payload, error)
 	VerifyToken(token string) (*Payload, error)
 }
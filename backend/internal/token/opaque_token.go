@@ -0,0 +1,29 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+const opaqueTokenBytes = 32
+
+// newOpaqueToken returns a random prefixed token for the user to keep and the
+// hash that should be stored in its place.
+func newOpaqueToken(prefix string) (token string, hash string, err error) {
+	secret := make([]byte, opaqueTokenBytes)
+	_, err = rand.Read(secret)
+	if err != nil {
+		return
+	}
+
+	token = prefix + base64.RawURLEncoding.EncodeToString(secret)
+	hash = hashOpaqueToken(token)
+	return
+}
+
+func hashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
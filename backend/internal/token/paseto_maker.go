@@ -0,0 +1,73 @@
+package token
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+)
+
+const symmetricKeySize = 32
+
+type PasetoMaker struct {
+	symmetricKey paseto.V4SymmetricKey
+	parser       paseto.Parser
+}
+
+func NewPasetoMaker(symmetricKey string) (Maker, error) {
+	if len(symmetricKey) != symmetricKeySize {
+		return nil, fmt.Errorf("invalid key size: must be exactly %d characters", symmetricKeySize)
+	}
+
+	key, err := paseto.V4SymmetricKeyFromBytes([]byte(symmetricKey))
+	if err != nil {
+		return nil, err
+	}
+
+	// Expiry is checked by Payload.Valid so both makers report ErrExpiredToken.
+	return &PasetoMaker{
+		symmetricKey: key,
+		parser:       paseto.NewParserWithoutExpiryCheck(),
+	}, nil
+}
+
+func (maker *PasetoMaker) CreateToken(userID int64, username string, role string, tokenType string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(userID, username, role, tokenType, duration)
+	if err != nil {
+		return "", payload, err
+	}
+
+	claims, err := json.Marshal(payload)
+	if err != nil {
+		return "", payload, err
+	}
+
+	pasetoToken, err := paseto.NewTokenFromClaimsJSON(claims, nil)
+	if err != nil {
+		return "", payload, err
+	}
+
+	token := pasetoToken.V4Encrypt(maker.symmetricKey, nil)
+	return token, payload, nil
+}
+
+func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
+	pasetoToken, err := maker.parser.ParseV4Local(maker.symmetricKey, token, nil)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	payload := &Payload{}
+	err = json.Unmarshal(pasetoToken.ClaimsJSON(), payload)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	err = payload.Valid()
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,124 @@
+package token
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasetoMaker(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomPrintableString(32))
+	require.NoError(t, err)
+
+	userID := rand.Int64()
+	username := util.RandomUsername()
+	role := util.UserRole
+	duration := time.Minute
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(duration)
+
+	token, payload, err := maker.CreateToken(userID, username, role, TokenTypeAccess, duration)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, payload)
+
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.NotEmpty(t, payload)
+
+	require.NotZero(t, payload.ID)
+	require.Equal(t, userID, payload.UserID)
+	require.Equal(t, username, payload.Username)
+	require.Equal(t, role, payload.Role)
+	require.Equal(t, TokenTypeAccess, payload.Type)
+	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+	require.WithinDuration(t, expiredAt, payload.ExpireAt, time.Second)
+}
+
+func TestExpiredPasetoToken(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomPrintableString(32))
+	require.NoError(t, err)
+
+	userID := rand.Int64()
+	username := util.RandomUsername()
+	role := util.UserRole
+	duration := -time.Minute
+
+	token, payload, err := maker.CreateToken(userID, username, role, TokenTypeAccess, duration)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, payload)
+
+	payload, err = maker.VerifyToken(token)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestInvalidPasetoTokenWrongKey(t *testing.T) {
+	maker1, err := NewPasetoMaker(util.RandomPrintableString(32))
+	require.NoError(t, err)
+
+	maker2, err := NewPasetoMaker(util.RandomPrintableString(32))
+	require.NoError(t, err)
+
+	token, payload, err := maker1.CreateToken(rand.Int64(), util.RandomUsername(), util.UserRole, TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, payload)
+
+	payload, err = maker2.VerifyToken(token)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestInvalidPasetoTokenTampered(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomPrintableString(32))
+	require.NoError(t, err)
+
+	token, payload, err := maker.CreateToken(rand.Int64(), util.RandomUsername(), util.UserRole, TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, payload)
+
+	tampered := []byte(token)
+	i := len(tampered) / 2
+	if tampered[i] == 'A' {
+		tampered[i] = 'B'
+	} else {
+		tampered[i] = 'A'
+	}
+
+	payload, err = maker.VerifyToken(string(tampered))
+	require.Error(t, err)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestInvalidPasetoTokenPublicPurpose(t *testing.T) {
+	payload, err := NewPayload(rand.Int64(), util.RandomUsername(), util.UserRole, TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	pasetoToken := paseto.NewToken()
+	pasetoToken.SetExpiration(payload.ExpireAt)
+	token := pasetoToken.V4Sign(paseto.NewV4AsymmetricSecretKey(), nil)
+
+	maker, err := NewPasetoMaker(util.RandomPrintableString(32))
+	require.NoError(t, err)
+
+	payload, err = maker.VerifyToken(token)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestInvalidPasetoKeySize(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomPrintableString(31))
+	require.Error(t, err)
+	require.Nil(t, maker)
+}
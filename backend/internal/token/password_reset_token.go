@@ -0,0 +1,13 @@
+package token
+
+const PasswordResetTokenPrefix = "tmprt_"
+
+// NewPasswordResetToken returns a random single-use reset token to deliver to
+// the user and the hash that should be stored in its place.
+func NewPasswordResetToken() (token string, hash string, err error) {
+	return newOpaqueToken(PasswordResetTokenPrefix)
+}
+
+func HashPasswordResetToken(token string) string {
+	return hashOpaqueToken(token)
+}
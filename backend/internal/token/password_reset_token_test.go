@@ -0,0 +1,20 @@
+package token
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordResetToken(t *testing.T) {
+	token1, hash1, err := NewPasswordResetToken()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(token1, PasswordResetTokenPrefix))
+	require.Equal(t, hash1, HashPasswordResetToken(token1))
+
+	token2, hash2, err := NewPasswordResetToken()
+	require.NoError(t, err)
+	require.NotEqual(t, token1, token2)
+	require.NotEqual(t, hash1, hash2)
+}
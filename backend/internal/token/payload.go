@@ -7,6 +7,12 @@ import (
 	"github.com/google/uuid"
 )
 
+// Access tokens authenticate requests, refresh tokens only renew access tokens.
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
 var (
 	ErrInvalidToken = errors.New("token is invalid")
 	ErrExpiredToken = errors.New("token has expired")
@@ -17,11 +23,13 @@ type Payload struct {
 	UserID   int64     `json:"user_id"`
 	Username string    `json:"username"`
 	Role     string    `json:"role"`
+	Type     string    `json:"token_type"`
+	Scopes   []string  `json:"scopes,omitempty"`
 	IssuedAt time.Time `json:"issued_at"`
 	ExpireAt time.Time `json:"expired_at"`
 }
 
-func NewPayload(userID int64, username string, duration time.Duration) (*Payload, error) {
+func NewPayload(userID int64, username string, role string, tokenType string, duration time.Duration) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -31,6 +39,8 @@ func NewPayload(userID int64, username string, duration time.Duration) (*Payload
 		ID:       tokenID,
 		UserID:   userID,
 		Username: username,
+		Role:     role,
+		Type:     tokenType,
 		IssuedAt: time.Now(),
 		ExpireAt: time.Now().Add(duration),
 	}
@@ -0,0 +1,21 @@
+package token
+
+import (
+	"strings"
+)
+
+const PersonalAccessTokenPrefix = "tmpat_"
+
+// NewPersonalAccessToken returns a random token for the user to keep and the
+// hash that should be stored in its place.
+func NewPersonalAccessToken() (token string, hash string, err error) {
+	return newOpaqueToken(PersonalAccessTokenPrefix)
+}
+
+func HashPersonalAccessToken(token string) string {
+	return hashOpaqueToken(token)
+}
+
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
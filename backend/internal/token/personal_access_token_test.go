@@ -0,0 +1,22 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersonalAccessToken(t *testing.T) {
+	token1, hash1, err := NewPersonalAccessToken()
+	require.NoError(t, err)
+	require.True(t, IsPersonalAccessToken(token1))
+	require.Equal(t, hash1, HashPersonalAccessToken(token1))
+	require.NotContains(t, hash1, token1)
+
+	token2, hash2, err := NewPersonalAccessToken()
+	require.NoError(t, err)
+	require.NotEqual(t, token1, token2)
+	require.NotEqual(t, hash1, hash2)
+
+	require.False(t, IsPersonalAccessToken("eyJhbGciOiJIUzI1NiJ9"))
+}
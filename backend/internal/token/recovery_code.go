@@ -0,0 +1,67 @@
+package token
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+const (
+	RecoveryCodeCount = 10
+	// recoveryCodeAlphabet leaves out characters that are easily confused
+	// when read back from paper.
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+	recoveryCodeLength   = 10
+)
+
+// NewRecoveryCodes returns one-time codes to show to the user once and the
+// hashes that should be stored in their place.
+func NewRecoveryCodes() (codes []string, hashes []string, err error) {
+	codes = make([]string, RecoveryCodeCount)
+	hashes = make([]string, RecoveryCodeCount)
+
+	for i := range codes {
+		var code []byte
+		code, err = randomRecoveryCode()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		codes[i] = string(code[:recoveryCodeLength/2]) + "-" + string(code[recoveryCodeLength/2:])
+		hashes[i] = HashRecoveryCode(codes[i])
+	}
+
+	return
+}
+
+// HashRecoveryCode ignores case, spaces and dashes so that a code typed back
+// slightly differently still matches.
+func HashRecoveryCode(code string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(code)))
+
+	return hashOpaqueToken(normalized)
+}
+
+func randomRecoveryCode() ([]byte, error) {
+	// Bytes at or above limit are dropped so that every character of the
+	// alphabet is equally likely.
+	limit := 256 - 256%len(recoveryCodeAlphabet)
+
+	code := make([]byte, 0, recoveryCodeLength)
+	buf := make([]byte, recoveryCodeLength)
+	for len(code) < recoveryCodeLength {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		for _, b := range buf {
+			if int(b) < limit && len(code) < recoveryCodeLength {
+				code = append(code, recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)])
+			}
+		}
+	}
+	return code, nil
+}
@@ -0,0 +1,28 @@
+package token
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes, err := NewRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, RecoveryCodeCount)
+	require.Len(t, hashes, RecoveryCodeCount)
+
+	seen := make(map[string]bool)
+	for i, code := range codes {
+		require.Len(t, code, recoveryCodeLength+1)
+		require.Equal(t, byte('-'), code[recoveryCodeLength/2])
+		require.Equal(t, hashes[i], HashRecoveryCode(code))
+		require.False(t, seen[code])
+		seen[code] = true
+	}
+
+	// Typing the code back in capitals and without the dash still matches.
+	typed := strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))
+	require.Equal(t, hashes[0], HashRecoveryCode(" "+typed+" "))
+}
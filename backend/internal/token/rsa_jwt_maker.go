@@ -0,0 +1,191 @@
+package token
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	minRSAKeySize = 2048
+	keyIDHeader   = "kid"
+)
+
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JWKSProvider is implemented by makers whose tokens can be verified by
+// other services using only published public keys.
+type JWKSProvider interface {
+	JWKS() JSONWebKeySet
+}
+
+// RSAJWTMaker signs tokens with a single active RS256 key and verifies them
+// against every configured public key, so keys can be rotated without
+// invalidating tokens that were signed with a retired key.
+type RSAJWTMaker struct {
+	signingKeyID     string
+	signingKey       *rsa.PrivateKey
+	verificationKeys map[string]*rsa.PublicKey
+}
+
+func NewRSAJWTMaker(signingKeyID string, signingKey *rsa.PrivateKey, verificationKeys map[string]*rsa.PublicKey) (Maker, error) {
+	if len(signingKeyID) == 0 {
+		return nil, errors.New("signing key ID is not specified")
+	}
+
+	if signingKey == nil {
+		return nil, errors.New("signing key is not specified")
+	}
+
+	keys := map[string]*rsa.PublicKey{
+		signingKeyID: &signingKey.PublicKey,
+	}
+	for kid, key := range verificationKeys {
+		if kid == signingKeyID {
+			continue
+		}
+		keys[kid] = key
+	}
+
+	for kid, key := range keys {
+		if key.N.BitLen() < minRSAKeySize {
+			return nil, fmt.Errorf("invalid key size for %s: must be at least %d bits", kid, minRSAKeySize)
+		}
+	}
+
+	return &RSAJWTMaker{
+		signingKeyID:     signingKeyID,
+		signingKey:       signingKey,
+		verificationKeys: keys,
+	}, nil
+}
+
+// NewRSAJWTMakerFromDir loads every "<kid>.pem" file in keysDir. Private keys
+// may sign and verify; public keys are only used to verify tokens signed
+// before a rotation. The key named by signingKeyID must be a private key.
+func NewRSAJWTMakerFromDir(keysDir string, signingKeyID string) (Maker, error) {
+	files, err := filepath.Glob(filepath.Join(keysDir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	var signingKey *rsa.PrivateKey
+	verificationKeys := make(map[string]*rsa.PublicKey)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		kid := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		if privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+			verificationKeys[kid] = &privateKey.PublicKey
+			if kid == signingKeyID {
+				signingKey = privateKey
+			}
+			continue
+		}
+
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key file %s: %w", file, err)
+		}
+		verificationKeys[kid] = publicKey
+	}
+
+	if signingKey == nil {
+		return nil, fmt.Errorf("private key %s is not found in %s", signingKeyID, keysDir)
+	}
+
+	return NewRSAJWTMaker(signingKeyID, signingKey, verificationKeys)
+}
+
+func (maker *RSAJWTMaker) CreateToken(userID int64, username string, role string, tokenType string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(userID, username, role, tokenType, duration)
+	if err != nil {
+		return "", payload, err
+	}
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodRS256, payload)
+	jwtToken.Header[keyIDHeader] = maker.signingKeyID
+	token, err := jwtToken.SignedString(maker.signingKey)
+	return token, payload, err
+}
+
+func (maker *RSAJWTMaker) VerifyToken(token string) (*Payload, error) {
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodRS256 {
+			return nil, ErrInvalidToken
+		}
+
+		kid, ok := token.Header[keyIDHeader].(string)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+
+		key, ok := maker.verificationKeys[kid]
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+		return key, nil
+	}
+
+	jwtToken, err := jwt.ParseWithClaims(token, &Payload{}, keyFunc)
+	if err != nil {
+		verr, ok := err.(*jwt.ValidationError)
+		if ok && errors.Is(verr.Inner, ErrExpiredToken) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	payload, ok := jwtToken.Claims.(*Payload)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	return payload, nil
+}
+
+func (maker *RSAJWTMaker) JWKS() JSONWebKeySet {
+	kids := make([]string, 0, len(maker.verificationKeys))
+	for kid := range maker.verificationKeys {
+		kids = append(kids, kid)
+	}
+	slices.Sort(kids)
+
+	keySet := JSONWebKeySet{
+		Keys: make([]JSONWebKey, 0, len(kids)),
+	}
+	for _, kid := range kids {
+		key := maker.verificationKeys[kid]
+		keySet.Keys = append(keySet.Keys, JSONWebKey{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: jwt.SigningMethodRS256.Alg(),
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	return keySet
+}
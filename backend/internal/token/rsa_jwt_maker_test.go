@@ -0,0 +1,215 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	mathrand "math/rand/v2"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/nguyen-duc-loc/task-management/backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomRSAKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, minRSAKeySize)
+	require.NoError(t, err)
+	return key
+}
+
+func TestRSAJWTMaker(t *testing.T) {
+	maker, err := NewRSAJWTMaker("key-1", randomRSAKey(t), nil)
+	require.NoError(t, err)
+
+	userID := mathrand.Int64()
+	username := util.RandomUsername()
+	role := util.UserRole
+	duration := time.Minute
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(duration)
+
+	token, payload, err := maker.CreateToken(userID, username, role, TokenTypeAccess, duration)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, payload)
+
+	jwtToken, _, err := new(jwt.Parser).ParseUnverified(token, &Payload{})
+	require.NoError(t, err)
+	require.Equal(t, "key-1", jwtToken.Header[keyIDHeader])
+	require.Equal(t, jwt.SigningMethodRS256.Alg(), jwtToken.Header["alg"])
+
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.NotEmpty(t, payload)
+
+	require.NotZero(t, payload.ID)
+	require.Equal(t, userID, payload.UserID)
+	require.Equal(t, username, payload.Username)
+	require.Equal(t, role, payload.Role)
+	require.Equal(t, TokenTypeAccess, payload.Type)
+	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+	require.WithinDuration(t, expiredAt, payload.ExpireAt, time.Second)
+}
+
+func TestExpiredRSAJWTToken(t *testing.T) {
+	maker, err := NewRSAJWTMaker("key-1", randomRSAKey(t), nil)
+	require.NoError(t, err)
+
+	token, payload, err := maker.CreateToken(mathrand.Int64(), util.RandomUsername(), util.UserRole, TokenTypeAccess, -time.Minute)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, payload)
+
+	payload, err = maker.VerifyToken(token)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestInvalidRSAJWTTokenAlgNone(t *testing.T) {
+	payload, err := NewPayload(mathrand.Int64(), util.RandomUsername(), util.UserRole, TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, payload)
+	jwtToken.Header[keyIDHeader] = "key-1"
+	token, err := jwtToken.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	maker, err := NewRSAJWTMaker("key-1", randomRSAKey(t), nil)
+	require.NoError(t, err)
+
+	payload, err = maker.VerifyToken(token)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestInvalidRSAJWTTokenAlgHS256(t *testing.T) {
+	key := randomRSAKey(t)
+	maker, err := NewRSAJWTMaker("key-1", key, nil)
+	require.NoError(t, err)
+
+	payload, err := NewPayload(mathrand.Int64(), util.RandomUsername(), util.UserRole, TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
+	jwtToken.Header[keyIDHeader] = "key-1"
+	token, err := jwtToken.SignedString(publicKeyDER)
+	require.NoError(t, err)
+
+	payload, err = maker.VerifyToken(token)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestInvalidRSAJWTTokenUnknownKeyID(t *testing.T) {
+	maker1, err := NewRSAJWTMaker("key-1", randomRSAKey(t), nil)
+	require.NoError(t, err)
+
+	maker2, err := NewRSAJWTMaker("key-2", randomRSAKey(t), nil)
+	require.NoError(t, err)
+
+	token, _, err := maker1.CreateToken(mathrand.Int64(), util.RandomUsername(), util.UserRole, TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker2.VerifyToken(token)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestRSAJWTMakerKeyRotation(t *testing.T) {
+	oldKey := randomRSAKey(t)
+	oldMaker, err := NewRSAJWTMaker("key-1", oldKey, nil)
+	require.NoError(t, err)
+
+	token, _, err := oldMaker.CreateToken(mathrand.Int64(), util.RandomUsername(), util.UserRole, TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	newMaker, err := NewRSAJWTMaker("key-2", randomRSAKey(t), map[string]*rsa.PublicKey{
+		"key-1": &oldKey.PublicKey,
+	})
+	require.NoError(t, err)
+
+	payload, err := newMaker.VerifyToken(token)
+	require.NoError(t, err)
+	require.NotEmpty(t, payload)
+
+	newToken, _, err := newMaker.CreateToken(mathrand.Int64(), util.RandomUsername(), util.UserRole, TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	jwtToken, _, err := new(jwt.Parser).ParseUnverified(newToken, &Payload{})
+	require.NoError(t, err)
+	require.Equal(t, "key-2", jwtToken.Header[keyIDHeader])
+}
+
+func TestRSAJWTMakerJWKS(t *testing.T) {
+	key1 := randomRSAKey(t)
+	key2 := randomRSAKey(t)
+	maker, err := NewRSAJWTMaker("key-2", key2, map[string]*rsa.PublicKey{
+		"key-1": &key1.PublicKey,
+	})
+	require.NoError(t, err)
+
+	provider, ok := maker.(JWKSProvider)
+	require.True(t, ok)
+
+	keySet := provider.JWKS()
+	require.Len(t, keySet.Keys, 2)
+	require.Equal(t, "key-1", keySet.Keys[0].Kid)
+	require.Equal(t, "key-2", keySet.Keys[1].Kid)
+
+	for _, key := range keySet.Keys {
+		require.Equal(t, "RSA", key.Kty)
+		require.Equal(t, "sig", key.Use)
+		require.Equal(t, "RS256", key.Alg)
+		require.NotEmpty(t, key.N)
+		require.Equal(t, "AQAB", key.E)
+	}
+}
+
+func TestInvalidRSAKeySize(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	maker, err := NewRSAJWTMaker("key-1", key, nil)
+	require.Error(t, err)
+	require.Nil(t, maker)
+}
+
+func TestNewRSAJWTMakerFromDir(t *testing.T) {
+	dir := t.TempDir()
+	oldKey := randomRSAKey(t)
+	newKey := randomRSAKey(t)
+
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(&oldKey.PublicKey)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "key-1.pem"), pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: publicKeyDER,
+	}), 0o600)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(dir, "key-2.pem"), pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(newKey),
+	}), 0o600)
+	require.NoError(t, err)
+
+	maker, err := NewRSAJWTMakerFromDir(dir, "key-2")
+	require.NoError(t, err)
+	require.Len(t, maker.(JWKSProvider).JWKS().Keys, 2)
+
+	maker, err = NewRSAJWTMakerFromDir(dir, "key-1")
+	require.Error(t, err)
+	require.Nil(t, maker)
+}
@@ -0,0 +1,73 @@
+package token
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTPCodeRFC6238(t *testing.T) {
+	// SHA1 test vectors from RFC 6238 appendix B, truncated to six digits.
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	testCases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tc := range testCases {
+		code, err := TOTPCode(secret, TOTPStep(time.Unix(tc.unix, 0)))
+		require.NoError(t, err)
+		require.Equal(t, tc.code, code)
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	secret, err := NewTOTPSecret()
+	require.NoError(t, err)
+
+	now := time.Now()
+	step := TOTPStep(now)
+
+	for _, s := range []int64{step - 1, step, step + 1} {
+		code, err := TOTPCode(secret, s)
+		require.NoError(t, err)
+
+		matched, err := VerifyTOTP(secret, code, now)
+		require.NoError(t, err)
+		require.Equal(t, s, matched)
+	}
+
+	code, err := TOTPCode(secret, step-3)
+	require.NoError(t, err)
+	_, err = VerifyTOTP(secret, code, now)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = VerifyTOTP(secret, "12345", now)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestTOTPURI(t *testing.T) {
+	secret, err := NewTOTPSecret()
+	require.NoError(t, err)
+
+	uri, err := url.Parse(TOTPURI("Task Management", "alice", secret))
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", uri.Scheme)
+	require.Equal(t, "totp", uri.Host)
+	require.Equal(t, "/Task Management:alice", uri.Path)
+	require.Equal(t, secret, uri.Query().Get("secret"))
+	require.Equal(t, "Task Management", uri.Query().Get("issuer"))
+	require.Equal(t, "6", uri.Query().Get("digits"))
+	require.Equal(t, "30", uri.Query().Get("period"))
+}
@@ -0,0 +1,14 @@
+package token
+
+const WorkspaceInvitationTokenPrefix = "tmwi_"
+
+// NewWorkspaceInvitationToken returns a random invitation token to share with
+// the people joining a workspace and the hash that should be stored in its
+// place.
+func NewWorkspaceInvitationToken() (token string, hash string, err error) {
+	return newOpaqueToken(WorkspaceInvitationTokenPrefix)
+}
+
+func HashWorkspaceInvitationToken(token string) string {
+	return hashOpaqueToken(token)
+}
@@ -0,0 +1,20 @@
+package token
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaceInvitationToken(t *testing.T) {
+	token1, hash1, err := NewWorkspaceInvitationToken()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(token1, WorkspaceInvitationTokenPrefix))
+	require.Equal(t, hash1, HashWorkspaceInvitationToken(token1))
+
+	token2, hash2, err := NewWorkspaceInvitationToken()
+	require.NoError(t, err)
+	require.NotEqual(t, token1, token2)
+	require.NotEqual(t, hash1, hash2)
+}
@@ -2,7 +2,11 @@ package util
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -22,8 +26,47 @@ type DatabaseConfig struct {
 }
 
 type JWTConfig struct {
-	SecretKey           string
-	AccessTokenDuration time.Duration
+	TokenMaker           string
+	SecretKey            string
+	KeysDir              string
+	SigningKeyID         string
+	AccessTokenDuration  time.Duration
+	RefreshTokenDuration time.Duration
+}
+
+type MailConfig struct {
+	SMTPHost      string
+	SMTPPort      string
+	SMTPUsername  string
+	SMTPPassword  string
+	From          string
+	PublicBaseURL string
+}
+
+// CookieConfig applies to the cookies set when a client opts into cookie
+// authentication at login.
+type CookieConfig struct {
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+}
+
+// OIDCConfig is empty when no OpenID Connect provider is configured.
+type OIDCConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// ReminderConfig controls the scheduler that delivers task reminders.
+type ReminderConfig struct {
+	PollInterval time.Duration
+	// Lease is how long a claimed reminder is reserved for the replica that
+	// claimed it before another replica may retry it.
+	Lease     time.Duration
+	BatchSize int32
 }
 
 func LoadSeverEnv() string {
@@ -125,7 +168,192 @@ func LoadJWTConfig() (jwtConfig JWTConfig, err error) {
 		return
 	}
 
+	refreshTokenDurationEnv := os.Getenv("JWT_REFRESH_TOKEN_DURATION")
+	if len(refreshTokenDurationEnv) == 0 {
+		err = errors.New("refresh token duration is not specified")
+		return
+	}
+	refreshTokenDuration, err := time.ParseDuration(refreshTokenDurationEnv)
+	if err != nil {
+		return
+	}
+
+	tokenMaker := os.Getenv("TOKEN_MAKER")
+	if len(tokenMaker) == 0 {
+		tokenMaker = "jwt"
+	}
+
+	jwtConfig.TokenMaker = tokenMaker
 	jwtConfig.SecretKey = secretKey
+	jwtConfig.KeysDir = os.Getenv("JWT_KEYS_DIR")
+	jwtConfig.SigningKeyID = os.Getenv("JWT_SIGNING_KEY_ID")
 	jwtConfig.AccessTokenDuration = accessTokenDuration
+	jwtConfig.RefreshTokenDuration = refreshTokenDuration
+	return
+}
+
+// LoadMailConfig defaults to a MailHog-style SMTP server on localhost:1025 so
+// that local setups work without any configuration.
+func LoadMailConfig() (mailConfig MailConfig, err error) {
+	serverEnv := LoadSeverEnv()
+
+	var password string
+	if serverEnv == "dev" {
+		password = os.Getenv("SMTP_PASSWORD")
+	} else {
+		var secrets Secrets
+		secrets, err = getSecrets()
+		if err != nil {
+			return
+		}
+		password = secrets.SMTPPassword
+	}
+
+	host := os.Getenv("SMTP_HOST")
+	if len(host) == 0 {
+		host = "localhost"
+	}
+
+	port := os.Getenv("SMTP_PORT")
+	if len(port) == 0 {
+		port = "1025"
+	}
+
+	from := os.Getenv("MAIL_FROM")
+	if len(from) == 0 {
+		from = "no-reply@localhost"
+	}
+
+	publicBaseURL := os.Getenv("PUBLIC_BASE_URL")
+	if len(publicBaseURL) == 0 {
+		publicBaseURL = "http://localhost:" + os.Getenv("SERVER_PORT")
+	}
+
+	mailConfig.SMTPHost = host
+	mailConfig.SMTPPort = port
+	mailConfig.SMTPUsername = os.Getenv("SMTP_USERNAME")
+	mailConfig.SMTPPassword = password
+	mailConfig.From = from
+	mailConfig.PublicBaseURL = strings.TrimSuffix(publicBaseURL, "/")
+	return
+}
+
+// LoadOIDCConfig returns an empty config unless OIDC_ISSUER_URL is set. The
+// redirect URL defaults to the callback route under PUBLIC_BASE_URL.
+func LoadOIDCConfig() (oidcConfig OIDCConfig, err error) {
+	issuerURL := os.Getenv("OIDC_ISSUER_URL")
+	if len(issuerURL) == 0 {
+		return
+	}
+
+	clientID := os.Getenv("OIDC_CLIENT_ID")
+	if len(clientID) == 0 {
+		err = errors.New("OIDC client ID is not specified")
+		return
+	}
+
+	// Public clients rely on PKCE alone and have no secret.
+	var clientSecret string
+	if LoadSeverEnv() == "dev" {
+		clientSecret = os.Getenv("OIDC_CLIENT_SECRET")
+	} else {
+		var secrets Secrets
+		secrets, err = getSecrets()
+		if err != nil {
+			return
+		}
+		clientSecret = secrets.OIDCClientSecret
+	}
+
+	redirectURL := os.Getenv("OIDC_REDIRECT_URL")
+	if len(redirectURL) == 0 {
+		var mailConfig MailConfig
+		mailConfig, err = LoadMailConfig()
+		if err != nil {
+			return
+		}
+		redirectURL = mailConfig.PublicBaseURL + "/auth/oidc/callback"
+	}
+
+	scopes := strings.Fields(os.Getenv("OIDC_SCOPES"))
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "profile", "email"}
+	}
+
+	oidcConfig.IssuerURL = strings.TrimSuffix(issuerURL, "/")
+	oidcConfig.ClientID = clientID
+	oidcConfig.ClientSecret = clientSecret
+	oidcConfig.RedirectURL = redirectURL
+	oidcConfig.Scopes = scopes
+	return
+}
+
+// LoadCookieConfig defaults to host-only, SameSite=Lax cookies that are only
+// marked Secure in production, where the API is served over HTTPS.
+func LoadCookieConfig() (cookieConfig CookieConfig, err error) {
+	secure := LoadSeverEnv() == "prod"
+	if secureEnv := os.Getenv("COOKIE_SECURE"); len(secureEnv) > 0 {
+		secure = secureEnv == "true"
+	}
+
+	var sameSite http.SameSite
+	switch strings.ToLower(os.Getenv("COOKIE_SAMESITE")) {
+	case "", "lax":
+		sameSite = http.SameSiteLaxMode
+	case "strict":
+		sameSite = http.SameSiteStrictMode
+	case "none":
+		sameSite = http.SameSiteNoneMode
+	default:
+		err = fmt.Errorf("unsupported cookie SameSite mode %s", os.Getenv("COOKIE_SAMESITE"))
+		return
+	}
+
+	// Browsers reject SameSite=None cookies that are not Secure.
+	if sameSite == http.SameSiteNoneMode && !secure {
+		err = errors.New("SameSite=None cookies must be secure")
+		return
+	}
+
+	cookieConfig.Domain = os.Getenv("COOKIE_DOMAIN")
+	cookieConfig.Secure = secure
+	cookieConfig.SameSite = sameSite
+	return
+}
+
+// LoadReminderConfig defaults to polling every 30 seconds for up to 100 due
+// reminders, each leased for a minute.
+func LoadReminderConfig() (reminderConfig ReminderConfig, err error) {
+	reminderConfig.PollInterval = 30 * time.Second
+	if pollIntervalEnv := os.Getenv("REMINDER_POLL_INTERVAL"); len(pollIntervalEnv) > 0 {
+		reminderConfig.PollInterval, err = time.ParseDuration(pollIntervalEnv)
+		if err != nil {
+			return
+		}
+	}
+
+	reminderConfig.Lease = time.Minute
+	if leaseEnv := os.Getenv("REMINDER_LEASE"); len(leaseEnv) > 0 {
+		reminderConfig.Lease, err = time.ParseDuration(leaseEnv)
+		if err != nil {
+			return
+		}
+	}
+
+	reminderConfig.BatchSize = 100
+	if batchSizeEnv := os.Getenv("REMINDER_BATCH_SIZE"); len(batchSizeEnv) > 0 {
+		var batchSize int64
+		batchSize, err = strconv.ParseInt(batchSizeEnv, 10, 32)
+		if err != nil {
+			return
+		}
+		reminderConfig.BatchSize = int32(batchSize)
+	}
+
+	// Leases are stored with a precision of one second.
+	if reminderConfig.PollInterval <= 0 || reminderConfig.Lease < time.Second || reminderConfig.BatchSize <= 0 {
+		err = errors.New("reminder poll interval and batch size must be positive and the lease at least a second")
+		return
+	}
 	return
 }
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -13,16 +14,71 @@ func TestPassword(t *testing.T) {
 	hashedPassword1, err := HashPassword(password)
 	require.NoError(t, err)
 	require.NotEmpty(t, hashedPassword1)
+	require.True(t, strings.HasPrefix(hashedPassword1, "$argon2id$v=19$m=19456,t=2,p=1$"))
 
 	err = CheckPassword(password, hashedPassword1)
 	require.NoError(t, err)
 
 	wrongPassword := RandomPrintableString(8)
 	err = CheckPassword(wrongPassword, hashedPassword1)
-	require.EqualError(t, err, bcrypt.ErrMismatchedHashAndPassword.Error())
+	require.ErrorIs(t, err, ErrMismatchedPassword)
 
 	hashedPassword2, err := HashPassword(password)
 	require.NoError(t, err)
 	require.NotEmpty(t, hashedPassword2)
 	require.NotEqual(t, hashedPassword1, hashedPassword2)
+
+	require.False(t, PasswordNeedsRehash(hashedPassword1))
+}
+
+func TestBcryptPassword(t *testing.T) {
+	password := RandomPrintableString(8)
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	require.NoError(t, err)
+
+	err = CheckPassword(password, string(hashedPassword))
+	require.NoError(t, err)
+
+	err = CheckPassword(RandomPrintableString(8), string(hashedPassword))
+	require.ErrorIs(t, err, ErrMismatchedPassword)
+
+	require.True(t, PasswordNeedsRehash(string(hashedPassword)))
+}
+
+func TestPasswordNeedsRehash(t *testing.T) {
+	password := RandomPrintableString(8)
+
+	weakParams := DefaultArgon2Params
+	weakParams.Memory = 8 * 1024
+	weakParams.Iterations = 1
+
+	hashedPassword, err := hashPasswordArgon2id(password, weakParams)
+	require.NoError(t, err)
+
+	err = CheckPassword(password, hashedPassword)
+	require.NoError(t, err)
+	require.True(t, PasswordNeedsRehash(hashedPassword))
+
+	strongParams := DefaultArgon2Params
+	strongParams.Iterations = DefaultArgon2Params.Iterations + 1
+
+	hashedPassword, err = hashPasswordArgon2id(password, strongParams)
+	require.NoError(t, err)
+	require.False(t, PasswordNeedsRehash(hashedPassword))
+}
+
+func TestCheckPasswordMalformedHash(t *testing.T) {
+	testCases := []string{
+		"$argon2id$v=19$m=19456,t=2,p=1$onlysalt",
+		"$argon2id$v=18$m=19456,t=2,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=x,t=2,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=19456,t=2,p=1$!!!$a2V5",
+	}
+
+	for _, hashedPassword := range testCases {
+		err := CheckPassword("password", hashedPassword)
+		require.ErrorIs(t, err, ErrUnsupportedPasswordHash)
+		require.True(t, PasswordNeedsRehash(hashedPassword))
+	}
 }
@@ -0,0 +1,6 @@
+package util
+
+const (
+	UserRole  = "user"
+	AdminRole = "admin"
+)
@@ -17,8 +17,10 @@ func init() {
 }
 
 type Secrets struct {
-	JWTSecretKey string `json:"JWT_SECRET_KEY"`
-	DBPassword   string `json:"DB_PASSWORD"`
+	JWTSecretKey     string `json:"JWT_SECRET_KEY"`
+	DBPassword       string `json:"DB_PASSWORD"`
+	SMTPPassword     string `json:"SMTP_PASSWORD"`
+	OIDCClientSecret string `json:"OIDC_CLIENT_SECRET"`
 }
 
 func getSecrets() (Secrets, error) {
@@ -0,0 +1,8 @@
+package util
+
+// Roles a user can be given on a task shared with them, from least to most
+// access. The creator of a task always has full access to it.
+const (
+	ShareRoleViewer = "viewer"
+	ShareRoleEditor = "editor"
+)
@@ -0,0 +1,8 @@
+package util
+
+// Roles of the members of a workspace, from most to least privileged.
+const (
+	WorkspaceRoleOwner  = "owner"
+	WorkspaceRoleAdmin  = "admin"
+	WorkspaceRoleMember = "member"
+)